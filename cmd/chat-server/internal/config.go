@@ -5,13 +5,48 @@ import (
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/postgresql"
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/server"
+	"github.com/Knoblauchpilze/chat-server/internal/gateway"
+	"github.com/Knoblauchpilze/chat-server/pkg/bus"
+	"github.com/Knoblauchpilze/chat-server/pkg/clients"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
 )
 
+type AttachmentsConfig struct {
+	StorageDirectory string
+	MaxSizeInBytes   int64
+	AllowedMimeTypes []string
+
+	ThumbnailSize      int
+	ThumbnailQueueSize int
+	// ThumbnailMaxPixels is the largest width times height of the images
+	// for which a thumbnail is generated.
+	ThumbnailMaxPixels int
+
+	// UnlinkedRetention defines how long an uploaded attachment is kept
+	// when it is not sent in a message.
+	UnlinkedRetention time.Duration
+}
+
 type Configuration struct {
-	Server                 server.Config
-	MessageQueueSize       int
-	ClientMessageQueueSize int
-	Database               postgresql.Config
+	Server                  server.Config
+	MessageQueueSize        int
+	MessageWorkers          int
+	MessageBatch            messages.BatchPolicy
+	MessageRetry            messages.RetryPolicy
+	ClientMessageQueueSize  int
+	MembershipSyncInterval  time.Duration
+	LongPoll                clients.PollPolicy
+	MessageBus              bus.Config
+	MaxPinsPerRoom          int
+	SchedulerPollInterval   time.Duration
+	OutboxPollInterval      time.Duration
+	ExpirationSweepInterval time.Duration
+	IdempotencyWindow       time.Duration
+	Attachments             AttachmentsConfig
+	TcpGateway              gateway.TcpConfig
+	IrcGateway              gateway.IrcConfig
+	GrpcGateway             gateway.GrpcConfig
+	Database                postgresql.Config
 }
 
 func DefaultConfig() Configuration {
@@ -24,8 +59,66 @@ func DefaultConfig() Configuration {
 			Port:            uint16(80),
 			ShutdownTimeout: 3 * time.Second,
 		},
-		MessageQueueSize:       10,
+		MessageQueueSize: 10,
+		MessageWorkers:   4,
+		MessageBatch: messages.BatchPolicy{
+			Size:  50,
+			Delay: 10 * time.Millisecond,
+		},
+		MessageRetry: messages.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     100 * time.Millisecond,
+		},
 		ClientMessageQueueSize: 2,
+		MembershipSyncInterval: 1 * time.Minute,
+		LongPoll: clients.PollPolicy{
+			BufferSize: 100,
+			Timeout:    25 * time.Second,
+			Retention:  1 * time.Minute,
+		},
+		MessageBus: bus.Config{
+			Backend: bus.InMemory,
+			Nats: bus.NatsConfig{
+				Url:           "nats://172.17.0.1:4222",
+				SubjectPrefix: "chat",
+			},
+		},
+		MaxPinsPerRoom:          10,
+		SchedulerPollInterval:   1 * time.Second,
+		OutboxPollInterval:      1 * time.Second,
+		ExpirationSweepInterval: 5 * time.Second,
+		IdempotencyWindow:       24 * time.Hour,
+		Attachments: AttachmentsConfig{
+			StorageDirectory: "data/attachments",
+			MaxSizeInBytes:   10 * 1024 * 1024,
+			AllowedMimeTypes: []string{
+				"image/png",
+				"image/jpeg",
+				"image/gif",
+				"application/pdf",
+				"text/plain",
+			},
+			ThumbnailSize:      256,
+			ThumbnailQueueSize: 10,
+			ThumbnailMaxPixels: 40 * 1000 * 1000,
+			UnlinkedRetention:  24 * time.Hour,
+		},
+		TcpGateway: gateway.TcpConfig{
+			Enabled: false,
+			Port:    7000,
+		},
+		IrcGateway: gateway.IrcConfig{
+			Enabled:    false,
+			Port:       6667,
+			ServerName: "chat-server",
+		},
+		GrpcGateway: gateway.GrpcConfig{
+			Enabled: false,
+			Port:    9090,
+			// Attachments are base64 encoded in the messages: this leaves
+			// room for the largest ones allowed by default.
+			MaxMessageSizeInBytes: 16 * 1024 * 1024,
+		},
 		Database: postgresql.NewConfigForDockerContainer(
 			defaultDatabaseName,
 			defaultDatabaseUser,
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"encoding/base64"
 	"testing"
 	"time"
 
@@ -25,12 +26,123 @@ func TestUnit_DefaultConfig_DefinesReasonableMessageQueueSize(t *testing.T) {
 	assert.Equal(t, 10, config.MessageQueueSize)
 }
 
+func TestUnit_DefaultConfig_DefinesReasonableMessageWorkers(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.Equal(t, 4, config.MessageWorkers)
+}
+
+func TestUnit_DefaultConfig_DefinesReasonableMessageBatch(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.Equal(t, 50, config.MessageBatch.Size)
+	assert.Equal(t, 10*time.Millisecond, config.MessageBatch.Delay)
+}
+
 func TestUnit_DefaultConfig_DefinesReasonableClientMessageQueueSize(t *testing.T) {
 	config := DefaultConfig()
 
 	assert.Equal(t, 2, config.ClientMessageQueueSize)
 }
 
+func TestUnit_DefaultConfig_DefinesInMemoryMessageBus(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.Equal(t, "memory", config.MessageBus.Backend)
+}
+
+func TestUnit_DefaultConfig_DefinesReasonableNatsConfig(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.Equal(t, "nats://172.17.0.1:4222", config.MessageBus.Nats.Url)
+	assert.Equal(t, "chat", config.MessageBus.Nats.SubjectPrefix)
+}
+
+func TestUnit_DefaultConfig_DefinesReasonableMaxPinsPerRoom(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.Equal(t, 10, config.MaxPinsPerRoom)
+}
+
+func TestUnit_DefaultConfig_DefinesReasonableSchedulerPollInterval(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.Equal(t, 1*time.Second, config.SchedulerPollInterval)
+}
+
+func TestUnit_DefaultConfig_DefinesReasonableOutboxPollInterval(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.Equal(t, 1*time.Second, config.OutboxPollInterval)
+}
+
+func TestUnit_DefaultConfig_DefinesReasonableMembershipSyncInterval(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.Equal(t, 1*time.Minute, config.MembershipSyncInterval)
+}
+
+func TestUnit_DefaultConfig_DefinesReasonableLongPoll(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.Equal(t, 100, config.LongPoll.BufferSize)
+	assert.Equal(t, 25*time.Second, config.LongPoll.Timeout)
+	assert.Equal(t, 1*time.Minute, config.LongPoll.Retention)
+}
+
+func TestUnit_DefaultConfig_DefinesReasonableExpirationSweepInterval(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.Equal(t, 5*time.Second, config.ExpirationSweepInterval)
+}
+
+func TestUnit_DefaultConfig_DefinesReasonableIdempotencyWindow(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.Equal(t, 24*time.Hour, config.IdempotencyWindow)
+}
+
+func TestUnit_DefaultConfig_DefinesReasonableMessageRetry(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.Equal(t, 3, config.MessageRetry.MaxAttempts)
+	assert.Equal(t, 100*time.Millisecond, config.MessageRetry.Backoff)
+}
+
+func TestUnit_DefaultConfig_DefinesAttachmentsStorage(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.Equal(t, "data/attachments", config.Attachments.StorageDirectory)
+	assert.Equal(t, int64(10*1024*1024), config.Attachments.MaxSizeInBytes)
+}
+
+func TestUnit_DefaultConfig_AllowsCommonAttachmentTypes(t *testing.T) {
+	config := DefaultConfig()
+
+	expected := []string{
+		"image/png",
+		"image/jpeg",
+		"image/gif",
+		"application/pdf",
+		"text/plain",
+	}
+	assert.Equal(t, expected, config.Attachments.AllowedMimeTypes)
+}
+
+func TestUnit_DefaultConfig_DefinesReasonableThumbnails(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.Equal(t, 256, config.Attachments.ThumbnailSize)
+	assert.Equal(t, 10, config.Attachments.ThumbnailQueueSize)
+	assert.Equal(t, 40*1000*1000, config.Attachments.ThumbnailMaxPixels)
+}
+
+func TestUnit_DefaultConfig_DefinesReasonableUnlinkedAttachmentRetention(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.Equal(t, 24*time.Hour, config.Attachments.UnlinkedRetention)
+}
+
 func TestUnit_DefaultConfig_SetsExpectedDbConnection(t *testing.T) {
 	config := DefaultConfig()
 
@@ -44,3 +156,32 @@ func TestUnit_DefaultConfig_DoesNotSetDbPassword(t *testing.T) {
 
 	assert.Equal(t, "comes-from-the-environment", config.Database.Password)
 }
+
+func TestUnit_DefaultConfig_DisablesTcpGateway(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.False(t, config.TcpGateway.Enabled)
+	assert.Equal(t, uint16(7000), config.TcpGateway.Port)
+}
+
+func TestUnit_DefaultConfig_DisablesIrcGateway(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.False(t, config.IrcGateway.Enabled)
+	assert.Equal(t, uint16(6667), config.IrcGateway.Port)
+	assert.Equal(t, "chat-server", config.IrcGateway.ServerName)
+}
+
+func TestUnit_DefaultConfig_DisablesGrpcGateway(t *testing.T) {
+	config := DefaultConfig()
+
+	assert.False(t, config.GrpcGateway.Enabled)
+	assert.Equal(t, uint16(9090), config.GrpcGateway.Port)
+}
+
+func TestUnit_DefaultConfig_GrpcMessagesFitLargestAttachment(t *testing.T) {
+	config := DefaultConfig()
+
+	encodedSize := base64.StdEncoding.EncodedLen(int(config.Attachments.MaxSizeInBytes))
+	assert.Greater(t, config.GrpcGateway.MaxMessageSizeInBytes, encodedSize)
+}
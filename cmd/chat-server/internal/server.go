@@ -3,15 +3,22 @@ package internal
 import (
 	"context"
 	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/process"
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/server"
 	"github.com/Knoblauchpilze/chat-server/internal/controller"
+	"github.com/Knoblauchpilze/chat-server/internal/gateway"
 	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/bus"
 	"github.com/Knoblauchpilze/chat-server/pkg/clients"
 	"github.com/Knoblauchpilze/chat-server/pkg/messages"
 	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/Knoblauchpilze/chat-server/pkg/storage"
+	"github.com/Knoblauchpilze/chat-server/pkg/thumbnails"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -24,53 +31,141 @@ func RunServer(ctx context.Context, config Configuration, log *slog.Logger) erro
 
 	repos := repositories.New(dbConn)
 
-	manager := clients.NewManager(repos)
-	processor := messages.NewMessageProcessor(config.MessageQueueSize, manager, repos)
+	store, err := storage.NewFilesystemStore(config.Attachments.StorageDirectory)
+	if err != nil {
+		return err
+	}
+
+	generator := thumbnails.NewGenerator(
+		config.Attachments.ThumbnailQueueSize,
+		config.Attachments.ThumbnailSize,
+		config.Attachments.ThumbnailMaxPixels,
+		store,
+		repos,
+	)
+
+	manager := clients.NewManager(config.MembershipSyncInterval, config.LongPoll, repos)
+	messageBus, err := bus.New(config.MessageBus, config.Database, dbConn, manager)
+	if err != nil {
+		return err
+	}
+
+	processor := messages.NewShardedMessageProcessor(
+		config.MessageWorkers,
+		config.MessageQueueSize,
+		config.MessageBatch,
+		config.MessageRetry,
+		messageBus,
+		repos,
+	)
+	relay := messages.NewOutboxRelay(config.OutboxPollInterval, repos, processor)
+	scheduler := messages.NewScheduler(config.SchedulerPollInterval, dbConn, repos, relay)
+	sweeper := messages.NewExpirationSweeper(
+		config.ExpirationSweepInterval,
+		config.Attachments.UnlinkedRetention,
+		dbConn,
+		repos,
+		store,
+		messageBus,
+	)
 
 	opts := service.MessageServiceOpts{
 		DbConn:                 dbConn,
 		Repos:                  repos,
-		Processor:              processor,
+		Relay:                  relay,
 		Manager:                manager,
+		Dispatcher:             messageBus,
 		ClientMessageQueueSize: config.ClientMessageQueueSize,
+		IdempotencyWindow:      config.IdempotencyWindow,
+	}
+
+	attachmentOpts := service.AttachmentServiceOpts{
+		Repos:            repos,
+		Store:            store,
+		MaxSizeInBytes:   config.Attachments.MaxSizeInBytes,
+		AllowedMimeTypes: config.Attachments.AllowedMimeTypes,
+		Thumbnails:       generator,
+	}
+
+	pinOpts := service.PinServiceOpts{
+		DbConn:         dbConn,
+		Repos:          repos,
+		Dispatcher:     messageBus,
+		MaxPinsPerRoom: config.MaxPinsPerRoom,
 	}
 
 	services := service.Services{
-		Registration: service.NewRegistrationService(dbConn, repos),
-		Room:         service.NewRoomService(dbConn, repos),
-		User:         service.NewUserService(dbConn, repos),
-		Message:      service.NewMessageService(opts),
+		Attachment:       service.NewAttachmentService(attachmentOpts),
+		DeadLetter:       service.NewDeadLetterService(dbConn, repos, relay),
+		Pin:              service.NewPinService(pinOpts),
+		Registration:     service.NewRegistrationService(dbConn, repos, messageBus),
+		Room:             service.NewRoomService(dbConn, repos, messageBus),
+		ScheduledMessage: service.NewScheduledMessageService(repos),
+		User:             service.NewUserService(dbConn, repos),
+		Message:          service.NewMessageService(opts),
 	}
 
-	s, err := configureHttpServer(config.Server, dbConn, services, log)
+	s, err := configureHttpServer(config, dbConn, services, processor, log)
 	if err != nil {
 		return err
 	}
 
+	tcpGateway := gateway.NewTcpGateway(config.TcpGateway, services)
+	ircGateway := gateway.NewIrcGateway(config.IrcGateway, services)
+	grpcGateway := gateway.NewGrpcGateway(config.GrpcGateway, services)
+
 	group, errCtx := errgroup.WithContext(ctx)
 
-	// Voluntarily ignore errors: they can only be produced (as of now, true)
-	// when the process is not configured correctly. However when using the
-	// `StartWithSignalHandler`, the process is build directly from the
-	// runnable given in input so there's no risk of having it misconfigured.
-	waitProcessor, _ := process.StartWithSignalHandler(errCtx, processor)
-	waitManager, _ := process.StartWithSignalHandler(errCtx, manager)
-	waitServer, _ := process.StartWithSignalHandler(errCtx, s)
+	runnables := []process.Runnable{
+		processor, messageBus, manager, generator, relay, scheduler, sweeper,
+		tcpGateway, ircGateway, grpcGateway, s,
+	}
+	for _, runnable := range runnables {
+		group.Go(func() error {
+			return process.SafeRunSync(runnable.Start)
+		})
+	}
 
-	group.Go(waitProcessor)
-	group.Go(waitManager)
-	group.Go(waitServer)
+	group.Go(func() error {
+		sigCtx, stop := signal.NotifyContext(errCtx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		<-sigCtx.Done()
+
+		// The components may not have started yet, for example when
+		// another one failed right away: stopping them before they start
+		// makes them return as soon as they do.
+		// Components are stopped from the producers to the consumers so
+		// that the messages accepted before the shutdown are persisted and
+		// sent to the clients before their connection is closed.
+		services.Message.StopAccepting()
+		return shutdownInOrder(
+			config.Server.ShutdownTimeout,
+			log,
+			shutdownStep{name: "relay", runnable: relay},
+			shutdownStep{name: "scheduler", runnable: scheduler},
+			shutdownStep{name: "sweeper", runnable: sweeper},
+			shutdownStep{name: "processor", runnable: processor},
+			shutdownStep{name: "bus", runnable: messageBus},
+			shutdownStep{name: "manager", runnable: manager},
+			shutdownStep{name: "tcp gateway", runnable: tcpGateway},
+			shutdownStep{name: "irc gateway", runnable: ircGateway},
+			shutdownStep{name: "grpc gateway", runnable: grpcGateway},
+			shutdownStep{name: "generator", runnable: generator},
+			shutdownStep{name: "server", runnable: s},
+		)
+	})
 
 	return group.Wait()
 }
 
 func configureHttpServer(
-	config server.Config,
+	config Configuration,
 	dbConn db.Connection,
 	services service.Services,
+	monitor messages.QueueMonitor,
 	log *slog.Logger,
 ) (server.Server, error) {
-	s := server.NewWithLogger(config, log)
+	s := server.NewWithLogger(config.Server, log)
 
 	for _, route := range controller.HealthCheckEndpoints(dbConn) {
 		if err := s.AddRoute(route); err != nil {
@@ -102,5 +197,35 @@ func configureHttpServer(
 		}
 	}
 
+	for _, route := range controller.AttachmentEndpoints(services.Attachment, config.Attachments.MaxSizeInBytes) {
+		if err := s.AddRoute(route); err != nil {
+			return s, err
+		}
+	}
+
+	for _, route := range controller.PinEndpoints(services.Pin) {
+		if err := s.AddRoute(route); err != nil {
+			return s, err
+		}
+	}
+
+	for _, route := range controller.ScheduledMessageEndpoints(services.ScheduledMessage) {
+		if err := s.AddRoute(route); err != nil {
+			return s, err
+		}
+	}
+
+	for _, route := range controller.DeadLetterEndpoints(services.DeadLetter) {
+		if err := s.AddRoute(route); err != nil {
+			return s, err
+		}
+	}
+
+	for _, route := range controller.ProcessorEndpoints(monitor) {
+		if err := s.AddRoute(route); err != nil {
+			return s, err
+		}
+	}
+
 	return s, nil
 }
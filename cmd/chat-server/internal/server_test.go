@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"regexp"
 	"sync"
 	"testing"
@@ -363,9 +364,28 @@ func newTestServerConfig(httpPort uint16) Configuration {
 	baseConfig.Server.Port = httpPort
 	baseConfig.Server.ShutdownTimeout = 100 * time.Millisecond
 
+	baseConfig.Attachments.StorageDirectory = filepath.Join(
+		os.TempDir(), "chat-server-attachments",
+	)
+
 	return Configuration{
-		Server:   baseConfig.Server,
-		Database: dbTestConfig,
+		Server:                  baseConfig.Server,
+		MessageWorkers:          baseConfig.MessageWorkers,
+		MessageBatch:            baseConfig.MessageBatch,
+		MessageBus:              baseConfig.MessageBus,
+		MessageRetry:            baseConfig.MessageRetry,
+		MembershipSyncInterval:  baseConfig.MembershipSyncInterval,
+		LongPoll:                baseConfig.LongPoll,
+		MaxPinsPerRoom:          baseConfig.MaxPinsPerRoom,
+		SchedulerPollInterval:   baseConfig.SchedulerPollInterval,
+		OutboxPollInterval:      baseConfig.OutboxPollInterval,
+		ExpirationSweepInterval: baseConfig.ExpirationSweepInterval,
+		IdempotencyWindow:       baseConfig.IdempotencyWindow,
+		Attachments:             baseConfig.Attachments,
+		TcpGateway:              baseConfig.TcpGateway,
+		IrcGateway:              baseConfig.IrcGateway,
+		GrpcGateway:             baseConfig.GrpcGateway,
+		Database:                dbTestConfig,
 	}
 }
 
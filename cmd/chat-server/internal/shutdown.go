@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/process"
+)
+
+type shutdownStep struct {
+	name     string
+	runnable process.Runnable
+}
+
+// shutdownInOrder stops the steps one after the other, waiting for each
+// of them to be stopped before moving on to the next one. When the
+// timeout expires the remaining steps are stopped without waiting.
+func shutdownInOrder(
+	timeout time.Duration, log *slog.Logger, steps ...shutdownStep,
+) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var err error
+
+	for _, step := range steps {
+		done := process.SafeRunAsync(step.runnable.Stop)
+
+		select {
+		case stopErr := <-done:
+			if stopErr != nil && err == nil {
+				err = stopErr
+			}
+		case <-ctx.Done():
+			log.Warn("Component did not stop in time", slog.String("component", step.name))
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_ShutdownInOrder_ExpectStepsStoppedInOrder(t *testing.T) {
+	var stopped []string
+	var lock sync.Mutex
+
+	steps := []shutdownStep{
+		newTestShutdownStep("first", 0, nil, &stopped, &lock),
+		newTestShutdownStep("second", 20*time.Millisecond, nil, &stopped, &lock),
+		newTestShutdownStep("third", 0, nil, &stopped, &lock),
+	}
+
+	err := shutdownInOrder(time.Second, slog.Default(), steps...)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, []string{"first", "second", "third"}, stopped)
+}
+
+func TestUnit_ShutdownInOrder_WhenStepFails_ExpectErrorReturnedAndNextStepsStopped(t *testing.T) {
+	var stopped []string
+	var lock sync.Mutex
+
+	testErr := fmt.Errorf("some error")
+	steps := []shutdownStep{
+		newTestShutdownStep("first", 0, testErr, &stopped, &lock),
+		newTestShutdownStep("second", 0, nil, &stopped, &lock),
+	}
+
+	err := shutdownInOrder(time.Second, slog.Default(), steps...)
+
+	assert.Equal(t, testErr, err)
+	assert.Equal(t, []string{"first", "second"}, stopped)
+}
+
+func TestUnit_ShutdownInOrder_WhenTimeoutExpires_ExpectNextStepsStopped(t *testing.T) {
+	var stopped []string
+	var lock sync.Mutex
+
+	steps := []shutdownStep{
+		newTestShutdownStep("slow", time.Second, nil, &stopped, &lock),
+		newTestShutdownStep("fast", 0, nil, &stopped, &lock),
+	}
+
+	start := time.Now()
+	err := shutdownInOrder(50*time.Millisecond, slog.Default(), steps...)
+	elapsed := time.Since(start)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Less(t, elapsed, 500*time.Millisecond)
+
+	// Give some time for the last step to be stopped
+	time.Sleep(50 * time.Millisecond)
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, []string{"fast"}, stopped)
+}
+
+type mockRunnable struct {
+	name    string
+	delay   time.Duration
+	err     error
+	stopped *[]string
+	lock    *sync.Mutex
+}
+
+func newTestShutdownStep(
+	name string,
+	delay time.Duration,
+	err error,
+	stopped *[]string,
+	lock *sync.Mutex,
+) shutdownStep {
+	return shutdownStep{
+		name: name,
+		runnable: &mockRunnable{
+			name:    name,
+			delay:   delay,
+			err:     err,
+			stopped: stopped,
+			lock:    lock,
+		},
+	}
+}
+
+func (m *mockRunnable) Start() error {
+	return nil
+}
+
+func (m *mockRunnable) Stop() error {
+	time.Sleep(m.delay)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	*m.stopped = append(*m.stopped, m.name)
+
+	return m.err
+}
@@ -1,4 +1,13 @@
+DELETE FROM bus_event;
+DELETE FROM room_sequence;
+DELETE FROM outbox;
+DELETE FROM dead_letter;
+DELETE FROM message_status;
+DELETE FROM idempotency_key;
 
+DELETE FROM scheduled_message;
+DELETE FROM pinned_message;
+DELETE FROM attachment;
 DELETE FROM message;
 
 DELETE FROM room_ban;
@@ -0,0 +1,9 @@
+
+CREATE TABLE idempotency_key (
+  chat_user UUID NOT NULL,
+  key TEXT NOT NULL,
+  message UUID NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (chat_user, key),
+  FOREIGN KEY (chat_user) REFERENCES chat_user(id)
+);
@@ -0,0 +1,3 @@
+
+DROP TRIGGER trigger_message_status_updated_at ON message_status;
+DROP TABLE message_status;
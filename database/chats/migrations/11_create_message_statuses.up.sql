@@ -0,0 +1,18 @@
+
+CREATE TABLE message_status (
+  message UUID NOT NULL,
+  room UUID NOT NULL,
+  status TEXT NOT NULL,
+  reason TEXT,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (message),
+  CHECK (status IN ('queued', 'persisted', 'failed'))
+);
+
+CREATE INDEX message_status_room_index ON message_status (room);
+
+CREATE TRIGGER trigger_message_status_updated_at
+  BEFORE UPDATE OR INSERT ON message_status
+  FOR EACH ROW
+  EXECUTE FUNCTION update_updated_at();
@@ -0,0 +1,15 @@
+
+CREATE TABLE dead_letter (
+  id UUID NOT NULL,
+  chat_user UUID NOT NULL,
+  room UUID NOT NULL,
+  message TEXT NOT NULL,
+  attachments UUID[] NOT NULL DEFAULT '{}',
+  expires_at TIMESTAMP WITH TIME ZONE,
+  reason TEXT NOT NULL,
+  attempts INTEGER NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX dead_letter_room_index ON dead_letter (room);
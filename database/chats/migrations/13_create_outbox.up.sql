@@ -0,0 +1,18 @@
+
+CREATE TABLE outbox (
+  id UUID NOT NULL,
+  sequence BIGSERIAL NOT NULL,
+  chat_user UUID NOT NULL,
+  room UUID NOT NULL,
+  message TEXT NOT NULL,
+  attachments UUID[] NOT NULL DEFAULT '{}',
+  expires_at TIMESTAMP WITH TIME ZONE,
+  idempotency_key TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  delivered_at TIMESTAMP WITH TIME ZONE,
+  PRIMARY KEY (id),
+  UNIQUE (sequence)
+);
+
+CREATE INDEX outbox_pending_index ON outbox (sequence) WHERE delivered_at IS NULL;
+CREATE INDEX outbox_room_index ON outbox (room);
@@ -0,0 +1,8 @@
+
+DROP TRIGGER trigger_message_sequence ON message;
+DROP FUNCTION assign_message_sequence;
+
+ALTER TABLE message DROP CONSTRAINT message_room_sequence_key;
+ALTER TABLE message DROP COLUMN sequence;
+
+DROP TABLE room_sequence;
@@ -0,0 +1,46 @@
+
+CREATE TABLE room_sequence (
+  room UUID NOT NULL,
+  last_sequence BIGINT NOT NULL,
+  PRIMARY KEY (room),
+  FOREIGN KEY (room) REFERENCES room(id) ON DELETE CASCADE
+);
+
+ALTER TABLE message ADD COLUMN sequence BIGINT NOT NULL DEFAULT 0;
+
+-- number the existing messages in the order they were created
+UPDATE message AS m
+  SET sequence = numbered.sequence
+  FROM (
+    SELECT
+      id,
+      ROW_NUMBER() OVER (PARTITION BY room ORDER BY created_at, id) AS sequence
+    FROM message
+  ) AS numbered
+  WHERE m.id = numbered.id;
+
+INSERT INTO room_sequence (room, last_sequence)
+  SELECT room, MAX(sequence) FROM message GROUP BY room;
+
+ALTER TABLE message ADD CONSTRAINT message_room_sequence_key UNIQUE (room, sequence);
+
+-- The counter of the room is updated in the same transaction as the
+-- message: it is rolled back along with it so the sequence has no gap.
+-- When the room does not exist the foreign key of the message fails.
+CREATE OR REPLACE FUNCTION assign_message_sequence() RETURNS TRIGGER AS $$
+  BEGIN
+    IF NOT EXISTS (SELECT 1 FROM room WHERE id = NEW.room) THEN
+      RETURN NEW;
+    END IF;
+
+    INSERT INTO room_sequence (room, last_sequence) VALUES (NEW.room, 1)
+      ON CONFLICT (room) DO UPDATE SET last_sequence = room_sequence.last_sequence + 1
+      RETURNING last_sequence INTO NEW.sequence;
+    RETURN NEW;
+  END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER trigger_message_sequence
+  BEFORE INSERT ON message
+  FOR EACH ROW
+  EXECUTE FUNCTION assign_message_sequence();
@@ -0,0 +1,2 @@
+
+DROP TABLE read_marker;
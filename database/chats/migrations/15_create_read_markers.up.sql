@@ -0,0 +1,11 @@
+
+CREATE TABLE read_marker (
+  room UUID NOT NULL,
+  chat_user UUID NOT NULL,
+  message UUID NOT NULL,
+  sequence BIGINT NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (room, chat_user),
+  FOREIGN KEY (room) REFERENCES room(id) ON DELETE CASCADE,
+  FOREIGN KEY (chat_user) REFERENCES chat_user(id) ON DELETE CASCADE
+);
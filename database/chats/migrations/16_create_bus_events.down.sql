@@ -0,0 +1,2 @@
+
+DROP TABLE bus_event;
@@ -0,0 +1,9 @@
+
+CREATE TABLE bus_event (
+  id BIGSERIAL NOT NULL,
+  payload TEXT NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX bus_event_created_at_index ON bus_event (created_at);
@@ -0,0 +1,18 @@
+
+CREATE TABLE attachment (
+  id UUID NOT NULL,
+  chat_user UUID NOT NULL,
+  room UUID NOT NULL,
+  message UUID,
+  name TEXT NOT NULL,
+  mime_type TEXT NOT NULL,
+  size BIGINT NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (chat_user) REFERENCES chat_user(id),
+  FOREIGN KEY (room) REFERENCES room(id),
+  FOREIGN KEY (message) REFERENCES message(id)
+);
+
+CREATE INDEX attachment_room_index ON attachment (room);
+CREATE INDEX attachment_message_index ON attachment (message);
@@ -0,0 +1,4 @@
+
+ALTER TABLE attachment DROP COLUMN thumbnail;
+ALTER TABLE attachment DROP COLUMN height;
+ALTER TABLE attachment DROP COLUMN width;
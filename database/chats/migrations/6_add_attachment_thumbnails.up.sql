@@ -0,0 +1,4 @@
+
+ALTER TABLE attachment ADD COLUMN width INTEGER;
+ALTER TABLE attachment ADD COLUMN height INTEGER;
+ALTER TABLE attachment ADD COLUMN thumbnail TEXT;
@@ -0,0 +1,4 @@
+
+DROP TABLE pinned_message;
+
+ALTER TABLE room_user DROP COLUMN moderator;
@@ -0,0 +1,15 @@
+
+ALTER TABLE room_user ADD COLUMN moderator BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE pinned_message (
+  room UUID NOT NULL,
+  message UUID NOT NULL,
+  pinned_by UUID NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (message),
+  FOREIGN KEY (room) REFERENCES room(id),
+  FOREIGN KEY (message) REFERENCES message(id),
+  FOREIGN KEY (pinned_by) REFERENCES chat_user(id)
+);
+
+CREATE INDEX pinned_message_room_index ON pinned_message (room);
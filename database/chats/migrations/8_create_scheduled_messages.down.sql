@@ -0,0 +1,3 @@
+
+DROP TRIGGER trigger_scheduled_message_updated_at ON scheduled_message;
+DROP TABLE scheduled_message;
@@ -0,0 +1,21 @@
+
+CREATE TABLE scheduled_message (
+  id UUID NOT NULL,
+  chat_user UUID NOT NULL,
+  room UUID NOT NULL,
+  message TEXT NOT NULL,
+  send_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (chat_user) REFERENCES chat_user(id),
+  FOREIGN KEY (room) REFERENCES room(id)
+);
+
+CREATE INDEX scheduled_message_room_index ON scheduled_message (room);
+CREATE INDEX scheduled_message_send_at_index ON scheduled_message (send_at);
+
+CREATE TRIGGER trigger_scheduled_message_updated_at
+  BEFORE UPDATE OR INSERT ON scheduled_message
+  FOR EACH ROW
+  EXECUTE FUNCTION update_updated_at();
@@ -0,0 +1,5 @@
+
+DROP INDEX message_expires_at_index;
+
+ALTER TABLE message DROP COLUMN expires_at;
+ALTER TABLE room DROP COLUMN message_ttl;
@@ -0,0 +1,5 @@
+
+ALTER TABLE room ADD COLUMN message_ttl INTEGER;
+ALTER TABLE message ADD COLUMN expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX message_expires_at_index ON message (expires_at);
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	goerrors "errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/rest"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+// multipartOverhead accounts for the fields and the headers of the parts
+// sent along with the attachment when uploading it.
+const multipartOverhead = 64 * 1024
+
+func AttachmentEndpoints(
+	attachmentService service.AttachmentService, maxSizeInBytes int64,
+) rest.Routes {
+	var out rest.Routes
+
+	upload := func(c *echo.Context, s service.AttachmentService) error {
+		return uploadAttachment(c, s, maxSizeInBytes)
+	}
+	postHandler := createComponentAwareHttpHandler(upload, attachmentService)
+	post := newRoute(http.MethodPost, "/rooms/:id/attachments", postHandler)
+	out = append(out, post)
+
+	getHandler := createComponentAwareHttpHandler(downloadAttachment, attachmentService)
+	get := rest.NewRawRoute(http.MethodGet, "/attachments/:id", getHandler)
+	out = append(out, get)
+
+	thumbnailHandler := createComponentAwareHttpHandler(downloadThumbnail, attachmentService)
+	thumbnail := rest.NewRawRoute(http.MethodGet, "/attachments/:id/thumbnail", thumbnailHandler)
+	out = append(out, thumbnail)
+
+	return out
+}
+
+func uploadAttachment(
+	c *echo.Context, s service.AttachmentService, maxSizeInBytes int64,
+) error {
+	maybeId := c.Param("id")
+	id, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	// Larger bodies are rejected before being buffered. Other parsing
+	// errors are reported when reading the fields.
+	req := c.Request()
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, maxSizeInBytes+multipartOverhead)
+	var tooLarge *http.MaxBytesError
+	if _, err := c.MultipartForm(); goerrors.As(err, &tooLarge) {
+		return c.JSON(http.StatusRequestEntityTooLarge, "Attachment is too large")
+	}
+
+	maybeUser := c.FormValue("user")
+	user, err := uuid.Parse(maybeUser)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid user syntax")
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid attachment syntax")
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid attachment syntax")
+	}
+	defer file.Close()
+
+	attachmentDtoRequest := communication.AttachmentDtoRequest{
+		User: user,
+		Room: id,
+		Name: header.Filename,
+	}
+
+	out, err := s.Upload(c.Request().Context(), attachmentDtoRequest, file)
+	if err != nil {
+		if errors.IsErrorWithCode(err, service.ErrInvalidName) {
+			return c.JSON(http.StatusBadRequest, "Invalid attachment name")
+		} else if errors.IsErrorWithCode(err, service.ErrUserNotInRoom) {
+			return c.JSON(http.StatusBadRequest, "User is not registered in the room")
+		} else if errors.IsErrorWithCode(err, service.ErrUnsupportedMimeType) {
+			return c.JSON(http.StatusUnsupportedMediaType, "Unsupported attachment type")
+		} else if errors.IsErrorWithCode(err, service.ErrAttachmentTooLarge) {
+			return c.JSON(http.StatusRequestEntityTooLarge, "Attachment is too large")
+		}
+
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusCreated, out)
+}
+
+func downloadAttachment(c *echo.Context, s service.AttachmentService) error {
+	maybeId := c.Param("id")
+	id, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	maybeUser := c.QueryParam("user")
+	user, err := uuid.Parse(maybeUser)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid user syntax")
+	}
+
+	attachment, data, err := s.Download(c.Request().Context(), user, id)
+	if err != nil {
+		if errors.IsErrorWithCode(err, db.NoMatchingRows) {
+			return c.JSON(http.StatusNotFound, "No such attachment")
+		} else if errors.IsErrorWithCode(err, service.ErrUserNotInRoom) {
+			return c.JSON(http.StatusForbidden, "User is not registered in the room")
+		}
+
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+	defer data.Close()
+
+	disposition := mime.FormatMediaType(
+		"attachment", map[string]string{"filename": attachment.Name},
+	)
+	c.Response().Header().Set(echo.HeaderContentDisposition, disposition)
+	c.Response().Header().Set(echo.HeaderContentLength, strconv.FormatInt(attachment.Size, 10))
+
+	return c.Stream(http.StatusOK, attachment.MimeType, data)
+}
+
+func downloadThumbnail(c *echo.Context, s service.AttachmentService) error {
+	maybeId := c.Param("id")
+	id, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	maybeUser := c.QueryParam("user")
+	user, err := uuid.Parse(maybeUser)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid user syntax")
+	}
+
+	data, err := s.DownloadThumbnail(c.Request().Context(), user, id)
+	if err != nil {
+		if errors.IsErrorWithCode(err, db.NoMatchingRows) {
+			return c.JSON(http.StatusNotFound, "No such attachment")
+		} else if errors.IsErrorWithCode(err, service.ErrNoThumbnail) {
+			return c.JSON(http.StatusNotFound, "No thumbnail for attachment")
+		} else if errors.IsErrorWithCode(err, service.ErrUserNotInRoom) {
+			return c.JSON(http.StatusForbidden, "User is not registered in the room")
+		}
+
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+	defer data.Close()
+
+	return c.Stream(http.StatusOK, "image/png", data)
+}
@@ -0,0 +1,294 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/Knoblauchpilze/chat-server/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+var testPngHeader = []byte("\x89PNG\x0D\x0A\x1A\x0A")
+
+const testMaxAttachmentSize = 1024
+
+func TestUnit_AttachmentController_UploadAttachment_WhenBodyIsTooLarge_ExpectRequestEntityTooLarge(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), testMaxAttachmentSize+multipartOverhead)
+	req := generateTestMultipartRequest(t, uuid.NewString(), "my-file.png", data)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err := uploadAttachment(ctx, nil, testMaxAttachmentSize)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rw.Code)
+}
+
+func TestIT_AttachmentController_UploadAttachment_WhenIdHasWrongSyntax_ExpectBadRequest(t *testing.T) {
+	service, dbConn := newTestAttachmentService(t)
+	defer dbConn.Close(context.Background())
+	req := generateTestMultipartRequest(t, uuid.NewString(), "my-file.png", testPngHeader)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: "not-a-uuid"}})
+
+	err := uploadAttachment(ctx, service, testMaxAttachmentSize)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	expectedBody := []byte("\"Invalid id syntax\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_AttachmentController_UploadAttachment_WhenFileIsMissing_ExpectBadRequest(t *testing.T) {
+	service, dbConn := newTestAttachmentService(t)
+	defer dbConn.Close(context.Background())
+	req := generateTestMultipartRequest(t, uuid.NewString(), "", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err := uploadAttachment(ctx, service, testMaxAttachmentSize)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	expectedBody := []byte("\"Invalid attachment syntax\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_AttachmentController_UploadAttachment_WhenTypeIsNotAllowed_ExpectUnsupportedMediaType(t *testing.T) {
+	service, dbConn := newTestAttachmentService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	data := []byte("<html><body>hello</body></html>")
+	req := generateTestMultipartRequest(t, user.Id.String(), "my-file.html", data)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: room.Id.String()}})
+
+	err := uploadAttachment(ctx, service, testMaxAttachmentSize)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rw.Code)
+}
+
+func TestIT_AttachmentController_UploadAttachment(t *testing.T) {
+	service, dbConn := newTestAttachmentService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	req := generateTestMultipartRequest(t, user.Id.String(), "my-file.png", testPngHeader)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: room.Id.String()}})
+
+	err := uploadAttachment(ctx, service, testMaxAttachmentSize)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	var responseDto communication.AttachmentDtoResponse
+	err = json.Unmarshal(rw.Body.Bytes(), &responseDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, user.Id, responseDto.User)
+	assert.Equal(t, room.Id, responseDto.Room)
+	assert.Equal(t, "my-file.png", responseDto.Name)
+	assert.Equal(t, "image/png", responseDto.MimeType)
+}
+
+func TestIT_AttachmentController_DownloadAttachment_WhenUserHasWrongSyntax_ExpectBadRequest(t *testing.T) {
+	service, dbConn := newTestAttachmentService(t)
+	defer dbConn.Close(context.Background())
+	req := generateTestRequestWithQueryParam(http.MethodGet, "user", "not-a-uuid")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err := downloadAttachment(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	expectedBody := []byte("\"Invalid user syntax\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_AttachmentController_DownloadAttachment_WhenAttachmentDoesNotExist_ExpectNotFound(t *testing.T) {
+	service, dbConn := newTestAttachmentService(t)
+	defer dbConn.Close(context.Background())
+	req := generateTestRequestWithQueryParam(http.MethodGet, "user", uuid.NewString())
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err := downloadAttachment(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+}
+
+func TestIT_AttachmentController_DownloadAttachment_WhenUserNotInRoom_ExpectForbidden(t *testing.T) {
+	service, dbConn := newTestAttachmentService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	other := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	attachment := uploadTestAttachment(t, service, user.Id, room.Id)
+
+	req := generateTestRequestWithQueryParam(http.MethodGet, "user", other.Id.String())
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: attachment.Id.String()}})
+
+	err := downloadAttachment(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+}
+
+func TestIT_AttachmentController_DownloadAttachment(t *testing.T) {
+	service, dbConn := newTestAttachmentService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	attachment := uploadTestAttachment(t, service, user.Id, room.Id)
+
+	req := generateTestRequestWithQueryParam(http.MethodGet, "user", user.Id.String())
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: attachment.Id.String()}})
+
+	err := downloadAttachment(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "image/png", rw.Header().Get("Content-Type"))
+	assert.Equal(
+		t,
+		"attachment; filename="+attachment.Name,
+		rw.Header().Get("Content-Disposition"),
+	)
+	assert.Equal(t, testPngHeader, rw.Body.Bytes())
+}
+
+func TestIT_AttachmentController_DownloadThumbnail_WhenNotGenerated_ExpectNotFound(t *testing.T) {
+	service, dbConn := newTestAttachmentService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	attachment := uploadTestAttachment(t, service, user.Id, room.Id)
+
+	req := generateTestRequestWithQueryParam(http.MethodGet, "user", user.Id.String())
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: attachment.Id.String()}})
+
+	err := downloadThumbnail(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+	expectedBody := []byte("\"No thumbnail for attachment\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_AttachmentController_DownloadThumbnail_WhenUserHasWrongSyntax_ExpectBadRequest(t *testing.T) {
+	service, dbConn := newTestAttachmentService(t)
+	defer dbConn.Close(context.Background())
+	req := generateTestRequestWithQueryParam(http.MethodGet, "user", "not-a-uuid")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err := downloadThumbnail(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+}
+
+func newTestAttachmentService(t *testing.T) (service.AttachmentService, db.Connection) {
+	dbConn := newTestDbConnection(t)
+
+	store, err := storage.NewFilesystemStore(t.TempDir())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	opts := service.AttachmentServiceOpts{
+		Repos:            repositories.New(dbConn),
+		Store:            store,
+		MaxSizeInBytes:   testMaxAttachmentSize,
+		AllowedMimeTypes: []string{"image/png"},
+	}
+
+	return service.NewAttachmentService(opts), dbConn
+}
+
+func generateTestMultipartRequest(
+	t *testing.T, user string, fileName string, data []byte,
+) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	err := writer.WriteField("user", user)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	if fileName != "" {
+		part, err := writer.CreateFormFile("file", fileName)
+		assert.Nil(t, err, "Actual err: %v", err)
+		_, err = part.Write(data)
+		assert.Nil(t, err, "Actual err: %v", err)
+	}
+
+	err = writer.Close()
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func uploadTestAttachment(
+	t *testing.T, s service.AttachmentService, user uuid.UUID, room uuid.UUID,
+) communication.AttachmentDtoResponse {
+	attachmentDtoRequest := communication.AttachmentDtoRequest{
+		User: user,
+		Room: room,
+		Name: "my-file-" + uuid.NewString() + ".png",
+	}
+
+	out, err := s.Upload(
+		context.Background(), attachmentDtoRequest, bytes.NewReader(testPngHeader),
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return out
+}
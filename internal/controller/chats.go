@@ -2,15 +2,24 @@ package controller
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/rest"
+	"github.com/Knoblauchpilze/chat-server/internal/frames"
 	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/clients"
 	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/encoders"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v5"
 )
 
+const idempotencyKeyHeader = "Idempotency-Key"
+
 func MessageEndpoints(service service.MessageService) rest.Routes {
 	var out rest.Routes
 
@@ -19,9 +28,29 @@ func MessageEndpoints(service service.MessageService) rest.Routes {
 	out = append(out, get)
 
 	postHandler := createComponentAwareHttpHandler(postMessage, service)
-	post := rest.NewRoute(http.MethodPost, "/rooms/:id/messages", postHandler)
+	post := newRoute(http.MethodPost, "/rooms/:id/messages", postHandler)
 	out = append(out, post)
 
+	statusHandler := createComponentAwareHttpHandler(getMessageStatus, service)
+	status := newRoute(http.MethodGet, "/messages/:id/status", statusHandler)
+	out = append(out, status)
+
+	wsHandler := createComponentAwareHttpHandler(serveWebSocket, service)
+	ws := rest.NewRawRoute(http.MethodGet, "/users/:id/ws", wsHandler)
+	out = append(out, ws)
+
+	pollHandler := createComponentAwareHttpHandler(pollMessages, service)
+	poll := newRoute(http.MethodGet, "/users/:id/poll", pollHandler)
+	out = append(out, poll)
+
+	typingHandler := createComponentAwareHttpHandler(notifyTyping, service)
+	typing := newRoute(http.MethodPost, "/rooms/:id/typing", typingHandler)
+	out = append(out, typing)
+
+	readHandler := createComponentAwareHttpHandler(markAsRead, service)
+	read := newRoute(http.MethodPost, "/rooms/:id/read", readHandler)
+	out = append(out, read)
+
 	return out
 }
 
@@ -39,19 +68,124 @@ func postMessage(c *echo.Context, s service.MessageService) error {
 	}
 
 	messageDtoRequest.Room = id
+	if key := c.Request().Header.Get(idempotencyKeyHeader); key != "" {
+		messageDtoRequest.IdempotencyKey = key
+	}
 
-	err = s.PostMessage(c.Request().Context(), messageDtoRequest)
+	if messageDtoRequest.SendAt != nil {
+		return scheduleMessage(c, s, messageDtoRequest)
+	}
+
+	out, err := s.PostMessage(c.Request().Context(), messageDtoRequest)
 	if err != nil {
-		if errors.IsErrorWithCode(err, service.ErrEmptyMessage) {
-			return c.JSON(http.StatusBadRequest, "Invalid empty message")
-		} else if errors.IsErrorWithCode(err, service.ErrUserNotInRoom) {
-			return c.JSON(http.StatusBadRequest, "User is not registered in the room")
+		return c.JSON(frames.PostMessageError(err))
+	}
+
+	return c.JSON(http.StatusAccepted, out)
+}
+
+func getMessageStatus(c *echo.Context, s service.MessageService) error {
+	maybeId := c.Param("id")
+	id, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	out, err := s.GetStatus(c.Request().Context(), id)
+	if err != nil {
+		if errors.IsErrorWithCode(err, db.NoMatchingRows) {
+			return c.JSON(http.StatusNotFound, "No such message")
+		}
+
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+func scheduleMessage(
+	c *echo.Context,
+	s service.MessageService,
+	messageDtoRequest communication.MessageDtoRequest,
+) error {
+	out, err := s.ScheduleMessage(c.Request().Context(), messageDtoRequest)
+	if err != nil {
+		return c.JSON(frames.ScheduleMessageError(err))
+	}
+
+	return c.JSON(http.StatusCreated, out)
+}
+
+func pollMessages(c *echo.Context, s service.MessageService) error {
+	maybeId := c.Param("id")
+	id, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	since, err := clients.ParseCursor(c.QueryParam("since"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid cursor")
+	}
+
+	out, err := s.Poll(c.Request().Context(), id, since)
+	if err != nil {
+		if errors.IsErrorWithCode(err, clients.ErrClientAlreadyRegistered) {
+			return c.JSON(http.StatusConflict, "User is already connected")
+		} else if errors.IsErrorWithCode(err, service.ErrShuttingDown) {
+			return c.JSON(http.StatusServiceUnavailable, "Server is shutting down")
 		}
 
 		return c.JSON(http.StatusInternalServerError, err)
 	}
 
-	return c.NoContent(http.StatusAccepted)
+	return c.JSON(http.StatusOK, out)
+}
+
+func notifyTyping(c *echo.Context, s service.MessageService) error {
+	maybeId := c.Param("id")
+	id, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	var typingDtoRequest communication.TypingDtoRequest
+	err = c.Bind(&typingDtoRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid typing syntax")
+	}
+
+	typingDtoRequest.Room = id
+
+	err = s.Typing(c.Request().Context(), typingDtoRequest)
+	if err != nil {
+		return c.JSON(frames.TypingError(err))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func markAsRead(c *echo.Context, s service.MessageService) error {
+	maybeId := c.Param("id")
+	id, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	var markerDtoRequest communication.ReadMarkerDtoRequest
+	err = c.Bind(&markerDtoRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid read marker syntax")
+	}
+
+	markerDtoRequest.Room = id
+
+	err = s.MarkAsRead(c.Request().Context(), markerDtoRequest)
+	if err != nil {
+		return c.JSON(frames.MarkAsReadError(err))
+	}
+
+	return c.NoContent(http.StatusNoContent)
 }
 
 func subscribeToMessages(c *echo.Context, s service.MessageService) error {
@@ -61,12 +195,81 @@ func subscribeToMessages(c *echo.Context, s service.MessageService) error {
 		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
 	}
 
+	filter, err := parseFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid room id")
+	}
+
+	encoder, err := encoders.FromName(c.QueryParam(encodingQueryParam))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid encoding")
+	}
+
+	snapshot, err := parseSnapshot(c.QueryParam("snapshot"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid snapshot")
+	}
+
+	var response http.ResponseWriter = c.Response()
+	compression := clients.CompressionFromAcceptEncoding(
+		c.Request().Header.Get(echo.HeaderAcceptEncoding),
+	)
+	if compression != clients.NoCompression {
+		compressed := clients.NewCompressedResponseWriter(response, compression)
+		defer compressed.Close()
+		response = compressed
+	}
+
 	// TODO: We could pass on the logger taken from the context
-	err = s.ServeClient(c.Request().Context(), id, c.Response())
+	err = s.ServeClient(c.Request().Context(), id, response, filter, encoder, snapshot)
 	if err != nil {
+		if errors.IsErrorWithCode(err, service.ErrShuttingDown) {
+			return c.JSON(http.StatusServiceUnavailable, "Server is shutting down")
+		}
+		if errors.IsErrorWithCode(err, service.ErrUserNotInRoom) {
+			return c.JSON(http.StatusBadRequest, "User is not registered in the room")
+		}
+		if errors.IsErrorWithCode(err, service.ErrInvalidEventType) {
+			return c.JSON(http.StatusBadRequest, "Invalid event type")
+		}
+
 		return c.JSON(http.StatusInternalServerError, err)
 	}
 
 	// https://echo.labstack.com/docs/cookbook/sse#server
 	return nil
 }
+
+// parseFilter reads the comma separated lists of rooms and event types
+// to which the subscription is restricted.
+func parseFilter(c *echo.Context) (clients.Filter, error) {
+	var filter clients.Filter
+
+	for _, maybeRoom := range splitQueryParam(c.QueryParam("rooms")) {
+		room, err := uuid.Parse(maybeRoom)
+		if err != nil {
+			return clients.Filter{}, err
+		}
+		filter.Rooms = append(filter.Rooms, room)
+	}
+
+	for _, t := range splitQueryParam(c.QueryParam("types")) {
+		filter.Types = append(filter.Types, events.Type(t))
+	}
+
+	return filter, nil
+}
+
+func parseSnapshot(maybeSnapshot string) (bool, error) {
+	if maybeSnapshot == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(maybeSnapshot)
+}
+
+func splitQueryParam(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
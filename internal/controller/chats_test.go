@@ -17,6 +17,7 @@ import (
 	"github.com/Knoblauchpilze/chat-server/internal/service"
 	"github.com/Knoblauchpilze/chat-server/pkg/clients"
 	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
 	"github.com/Knoblauchpilze/chat-server/pkg/messages"
 	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
 	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
@@ -108,6 +109,43 @@ func TestIT_ChatsController_PostMessageForRoom_WhenUserIsNotInRoom_ExpectBadRequ
 	)
 }
 
+func TestIT_ChatsController_PostMessageForRoom_WhenShuttingDown_ExpectServiceUnavailable(t *testing.T) {
+	service, dbConn, _ := newTestMessageService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	requestDto := communication.MessageDtoRequest{
+		User:    user.Id,
+		Room:    room.Id,
+		Message: "hello there",
+	}
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(requestDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: room.Id.String()}})
+
+	service.StopAccepting()
+	err = postMessage(ctx, service)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+	expectedBody := []byte("\"Server is shutting down\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
 func TestIT_ChatsController_PostMessageForRoom_ReturnsAccepted(t *testing.T) {
 	service, dbConn, _ := newTestMessageService(t)
 	defer dbConn.Close(context.Background())
@@ -136,7 +174,42 @@ func TestIT_ChatsController_PostMessageForRoom_ReturnsAccepted(t *testing.T) {
 	assert.Equal(t, http.StatusAccepted, rw.Code)
 }
 
-func TestIT_ChatsController_PostMessageForRoom_SendsMessageToProcessor(t *testing.T) {
+func TestIT_ChatsController_PostMessageForRoom_ReturnsMessageStatus(t *testing.T) {
+	service, dbConn, mock := newTestMessageService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	requestDto := communication.MessageDtoRequest{
+		User:    user.Id,
+		Message: "hello",
+	}
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(requestDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: room.Id.String()}})
+
+	err = postMessage(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	var responseDto communication.MessageStatusDtoResponse
+	err = json.Unmarshal(rw.Body.Bytes(), &responseDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusAccepted, rw.Code)
+	assert.Equal(t, 1, mock.notified)
+	assertOutboxEntryExists(t, dbConn, responseDto.Id)
+	assert.Equal(t, room.Id, responseDto.Room)
+	assert.Equal(t, "queued", responseDto.Status)
+}
+
+func TestIT_ChatsController_PostMessageForRoom_WritesMessageToOutbox(t *testing.T) {
 	service, dbConn, mock := newTestMessageService(t)
 	defer dbConn.Close(context.Background())
 	user := insertTestUser(t, dbConn)
@@ -160,14 +233,11 @@ func TestIT_ChatsController_PostMessageForRoom_SendsMessageToProcessor(t *testin
 
 	err = postMessage(ctx, service)
 
-	// Wait for message to be processed
-	time.Sleep(50 * time.Millisecond)
-
 	assert.Nil(t, err, "Actual err: %v", err)
 	assert.Equal(t, http.StatusAccepted, rw.Code)
 
-	assert.Len(t, mock.enqueued, 1)
-	actual := mock.enqueued[0]
+	assert.Equal(t, 1, mock.notified)
+	actual := getTestOutboxEntryFromResponse(t, dbConn, rw)
 	assert.Equal(t, requestDto.User, actual.ChatUser)
 	assert.Equal(t, requestDto.Room, actual.Room)
 	assert.Equal(t, requestDto.Message, actual.Message)
@@ -199,17 +269,330 @@ func TestIT_ChatsController_PostMessageForRoom_WhenRoomInRequestDoesNotMatchRout
 
 	err = postMessage(ctx, service)
 
-	// Wait for message to be processed
-	time.Sleep(50 * time.Millisecond)
-
 	assert.Nil(t, err, "Actual err: %v", err)
 	assert.Equal(t, http.StatusAccepted, rw.Code)
 
-	assert.Len(t, mock.enqueued, 1)
-	actual := mock.enqueued[0]
+	assert.Equal(t, 1, mock.notified)
+	actual := getTestOutboxEntryFromResponse(t, dbConn, rw)
 	assert.Equal(t, room.Id, actual.Room)
 }
 
+func TestIT_ChatsController_PostMessageForRoom_WhenSendAtIsSet_ExpectScheduled(t *testing.T) {
+	service, dbConn, mock := newTestMessageService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	sendAt := time.Now().Add(time.Hour)
+	requestDto := communication.MessageDtoRequest{
+		User:    user.Id,
+		Room:    room.Id,
+		Message: fmt.Sprintf("%s will say hello to %s", user.Name, room.Id),
+		SendAt:  &sendAt,
+	}
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(requestDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: room.Id.String()}})
+
+	err = postMessage(ctx, service)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	assert.Zero(t, mock.notified)
+
+	var responseDto communication.ScheduledMessageDtoResponse
+	err = json.Unmarshal(rw.Body.Bytes(), &responseDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, user.Id, responseDto.User)
+	assert.Equal(t, room.Id, responseDto.Room)
+	assert.Equal(t, requestDto.Message, responseDto.Message)
+}
+
+func TestIT_ChatsController_PostMessageForRoom_WhenSendAtIsInThePast_ExpectBadRequest(t *testing.T) {
+	service, dbConn, _ := newTestMessageService(t)
+	defer dbConn.Close(context.Background())
+
+	sendAt := time.Now().Add(-time.Hour)
+	requestDto := communication.MessageDtoRequest{
+		User:    uuid.New(),
+		Message: "hello",
+		SendAt:  &sendAt,
+	}
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(requestDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err = postMessage(ctx, service)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	expectedBody := []byte("\"Send time is not in the future\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_ChatsController_PostMessageForRoom_WhenTtlIsInvalid_ExpectBadRequest(t *testing.T) {
+	service, dbConn, _ := newTestMessageService(t)
+	defer dbConn.Close(context.Background())
+
+	ttl := -10
+	requestDto := communication.MessageDtoRequest{
+		User:    uuid.New(),
+		Message: "hello",
+		Ttl:     &ttl,
+	}
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(requestDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err = postMessage(ctx, service)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	expectedBody := []byte("\"Invalid ttl\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_ChatsController_PostMessageForRoom_WithIdempotencyKeyHeader_ExpectKeyForwarded(t *testing.T) {
+	service, dbConn, mock := newTestMessageService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	requestDto := communication.MessageDtoRequest{
+		User:    user.Id,
+		Message: "hello",
+	}
+
+	key := uuid.NewString()
+	var ids []uuid.UUID
+	for range 2 {
+		var body bytes.Buffer
+		err := json.NewEncoder(&body).Encode(requestDto)
+		assert.Nil(t, err, "Actual err: %v", err)
+
+		req := httptest.NewRequest(http.MethodPost, "/", &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", key)
+		ctx, rw := generateTestEchoContextFromRequest(req)
+		ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: room.Id.String()}})
+
+		err = postMessage(ctx, service)
+
+		assert.Nil(t, err, "Actual err: %v", err)
+		assert.Equal(t, http.StatusAccepted, rw.Code)
+		ids = append(ids, getTestOutboxEntryFromResponse(t, dbConn, rw).Id)
+	}
+
+	assert.Equal(t, 1, mock.notified)
+	assert.Equal(t, ids[0], ids[1])
+	actual := getTestOutboxEntry(t, dbConn, ids[0])
+	assert.Equal(t, key, actual.IdempotencyKey)
+}
+
+func TestIT_ChatsController_GetMessageStatus_WhenIdHasWrongSyntax_ExpectBadRequest(t *testing.T) {
+	service, dbConn, _ := newTestMessageService(t)
+	defer dbConn.Close(context.Background())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: "not-a-uuid"}})
+
+	err := getMessageStatus(ctx, service)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	expectedBody := []byte("\"Invalid id syntax\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_ChatsController_GetMessageStatus_WhenMessageDoesNotExist_ExpectNotFound(t *testing.T) {
+	service, dbConn, _ := newTestMessageService(t)
+	defer dbConn.Close(context.Background())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err := getMessageStatus(ctx, service)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+	expectedBody := []byte("\"No such message\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_ChatsController_GetMessageStatus(t *testing.T) {
+	service, dbConn, _ := newTestMessageService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	requestDto := communication.MessageDtoRequest{
+		User:    user.Id,
+		Room:    room.Id,
+		Message: "hello",
+	}
+	posted, err := service.PostMessage(context.Background(), requestDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: posted.Id.String()}})
+
+	err = getMessageStatus(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	var responseDto communication.MessageStatusDtoResponse
+	err = json.Unmarshal(rw.Body.Bytes(), &responseDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, posted, responseDto)
+}
+
+func TestUnit_ParseFilter(t *testing.T) {
+	room1 := uuid.MustParse("111838db-a871-47be-9149-c974fd356316")
+	room2 := uuid.MustParse("8f102c70-8eba-4094-bd4d-7f70d71b21f2")
+
+	type testCase struct {
+		query    string
+		expected clients.Filter
+	}
+
+	testCases := map[string]testCase{
+		"noFilter": {
+			query:    "/",
+			expected: clients.Filter{},
+		},
+		"rooms": {
+			query: fmt.Sprintf("/?rooms=%s,%s", room1, room2),
+			expected: clients.Filter{
+				Rooms: []uuid.UUID{room1, room2},
+			},
+		},
+		"types": {
+			query: "/?types=message,pin",
+			expected: clients.Filter{
+				Types: []events.Type{events.Message, events.Pin},
+			},
+		},
+		"roomsAndTypes": {
+			query: fmt.Sprintf("/?rooms=%s&types=typing", room1),
+			expected: clients.Filter{
+				Rooms: []uuid.UUID{room1},
+				Types: []events.Type{events.Typing},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, testCase.query, nil)
+			ctx, _ := generateTestEchoContextFromRequest(req)
+
+			actual, err := parseFilter(ctx)
+
+			assert.Nil(t, err, "Actual err: %v", err)
+			assert.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestUnit_ParseFilter_WhenRoomHasWrongSyntax_ExpectError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?rooms=not-a-uuid", nil)
+	ctx, _ := generateTestEchoContextFromRequest(req)
+
+	_, err := parseFilter(ctx)
+
+	assert.NotNil(t, err)
+}
+
+func TestUnit_ParseSnapshot(t *testing.T) {
+	type testCase struct {
+		value    string
+		expected bool
+	}
+
+	testCases := map[string]testCase{
+		"empty": {
+			value:    "",
+			expected: false,
+		},
+		"true": {
+			value:    "true",
+			expected: true,
+		},
+		"false": {
+			value:    "false",
+			expected: false,
+		},
+		"one": {
+			value:    "1",
+			expected: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := parseSnapshot(testCase.value)
+
+			assert.Nil(t, err, "Actual err: %v", err)
+			assert.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestUnit_ParseSnapshot_WhenValueHasWrongSyntax_ExpectError(t *testing.T) {
+	_, err := parseSnapshot("yes")
+
+	assert.NotNil(t, err)
+}
+
 func TestIT_ChatsController_SubscribeToMessages_WhenIdHasWrongSyntax_ExpectBadRequest(t *testing.T) {
 	service, dbConn, _ := newTestMessageService(t)
 	defer dbConn.Close(context.Background())
@@ -230,16 +613,60 @@ func TestIT_ChatsController_SubscribeToMessages_WhenIdHasWrongSyntax_ExpectBadRe
 	)
 }
 
+func TestIT_ChatsController_SubscribeToMessages_WhenEncodingIsUnknown_ExpectBadRequest(t *testing.T) {
+	service, dbConn, _ := newTestMessageService(t)
+	defer dbConn.Close(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/?encoding=xml", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err := subscribeToMessages(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	expectedBody := []byte("\"Invalid encoding\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_ChatsController_SubscribeToMessages_WhenShuttingDown_ExpectServiceUnavailable(t *testing.T) {
+	service, dbConn, _ := newTestMessageService(t)
+	defer dbConn.Close(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	service.StopAccepting()
+	err := subscribeToMessages(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+	expectedBody := []byte("\"Server is shutting down\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
 func TestIT_ChatsController_SubscribeToMessage_ReceivesPostedMessage(t *testing.T) {
 	dbConn := newTestDbConnection(t)
 	defer dbConn.Close(context.Background())
 	repos := repositories.New(dbConn)
-	manager := clients.NewManager(repos)
-	processor := messages.NewMessageProcessor(1, manager, repos)
+	manager := clients.NewManager(time.Minute, clients.PollPolicy{}, repos)
+	processor := messages.NewMessageProcessor(1, messages.RetryPolicy{MaxAttempts: 1}, manager, repos)
+	relay := messages.NewOutboxRelay(10*time.Millisecond, repos, processor)
 	opts := service.MessageServiceOpts{
 		DbConn:                 dbConn,
 		Repos:                  repos,
-		Processor:              processor,
+		Relay:                  relay,
 		Manager:                manager,
 		ClientMessageQueueSize: 1,
 	}
@@ -258,6 +685,7 @@ func TestIT_ChatsController_SubscribeToMessage_ReceivesPostedMessage(t *testing.
 	}
 
 	wg := asyncStartProcessorAndAssertNoError(t, processor)
+	wgRelay := asyncStartRelayAndAssertNoError(t, relay)
 
 	// Post a message with a delay to let the user the time to subscribe
 	go func() {
@@ -288,6 +716,9 @@ func TestIT_ChatsController_SubscribeToMessage_ReceivesPostedMessage(t *testing.
 	err := subscribeToMessages(ctx, service)
 	assert.Nil(t, err, "Actual err: %v", err)
 
+	err = relay.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wgRelay.Wait()
 	err = processor.Stop()
 	assert.Nil(t, err, "Actual err: %v", err)
 	wg.Wait()
@@ -320,24 +751,156 @@ data: {"id":"%s","user":"%s","room":"%s","message":"%s","created_at":"%s"}
 	)
 }
 
+func TestIT_ChatsController_PollMessages_WhenCursorHasWrongSyntax_ExpectBadRequest(t *testing.T) {
+	service, dbConn, _ := newTestMessageService(t)
+	defer dbConn.Close(context.Background())
+	req := generateTestRequestWithQueryParam(http.MethodGet, "since", "not-a-cursor")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err := pollMessages(ctx, service)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	expectedBody := []byte("\"Invalid cursor\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_ChatsController_PollMessages_ReturnsPostedMessage(t *testing.T) {
+	dbConn := newTestDbConnection(t)
+	defer dbConn.Close(context.Background())
+	repos := repositories.New(dbConn)
+	policy := clients.PollPolicy{BufferSize: 10, Timeout: time.Second, Retention: time.Minute}
+	manager := clients.NewManager(time.Minute, policy, repos)
+	opts := service.MessageServiceOpts{
+		DbConn:                 dbConn,
+		Repos:                  repos,
+		Manager:                manager,
+		Dispatcher:             manager,
+		ClientMessageQueueSize: 1,
+	}
+	service := service.NewMessageService(opts)
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		typing := communication.TypingDtoRequest{User: user.Id, Room: room.Id}
+		err := service.Typing(context.Background(), typing)
+		assert.Nil(t, err, "Actual err: %v", err)
+	}()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: user.Id.String()}})
+
+	err := pollMessages(ctx, service)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	expectedBodyRegex := fmt.Sprintf(
+		`{"cursor":"[0-9a-f-]+:1","resync":false,"events":\[{"id":"[0-9a-f-]+","type":"typing","data":{"room":"%s","user":"%s"}}\]}`,
+		room.Id,
+		user.Id,
+	)
+	assert.Regexp(t, regexp.MustCompile(expectedBodyRegex), rw.Body.String())
+}
+
+func TestIT_ChatsController_NotifyTyping_WhenUserIsNotInRoom_ExpectBadRequest(t *testing.T) {
+	service, dbConn, _ := newTestMessageService(t)
+	defer dbConn.Close(context.Background())
+	requestDto := communication.TypingDtoRequest{
+		User: uuid.New(),
+	}
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(requestDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err = notifyTyping(ctx, service)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	expectedBody := []byte("\"User is not registered in the room\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_ChatsController_MarkAsRead_WhenMessageIsInAnotherRoom_ExpectNotFound(t *testing.T) {
+	service, dbConn, _ := newTestMessageService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	otherRoom := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, otherRoom.Id)
+	msg := insertTestMessage(t, dbConn, user.Id, otherRoom.Id)
+
+	requestDto := communication.ReadMarkerDtoRequest{
+		User:    user.Id,
+		Message: msg.Id,
+	}
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(requestDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: room.Id.String()}})
+
+	err = markAsRead(ctx, service)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+	expectedBody := []byte("\"No such message in the room\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
 func newTestMessageService(
 	t *testing.T,
-) (service.MessageService, db.Connection, *mockProcessor) {
+) (service.MessageService, db.Connection, *mockRelay) {
 	dbConn := newTestDbConnection(t)
 	repos := repositories.New(dbConn)
-	mock := &mockProcessor{}
+	mock := &mockRelay{}
 	opts := service.MessageServiceOpts{
 		DbConn:                 dbConn,
 		Repos:                  repos,
-		Processor:              mock,
+		Relay:                  mock,
 		Manager:                nil,
 		ClientMessageQueueSize: 1,
+		IdempotencyWindow:      time.Minute,
 	}
 	return service.NewMessageService(opts), dbConn, mock
 }
 
 func asyncStartProcessorAndAssertNoError(
-	t *testing.T, processor messages.Processor,
+	t *testing.T, processor messages.Processor[persistence.Message],
 ) *sync.WaitGroup {
 	var wg sync.WaitGroup
 
@@ -361,18 +924,66 @@ func asyncStartProcessorAndAssertNoError(
 	return &wg
 }
 
-type mockProcessor struct {
-	enqueued []persistence.Message
+func asyncStartRelayAndAssertNoError(
+	t *testing.T, relay messages.OutboxRelay,
+) *sync.WaitGroup {
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("Relay panicked: %v", r)
+			}
+		}()
+
+		err := relay.Start()
+		assert.Nil(t, err, "Actual err: %v", err)
+	}()
+
+	return &wg
+}
+
+func getTestOutboxEntry(
+	t *testing.T, conn db.Connection, id uuid.UUID,
+) persistence.OutboxEntry {
+	repo := repositories.NewOutboxRepository(conn)
+
+	entry, err := repo.Get(context.Background(), id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return entry
+}
+
+func getTestOutboxEntryFromResponse(
+	t *testing.T, conn db.Connection, rw *httptest.ResponseRecorder,
+) persistence.OutboxEntry {
+	var responseDto communication.MessageStatusDtoResponse
+	err := json.Unmarshal(rw.Body.Bytes(), &responseDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return getTestOutboxEntry(t, conn, responseDto.Id)
+}
+
+func assertOutboxEntryExists(t *testing.T, conn db.Connection, id uuid.UUID) {
+	entry := getTestOutboxEntry(t, conn, id)
+	assert.Equal(t, id, entry.Id)
+}
+
+type mockRelay struct {
+	notified int
 }
 
-func (m *mockProcessor) Start() error {
+func (m *mockRelay) Start() error {
 	return nil
 }
 
-func (m *mockProcessor) Stop() error {
+func (m *mockRelay) Stop() error {
 	return nil
 }
 
-func (m *mockProcessor) Enqueue(msg persistence.Message) {
-	m.enqueued = append(m.enqueued, msg)
+func (m *mockRelay) Notify() {
+	m.notified++
 }
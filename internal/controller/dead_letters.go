@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/rest"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+func DeadLetterEndpoints(service service.DeadLetterService) rest.Routes {
+	var out rest.Routes
+
+	listHandler := createComponentAwareHttpHandler(listDeadLetters, service)
+	list := newRoute(http.MethodGet, "/admin/dead-letters", listHandler)
+	out = append(out, list)
+
+	replayHandler := createComponentAwareHttpHandler(replayDeadLetter, service)
+	replay := newRoute(http.MethodPost, "/admin/dead-letters/:id/replay", replayHandler)
+	out = append(out, replay)
+
+	deleteHandler := createComponentAwareHttpHandler(discardDeadLetter, service)
+	delete := newRoute(http.MethodDelete, "/admin/dead-letters/:id", deleteHandler)
+	out = append(out, delete)
+
+	return out
+}
+
+func listDeadLetters(c *echo.Context, s service.DeadLetterService) error {
+	letters, err := s.List(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	out, err := marshalNilToEmptySlice(letters)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.JSONBlob(http.StatusOK, out)
+}
+
+func replayDeadLetter(c *echo.Context, s service.DeadLetterService) error {
+	maybeId := c.Param("id")
+	id, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	out, err := s.Replay(c.Request().Context(), id)
+	if err != nil {
+		if errors.IsErrorWithCode(err, db.NoMatchingRows) {
+			return c.JSON(http.StatusNotFound, "No such dead letter")
+		}
+
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusAccepted, out)
+}
+
+func discardDeadLetter(c *echo.Context, s service.DeadLetterService) error {
+	maybeId := c.Param("id")
+	id, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	err = s.Discard(c.Request().Context(), id)
+	if err != nil {
+		if errors.IsErrorWithCode(err, db.NoMatchingRows) {
+			return c.JSON(http.StatusNotFound, "No such dead letter")
+		}
+
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
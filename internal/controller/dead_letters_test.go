@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_DeadLetterController_List(t *testing.T) {
+	service, dbConn, _ := newTestDeadLetterService(t)
+	defer dbConn.Close(context.Background())
+	letter := insertTestDeadLetter(t, dbConn, uuid.New(), uuid.New())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+
+	err := listDeadLetters(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	var responseDtos []communication.DeadLetterDtoResponse
+	err = json.Unmarshal(rw.Body.Bytes(), &responseDtos)
+	assert.Nil(t, err, "Actual err: %v", err)
+	ids := make([]uuid.UUID, 0, len(responseDtos))
+	for _, dto := range responseDtos {
+		ids = append(ids, dto.Id)
+	}
+	assert.Contains(t, ids, letter.Id)
+}
+
+func TestIT_DeadLetterController_Replay(t *testing.T) {
+	service, dbConn, mock := newTestDeadLetterService(t)
+	defer dbConn.Close(context.Background())
+	letter := insertTestDeadLetter(t, dbConn, uuid.New(), uuid.New())
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: letter.Id.String()}})
+
+	err := replayDeadLetter(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	var responseDto communication.MessageStatusDtoResponse
+	err = json.Unmarshal(rw.Body.Bytes(), &responseDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusAccepted, rw.Code)
+	assert.Equal(t, letter.Id, responseDto.Id)
+	assert.Equal(t, "queued", responseDto.Status)
+	assert.Equal(t, 1, mock.notified)
+	assertOutboxEntryExists(t, dbConn, letter.Id)
+}
+
+func TestIT_DeadLetterController_Replay_WhenIdHasWrongSyntax_ExpectBadRequest(t *testing.T) {
+	service, dbConn, _ := newTestDeadLetterService(t)
+	defer dbConn.Close(context.Background())
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: "not-a-uuid"}})
+
+	err := replayDeadLetter(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	expectedBody := []byte("\"Invalid id syntax\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_DeadLetterController_Replay_WhenLetterDoesNotExist_ExpectNotFound(t *testing.T) {
+	service, dbConn, _ := newTestDeadLetterService(t)
+	defer dbConn.Close(context.Background())
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err := replayDeadLetter(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+	expectedBody := []byte("\"No such dead letter\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_DeadLetterController_Discard(t *testing.T) {
+	service, dbConn, _ := newTestDeadLetterService(t)
+	defer dbConn.Close(context.Background())
+	letter := insertTestDeadLetter(t, dbConn, uuid.New(), uuid.New())
+
+	req := httptest.NewRequest(http.MethodDelete, "/", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: letter.Id.String()}})
+
+	err := discardDeadLetter(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+}
+
+func TestIT_DeadLetterController_Discard_WhenLetterDoesNotExist_ExpectNotFound(t *testing.T) {
+	service, dbConn, _ := newTestDeadLetterService(t)
+	defer dbConn.Close(context.Background())
+
+	req := httptest.NewRequest(http.MethodDelete, "/", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err := discardDeadLetter(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+}
+
+func newTestDeadLetterService(
+	t *testing.T,
+) (service.DeadLetterService, db.Connection, *mockRelay) {
+	dbConn := newTestDbConnection(t)
+	repos := repositories.New(dbConn)
+	mock := &mockRelay{}
+	return service.NewDeadLetterService(dbConn, repos, mock), dbConn, mock
+}
+
+func insertTestDeadLetter(
+	t *testing.T, conn db.Connection, user uuid.UUID, room uuid.UUID,
+) persistence.DeadLetter {
+	repo := repositories.NewDeadLetterRepository(conn)
+
+	letter := persistence.DeadLetter{
+		Id:       uuid.New(),
+		ChatUser: user,
+		Room:     room,
+		Message:  "my-dead-letter-" + uuid.NewString(),
+		Reason:   "some reason",
+		Attempts: 1,
+	}
+	out, err := repo.Create(context.Background(), letter)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return out
+}
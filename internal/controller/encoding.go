@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/middleware"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/rest"
+	"github.com/Knoblauchpilze/chat-server/pkg/encoders"
+	"github.com/labstack/echo/v5"
+)
+
+// encodingQueryParam selects the encoding of the streaming endpoints,
+// which can't rely on the `Accept` header as browsers do not allow to
+// set it for SSE and WebSocket connections.
+const encodingQueryParam = "encoding"
+
+// newRoute creates a route whose responses are wrapped in the envelope of
+// the toolkit, and encoded with the encoding which the client prefers
+// according to its `Accept` header. The route is raw so that the encoding
+// is installed below the envelope: the server still applies the other
+// middlewares of the toolkit.
+func newRoute(method string, path string, handler echo.HandlerFunc) rest.Route {
+	return rest.NewRawRoute(method, path, withNegotiatedEncoding(handler))
+}
+
+func withNegotiatedEncoding(handler echo.HandlerFunc) echo.HandlerFunc {
+	// The tracer of the server runs before the envelope sets the id of the
+	// request so it is applied again.
+	enveloped := middleware.ResponseEnvelope()(middleware.RequestTracer()(handler))
+
+	return func(c *echo.Context) error {
+		c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+
+		encoder := encoders.FromAccept(c.Request().Header.Get(echo.HeaderAccept))
+		if encoder.Binary {
+			resp, err := echo.UnwrapResponse(c.Response())
+			if err == nil {
+				resp.ResponseWriter = newEncodedResponseWriter(resp.ResponseWriter, encoder)
+			}
+		}
+
+		return enveloped(c)
+	}
+}
+
+// encodedResponseWriter converts the JSON documents written to it to the
+// encoding. It sits below the envelope, which writes each response in a
+// single call: anything else than a full document is an error.
+type encodedResponseWriter struct {
+	http.ResponseWriter
+	encoder encoders.Encoder
+
+	wroteHeader bool
+	encode      bool
+}
+
+func newEncodedResponseWriter(
+	w http.ResponseWriter, encoder encoders.Encoder,
+) *encodedResponseWriter {
+	return &encodedResponseWriter{
+		ResponseWriter: w,
+		encoder:        encoder,
+	}
+}
+
+func (w *encodedResponseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	// Other content types are left untouched.
+	header := w.Header()
+	if strings.HasPrefix(header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		w.encode = true
+		header.Set(echo.HeaderContentType, w.encoder.ContentType)
+		header.Del(echo.HeaderContentLength)
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *encodedResponseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.encode {
+		return w.ResponseWriter.Write(data)
+	}
+
+	if !json.Valid(data) {
+		return 0, errors.NewCode(encoders.ErrEncodingFailed)
+	}
+
+	out, err := w.encoder.FromJson(data)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := w.ResponseWriter.Write(out); err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+func (w *encodedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
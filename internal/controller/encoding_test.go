@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/encoders"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var encodingTestUser = communication.UserDtoResponse{
+	Id:   uuid.MustParse("f2d9ce22-179d-431c-b63d-43d5a8ab5e18"),
+	Name: "alice",
+}
+
+func TestUnit_WithNegotiatedEncoding_WhenNoAcceptHeader_ExpectJsonEnvelope(t *testing.T) {
+	rw := serveTestRequestWithAccept(t, "")
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+
+	var actual map[string]any
+	err := json.Unmarshal(rw.Body.Bytes(), &actual)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assertEncodedEnvelope(t, rw, actual)
+}
+
+func TestUnit_WithNegotiatedEncoding_WhenAcceptIsMsgPack_ExpectMsgPackEnvelope(t *testing.T) {
+	rw := serveTestRequestWithAccept(t, "application/msgpack")
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, encoders.MsgPack.ContentType, rw.Header().Get("Content-Type"))
+
+	var actual map[string]any
+	err := msgpack.Unmarshal(rw.Body.Bytes(), &actual)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assertEncodedEnvelope(t, rw, actual)
+}
+
+func TestUnit_WithNegotiatedEncoding_WhenAcceptIsCbor_ExpectCborEnvelope(t *testing.T) {
+	rw := serveTestRequestWithAccept(t, "text/html, application/cbor")
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, encoders.Cbor.ContentType, rw.Header().Get("Content-Type"))
+
+	decMode, err := cbor.DecOptions{
+		DefaultMapType: reflect.TypeOf(map[string]any(nil)),
+	}.DecMode()
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	var actual map[string]any
+	err = decMode.Unmarshal(rw.Body.Bytes(), &actual)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assertEncodedEnvelope(t, rw, actual)
+}
+
+func TestUnit_WithNegotiatedEncoding_WhenNoContent_ExpectStatusKept(t *testing.T) {
+	req := httptest.NewRequest(http.MethodDelete, "/", nil)
+	req.Header.Set("Accept", "application/msgpack")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+
+	handler := withNegotiatedEncoding(func(c *echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	err := handler(ctx)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+	assert.Empty(t, rw.Body.Bytes())
+}
+
+func TestUnit_WithNegotiatedEncoding_WhenRequestHasId_ExpectIdKept(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-Id", "my-request")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+
+	handler := withNegotiatedEncoding(func(c *echo.Context) error {
+		return c.JSON(http.StatusOK, encodingTestUser)
+	})
+	err := handler(ctx)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, "my-request", rw.Header().Get("X-Request-Id"))
+}
+
+func TestUnit_EncodedResponseWriter_ExpectDocumentEncoded(t *testing.T) {
+	rw := httptest.NewRecorder()
+	rw.Header().Set("Content-Type", "application/json")
+	w := newEncodedResponseWriter(rw, encoders.MsgPack)
+
+	n, err := w.Write([]byte(`{"name":"alice"}`))
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, 16, n)
+
+	var actual map[string]any
+	err = msgpack.Unmarshal(rw.Body.Bytes(), &actual)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, map[string]any{"name": "alice"}, actual)
+}
+
+func TestUnit_EncodedResponseWriter_WhenDocumentIsIncomplete_ExpectError(t *testing.T) {
+	for _, data := range []string{`{"name":`, `{"id":1}` + "\n" + `{"id":`} {
+		rw := httptest.NewRecorder()
+		rw.Header().Set("Content-Type", "application/json")
+		w := newEncodedResponseWriter(rw, encoders.MsgPack)
+
+		_, err := w.Write([]byte(data))
+
+		assert.True(
+			t,
+			errors.IsErrorWithCode(err, encoders.ErrEncodingFailed),
+			"Data: %q, actual err: %v",
+			data,
+			err,
+		)
+		assert.Empty(t, rw.Body.Bytes())
+	}
+}
+
+func TestUnit_EncodedResponseWriter_WhenDataIsNotJson_ExpectError(t *testing.T) {
+	rw := httptest.NewRecorder()
+	rw.Header().Set("Content-Type", "application/json")
+	w := newEncodedResponseWriter(rw, encoders.MsgPack)
+
+	_, err := w.Write([]byte("not-json"))
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, encoders.ErrEncodingFailed),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func serveTestRequestWithAccept(t *testing.T, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	ctx, rw := generateTestEchoContextFromRequest(req)
+
+	handler := withNegotiatedEncoding(func(c *echo.Context) error {
+		return c.JSON(http.StatusOK, encodingTestUser)
+	})
+	err := handler(ctx)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, "Accept", rw.Header().Get("Vary"))
+
+	return rw
+}
+
+func assertEncodedEnvelope(
+	t *testing.T, rw *httptest.ResponseRecorder, actual map[string]any,
+) {
+	data, err := json.Marshal(encodingTestUser)
+	assert.Nil(t, err, "Actual err: %v", err)
+	var details map[string]any
+	err = json.Unmarshal(data, &details)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	expected := map[string]any{
+		"requestId": rw.Header().Get("X-Request-Id"),
+		"status":    "SUCCESS",
+		"details":   details,
+	}
+	assert.Equal(t, expected, actual)
+}
@@ -12,7 +12,7 @@ func HealthCheckEndpoints(pool db.Connection) rest.Routes {
 	var out rest.Routes
 
 	getHandler := createComponentAwareHttpHandler(healthcheck, pool)
-	get := rest.NewRoute(http.MethodGet, "/healthcheck", getHandler)
+	get := newRoute(http.MethodGet, "/healthcheck", getHandler)
 	out = append(out, get)
 
 	return out
@@ -90,6 +90,33 @@ func assertMessageExists(t *testing.T, conn db.Connection, id uuid.UUID) {
 	assert.Equal(t, id, value)
 }
 
+func setUserAsModerator(t *testing.T, conn db.Connection, user uuid.UUID, room uuid.UUID) {
+	sqlQuery := `UPDATE room_user SET moderator = true WHERE room = $1 AND chat_user = $2`
+
+	count, err := conn.Exec(
+		context.Background(),
+		sqlQuery,
+		room,
+		user,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, int64(1), count)
+}
+
+func assertUserIsModerator(
+	t *testing.T, conn db.Connection, user uuid.UUID, room uuid.UUID,
+) {
+	value, err := db.QueryOne[bool](
+		context.Background(),
+		conn,
+		"SELECT moderator FROM room_user WHERE chat_user = $1 AND room = $2",
+		user,
+		room,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.True(t, value)
+}
+
 func assertUserRegisteredInRoom(
 	t *testing.T, conn db.Connection, user uuid.UUID, room uuid.UUID,
 ) {
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/rest"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+func PinEndpoints(service service.PinService) rest.Routes {
+	var out rest.Routes
+
+	// The server does not support PUT requests so we use POST instead.
+	postHandler := createComponentAwareHttpHandler(pinMessage, service)
+	post := newRoute(http.MethodPost, "/rooms/:room/pins/:message", postHandler)
+	out = append(out, post)
+
+	deleteHandler := createComponentAwareHttpHandler(unpinMessage, service)
+	delete := newRoute(http.MethodDelete, "/rooms/:room/pins/:message", deleteHandler)
+	out = append(out, delete)
+
+	listHandler := createComponentAwareHttpHandler(listPinForRoom, service)
+	list := newRoute(http.MethodGet, "/rooms/:id/pins", listHandler)
+	out = append(out, list)
+
+	return out
+}
+
+func pinMessage(c *echo.Context, s service.PinService) error {
+	maybeId := c.Param("room")
+	room, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid room syntax")
+	}
+
+	maybeId = c.Param("message")
+	message, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid message syntax")
+	}
+
+	maybeUser := c.QueryParam("user")
+	user, err := uuid.Parse(maybeUser)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid user syntax")
+	}
+
+	out, err := s.Pin(c.Request().Context(), user, room, message)
+	if err != nil {
+		if errors.IsErrorWithCode(err, service.ErrNotModerator) {
+			return c.JSON(http.StatusForbidden, "User is not a moderator of the room")
+		} else if errors.IsErrorWithCode(err, db.NoMatchingRows) {
+			return c.JSON(http.StatusNotFound, "No such message")
+		} else if errors.IsErrorWithCode(err, repositories.ErrMessageAlreadyPinned) {
+			return c.JSON(http.StatusConflict, "Message already pinned")
+		} else if errors.IsErrorWithCode(err, service.ErrTooManyPins) {
+			return c.JSON(http.StatusConflict, "Too many pinned messages in room")
+		}
+
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusCreated, out)
+}
+
+func unpinMessage(c *echo.Context, s service.PinService) error {
+	maybeId := c.Param("room")
+	room, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid room syntax")
+	}
+
+	maybeId = c.Param("message")
+	message, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid message syntax")
+	}
+
+	maybeUser := c.QueryParam("user")
+	user, err := uuid.Parse(maybeUser)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid user syntax")
+	}
+
+	err = s.Unpin(c.Request().Context(), user, room, message)
+	if err != nil {
+		if errors.IsErrorWithCode(err, service.ErrNotModerator) {
+			return c.JSON(http.StatusForbidden, "User is not a moderator of the room")
+		} else if errors.IsErrorWithCode(err, db.NoMatchingRows) {
+			return c.JSON(http.StatusNotFound, "No such pin")
+		}
+
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func listPinForRoom(c *echo.Context, s service.PinService) error {
+	maybeId := c.Param("id")
+	id, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	pins, err := s.ListForRoom(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	out, err := marshalNilToEmptySlice(pins)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.JSONBlob(http.StatusOK, out)
+}
@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/clients"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_PinController_PinMessage_WhenUserHasWrongSyntax_ExpectBadRequest(t *testing.T) {
+	service, dbConn := newTestPinService(t)
+	defer dbConn.Close(context.Background())
+	req := generateTestRequestWithQueryParam(http.MethodPost, "user", "not-a-uuid")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{
+		{Name: "room", Value: uuid.NewString()},
+		{Name: "message", Value: uuid.NewString()},
+	})
+
+	err := pinMessage(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	expectedBody := []byte("\"Invalid user syntax\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_PinController_PinMessage_WhenUserIsNotModerator_ExpectForbidden(t *testing.T) {
+	service, dbConn := newTestPinService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	msg := insertTestMessage(t, dbConn, user.Id, room.Id)
+
+	req := generateTestRequestWithQueryParam(http.MethodPost, "user", user.Id.String())
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{
+		{Name: "room", Value: room.Id.String()},
+		{Name: "message", Value: msg.Id.String()},
+	})
+
+	err := pinMessage(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+}
+
+func TestIT_PinController_PinMessage(t *testing.T) {
+	service, dbConn := newTestPinService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	setUserAsModerator(t, dbConn, user.Id, room.Id)
+	msg := insertTestMessage(t, dbConn, user.Id, room.Id)
+
+	req := generateTestRequestWithQueryParam(http.MethodPost, "user", user.Id.String())
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{
+		{Name: "room", Value: room.Id.String()},
+		{Name: "message", Value: msg.Id.String()},
+	})
+
+	err := pinMessage(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	var responseDto communication.PinDtoResponse
+	err = json.Unmarshal(rw.Body.Bytes(), &responseDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, room.Id, responseDto.Room)
+	assert.Equal(t, msg.Id, responseDto.Message.Id)
+	assert.Equal(t, user.Id, responseDto.PinnedBy)
+}
+
+func TestIT_PinController_UnpinMessage_WhenNotPinned_ExpectNotFound(t *testing.T) {
+	service, dbConn := newTestPinService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	setUserAsModerator(t, dbConn, user.Id, room.Id)
+
+	req := generateTestRequestWithQueryParam(http.MethodDelete, "user", user.Id.String())
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{
+		{Name: "room", Value: room.Id.String()},
+		{Name: "message", Value: uuid.NewString()},
+	})
+
+	err := unpinMessage(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+}
+
+func TestIT_PinController_UnpinMessage(t *testing.T) {
+	service, dbConn := newTestPinService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	setUserAsModerator(t, dbConn, user.Id, room.Id)
+	msg := insertTestMessage(t, dbConn, user.Id, room.Id)
+	_, err := service.Pin(context.Background(), user.Id, room.Id, msg.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	req := generateTestRequestWithQueryParam(http.MethodDelete, "user", user.Id.String())
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{
+		{Name: "room", Value: room.Id.String()},
+		{Name: "message", Value: msg.Id.String()},
+	})
+
+	err = unpinMessage(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+}
+
+func TestIT_PinController_ListPinForRoom_WhenNoPins_ExpectEmptyList(t *testing.T) {
+	service, dbConn := newTestPinService(t)
+	defer dbConn.Close(context.Background())
+	room := insertTestRoom(t, dbConn)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: room.Id.String()}})
+
+	err := listPinForRoom(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, []byte("[]"), rw.Body.Bytes(), "Actual body: %s", rw.Body.String())
+}
+
+func newTestPinService(t *testing.T) (service.PinService, db.Connection) {
+	dbConn := newTestDbConnection(t)
+	repos := repositories.New(dbConn)
+
+	opts := service.PinServiceOpts{
+		DbConn:         dbConn,
+		Repos:          repos,
+		Dispatcher:     clients.NewManager(time.Minute, clients.PollPolicy{}, repos),
+		MaxPinsPerRoom: 2,
+	}
+
+	return service.NewPinService(opts), dbConn
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/rest"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+	"github.com/labstack/echo/v5"
+)
+
+func ProcessorEndpoints(monitor messages.QueueMonitor) rest.Routes {
+	var out rest.Routes
+
+	getHandler := createComponentAwareHttpHandler(getQueueDepths, monitor)
+	get := newRoute(http.MethodGet, "/admin/processor/queues", getHandler)
+	out = append(out, get)
+
+	return out
+}
+
+func getQueueDepths(c *echo.Context, monitor messages.QueueMonitor) error {
+	out := communication.ToQueueDepthDtoResponses(monitor.QueueDepths())
+	return c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_ProcessorController_GetQueueDepths(t *testing.T) {
+	monitor := &mockQueueMonitor{depths: []int{2, 0, 5}}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+
+	err := getQueueDepths(ctx, monitor)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	expectedBody := `
+	[
+		{"shard": 0, "depth": 2},
+		{"shard": 1, "depth": 0},
+		{"shard": 2, "depth": 5}
+	]`
+	assert.JSONEq(t, expectedBody, rw.Body.String())
+}
+
+type mockQueueMonitor struct {
+	depths []int
+}
+
+func (m *mockQueueMonitor) QueueDepths() []int {
+	return m.depths
+}
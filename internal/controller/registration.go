@@ -5,6 +5,7 @@ import (
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/rest"
+	"github.com/Knoblauchpilze/chat-server/internal/frames"
 	"github.com/Knoblauchpilze/chat-server/internal/service"
 	"github.com/Knoblauchpilze/chat-server/pkg/communication"
 	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
@@ -16,13 +17,17 @@ func RegistrationEndpoints(service service.RegistrationService) rest.Routes {
 	var out rest.Routes
 
 	postHandler := createComponentAwareHttpHandler(addUserInRoom, service)
-	post := rest.NewRoute(http.MethodPost, "/rooms/:id/users", postHandler)
+	post := newRoute(http.MethodPost, "/rooms/:id/users", postHandler)
 	out = append(out, post)
 
 	deleteHandler := createComponentAwareHttpHandler(deleteUserFromRoom, service)
-	delete := rest.NewRoute(http.MethodDelete, "/rooms/:room/users/:user", deleteHandler)
+	delete := newRoute(http.MethodDelete, "/rooms/:room/users/:user", deleteHandler)
 	out = append(out, delete)
 
+	patchHandler := createComponentAwareHttpHandler(updateUserInRoom, service)
+	patch := newRoute(http.MethodPatch, "/rooms/:room/users/:user", patchHandler)
+	out = append(out, patch)
+
 	return out
 }
 
@@ -43,17 +48,7 @@ func addUserInRoom(c *echo.Context, s service.RegistrationService) error {
 		c.Request().Context(), registrationDtoRequest.User, room,
 	)
 	if err != nil {
-		if errors.IsErrorWithCode(err, repositories.ErrNoSuchUser) {
-			return c.JSON(http.StatusBadRequest, "Invalid user id")
-		}
-		if errors.IsErrorWithCode(err, repositories.ErrNoSuchRoom) {
-			return c.JSON(http.StatusBadRequest, "Invalid room id")
-		}
-		if errors.IsErrorWithCode(err, repositories.ErrUserAlreadyRegisteredInRoom) {
-			return c.JSON(http.StatusConflict, "User already registered in room")
-		}
-
-		return c.JSON(http.StatusInternalServerError, err)
+		return c.JSON(frames.RegistrationError(err))
 	}
 
 	return c.NoContent(http.StatusNoContent)
@@ -79,3 +74,45 @@ func deleteUserFromRoom(c *echo.Context, s service.RegistrationService) error {
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+func updateUserInRoom(c *echo.Context, s service.RegistrationService) error {
+	maybeId := c.Param("room")
+	room, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	maybeId = c.Param("user")
+	user, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	maybeCaller := c.QueryParam("caller")
+	caller, err := uuid.Parse(maybeCaller)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid caller syntax")
+	}
+
+	var moderatorDtoRequest communication.RoomModeratorDtoRequest
+	err = c.Bind(&moderatorDtoRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid moderator syntax")
+	}
+
+	err = s.SetModerator(
+		c.Request().Context(), caller, user, room, moderatorDtoRequest.Moderator,
+	)
+	if err != nil {
+		if errors.IsErrorWithCode(err, service.ErrNotModerator) {
+			return c.JSON(http.StatusForbidden, "Caller is not a moderator of the room")
+		}
+		if errors.IsErrorWithCode(err, repositories.ErrUserNotRegisteredInRoom) {
+			return c.JSON(http.StatusNotFound, "User is not registered in the room")
+		}
+
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
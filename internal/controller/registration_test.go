@@ -8,9 +8,11 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
 	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/clients"
 	"github.com/Knoblauchpilze/chat-server/pkg/communication"
 	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
 	"github.com/google/uuid"
@@ -158,8 +160,128 @@ func TestIT_RegistrationController_DeleteUserFromRoom(t *testing.T) {
 	assertUserNotRegisteredInRoom(t, dbConn, user.Id, room.Id)
 }
 
+func TestIT_RegistrationController_UpdateUserInRoom_SetsModerator(t *testing.T) {
+	service, dbConn := newTestRegistrationService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	body := bytes.NewBufferString(`{"moderator":true}`)
+	req := httptest.NewRequest(http.MethodPatch, "/?caller="+user.Id.String(), body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{
+		{
+			Name:  "room",
+			Value: room.Id.String(),
+		},
+		{
+			Name:  "user",
+			Value: user.Id.String(),
+		},
+	})
+
+	err := updateUserInRoom(ctx, service)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+	assertUserIsModerator(t, dbConn, user.Id, room.Id)
+}
+
+func TestIT_RegistrationController_UpdateUserInRoom_WhenCallerHasWrongSyntax_ExpectBadRequest(t *testing.T) {
+	service, dbConn := newTestRegistrationService(t)
+	defer dbConn.Close(context.Background())
+
+	body := bytes.NewBufferString(`{"moderator":true}`)
+	req := httptest.NewRequest(http.MethodPatch, "/?caller=not-a-uuid", body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{
+		{
+			Name:  "room",
+			Value: uuid.NewString(),
+		},
+		{
+			Name:  "user",
+			Value: uuid.NewString(),
+		},
+	})
+
+	err := updateUserInRoom(ctx, service)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+}
+
+func TestIT_RegistrationController_UpdateUserInRoom_WhenCallerIsNotModerator_ExpectForbidden(t *testing.T) {
+	service, dbConn := newTestRegistrationService(t)
+	defer dbConn.Close(context.Background())
+	moderator := insertTestUser(t, dbConn)
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, moderator.Id, room.Id)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	setUserAsModerator(t, dbConn, moderator.Id, room.Id)
+
+	body := bytes.NewBufferString(`{"moderator":true}`)
+	req := httptest.NewRequest(http.MethodPatch, "/?caller="+user.Id.String(), body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{
+		{
+			Name:  "room",
+			Value: room.Id.String(),
+		},
+		{
+			Name:  "user",
+			Value: user.Id.String(),
+		},
+	})
+
+	err := updateUserInRoom(ctx, service)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+}
+
+func TestIT_RegistrationController_UpdateUserInRoom_WhenUserNotRegistered_ExpectNotFound(t *testing.T) {
+	service, dbConn := newTestRegistrationService(t)
+	defer dbConn.Close(context.Background())
+	moderator := insertTestUser(t, dbConn)
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, moderator.Id, room.Id)
+	setUserAsModerator(t, dbConn, moderator.Id, room.Id)
+
+	body := bytes.NewBufferString(`{"moderator":true}`)
+	req := httptest.NewRequest(http.MethodPatch, "/?caller="+moderator.Id.String(), body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{
+		{
+			Name:  "room",
+			Value: room.Id.String(),
+		},
+		{
+			Name:  "user",
+			Value: user.Id.String(),
+		},
+	})
+
+	err := updateUserInRoom(ctx, service)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+}
+
 func newTestRegistrationService(t *testing.T) (service.RegistrationService, db.Connection) {
 	dbConn := newTestDbConnection(t)
 	repos := repositories.New(dbConn)
-	return service.NewRegistrationService(dbConn, repos), dbConn
+	manager := clients.NewManager(time.Minute, clients.PollPolicy{}, repos)
+	return service.NewRegistrationService(dbConn, repos, manager), dbConn
 }
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/pgx"
@@ -17,27 +18,31 @@ func RoomEndpoints(service service.RoomService) rest.Routes {
 	var out rest.Routes
 
 	postHandler := createComponentAwareHttpHandler(createRoom, service)
-	post := rest.NewRoute(http.MethodPost, "/rooms", postHandler)
+	post := newRoute(http.MethodPost, "/rooms", postHandler)
 	out = append(out, post)
 
 	getHandler := createComponentAwareHttpHandler(getRoom, service)
-	get := rest.NewRoute(http.MethodGet, "/rooms/:id", getHandler)
+	get := newRoute(http.MethodGet, "/rooms/:id", getHandler)
 	out = append(out, get)
 
 	listHandler := createComponentAwareHttpHandler(listRoom, service)
-	list := rest.NewRoute(http.MethodGet, "/rooms", listHandler)
+	list := newRoute(http.MethodGet, "/rooms", listHandler)
 	out = append(out, list)
 
 	listUserForRoomHandler := createComponentAwareHttpHandler(listUserForRoom, service)
-	listUserForRoom := rest.NewRoute(http.MethodGet, "/rooms/:id/users", listUserForRoomHandler)
+	listUserForRoom := newRoute(http.MethodGet, "/rooms/:id/users", listUserForRoomHandler)
 	out = append(out, listUserForRoom)
 
 	listMessageForRoomHandler := createComponentAwareHttpHandler(listMessageForRoom, service)
-	listMessageForRoom := rest.NewRoute(http.MethodGet, "/rooms/:id/messages", listMessageForRoomHandler)
+	listMessageForRoom := newRoute(http.MethodGet, "/rooms/:id/messages", listMessageForRoomHandler)
 	out = append(out, listMessageForRoom)
 
+	patchHandler := createComponentAwareHttpHandler(updateRoomMessageTtl, service)
+	patch := newRoute(http.MethodPatch, "/rooms/:id", patchHandler)
+	out = append(out, patch)
+
 	deleteHandler := createComponentAwareHttpHandler(deleteRoom, service)
-	delete := rest.NewRoute(http.MethodDelete, "/rooms/:id", deleteHandler)
+	delete := newRoute(http.MethodDelete, "/rooms/:id", deleteHandler)
 	out = append(out, delete)
 
 	return out
@@ -55,6 +60,9 @@ func createRoom(c *echo.Context, s service.RoomService) error {
 		if errors.IsErrorWithCode(err, service.ErrInvalidName) {
 			return c.JSON(http.StatusBadRequest, "Invalid room name")
 		}
+		if errors.IsErrorWithCode(err, service.ErrInvalidTtl) {
+			return c.JSON(http.StatusBadRequest, "Invalid message ttl")
+		}
 		if errors.IsErrorWithCode(err, pgx.UniqueConstraintViolation) {
 			return c.JSON(http.StatusConflict, "Room name already in use")
 		}
@@ -125,8 +133,21 @@ func listMessageForRoom(c *echo.Context, s service.RoomService) error {
 		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
 	}
 
-	messages, err := s.ListMessageForRoom(c.Request().Context(), id)
+	from, err := parseSequence(c.QueryParam("from"))
 	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid sequence range")
+	}
+	to, err := parseSequence(c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid sequence range")
+	}
+
+	messages, err := s.ListMessageForRoom(c.Request().Context(), id, from, to)
+	if err != nil {
+		if errors.IsErrorWithCode(err, service.ErrInvalidSequenceRange) {
+			return c.JSON(http.StatusBadRequest, "Invalid sequence range")
+		}
+
 		return c.JSON(http.StatusInternalServerError, err)
 	}
 
@@ -138,6 +159,34 @@ func listMessageForRoom(c *echo.Context, s service.RoomService) error {
 	return c.JSONBlob(http.StatusOK, out)
 }
 
+func updateRoomMessageTtl(c *echo.Context, s service.RoomService) error {
+	maybeId := c.Param("id")
+	id, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	var ttlDtoRequest communication.RoomMessageTtlDtoRequest
+	err = c.Bind(&ttlDtoRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid message ttl syntax")
+	}
+
+	out, err := s.UpdateMessageTtl(c.Request().Context(), id, ttlDtoRequest)
+	if err != nil {
+		if errors.IsErrorWithCode(err, service.ErrInvalidTtl) {
+			return c.JSON(http.StatusBadRequest, "Invalid message ttl")
+		}
+		if errors.IsErrorWithCode(err, db.NoMatchingRows) {
+			return c.JSON(http.StatusNotFound, "No such room")
+		}
+
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
 func deleteRoom(c *echo.Context, s service.RoomService) error {
 	maybeId := c.Param("id")
 	id, err := uuid.Parse(maybeId)
@@ -152,3 +201,10 @@ func deleteRoom(c *echo.Context, s service.RoomService) error {
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+func parseSequence(maybeSequence string) (int64, error) {
+	if maybeSequence == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(maybeSequence, 10, 64)
+}
@@ -9,9 +9,11 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
 	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/clients"
 	"github.com/Knoblauchpilze/chat-server/pkg/communication"
 	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
 	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
@@ -300,6 +302,165 @@ func TestIT_RoomController_ListMessageForRoom_WhenNoMessageInRoom_ExpectEmptySli
 	assert.Equal(t, []communication.UserDtoResponse{}, responseDto)
 }
 
+func TestIT_RoomController_ListMessageForRoom_WithSequenceRange(t *testing.T) {
+	service, dbConn := newTestRoomService(t)
+	defer dbConn.Close(context.Background())
+	room := insertTestRoom(t, dbConn)
+	user := insertTestUser(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	insertTestMessage(t, dbConn, user.Id, room.Id)
+	msg2 := insertTestMessage(t, dbConn, user.Id, room.Id)
+
+	target := fmt.Sprintf("/?from=%d", msg2.Sequence)
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: room.Id.String()}})
+
+	err := listMessageForRoom(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	var responseDto []communication.MessageDtoResponse
+	err = json.Unmarshal(rw.Body.Bytes(), &responseDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	expected := []communication.MessageDtoResponse{
+		communication.ToMessageDtoResponse(msg2),
+	}
+	assert.Equal(t, expected, responseDto)
+}
+
+func TestIT_RoomController_ListMessageForRoom_WhenSequenceRangeIsInvalid_ExpectBadRequest(t *testing.T) {
+	type testCase struct {
+		query string
+	}
+
+	testCases := map[string]testCase{
+		"notANumber":  {query: "from=abc"},
+		"negative":    {query: "to=-1"},
+		"fromAfterTo": {query: "from=5&to=2"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			service, dbConn := newTestRoomService(t)
+			defer dbConn.Close(context.Background())
+
+			req := httptest.NewRequest(http.MethodGet, "/?"+tc.query, nil)
+			ctx, rw := generateTestEchoContextFromRequest(req)
+			ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+			err := listMessageForRoom(ctx, service)
+			assert.Nil(t, err, "Actual err: %v", err)
+
+			assert.Equal(t, http.StatusBadRequest, rw.Code)
+			expectedBody := []byte("\"Invalid sequence range\"\n")
+			assert.Equal(
+				t,
+				expectedBody,
+				rw.Body.Bytes(),
+				"Actual body: %s",
+				rw.Body.String(),
+			)
+		})
+	}
+}
+
+func TestIT_RoomController_UpdateRoomMessageTtl(t *testing.T) {
+	service, dbConn := newTestRoomService(t)
+	defer dbConn.Close(context.Background())
+	room := insertTestRoom(t, dbConn)
+
+	ttl := 3600
+	requestDto := communication.RoomMessageTtlDtoRequest{
+		MessageTtl: &ttl,
+	}
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(requestDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	req := httptest.NewRequest(http.MethodPatch, "/", &body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: room.Id.String()}})
+
+	err = updateRoomMessageTtl(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	var responseDto communication.RoomDtoResponse
+	err = json.Unmarshal(rw.Body.Bytes(), &responseDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, room.Id, responseDto.Id)
+	assert.Equal(t, &ttl, responseDto.MessageTtl)
+}
+
+func TestIT_RoomController_UpdateRoomMessageTtl_WhenTtlIsInvalid_ExpectBadRequest(t *testing.T) {
+	service, dbConn := newTestRoomService(t)
+	defer dbConn.Close(context.Background())
+	room := insertTestRoom(t, dbConn)
+
+	ttl := 0
+	requestDto := communication.RoomMessageTtlDtoRequest{
+		MessageTtl: &ttl,
+	}
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(requestDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	req := httptest.NewRequest(http.MethodPatch, "/", &body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: room.Id.String()}})
+
+	err = updateRoomMessageTtl(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	expectedBody := []byte("\"Invalid message ttl\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_RoomController_UpdateRoomMessageTtl_WhenRoomDoesNotExist_ExpectNotFound(t *testing.T) {
+	service, dbConn := newTestRoomService(t)
+	defer dbConn.Close(context.Background())
+
+	ttl := 3600
+	requestDto := communication.RoomMessageTtlDtoRequest{
+		MessageTtl: &ttl,
+	}
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(requestDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	req := httptest.NewRequest(http.MethodPatch, "/", &body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err = updateRoomMessageTtl(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+	expectedBody := []byte("\"No such room\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
 func TestIT_RoomController_DeleteRoom_WhenIdHasWrongSyntax_ExpectBadRequest(t *testing.T) {
 	service, dbConn := newTestRoomService(t)
 	defer dbConn.Close(context.Background())
@@ -355,7 +516,8 @@ func TestIT_RoomController_DeleteRoom_WhenRoomDoesNotExist_ExpectSuccess(t *test
 func newTestRoomService(t *testing.T) (service.RoomService, db.Connection) {
 	dbConn := newTestDbConnection(t)
 	repos := repositories.New(dbConn)
-	return service.NewRoomService(dbConn, repos), dbConn
+	manager := clients.NewManager(time.Minute, clients.PollPolicy{}, repos)
+	return service.NewRoomService(dbConn, repos, manager), dbConn
 }
 
 func insertTestRoom(t *testing.T, conn db.Connection) persistence.Room {
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/rest"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+func ScheduledMessageEndpoints(service service.ScheduledMessageService) rest.Routes {
+	var out rest.Routes
+
+	listHandler := createComponentAwareHttpHandler(listScheduledMessagesForRoom, service)
+	list := newRoute(http.MethodGet, "/rooms/:id/scheduled-messages", listHandler)
+	out = append(out, list)
+
+	patchHandler := createComponentAwareHttpHandler(updateScheduledMessage, service)
+	patch := newRoute(http.MethodPatch, "/scheduled-messages/:id", patchHandler)
+	out = append(out, patch)
+
+	deleteHandler := createComponentAwareHttpHandler(cancelScheduledMessage, service)
+	delete := newRoute(http.MethodDelete, "/scheduled-messages/:id", deleteHandler)
+	out = append(out, delete)
+
+	return out
+}
+
+func listScheduledMessagesForRoom(c *echo.Context, s service.ScheduledMessageService) error {
+	maybeId := c.Param("id")
+	id, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	messages, err := s.ListForRoom(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	out, err := marshalNilToEmptySlice(messages)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.JSONBlob(http.StatusOK, out)
+}
+
+func updateScheduledMessage(c *echo.Context, s service.ScheduledMessageService) error {
+	maybeId := c.Param("id")
+	id, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	var messageDtoRequest communication.ScheduledMessageDtoRequest
+	err = c.Bind(&messageDtoRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid message syntax")
+	}
+
+	out, err := s.Update(c.Request().Context(), id, messageDtoRequest)
+	if err != nil {
+		if errors.IsErrorWithCode(err, db.NoMatchingRows) {
+			return c.JSON(http.StatusNotFound, "No such scheduled message")
+		} else if errors.IsErrorWithCode(err, service.ErrNotMessageAuthor) {
+			return c.JSON(http.StatusForbidden, "User is not the author of the message")
+		} else if errors.IsErrorWithCode(err, service.ErrEmptyMessage) {
+			return c.JSON(http.StatusBadRequest, "Invalid empty message")
+		} else if errors.IsErrorWithCode(err, service.ErrInvalidSendAt) {
+			return c.JSON(http.StatusBadRequest, "Send time is not in the future")
+		}
+
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+func cancelScheduledMessage(c *echo.Context, s service.ScheduledMessageService) error {
+	maybeId := c.Param("id")
+	id, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	maybeUser := c.QueryParam("user")
+	user, err := uuid.Parse(maybeUser)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid user syntax")
+	}
+
+	err = s.Cancel(c.Request().Context(), user, id)
+	if err != nil {
+		if errors.IsErrorWithCode(err, db.NoMatchingRows) {
+			return c.JSON(http.StatusNotFound, "No such scheduled message")
+		} else if errors.IsErrorWithCode(err, service.ErrNotMessageAuthor) {
+			return c.JSON(http.StatusForbidden, "User is not the author of the message")
+		}
+
+		return c.JSON(http.StatusInternalServerError, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,220 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_ScheduledMessageController_ListForRoom(t *testing.T) {
+	service, dbConn := newTestScheduledMessageService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	msg := insertTestScheduledMessage(t, dbConn, user.Id, room.Id)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: room.Id.String()}})
+
+	err := listScheduledMessagesForRoom(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	var responseDtos []communication.ScheduledMessageDtoResponse
+	err = json.Unmarshal(rw.Body.Bytes(), &responseDtos)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, 1, len(responseDtos))
+	assert.Equal(t, msg.Id, responseDtos[0].Id)
+}
+
+func TestIT_ScheduledMessageController_ListForRoom_WhenNoMessages_ExpectEmptyList(t *testing.T) {
+	service, dbConn := newTestScheduledMessageService(t)
+	defer dbConn.Close(context.Background())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err := listScheduledMessagesForRoom(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, []byte("[]"), rw.Body.Bytes())
+}
+
+func TestIT_ScheduledMessageController_Update(t *testing.T) {
+	service, dbConn := newTestScheduledMessageService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	msg := insertTestScheduledMessage(t, dbConn, user.Id, room.Id)
+
+	message := "updated message"
+	requestDto := communication.ScheduledMessageDtoRequest{
+		User:    user.Id,
+		Message: &message,
+	}
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(requestDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	req := httptest.NewRequest(http.MethodPatch, "/", &body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: msg.Id.String()}})
+
+	err = updateScheduledMessage(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	var responseDto communication.ScheduledMessageDtoResponse
+	err = json.Unmarshal(rw.Body.Bytes(), &responseDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, msg.Id, responseDto.Id)
+	assert.Equal(t, message, responseDto.Message)
+}
+
+func TestIT_ScheduledMessageController_Update_WhenUserIsNotTheAuthor_ExpectForbidden(t *testing.T) {
+	service, dbConn := newTestScheduledMessageService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	msg := insertTestScheduledMessage(t, dbConn, user.Id, room.Id)
+
+	message := "updated message"
+	requestDto := communication.ScheduledMessageDtoRequest{
+		User:    uuid.New(),
+		Message: &message,
+	}
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(requestDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	req := httptest.NewRequest(http.MethodPatch, "/", &body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: msg.Id.String()}})
+
+	err = updateScheduledMessage(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+}
+
+func TestIT_ScheduledMessageController_Update_WhenMessageDoesNotExist_ExpectNotFound(t *testing.T) {
+	service, dbConn := newTestScheduledMessageService(t)
+	defer dbConn.Close(context.Background())
+
+	requestDto := communication.ScheduledMessageDtoRequest{
+		User: uuid.New(),
+	}
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(requestDto)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	req := httptest.NewRequest(http.MethodPatch, "/", &body)
+	req.Header.Set("Content-Type", "application/json")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err = updateScheduledMessage(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+}
+
+func TestIT_ScheduledMessageController_Cancel(t *testing.T) {
+	service, dbConn := newTestScheduledMessageService(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	msg := insertTestScheduledMessage(t, dbConn, user.Id, room.Id)
+
+	req := generateTestRequestWithQueryParam(http.MethodDelete, "user", user.Id.String())
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: msg.Id.String()}})
+
+	err := cancelScheduledMessage(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+}
+
+func TestIT_ScheduledMessageController_Cancel_WhenUserHasWrongSyntax_ExpectBadRequest(t *testing.T) {
+	service, dbConn := newTestScheduledMessageService(t)
+	defer dbConn.Close(context.Background())
+
+	req := generateTestRequestWithQueryParam(http.MethodDelete, "user", "not-a-uuid")
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err := cancelScheduledMessage(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	expectedBody := []byte("\"Invalid user syntax\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_ScheduledMessageController_Cancel_WhenMessageDoesNotExist_ExpectNotFound(t *testing.T) {
+	service, dbConn := newTestScheduledMessageService(t)
+	defer dbConn.Close(context.Background())
+
+	req := generateTestRequestWithQueryParam(http.MethodDelete, "user", uuid.NewString())
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: uuid.NewString()}})
+
+	err := cancelScheduledMessage(ctx, service)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+}
+
+func newTestScheduledMessageService(
+	t *testing.T,
+) (service.ScheduledMessageService, db.Connection) {
+	dbConn := newTestDbConnection(t)
+	repos := repositories.New(dbConn)
+	return service.NewScheduledMessageService(repos), dbConn
+}
+
+func insertTestScheduledMessage(
+	t *testing.T, conn db.Connection, user uuid.UUID, room uuid.UUID,
+) persistence.ScheduledMessage {
+	repo := repositories.NewScheduledMessageRepository(conn)
+
+	msg := persistence.ScheduledMessage{
+		Id:       uuid.New(),
+		ChatUser: user,
+		Room:     room,
+		Message:  "my-scheduled-message-" + uuid.NewString(),
+		SendAt:   time.Now().Add(time.Hour),
+	}
+	out, err := repo.Create(context.Background(), msg)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return out
+}
@@ -17,23 +17,23 @@ func UserEndpoints(service service.UserService) rest.Routes {
 	var out rest.Routes
 
 	postHandler := createComponentAwareHttpHandler(createUser, service)
-	post := rest.NewRoute(http.MethodPost, "/users", postHandler)
+	post := newRoute(http.MethodPost, "/users", postHandler)
 	out = append(out, post)
 
 	getHandler := createComponentAwareHttpHandler(getUser, service)
-	get := rest.NewRoute(http.MethodGet, "/users/:id", getHandler)
+	get := newRoute(http.MethodGet, "/users/:id", getHandler)
 	out = append(out, get)
 
 	listHandler := createComponentAwareHttpHandler(listUsers, service)
-	list := rest.NewRoute(http.MethodGet, "/users", listHandler)
+	list := newRoute(http.MethodGet, "/users", listHandler)
 	out = append(out, list)
 
 	listForUserHandler := createComponentAwareHttpHandler(listForUser, service)
-	listForUser := rest.NewRoute(http.MethodGet, "/users/:id/rooms", listForUserHandler)
+	listForUser := newRoute(http.MethodGet, "/users/:id/rooms", listForUserHandler)
 	out = append(out, listForUser)
 
 	deleteHandler := createComponentAwareHttpHandler(deleteUser, service)
-	delete := rest.NewRoute(http.MethodDelete, "/users/:id", deleteHandler)
+	delete := newRoute(http.MethodDelete, "/users/:id", deleteHandler)
 	out = append(out, delete)
 
 	return out
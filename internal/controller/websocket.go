@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/internal/frames"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/clients"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/encoders"
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+)
+
+func serveWebSocket(c *echo.Context, s service.MessageService) error {
+	maybeId := c.Param("id")
+	id, err := uuid.Parse(maybeId)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid id syntax")
+	}
+
+	encoder, err := encoders.FromName(c.QueryParam(encodingQueryParam))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "Invalid encoding")
+	}
+
+	conn, err := websocket.Accept(c.Response(), c.Request(), nil)
+	if err != nil {
+		// Accept already answered the client.
+		return nil
+	}
+	defer conn.CloseNow()
+
+	// The request context is not cancelled when the client goes away once
+	// the connection is hijacked: the read loop takes care of it.
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		readFramesUntilClosed(ctx, conn, id, s, encoder)
+	}()
+
+	err = s.ServeEvents(ctx, id, clients.NewWebSocketCallback(conn, encoder))
+	if errors.IsErrorWithCode(err, service.ErrShuttingDown) {
+		conn.Close(websocket.StatusTryAgainLater, "Server is shutting down")
+	} else if err != nil {
+		conn.Close(websocket.StatusInternalError, "")
+	} else {
+		conn.Close(websocket.StatusNormalClosure, "")
+	}
+
+	wg.Wait()
+
+	// The connection is hijacked so there is no response to write.
+	return nil
+}
+
+func readFramesUntilClosed(
+	ctx context.Context,
+	conn *websocket.Conn,
+	user uuid.UUID,
+	s service.MessageService,
+	encoder encoders.Encoder,
+) {
+	// The replies use the same encoding as the events. The frames of the
+	// client are always JSON.
+	frameType := clients.WebSocketFrameType(encoder)
+
+	for {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+
+		reply := handleFrame(ctx, user, s, data)
+
+		payload, err := encoder.Marshal(reply)
+		if err != nil {
+			return
+		}
+
+		err = conn.Write(ctx, frameType, payload)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func handleFrame(
+	ctx context.Context,
+	user uuid.UUID,
+	s service.MessageService,
+	data []byte,
+) communication.ReplyDtoResponse {
+	out := communication.ReplyDtoResponse{
+		Type: communication.ReplyFrame,
+	}
+
+	var frame communication.FrameDtoRequest
+	err := json.Unmarshal(data, &frame)
+	if err != nil {
+		out.Status, out.Data = http.StatusBadRequest, "Invalid frame syntax"
+		return out
+	}
+
+	out.Ref = frame.Ref
+	out.Status, out.Data = frames.Dispatch(ctx, user, s, frame)
+
+	return out
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/clients"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_HandleFrame_WhenFrameHasWrongSyntax_ExpectBadRequest(t *testing.T) {
+	out := handleFrame(context.Background(), uuid.New(), nil, []byte("not-a-frame"))
+
+	expected := communication.ReplyDtoResponse{
+		Type:   communication.ReplyFrame,
+		Status: http.StatusBadRequest,
+		Data:   "Invalid frame syntax",
+	}
+	assert.Equal(t, expected, out)
+}
+
+func TestUnit_HandleFrame_WhenTypeIsUnknown_ExpectBadRequest(t *testing.T) {
+	frame := []byte(`{"ref":"abc","type":"unknown","data":{}}`)
+
+	out := handleFrame(context.Background(), uuid.New(), nil, frame)
+
+	expected := communication.ReplyDtoResponse{
+		Ref:    "abc",
+		Type:   communication.ReplyFrame,
+		Status: http.StatusBadRequest,
+		Data:   "Unknown frame type",
+	}
+	assert.Equal(t, expected, out)
+}
+
+func TestUnit_HandleFrame_WhenDataHasWrongSyntax_ExpectBadRequest(t *testing.T) {
+	frame := []byte(`{"ref":"abc","type":"message","data":"not-a-message"}`)
+
+	out := handleFrame(context.Background(), uuid.New(), nil, frame)
+
+	expected := communication.ReplyDtoResponse{
+		Ref:    "abc",
+		Type:   communication.ReplyFrame,
+		Status: http.StatusBadRequest,
+		Data:   "Invalid message syntax",
+	}
+	assert.Equal(t, expected, out)
+}
+
+func TestIT_ChatsController_ServeWebSocket_WhenIdHasWrongSyntax_ExpectBadRequest(t *testing.T) {
+	service, dbConn, _ := newTestMessageService(t)
+	defer dbConn.Close(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx, rw := generateTestEchoContextFromRequest(req)
+	ctx.SetPathValues([]echo.PathValue{{Name: "id", Value: "not-a-uuid"}})
+
+	err := serveWebSocket(ctx, service)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+	expectedBody := []byte("\"Invalid id syntax\"\n")
+	assert.Equal(
+		t,
+		expectedBody,
+		rw.Body.Bytes(),
+		"Actual body: %s",
+		rw.Body.String(),
+	)
+}
+
+func TestIT_ChatsController_ServeWebSocket_ReceivesTypingEvent(t *testing.T) {
+	dbConn := newTestDbConnection(t)
+	defer dbConn.Close(context.Background())
+	repos := repositories.New(dbConn)
+	manager := clients.NewManager(time.Minute, clients.PollPolicy{}, repos)
+	opts := service.MessageServiceOpts{
+		DbConn:                 dbConn,
+		Repos:                  repos,
+		Manager:                manager,
+		Dispatcher:             manager,
+		ClientMessageQueueSize: 1,
+	}
+	service := service.NewMessageService(opts)
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	e := echo.New()
+	e.GET("/users/:id/ws", func(c *echo.Context) error {
+		return serveWebSocket(c, service)
+	})
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	url := fmt.Sprintf("ws%s/users/%s/ws", strings.TrimPrefix(server.URL, "http"), user.Id)
+	conn, _, err := websocket.Dial(ctx, url, nil)
+	assert.Nil(t, err, "Actual err: %v", err)
+	defer conn.CloseNow()
+
+	// Wait for the client to be registered
+	time.Sleep(50 * time.Millisecond)
+
+	frame := fmt.Sprintf(`{"ref":"abc","type":"typing","data":{"room":"%s"}}`, room.Id)
+	err = conn.Write(ctx, websocket.MessageText, []byte(frame))
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	// The event and the reply can be received in any order.
+	received := map[string]json.RawMessage{}
+	for range 2 {
+		_, data, err := conn.Read(ctx)
+		assert.Nil(t, err, "Actual err: %v", err)
+
+		var frame struct {
+			Type string          `json:"type"`
+			Ref  string          `json:"ref"`
+			Data json.RawMessage `json:"data"`
+		}
+		err = json.Unmarshal(data, &frame)
+		assert.Nil(t, err, "Actual err: %v", err)
+		received[frame.Type] = data
+	}
+
+	expectedReply := `{"ref":"abc","type":"reply","status":204}`
+	assert.Equal(t, expectedReply, string(received["reply"]))
+	expectedData := fmt.Sprintf(`"data":{"room":"%s","user":"%s"}`, room.Id, user.Id)
+	assert.Contains(t, string(received["typing"]), expectedData)
+
+	err = conn.Close(websocket.StatusNormalClosure, "")
+	assert.Nil(t, err, "Actual err: %v", err)
+}
@@ -0,0 +1,85 @@
+package frames
+
+import (
+	"net/http"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+)
+
+// PostMessageError maps the errors of the message service to the status
+// and body returned to the client. The REST endpoints use it as well so
+// that the frames are answered the same way.
+func PostMessageError(err error) (int, any) {
+	if errors.IsErrorWithCode(err, service.ErrEmptyMessage) {
+		return http.StatusBadRequest, "Invalid empty message"
+	} else if errors.IsErrorWithCode(err, service.ErrUserNotInRoom) {
+		return http.StatusBadRequest, "User is not registered in the room"
+	} else if errors.IsErrorWithCode(err, service.ErrInvalidAttachment) {
+		return http.StatusBadRequest, "Invalid attachment"
+	} else if errors.IsErrorWithCode(err, service.ErrInvalidTtl) {
+		return http.StatusBadRequest, "Invalid ttl"
+	} else if errors.IsErrorWithCode(err, service.ErrInvalidIdempotencyKey) {
+		return http.StatusBadRequest, "Invalid idempotency key"
+	} else if errors.IsErrorWithCode(err, service.ErrShuttingDown) {
+		return http.StatusServiceUnavailable, "Server is shutting down"
+	}
+
+	return http.StatusInternalServerError, err
+}
+
+// ScheduleMessageError is the equivalent of PostMessageError for the
+// messages sent later.
+func ScheduleMessageError(err error) (int, any) {
+	if errors.IsErrorWithCode(err, service.ErrInvalidSendAt) {
+		return http.StatusBadRequest, "Send time is not in the future"
+	} else if errors.IsErrorWithCode(err, service.ErrEmptyMessage) {
+		return http.StatusBadRequest, "Invalid empty message"
+	} else if errors.IsErrorWithCode(err, service.ErrUserNotInRoom) {
+		return http.StatusBadRequest, "User is not registered in the room"
+	} else if errors.IsErrorWithCode(err, service.ErrInvalidAttachment) {
+		return http.StatusBadRequest, "Scheduled messages can't have attachments"
+	} else if errors.IsErrorWithCode(err, service.ErrInvalidTtl) {
+		return http.StatusBadRequest, "Scheduled messages can't define a ttl"
+	} else if errors.IsErrorWithCode(err, service.ErrShuttingDown) {
+		return http.StatusServiceUnavailable, "Server is shutting down"
+	}
+
+	return http.StatusInternalServerError, err
+}
+
+// TypingError maps the errors of the typing notifications.
+func TypingError(err error) (int, any) {
+	if errors.IsErrorWithCode(err, service.ErrUserNotInRoom) {
+		return http.StatusBadRequest, "User is not registered in the room"
+	}
+
+	return http.StatusInternalServerError, err
+}
+
+// MarkAsReadError maps the errors of the read markers.
+func MarkAsReadError(err error) (int, any) {
+	if errors.IsErrorWithCode(err, service.ErrUserNotInRoom) {
+		return http.StatusBadRequest, "User is not registered in the room"
+	} else if errors.IsErrorWithCode(err, service.ErrMessageNotInRoom) {
+		return http.StatusNotFound, "No such message in the room"
+	}
+
+	return http.StatusInternalServerError, err
+}
+
+// RegistrationError maps the errors of the registration service.
+func RegistrationError(err error) (int, any) {
+	if errors.IsErrorWithCode(err, repositories.ErrNoSuchUser) {
+		return http.StatusBadRequest, "Invalid user id"
+	}
+	if errors.IsErrorWithCode(err, repositories.ErrNoSuchRoom) {
+		return http.StatusBadRequest, "Invalid room id"
+	}
+	if errors.IsErrorWithCode(err, repositories.ErrUserAlreadyRegisteredInRoom) {
+		return http.StatusConflict, "User already registered in room"
+	}
+
+	return http.StatusInternalServerError, err
+}
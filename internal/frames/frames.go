@@ -0,0 +1,89 @@
+package frames
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/google/uuid"
+)
+
+// Dispatch runs the action described by the frame on behalf of the
+// connected user. The user set in the frame is ignored. It returns the
+// status and the body the equivalent HTTP endpoint would answer with.
+func Dispatch(
+	ctx context.Context,
+	user uuid.UUID,
+	s service.MessageService,
+	frame communication.FrameDtoRequest,
+) (int, any) {
+	switch frame.Type {
+	case communication.MessageFrame:
+		var messageDtoRequest communication.MessageDtoRequest
+		if err := json.Unmarshal(frame.Data, &messageDtoRequest); err != nil {
+			return http.StatusBadRequest, "Invalid message syntax"
+		}
+		messageDtoRequest.User = user
+
+		if messageDtoRequest.SendAt != nil {
+			out, err := s.ScheduleMessage(ctx, messageDtoRequest)
+			if err != nil {
+				return ScheduleMessageError(err)
+			}
+			return http.StatusCreated, out
+		}
+
+		out, err := s.PostMessage(ctx, messageDtoRequest)
+		if err != nil {
+			return PostMessageError(err)
+		}
+		return http.StatusAccepted, out
+
+	case communication.TypingFrame:
+		var typingDtoRequest communication.TypingDtoRequest
+		if err := json.Unmarshal(frame.Data, &typingDtoRequest); err != nil {
+			return http.StatusBadRequest, "Invalid typing syntax"
+		}
+		typingDtoRequest.User = user
+
+		if err := s.Typing(ctx, typingDtoRequest); err != nil {
+			return TypingError(err)
+		}
+		return http.StatusNoContent, nil
+
+	case communication.ReadFrame:
+		var markerDtoRequest communication.ReadMarkerDtoRequest
+		if err := json.Unmarshal(frame.Data, &markerDtoRequest); err != nil {
+			return http.StatusBadRequest, "Invalid read marker syntax"
+		}
+		markerDtoRequest.User = user
+
+		if err := s.MarkAsRead(ctx, markerDtoRequest); err != nil {
+			return MarkAsReadError(err)
+		}
+		return http.StatusNoContent, nil
+	}
+
+	return http.StatusBadRequest, "Unknown frame type"
+}
+
+// DispatchJoin registers the connected user in the room described by the
+// frame. It is used by the transports where users join rooms themselves.
+func DispatchJoin(
+	ctx context.Context,
+	user uuid.UUID,
+	s service.RegistrationService,
+	frame communication.FrameDtoRequest,
+) (int, any) {
+	var joinDtoRequest communication.JoinDtoRequest
+	if err := json.Unmarshal(frame.Data, &joinDtoRequest); err != nil {
+		return http.StatusBadRequest, "Invalid registration syntax"
+	}
+
+	if err := s.RegisterUserInRoom(ctx, user, joinDtoRequest.Room); err != nil {
+		return RegistrationError(err)
+	}
+	return http.StatusNoContent, nil
+}
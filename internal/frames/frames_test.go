@@ -0,0 +1,54 @@
+package frames
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_Dispatch_WhenFrameTypeIsUnknown_ExpectBadRequest(t *testing.T) {
+	frame := communication.FrameDtoRequest{Type: "unknown"}
+
+	status, data := Dispatch(context.Background(), uuid.New(), nil, frame)
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "Unknown frame type", data)
+}
+
+func TestUnit_Dispatch_WhenMessageHasWrongSyntax_ExpectBadRequest(t *testing.T) {
+	frame := communication.FrameDtoRequest{
+		Type: communication.MessageFrame,
+		Data: json.RawMessage(`"not-a-message"`),
+	}
+
+	status, data := Dispatch(context.Background(), uuid.New(), nil, frame)
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "Invalid message syntax", data)
+}
+
+func TestUnit_DispatchJoin_WhenFrameHasWrongSyntax_ExpectBadRequest(t *testing.T) {
+	frame := communication.FrameDtoRequest{
+		Type: communication.JoinFrame,
+		Data: json.RawMessage(`"not-a-room"`),
+	}
+
+	status, data := DispatchJoin(context.Background(), uuid.New(), nil, frame)
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "Invalid registration syntax", data)
+}
+
+func TestUnit_PostMessageError_WhenShuttingDown_ExpectServiceUnavailable(t *testing.T) {
+	status, data := PostMessageError(errors.NewCode(service.ErrShuttingDown))
+
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, "Server is shutting down", data)
+}
@@ -0,0 +1,9 @@
+package gateway
+
+import "github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+
+const (
+	ErrListenFailed errors.ErrorCode = 1000
+	ErrAcceptFailed errors.ErrorCode = 1001
+	ErrServeFailed  errors.ErrorCode = 1002
+)
@@ -0,0 +1,530 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/pgx"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/chatrpc"
+	"github.com/Knoblauchpilze/chat-server/pkg/clients"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/Knoblauchpilze/chat-server/pkg/storage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcApiImpl implements the service described in chat.proto. The errors
+// of the services are translated to gRPC status codes by the interceptors
+// of the server.
+type grpcApiImpl struct {
+	chatrpc.UnimplementedChatServiceServer
+
+	services service.Services
+}
+
+func registerGrpcApi(server *grpc.Server, services service.Services) {
+	chatrpc.RegisterChatServiceServer(server, &grpcApiImpl{services: services})
+}
+
+func (a *grpcApiImpl) CreateUser(
+	ctx context.Context, in *chatrpc.UserDtoRequest,
+) (*chatrpc.UserDtoResponse, error) {
+	userDto, err := fromGrpcUserDtoRequest(in)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := a.services.User.Create(ctx, userDto)
+	return reply(user, err, toGrpcUserDtoResponse)
+}
+
+func (a *grpcApiImpl) GetUser(
+	ctx context.Context, in *chatrpc.IdDtoRequest,
+) (*chatrpc.UserDtoResponse, error) {
+	id, err := fromGrpcId("id", in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := a.services.User.Get(ctx, id)
+	return reply(user, err, toGrpcUserDtoResponse)
+}
+
+func (a *grpcApiImpl) GetUserByName(
+	ctx context.Context, in *chatrpc.NameDtoRequest,
+) (*chatrpc.UserDtoResponse, error) {
+	user, err := a.services.User.GetByName(ctx, in.Name)
+	return reply(user, err, toGrpcUserDtoResponse)
+}
+
+func (a *grpcApiImpl) ListRoomsForUser(
+	ctx context.Context, in *chatrpc.IdDtoRequest,
+) (*chatrpc.RoomsDtoResponse, error) {
+	id, err := fromGrpcId("id", in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	rooms, err := a.services.User.ListForUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &chatrpc.RoomsDtoResponse{Rooms: toGrpcList(rooms, toGrpcRoomDtoResponse)}, nil
+}
+
+func (a *grpcApiImpl) DeleteUser(
+	ctx context.Context, in *chatrpc.IdDtoRequest,
+) (*chatrpc.EmptyDtoResponse, error) {
+	id, err := fromGrpcId("id", in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return empty(a.services.User.Delete(ctx, id))
+}
+
+func (a *grpcApiImpl) CreateRoom(
+	ctx context.Context, in *chatrpc.RoomDtoRequest,
+) (*chatrpc.RoomDtoResponse, error) {
+	room, err := a.services.Room.Create(ctx, fromGrpcRoomDtoRequest(in))
+	return reply(room, err, toGrpcRoomDtoResponse)
+}
+
+func (a *grpcApiImpl) GetRoom(
+	ctx context.Context, in *chatrpc.IdDtoRequest,
+) (*chatrpc.RoomDtoResponse, error) {
+	id, err := fromGrpcId("id", in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := a.services.Room.Get(ctx, id)
+	return reply(room, err, toGrpcRoomDtoResponse)
+}
+
+func (a *grpcApiImpl) ListRooms(
+	ctx context.Context, in *chatrpc.EmptyDtoResponse,
+) (*chatrpc.RoomsDtoResponse, error) {
+	rooms, err := a.services.Room.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &chatrpc.RoomsDtoResponse{Rooms: toGrpcList(rooms, toGrpcRoomDtoResponse)}, nil
+}
+
+func (a *grpcApiImpl) ListUsersForRoom(
+	ctx context.Context, in *chatrpc.IdDtoRequest,
+) (*chatrpc.UsersDtoResponse, error) {
+	id, err := fromGrpcId("id", in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := a.services.Room.ListUserForRoom(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &chatrpc.UsersDtoResponse{Users: toGrpcList(users, toGrpcUserDtoResponse)}, nil
+}
+
+func (a *grpcApiImpl) ListMessagesForRoom(
+	ctx context.Context, in *chatrpc.RoomMessagesDtoRequest,
+) (*chatrpc.MessagesDtoResponse, error) {
+	room, err := fromGrpcId("room", in.Room)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := a.services.Room.ListMessageForRoom(ctx, room, in.From, in.To)
+	if err != nil {
+		return nil, err
+	}
+	out := &chatrpc.MessagesDtoResponse{
+		Messages: toGrpcList(messages, toGrpcMessageDtoResponse),
+	}
+	return out, nil
+}
+
+func (a *grpcApiImpl) DeleteRoom(
+	ctx context.Context, in *chatrpc.IdDtoRequest,
+) (*chatrpc.EmptyDtoResponse, error) {
+	id, err := fromGrpcId("id", in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return empty(a.services.Room.Delete(ctx, id))
+}
+
+func (a *grpcApiImpl) RegisterUserInRoom(
+	ctx context.Context, in *chatrpc.RegistrationDtoRequest,
+) (*chatrpc.EmptyDtoResponse, error) {
+	var f grpcFields
+	user, room := f.id("user", in.User), f.id("room", in.Room)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return empty(a.services.Registration.RegisterUserInRoom(ctx, user, room))
+}
+
+func (a *grpcApiImpl) UnregisterUserInRoom(
+	ctx context.Context, in *chatrpc.RegistrationDtoRequest,
+) (*chatrpc.EmptyDtoResponse, error) {
+	var f grpcFields
+	user, room := f.id("user", in.User), f.id("room", in.Room)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return empty(a.services.Registration.UnregisterUserInRoom(ctx, user, room))
+}
+
+func (a *grpcApiImpl) SetModerator(
+	ctx context.Context, in *chatrpc.RegistrationDtoRequest,
+) (*chatrpc.EmptyDtoResponse, error) {
+	var f grpcFields
+	caller, user, room := f.id("caller", in.Caller), f.id("user", in.User), f.id("room", in.Room)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return empty(a.services.Registration.SetModerator(ctx, caller, user, room, in.Moderator))
+}
+
+func (a *grpcApiImpl) PostMessage(
+	ctx context.Context, in *chatrpc.MessageDtoRequest,
+) (*chatrpc.MessageStatusDtoResponse, error) {
+	messageDto, err := fromGrpcMessageDtoRequest(in)
+	if err != nil {
+		return nil, err
+	}
+
+	messageStatus, err := a.services.Message.PostMessage(ctx, messageDto)
+	return reply(messageStatus, err, toGrpcMessageStatusDtoResponse)
+}
+
+func (a *grpcApiImpl) ScheduleMessage(
+	ctx context.Context, in *chatrpc.MessageDtoRequest,
+) (*chatrpc.ScheduledMessageDtoResponse, error) {
+	messageDto, err := fromGrpcMessageDtoRequest(in)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := a.services.Message.ScheduleMessage(ctx, messageDto)
+	return reply(message, err, toGrpcScheduledMessageDtoResponse)
+}
+
+func (a *grpcApiImpl) GetMessageStatus(
+	ctx context.Context, in *chatrpc.IdDtoRequest,
+) (*chatrpc.MessageStatusDtoResponse, error) {
+	id, err := fromGrpcId("id", in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	messageStatus, err := a.services.Message.GetStatus(ctx, id)
+	return reply(messageStatus, err, toGrpcMessageStatusDtoResponse)
+}
+
+func (a *grpcApiImpl) Typing(
+	ctx context.Context, in *chatrpc.TypingDtoRequest,
+) (*chatrpc.EmptyDtoResponse, error) {
+	var f grpcFields
+	typingDto := communication.TypingDtoRequest{
+		User: f.id("user", in.User),
+		Room: f.id("room", in.Room),
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return empty(a.services.Message.Typing(ctx, typingDto))
+}
+
+func (a *grpcApiImpl) MarkAsRead(
+	ctx context.Context, in *chatrpc.ReadMarkerDtoRequest,
+) (*chatrpc.EmptyDtoResponse, error) {
+	var f grpcFields
+	markerDto := communication.ReadMarkerDtoRequest{
+		User:    f.id("user", in.User),
+		Room:    f.id("room", in.Room),
+		Message: f.id("message", in.Message),
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return empty(a.services.Message.MarkAsRead(ctx, markerDto))
+}
+
+func (a *grpcApiImpl) ListScheduledMessagesForRoom(
+	ctx context.Context, in *chatrpc.IdDtoRequest,
+) (*chatrpc.ScheduledMessagesDtoResponse, error) {
+	id, err := fromGrpcId("id", in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := a.services.ScheduledMessage.ListForRoom(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	out := &chatrpc.ScheduledMessagesDtoResponse{
+		Messages: toGrpcList(messages, toGrpcScheduledMessageDtoResponse),
+	}
+	return out, nil
+}
+
+func (a *grpcApiImpl) UpdateScheduledMessage(
+	ctx context.Context, in *chatrpc.ScheduledMessageUpdateDtoRequest,
+) (*chatrpc.ScheduledMessageDtoResponse, error) {
+	id, messageDto, err := fromGrpcScheduledMessageUpdateDtoRequest(in)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := a.services.ScheduledMessage.Update(ctx, id, messageDto)
+	return reply(message, err, toGrpcScheduledMessageDtoResponse)
+}
+
+func (a *grpcApiImpl) CancelScheduledMessage(
+	ctx context.Context, in *chatrpc.UserIdDtoRequest,
+) (*chatrpc.EmptyDtoResponse, error) {
+	var f grpcFields
+	user, id := f.id("user", in.User), f.id("id", in.Id)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return empty(a.services.ScheduledMessage.Cancel(ctx, user, id))
+}
+
+func (a *grpcApiImpl) PinMessage(
+	ctx context.Context, in *chatrpc.PinDtoRequest,
+) (*chatrpc.PinDtoResponse, error) {
+	var f grpcFields
+	user, room, message := f.id("user", in.User), f.id("room", in.Room), f.id("message", in.Message)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	pin, err := a.services.Pin.Pin(ctx, user, room, message)
+	return reply(pin, err, toGrpcPinDtoResponse)
+}
+
+func (a *grpcApiImpl) UnpinMessage(
+	ctx context.Context, in *chatrpc.PinDtoRequest,
+) (*chatrpc.EmptyDtoResponse, error) {
+	var f grpcFields
+	user, room, message := f.id("user", in.User), f.id("room", in.Room), f.id("message", in.Message)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return empty(a.services.Pin.Unpin(ctx, user, room, message))
+}
+
+func (a *grpcApiImpl) ListPinsForRoom(
+	ctx context.Context, in *chatrpc.IdDtoRequest,
+) (*chatrpc.PinsDtoResponse, error) {
+	id, err := fromGrpcId("id", in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	pins, err := a.services.Pin.ListForRoom(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &chatrpc.PinsDtoResponse{Pins: toGrpcList(pins, toGrpcPinDtoResponse)}, nil
+}
+
+func (a *grpcApiImpl) UploadAttachment(
+	ctx context.Context, in *chatrpc.AttachmentUploadDtoRequest,
+) (*chatrpc.AttachmentDtoResponse, error) {
+	var f grpcFields
+	attachmentDto := communication.AttachmentDtoRequest{
+		User: f.id("user", in.User),
+		Room: f.id("room", in.Room),
+		Name: in.Name,
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	attachment, err := a.services.Attachment.Upload(ctx, attachmentDto, bytes.NewReader(in.Data))
+	return reply(attachment, err, toGrpcAttachmentDtoResponse)
+}
+
+func (a *grpcApiImpl) DownloadAttachment(
+	ctx context.Context, in *chatrpc.UserIdDtoRequest,
+) (*chatrpc.AttachmentContentDtoResponse, error) {
+	var f grpcFields
+	user, id := f.id("user", in.User), f.id("id", in.Id)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	attachment, content, err := a.services.Attachment.Download(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatrpc.AttachmentContentDtoResponse{
+		Attachment: toGrpcAttachmentDtoResponse(attachment),
+		Data:       data,
+	}, nil
+}
+
+func (a *grpcApiImpl) DownloadThumbnail(
+	ctx context.Context, in *chatrpc.UserIdDtoRequest,
+) (*chatrpc.ThumbnailDtoResponse, error) {
+	var f grpcFields
+	user, id := f.id("user", in.User), f.id("id", in.Id)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	content, err := a.services.Attachment.DownloadThumbnail(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatrpc.ThumbnailDtoResponse{Data: data}, nil
+}
+
+// Subscribe streams the events of the user until the client goes away or
+// the server shuts down.
+func (a *grpcApiImpl) Subscribe(
+	in *chatrpc.IdDtoRequest, stream grpc.ServerStreamingServer[chatrpc.EventDtoResponse],
+) error {
+	user, err := fromGrpcId("id", in.Id)
+	if err != nil {
+		return err
+	}
+
+	callback := func(event events.Event) error {
+		out, err := toGrpcEventDtoResponse(event)
+		if err != nil {
+			return err
+		}
+		return stream.Send(out)
+	}
+
+	return a.services.Message.ServeEvents(stream.Context(), user, callback)
+}
+
+func reply[Dto any, Response any](
+	dto Dto, err error, convert func(Dto) *Response,
+) (*Response, error) {
+	if err != nil {
+		return nil, err
+	}
+	return convert(dto), nil
+}
+
+func empty(err error) (*chatrpc.EmptyDtoResponse, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &chatrpc.EmptyDtoResponse{}, nil
+}
+
+// grpcUnaryErrorInterceptor and grpcStreamErrorInterceptor translate the
+// errors of the services for all the methods of the API.
+func grpcUnaryErrorInterceptor(
+	ctx context.Context,
+	req any,
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	out, err := handler(ctx, req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return out, nil
+}
+
+func grpcStreamErrorInterceptor(
+	srv any,
+	stream grpc.ServerStream,
+	_ *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if err := handler(srv, stream); err != nil {
+		return grpcError(err)
+	}
+	return nil
+}
+
+var grpcErrorCodes = []struct {
+	err  errors.ErrorCode
+	code codes.Code
+}{
+	{err: db.NoMatchingRows, code: codes.NotFound},
+	{err: pgx.UniqueConstraintViolation, code: codes.AlreadyExists},
+	{err: service.ErrInvalidName, code: codes.InvalidArgument},
+	{err: service.ErrEmptyMessage, code: codes.InvalidArgument},
+	{err: service.ErrUserNotInRoom, code: codes.InvalidArgument},
+	{err: service.ErrAttachmentTooLarge, code: codes.InvalidArgument},
+	{err: service.ErrUnsupportedMimeType, code: codes.InvalidArgument},
+	{err: service.ErrInvalidAttachment, code: codes.InvalidArgument},
+	{err: service.ErrNoThumbnail, code: codes.NotFound},
+	{err: service.ErrNotModerator, code: codes.PermissionDenied},
+	{err: service.ErrTooManyPins, code: codes.FailedPrecondition},
+	{err: service.ErrInvalidSendAt, code: codes.InvalidArgument},
+	{err: service.ErrNotMessageAuthor, code: codes.PermissionDenied},
+	{err: service.ErrInvalidTtl, code: codes.InvalidArgument},
+	{err: service.ErrInvalidIdempotencyKey, code: codes.InvalidArgument},
+	{err: service.ErrShuttingDown, code: codes.Unavailable},
+	{err: service.ErrInvalidSequenceRange, code: codes.InvalidArgument},
+	{err: service.ErrMessageNotInRoom, code: codes.NotFound},
+	{err: service.ErrLeavingRoomIsNotAllowed, code: codes.PermissionDenied},
+	{err: repositories.ErrNoSuchUser, code: codes.InvalidArgument},
+	{err: repositories.ErrNoSuchRoom, code: codes.InvalidArgument},
+	{err: repositories.ErrUserAlreadyRegisteredInRoom, code: codes.AlreadyExists},
+	{err: repositories.ErrUserNotRegisteredInRoom, code: codes.NotFound},
+	{err: repositories.ErrMessageAlreadyPinned, code: codes.AlreadyExists},
+	{err: storage.ErrNoSuchBlob, code: codes.NotFound},
+	{err: clients.ErrClientAlreadyRegistered, code: codes.AlreadyExists},
+	{err: clients.ErrInvalidCursor, code: codes.InvalidArgument},
+}
+
+// grpcError keeps the message of the service error, which includes its
+// code, so that the clients can tell the errors apart like with the REST
+// API. Errors which already carry a status, like the ones of the streams,
+// are returned as is.
+func grpcError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	for _, mapping := range grpcErrorCodes {
+		if errors.IsErrorWithCode(err, mapping.err) {
+			return status.Error(mapping.code, err.Error())
+		}
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,251 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/chatrpc"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// grpcFields parses the fields of the protobuf requests, which hold the
+// identifiers and the times as strings, and keeps the first error so that
+// a request is converted in one go. Empty fields are the zero value like
+// missing fields in the JSON of the REST API.
+type grpcFields struct {
+	err error
+}
+
+func (f *grpcFields) id(name string, value string) uuid.UUID {
+	if value == "" || f.err != nil {
+		return uuid.Nil
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		f.fail(name, err)
+	}
+	return id
+}
+
+func (f *grpcFields) ids(name string, values []string) []uuid.UUID {
+	var out []uuid.UUID
+	for _, value := range values {
+		out = append(out, f.id(name, value))
+	}
+	return out
+}
+
+func (f *grpcFields) optionalTime(name string, value *string) *time.Time {
+	if value == nil || f.err != nil {
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		f.fail(name, err)
+		return nil
+	}
+	return &t
+}
+
+func (f *grpcFields) fail(name string, err error) {
+	f.err = status.Error(codes.InvalidArgument, fmt.Sprintf("Invalid %s: %v", name, err))
+}
+
+func fromGrpcId(name string, value string) (uuid.UUID, error) {
+	var f grpcFields
+	id := f.id(name, value)
+	return id, f.err
+}
+
+func optionalInt(value *int32) *int {
+	if value == nil {
+		return nil
+	}
+	out := int(*value)
+	return &out
+}
+
+func optionalInt32(value *int) *int32 {
+	if value == nil {
+		return nil
+	}
+	out := int32(*value)
+	return &out
+}
+
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	out := formatTime(*t)
+	return &out
+}
+
+func toGrpcList[In any, Out any](in []In, convert func(In) *Out) []*Out {
+	out := make([]*Out, 0, len(in))
+	for _, element := range in {
+		out = append(out, convert(element))
+	}
+	return out
+}
+
+func fromGrpcUserDtoRequest(in *chatrpc.UserDtoRequest) (communication.UserDtoRequest, error) {
+	var f grpcFields
+	out := communication.UserDtoRequest{
+		Name:    in.Name,
+		ApiUser: f.id("api_user", in.ApiUser),
+	}
+	return out, f.err
+}
+
+func toGrpcUserDtoResponse(user communication.UserDtoResponse) *chatrpc.UserDtoResponse {
+	return &chatrpc.UserDtoResponse{
+		Id:        user.Id.String(),
+		Name:      user.Name,
+		ApiUser:   user.ApiUser.String(),
+		CreatedAt: formatTime(user.CreatedAt),
+	}
+}
+
+func fromGrpcRoomDtoRequest(in *chatrpc.RoomDtoRequest) communication.RoomDtoRequest {
+	return communication.RoomDtoRequest{
+		Name:       in.Name,
+		MessageTtl: optionalInt(in.MessageTtl),
+	}
+}
+
+func toGrpcRoomDtoResponse(room communication.RoomDtoResponse) *chatrpc.RoomDtoResponse {
+	return &chatrpc.RoomDtoResponse{
+		Id:         room.Id.String(),
+		Name:       room.Name,
+		MessageTtl: optionalInt32(room.MessageTtl),
+		CreatedAt:  formatTime(room.CreatedAt),
+	}
+}
+
+func fromGrpcMessageDtoRequest(in *chatrpc.MessageDtoRequest) (communication.MessageDtoRequest, error) {
+	var f grpcFields
+	out := communication.MessageDtoRequest{
+		User:           f.id("user", in.User),
+		Room:           f.id("room", in.Room),
+		Message:        in.Message,
+		Attachments:    f.ids("attachments", in.Attachments),
+		SendAt:         f.optionalTime("send_at", in.SendAt),
+		Ttl:            optionalInt(in.Ttl),
+		IdempotencyKey: in.IdempotencyKey,
+	}
+	return out, f.err
+}
+
+func toGrpcMessageDtoResponse(message communication.MessageDtoResponse) *chatrpc.MessageDtoResponse {
+	return &chatrpc.MessageDtoResponse{
+		Id:             message.Id.String(),
+		User:           message.User.String(),
+		Room:           message.Room.String(),
+		Message:        message.Message,
+		Attachments:    toGrpcList(message.Attachments, toGrpcAttachmentDtoResponse),
+		Sequence:       message.Sequence,
+		CreatedAt:      formatTime(message.CreatedAt),
+		ExpiresAt:      formatOptionalTime(message.ExpiresAt),
+		IdempotencyKey: message.IdempotencyKey,
+	}
+}
+
+func toGrpcMessageStatusDtoResponse(
+	messageStatus communication.MessageStatusDtoResponse,
+) *chatrpc.MessageStatusDtoResponse {
+	return &chatrpc.MessageStatusDtoResponse{
+		Id:        messageStatus.Id.String(),
+		Room:      messageStatus.Room.String(),
+		Status:    messageStatus.Status,
+		Reason:    messageStatus.Reason,
+		CreatedAt: formatTime(messageStatus.CreatedAt),
+		UpdatedAt: formatTime(messageStatus.UpdatedAt),
+	}
+}
+
+func fromGrpcScheduledMessageUpdateDtoRequest(
+	in *chatrpc.ScheduledMessageUpdateDtoRequest,
+) (uuid.UUID, communication.ScheduledMessageDtoRequest, error) {
+	var f grpcFields
+	id := f.id("id", in.Id)
+	out := communication.ScheduledMessageDtoRequest{
+		User:    f.id("user", in.User),
+		Message: in.Message,
+		SendAt:  f.optionalTime("send_at", in.SendAt),
+	}
+	return id, out, f.err
+}
+
+func toGrpcScheduledMessageDtoResponse(
+	message communication.ScheduledMessageDtoResponse,
+) *chatrpc.ScheduledMessageDtoResponse {
+	return &chatrpc.ScheduledMessageDtoResponse{
+		Id:        message.Id.String(),
+		User:      message.User.String(),
+		Room:      message.Room.String(),
+		Message:   message.Message,
+		SendAt:    formatTime(message.SendAt),
+		CreatedAt: formatTime(message.CreatedAt),
+		UpdatedAt: formatTime(message.UpdatedAt),
+	}
+}
+
+func toGrpcPinDtoResponse(pin communication.PinDtoResponse) *chatrpc.PinDtoResponse {
+	return &chatrpc.PinDtoResponse{
+		Room:     pin.Room.String(),
+		Message:  toGrpcMessageDtoResponse(pin.Message),
+		PinnedBy: pin.PinnedBy.String(),
+		PinnedAt: formatTime(pin.PinnedAt),
+	}
+}
+
+func toGrpcAttachmentDtoResponse(
+	attachment communication.AttachmentDtoResponse,
+) *chatrpc.AttachmentDtoResponse {
+	return &chatrpc.AttachmentDtoResponse{
+		Id:           attachment.Id.String(),
+		User:         attachment.User.String(),
+		Room:         attachment.Room.String(),
+		Name:         attachment.Name,
+		MimeType:     attachment.MimeType,
+		Size:         attachment.Size,
+		Width:        optionalInt32(attachment.Width),
+		Height:       optionalInt32(attachment.Height),
+		HasThumbnail: attachment.HasThumbnail,
+		CreatedAt:    formatTime(attachment.CreatedAt),
+	}
+}
+
+// toGrpcEventDtoResponse sends the data of the event as the JSON value the
+// SSE events of the REST API carry.
+func toGrpcEventDtoResponse(event events.Event) (*chatrpc.EventDtoResponse, error) {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var value structpb.Value
+	if err := value.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	out := &chatrpc.EventDtoResponse{
+		Id:   event.Id.String(),
+		Type: string(event.Type),
+		Data: &value,
+	}
+	return out, nil
+}
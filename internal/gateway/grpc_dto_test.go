@@ -0,0 +1,99 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/chatrpc"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnit_FromGrpcMessageDtoRequest(t *testing.T) {
+	sendAt := "2025-05-04T20:56:16Z"
+	ttl := int32(30)
+	in := chatrpc.MessageDtoRequest{
+		User:        "f2d9ce22-179d-431c-b63d-43d5a8ab5e18",
+		Room:        "111838db-a871-47be-9149-c974fd356316",
+		Message:     "Hello",
+		Attachments: []string{"8f102c70-8eba-4094-bd4d-7f70d71b21f2"},
+		SendAt:      &sendAt,
+		Ttl:         &ttl,
+	}
+
+	actual, err := fromGrpcMessageDtoRequest(&in)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	expectedSendAt := time.Date(2025, 5, 4, 20, 56, 16, 0, time.UTC)
+	expectedTtl := 30
+	expected := communication.MessageDtoRequest{
+		User:        uuid.MustParse("f2d9ce22-179d-431c-b63d-43d5a8ab5e18"),
+		Room:        uuid.MustParse("111838db-a871-47be-9149-c974fd356316"),
+		Message:     "Hello",
+		Attachments: []uuid.UUID{uuid.MustParse("8f102c70-8eba-4094-bd4d-7f70d71b21f2")},
+		SendAt:      &expectedSendAt,
+		Ttl:         &expectedTtl,
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestUnit_FromGrpcMessageDtoRequest_WhenFieldIsInvalid_ExpectInvalidArgument(t *testing.T) {
+	sendAt := "tomorrow"
+	testCases := map[string]*chatrpc.MessageDtoRequest{
+		"room":        {Room: "not-a-uuid"},
+		"attachments": {Attachments: []string{"not-a-uuid"}},
+		"send_at":     {SendAt: &sendAt},
+	}
+
+	for field, in := range testCases {
+		t.Run(field, func(t *testing.T) {
+			_, err := fromGrpcMessageDtoRequest(in)
+
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), "Actual err: %v", err)
+			assert.Contains(t, status.Convert(err).Message(), field)
+		})
+	}
+}
+
+func TestUnit_ToGrpcMessageDtoResponse(t *testing.T) {
+	createdAt := time.Date(2025, 5, 4, 20, 56, 16, 500, time.UTC)
+	message := communication.MessageDtoResponse{
+		Id:        uuid.MustParse("8f102c70-8eba-4094-bd4d-7f70d71b21f2"),
+		User:      uuid.MustParse("f2d9ce22-179d-431c-b63d-43d5a8ab5e18"),
+		Room:      uuid.MustParse("111838db-a871-47be-9149-c974fd356316"),
+		Message:   "Hello",
+		Sequence:  3,
+		CreatedAt: createdAt,
+	}
+
+	actual := toGrpcMessageDtoResponse(message)
+
+	assert.Equal(t, "8f102c70-8eba-4094-bd4d-7f70d71b21f2", actual.Id)
+	assert.Equal(t, "111838db-a871-47be-9149-c974fd356316", actual.Room)
+	assert.Equal(t, int64(3), actual.Sequence)
+	assert.Equal(t, "2025-05-04T20:56:16.0000005Z", actual.CreatedAt)
+	assert.Nil(t, actual.ExpiresAt)
+	assert.Empty(t, actual.Attachments)
+}
+
+func TestUnit_ToGrpcEventDtoResponse_ExpectDataAsJsonValue(t *testing.T) {
+	msg := persistence.Message{
+		Id:       uuid.MustParse("8f102c70-8eba-4094-bd4d-7f70d71b21f2"),
+		ChatUser: uuid.MustParse("f2d9ce22-179d-431c-b63d-43d5a8ab5e18"),
+		Room:     uuid.MustParse("111838db-a871-47be-9149-c974fd356316"),
+		Message:  "Hello",
+	}
+
+	actual, err := toGrpcEventDtoResponse(events.FromMessage(msg))
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, "message", actual.Type)
+	data := actual.Data.GetStructValue().AsMap()
+	assert.Equal(t, "Hello", data["message"])
+	assert.Equal(t, "111838db-a871-47be-9149-c974fd356316", data["room"])
+}
@@ -0,0 +1,122 @@
+package gateway
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"google.golang.org/grpc"
+)
+
+type GrpcConfig struct {
+	Enabled bool
+	Port    uint16
+	// MaxMessageSizeInBytes bounds the requests and the responses, which
+	// carry the content of the attachments. The limits of gRPC apply when
+	// it is not set.
+	MaxMessageSizeInBytes int
+}
+
+// grpcGatewayImpl serves the gRPC API on its own port. Like the other
+// gateways it simply waits to be stopped when disabled.
+type grpcGatewayImpl struct {
+	enabled bool
+	port    uint16
+	server  *grpc.Server
+
+	running atomic.Bool
+	stopped atomic.Bool
+	quit    chan struct{}
+	done    chan struct{}
+
+	lock     sync.Mutex
+	listener net.Listener
+}
+
+// NewGrpcGateway serves the same operations as the REST API and a stream
+// of the events of a user, backed by the same services.
+func NewGrpcGateway(config GrpcConfig, services service.Services) Gateway {
+	return newGrpcGateway(config, services)
+}
+
+func newGrpcGateway(config GrpcConfig, services service.Services) *grpcGatewayImpl {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(grpcUnaryErrorInterceptor),
+		grpc.ChainStreamInterceptor(grpcStreamErrorInterceptor),
+	}
+	// Without a limit the defaults of gRPC apply.
+	if config.MaxMessageSizeInBytes > 0 {
+		opts = append(
+			opts,
+			grpc.MaxRecvMsgSize(config.MaxMessageSizeInBytes),
+			grpc.MaxSendMsgSize(config.MaxMessageSizeInBytes),
+		)
+	}
+
+	server := grpc.NewServer(opts...)
+	registerGrpcApi(server, services)
+
+	return &grpcGatewayImpl{
+		enabled: config.Enabled,
+		port:    config.Port,
+		server:  server,
+
+		quit: make(chan struct{}),
+		done: make(chan struct{}, 1),
+	}
+}
+
+func (g *grpcGatewayImpl) Start() error {
+	if !g.running.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	defer func() {
+		g.done <- struct{}{}
+	}()
+
+	if !g.enabled {
+		<-g.quit
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", g.port))
+	if err != nil {
+		return errors.WrapCode(err, ErrListenFailed)
+	}
+
+	g.lock.Lock()
+	g.listener = listener
+	g.lock.Unlock()
+
+	// Serve returns nil once the server is stopped, and ErrServerStopped
+	// when it was stopped before serving.
+	err = g.server.Serve(listener)
+	if err != nil && err != grpc.ErrServerStopped {
+		return errors.WrapCode(err, ErrServeFailed)
+	}
+
+	return nil
+}
+
+func (g *grpcGatewayImpl) Stop() error {
+	if !g.stopped.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	close(g.quit)
+	// The streams end when the manager stops the clients, which happens
+	// before the gateways are stopped. Serving after this fails right away.
+	g.server.GracefulStop()
+
+	// A component stopped before it started returns as soon as it starts:
+	// there is nothing to wait for.
+	if g.running.Load() {
+		<-g.done
+	}
+
+	return nil
+}
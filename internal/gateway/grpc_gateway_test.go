@@ -0,0 +1,336 @@
+package gateway
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/chatrpc"
+	"github.com/Knoblauchpilze/chat-server/pkg/clients"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/Knoblauchpilze/chat-server/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var grpcTestUser = communication.UserDtoResponse{
+	Id:   uuid.MustParse("f2d9ce22-179d-431c-b63d-43d5a8ab5e18"),
+	Name: "alice",
+}
+
+func TestUnit_GrpcGateway_WhenDisabled_ExpectStopsWithoutListening(t *testing.T) {
+	g := newGrpcGateway(GrpcConfig{Enabled: false}, newMockGrpcServices())
+
+	wg := asyncStartGrpcGatewayAndAssertNoError(t, g)
+
+	assert.Nil(t, g.listener)
+
+	err := g.Stop()
+	wg.Wait()
+	assert.Nil(t, err, "Actual err: %v", err)
+}
+
+func TestUnit_GrpcGateway_GetUser(t *testing.T) {
+	conn, cleanup := newTestGrpcClient(t, newMockGrpcServices())
+	defer cleanup()
+
+	client := chatrpc.NewChatServiceClient(conn)
+	in := chatrpc.IdDtoRequest{Id: grpcTestUser.Id.String()}
+	out, err := client.GetUser(context.Background(), &in)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.True(t, proto.Equal(toGrpcUserDtoResponse(grpcTestUser), out), "Actual: %v", out)
+}
+
+func TestUnit_GrpcGateway_GetUser_WhenUserDoesNotExist_ExpectNotFound(t *testing.T) {
+	conn, cleanup := newTestGrpcClient(t, newMockGrpcServices())
+	defer cleanup()
+
+	client := chatrpc.NewChatServiceClient(conn)
+	in := chatrpc.IdDtoRequest{Id: uuid.New().String()}
+	_, err := client.GetUser(context.Background(), &in)
+
+	assert.Equal(t, codes.NotFound, status.Code(err), "Actual err: %v", err)
+}
+
+func TestUnit_GrpcGateway_WhenRequestHasWrongSyntax_ExpectInvalidArgument(t *testing.T) {
+	conn, cleanup := newTestGrpcClient(t, newMockGrpcServices())
+	defer cleanup()
+
+	client := chatrpc.NewChatServiceClient(conn)
+	in := chatrpc.IdDtoRequest{Id: "not-a-uuid"}
+	_, err := client.GetUser(context.Background(), &in)
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "Actual err: %v", err)
+}
+
+func TestUnit_GrpcGateway_Subscribe_ExpectEventsStreamed(t *testing.T) {
+	services := newMockGrpcServices()
+	conn, cleanup := newTestGrpcClient(t, services)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	client := chatrpc.NewChatServiceClient(conn)
+	stream, err := client.Subscribe(ctx, &chatrpc.IdDtoRequest{Id: grpcTestUser.Id.String()})
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	out, err := stream.Recv()
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, "shutdown", out.Type)
+	assert.Equal(t, []uuid.UUID{grpcTestUser.Id}, services.Message.(*mockGrpcMessageService).users)
+}
+
+func TestUnit_GrpcGateway_PinMessage_WhenUserIsNotModerator_ExpectPermissionDenied(t *testing.T) {
+	conn, cleanup := newTestGrpcClient(t, newMockGrpcServices())
+	defer cleanup()
+
+	client := chatrpc.NewChatServiceClient(conn)
+	in := chatrpc.PinDtoRequest{
+		Room:    uuid.New().String(),
+		Message: uuid.New().String(),
+		User:    uuid.New().String(),
+	}
+	_, err := client.PinMessage(context.Background(), &in)
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "Actual err: %v", err)
+}
+
+func TestUnit_GrpcGateway_UploadAttachment_ExpectContentForwarded(t *testing.T) {
+	services := newMockGrpcServices()
+	conn, cleanup := newTestGrpcClient(t, services)
+	defer cleanup()
+
+	client := chatrpc.NewChatServiceClient(conn)
+	in := chatrpc.AttachmentUploadDtoRequest{
+		User: grpcTestUser.Id.String(),
+		Room: uuid.New().String(),
+		Name: "notes.txt",
+		Data: []byte("some content"),
+	}
+	out, err := client.UploadAttachment(context.Background(), &in)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, "notes.txt", out.Name)
+	assert.Equal(t, []byte("some content"), services.Attachment.(*mockGrpcAttachmentService).data)
+}
+
+func TestUnit_GrpcGateway_DownloadAttachment_ExpectContentReturned(t *testing.T) {
+	conn, cleanup := newTestGrpcClient(t, newMockGrpcServices())
+	defer cleanup()
+
+	client := chatrpc.NewChatServiceClient(conn)
+	in := chatrpc.UserIdDtoRequest{Id: uuid.New().String(), User: grpcTestUser.Id.String()}
+	out, err := client.DownloadAttachment(context.Background(), &in)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, in.Id, out.Attachment.Id)
+	assert.Equal(t, []byte("some content"), out.Data)
+}
+
+func TestUnit_GrpcGateway_WhenMessageIsTooLarge_ExpectResourceExhausted(t *testing.T) {
+	config := GrpcConfig{Enabled: true, Port: 0, MaxMessageSizeInBytes: 1024}
+	g := newGrpcGateway(config, newMockGrpcServices())
+	wg := asyncStartGrpcGatewayAndAssertNoError(t, g)
+	conn := dialTestGrpcGateway(t, g)
+	defer func() {
+		conn.Close()
+		err := g.Stop()
+		wg.Wait()
+		assert.Nil(t, err, "Actual err: %v", err)
+	}()
+
+	client := chatrpc.NewChatServiceClient(conn)
+	in := chatrpc.AttachmentUploadDtoRequest{
+		User: grpcTestUser.Id.String(),
+		Room: uuid.New().String(),
+		Name: "notes.txt",
+		Data: make([]byte, 2048),
+	}
+	_, err := client.UploadAttachment(context.Background(), &in)
+
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "Actual err: %v", err)
+}
+
+func TestUnit_GrpcError(t *testing.T) {
+	type testCase struct {
+		err      error
+		expected codes.Code
+	}
+
+	testCases := map[string]testCase{
+		"noMatchingRows": {
+			err:      errors.NewCode(db.NoMatchingRows),
+			expected: codes.NotFound,
+		},
+		"emptyMessage": {
+			err:      errors.NewCode(service.ErrEmptyMessage),
+			expected: codes.InvalidArgument,
+		},
+		"alreadyRegistered": {
+			err:      errors.NewCode(repositories.ErrUserAlreadyRegisteredInRoom),
+			expected: codes.AlreadyExists,
+		},
+		"alreadyConnected": {
+			err:      errors.NewCode(clients.ErrClientAlreadyRegistered),
+			expected: codes.AlreadyExists,
+		},
+		"notModerator": {
+			err:      errors.NewCode(service.ErrNotModerator),
+			expected: codes.PermissionDenied,
+		},
+		"tooManyPins": {
+			err:      errors.NewCode(service.ErrTooManyPins),
+			expected: codes.FailedPrecondition,
+		},
+		"invalidSendAt": {
+			err:      errors.NewCode(service.ErrInvalidSendAt),
+			expected: codes.InvalidArgument,
+		},
+		"noSuchBlob": {
+			err:      errors.NewCode(storage.ErrNoSuchBlob),
+			expected: codes.NotFound,
+		},
+		"invalidCursor": {
+			err:      errors.NewCode(clients.ErrInvalidCursor),
+			expected: codes.InvalidArgument,
+		},
+		"shuttingDown": {
+			err:      errors.NewCode(service.ErrShuttingDown),
+			expected: codes.Unavailable,
+		},
+		"unknown": {
+			err:      errors.New("some error"),
+			expected: codes.Internal,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := grpcError(testCase.err)
+
+			assert.Equal(t, testCase.expected, status.Code(actual))
+			assert.Equal(t, testCase.err.Error(), status.Convert(actual).Message())
+		})
+	}
+}
+
+func TestUnit_GrpcError_WhenErrorHasStatus_ExpectUnchanged(t *testing.T) {
+	err := status.Error(codes.InvalidArgument, "Invalid request syntax")
+
+	actual := grpcError(err)
+
+	assert.Equal(t, err, actual)
+}
+
+func newTestGrpcClient(t *testing.T, services service.Services) (*grpc.ClientConn, func()) {
+	g := newGrpcGateway(GrpcConfig{Enabled: true, Port: 0}, services)
+	wg := asyncStartGrpcGatewayAndAssertNoError(t, g)
+
+	conn := dialTestGrpcGateway(t, g)
+
+	cleanup := func() {
+		conn.Close()
+		err := g.Stop()
+		wg.Wait()
+		assert.Nil(t, err, "Actual err: %v", err)
+	}
+
+	return conn, cleanup
+}
+
+func newMockGrpcServices() service.Services {
+	return service.Services{
+		Attachment: &mockGrpcAttachmentService{},
+		Pin:        &mockGrpcPinService{},
+		User:       &mockGrpcUserService{},
+		Message:    &mockGrpcMessageService{},
+	}
+}
+
+type mockGrpcUserService struct {
+	service.UserService
+}
+
+func (m *mockGrpcUserService) Get(
+	_ context.Context, id uuid.UUID,
+) (communication.UserDtoResponse, error) {
+	if id != grpcTestUser.Id {
+		return communication.UserDtoResponse{}, errors.NewCode(db.NoMatchingRows)
+	}
+
+	return grpcTestUser, nil
+}
+
+type mockGrpcMessageService struct {
+	service.MessageService
+
+	users []uuid.UUID
+}
+
+func (m *mockGrpcMessageService) ServeEvents(
+	ctx context.Context,
+	user uuid.UUID,
+	callback messages.MessageCallback[events.Event],
+) error {
+	m.users = append(m.users, user)
+
+	if err := callback(events.FromShutdown()); err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+type mockGrpcPinService struct {
+	service.PinService
+}
+
+func (m *mockGrpcPinService) Pin(
+	_ context.Context, _ uuid.UUID, _ uuid.UUID, _ uuid.UUID,
+) (communication.PinDtoResponse, error) {
+	return communication.PinDtoResponse{}, errors.NewCode(service.ErrNotModerator)
+}
+
+type mockGrpcAttachmentService struct {
+	service.AttachmentService
+
+	data []byte
+}
+
+func (m *mockGrpcAttachmentService) Upload(
+	_ context.Context, attachmentDto communication.AttachmentDtoRequest, data io.Reader,
+) (communication.AttachmentDtoResponse, error) {
+	var err error
+	m.data, err = io.ReadAll(data)
+
+	out := communication.AttachmentDtoResponse{
+		Id:   uuid.New(),
+		User: attachmentDto.User,
+		Room: attachmentDto.Room,
+		Name: attachmentDto.Name,
+	}
+	return out, err
+}
+
+func (m *mockGrpcAttachmentService) Download(
+	_ context.Context, user uuid.UUID, id uuid.UUID,
+) (communication.AttachmentDtoResponse, io.ReadCloser, error) {
+	out := communication.AttachmentDtoResponse{Id: id, User: user}
+	return out, io.NopCloser(strings.NewReader("some content")), nil
+}
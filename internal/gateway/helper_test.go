@@ -0,0 +1,141 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/postgresql"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var dbTestConfig = postgresql.NewConfigForLocalhost(
+	"db_chat_server",
+	"chat_server_manager",
+	"manager_password",
+)
+
+func newTestDbConnection(t *testing.T) db.Connection {
+	conn, err := db.New(context.Background(), dbTestConfig)
+	assert.Nil(t, err, "Actual err: %v", err)
+	return conn
+}
+
+func insertTestUser(t *testing.T, conn db.Connection) persistence.User {
+	repo := repositories.NewUserRepository(conn)
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	id := uuid.New()
+	user := persistence.User{
+		Id:      id,
+		Name:    fmt.Sprintf("my-user-%s", id),
+		ApiUser: uuid.New(),
+	}
+	out, err := repo.Create(context.Background(), tx, user)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return out
+}
+
+func insertTestRoom(t *testing.T, conn db.Connection) persistence.Room {
+	repo := repositories.NewRoomRepository(conn)
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	id := uuid.New()
+	room := persistence.Room{
+		Id:   id,
+		Name: fmt.Sprintf("my-room-%s", id),
+	}
+	out, err := repo.Create(context.Background(), tx, room)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return out
+}
+
+func asyncStartListenerAndAssertNoError(
+	t *testing.T, l *listenerImpl,
+) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				assert.Fail(t, "Listener panicked: %v", r)
+			}
+		}()
+
+		err := l.Start()
+		assert.Nil(t, err, "Actual err: %v", err)
+	}()
+
+	// Wait for the listener to be ready
+	time.Sleep(50 * time.Millisecond)
+
+	return &wg
+}
+
+func dialTestListener(t *testing.T, l *listenerImpl) net.Conn {
+	l.lock.Lock()
+	address := l.listener.Addr().String()
+	l.lock.Unlock()
+
+	conn, err := net.Dial("tcp", address)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return conn
+}
+
+func asyncStartGrpcGatewayAndAssertNoError(
+	t *testing.T, g *grpcGatewayImpl,
+) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				assert.Fail(t, "Gateway panicked: %v", r)
+			}
+		}()
+
+		err := g.Start()
+		assert.Nil(t, err, "Actual err: %v", err)
+	}()
+
+	// Wait for the gateway to be ready
+	time.Sleep(50 * time.Millisecond)
+
+	return &wg
+}
+
+func dialTestGrpcGateway(t *testing.T, g *grpcGatewayImpl) *grpc.ClientConn {
+	g.lock.Lock()
+	address := g.listener.Addr().String()
+	g.lock.Unlock()
+
+	conn, err := grpc.NewClient(
+		address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return conn
+}
@@ -0,0 +1,463 @@
+package gateway
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
+)
+
+const channelPrefix = "#"
+
+// handleMessage executes a command received from a registered client. It
+// returns false when the connection should be closed.
+func (s *ircSession) handleMessage(ctx context.Context, msg ircMessage) bool {
+	switch msg.Command {
+	case "PING":
+		s.pong(msg)
+	case "PONG":
+	case "QUIT":
+		s.quit()
+		return false
+	case "NICK":
+		s.reply("432", s.nick, "Changing nickname is not supported")
+	case "USER":
+		s.reply("462", "You may not reregister")
+	case "JOIN":
+		s.forEachChannel(ctx, msg, s.join)
+	case "PART":
+		s.forEachChannel(ctx, msg, s.part)
+	case "PRIVMSG":
+		s.privmsg(ctx, msg)
+	case "NAMES":
+		s.namesForMessage(ctx, msg)
+	case "LIST":
+		s.list(ctx)
+	case "TOPIC":
+		s.topic(ctx, msg)
+	default:
+		s.reply("421", msg.Command, "Unknown command")
+	}
+
+	return true
+}
+
+func (s *ircSession) pong(msg ircMessage) {
+	s.send(ircMessage{
+		Prefix:  s.gateway.serverName,
+		Command: "PONG",
+		Params:  append([]string{s.gateway.serverName}, msg.Params...),
+	})
+}
+
+func (s *ircSession) quit() {
+	s.send(ircMessage{Command: "ERROR", Params: []string{"Closing link"}})
+}
+
+// forEachChannel calls the handler for each of the comma separated channels
+// of the first parameter.
+func (s *ircSession) forEachChannel(
+	ctx context.Context,
+	msg ircMessage,
+	handler func(ctx context.Context, channel string),
+) {
+	if len(msg.Params) == 0 {
+		s.reply("461", msg.Command, "Not enough parameters")
+		return
+	}
+
+	for _, channel := range strings.Split(msg.Params[0], ",") {
+		handler(ctx, channel)
+	}
+}
+
+func (s *ircSession) joinRegisteredRooms(ctx context.Context) {
+	rooms, err := s.gateway.userService.ListForUser(ctx, s.user)
+	if err != nil {
+		return
+	}
+
+	for _, room := range rooms {
+		channel, ok := s.channelOf(ctx, room.Id, room.Name)
+		if !ok {
+			continue
+		}
+
+		s.setJoined(room.Id, true)
+		s.sendJoin(ctx, room.Id, channel)
+	}
+}
+
+func (s *ircSession) join(ctx context.Context, channel string) {
+	room, ok := s.lookupRoom(ctx, channel)
+	if !ok {
+		s.reply("403", channel, "No such channel")
+		return
+	}
+
+	err := s.gateway.registrationService.RegisterUserInRoom(ctx, s.user, room)
+	if err != nil && !errors.IsErrorWithCode(err, repositories.ErrUserAlreadyRegisteredInRoom) {
+		s.reply("403", channel, "Cannot join channel")
+		return
+	}
+
+	s.setJoined(room, true)
+	s.sendJoin(ctx, room, channel)
+}
+
+func (s *ircSession) sendJoin(ctx context.Context, room uuid.UUID, channel string) {
+	s.send(ircMessage{Prefix: s.prefix(), Command: "JOIN", Params: []string{channel}})
+	s.reply("331", channel, "No topic is set")
+	s.names(ctx, room, channel)
+}
+
+func (s *ircSession) part(ctx context.Context, channel string) {
+	room, ok := s.lookupRoom(ctx, channel)
+	if !ok {
+		s.reply("403", channel, "No such channel")
+		return
+	}
+
+	if !s.isJoined(room) {
+		s.reply("442", channel, "You're not on that channel")
+		return
+	}
+
+	err := s.gateway.registrationService.UnregisterUserInRoom(ctx, s.user, room)
+	if errors.IsErrorWithCode(err, service.ErrLeavingRoomIsNotAllowed) {
+		s.reply("482", channel, "Leaving this channel is not allowed")
+		return
+	} else if err != nil {
+		s.reply("403", channel, "Cannot leave channel")
+		return
+	}
+
+	s.setJoined(room, false)
+	s.send(ircMessage{Prefix: s.prefix(), Command: "PART", Params: []string{channel}})
+}
+
+func (s *ircSession) privmsg(ctx context.Context, msg ircMessage) {
+	if len(msg.Params) == 0 {
+		s.reply("411", "No recipient given (PRIVMSG)")
+		return
+	}
+
+	target := msg.Params[0]
+	if !strings.HasPrefix(target, channelPrefix) {
+		// The users can only talk in rooms.
+		s.reply("401", target, "No such nick/channel")
+		return
+	}
+
+	room, ok := s.lookupRoom(ctx, target)
+	if !ok {
+		s.reply("403", target, "No such channel")
+		return
+	}
+
+	messageDtoRequest := communication.MessageDtoRequest{
+		User: s.user,
+		Room: room,
+	}
+	if len(msg.Params) > 1 {
+		messageDtoRequest.Message = msg.Params[1]
+	}
+
+	_, err := s.gateway.messageService.PostMessage(ctx, messageDtoRequest)
+	if errors.IsErrorWithCode(err, service.ErrEmptyMessage) {
+		s.reply("412", "No text to send")
+	} else if errors.IsErrorWithCode(err, service.ErrUserNotInRoom) {
+		s.reply("404", target, "Cannot send to channel")
+	} else if errors.IsErrorWithCode(err, service.ErrShuttingDown) {
+		s.reply("404", target, "Server is shutting down")
+	} else if err != nil {
+		s.reply("404", target, "Failed to send message")
+	}
+}
+
+func (s *ircSession) namesForMessage(ctx context.Context, msg ircMessage) {
+	if len(msg.Params) > 0 {
+		s.forEachChannel(ctx, msg, func(ctx context.Context, channel string) {
+			room, ok := s.lookupRoom(ctx, channel)
+			if !ok {
+				s.reply("366", channel, "End of /NAMES list")
+				return
+			}
+			s.names(ctx, room, channel)
+		})
+		return
+	}
+
+	for _, room := range s.joinedRooms() {
+		s.names(ctx, room, s.roomChannel(ctx, room))
+	}
+}
+
+func (s *ircSession) names(ctx context.Context, room uuid.UUID, channel string) {
+	users, err := s.gateway.roomService.ListUserForRoom(ctx, room)
+	if err == nil {
+		var names []string
+		for _, user := range users {
+			nick := ircNick(user.Name)
+			s.rememberUser(user.Id, nick)
+			names = append(names, nick)
+		}
+
+		s.reply("353", "=", channel, strings.Join(names, " "))
+	}
+
+	s.reply("366", channel, "End of /NAMES list")
+}
+
+func (s *ircSession) list(ctx context.Context) {
+	rooms, err := s.gateway.roomService.List(ctx)
+	if err != nil {
+		s.reply("323", "End of /LIST")
+		return
+	}
+
+	counts, err := s.gateway.roomService.CountUsers(ctx)
+	if err != nil {
+		s.reply("323", "End of /LIST")
+		return
+	}
+
+	s.reply("321", "Channel", "Users  Name")
+	for _, room := range rooms {
+		channel, ok := s.channelOf(ctx, room.Id, room.Name)
+		if !ok {
+			continue
+		}
+
+		s.reply("322", channel, strconv.Itoa(counts[room.Id]), "")
+	}
+	s.reply("323", "End of /LIST")
+}
+
+func (s *ircSession) topic(ctx context.Context, msg ircMessage) {
+	if len(msg.Params) == 0 {
+		s.reply("461", msg.Command, "Not enough parameters")
+		return
+	}
+
+	channel := msg.Params[0]
+	if _, ok := s.lookupRoom(ctx, channel); !ok {
+		s.reply("403", channel, "No such channel")
+		return
+	}
+
+	// The rooms do not have a topic.
+	if len(msg.Params) > 1 {
+		s.reply("482", channel, "Setting the topic is not supported")
+		return
+	}
+
+	s.reply("331", channel, "No topic is set")
+}
+
+// generateEventCallback sends the messages posted in the rooms of the user
+// as PRIVMSG. The other events have no equivalent in IRC.
+func (s *ircSession) generateEventCallback(ctx context.Context) messages.MessageCallback[events.Event] {
+	return func(event events.Event) error {
+		switch event.Type {
+		case events.Shutdown:
+			return s.send(ircMessage{Command: "ERROR", Params: []string{"Server is shutting down"}})
+		case events.Message:
+			msg, ok := event.Data.(communication.MessageDtoResponse)
+			// IRC clients do not expect their own messages to be echoed.
+			if !ok || msg.User == s.user {
+				return nil
+			}
+			return s.deliver(ctx, msg)
+		}
+
+		return nil
+	}
+}
+
+func (s *ircSession) deliver(ctx context.Context, msg communication.MessageDtoResponse) error {
+	author := s.userNick(ctx, msg.User)
+	channel := s.roomChannel(ctx, msg.Room)
+	if channel == "" {
+		// The client can't be in a room that is not exposed.
+		return nil
+	}
+
+	// A line can't hold a multi-line message.
+	for _, line := range ircLines(msg.Message) {
+		out := ircMessage{
+			Prefix:  userPrefix(author, s.gateway.serverName),
+			Command: "PRIVMSG",
+			Params:  []string{channel, line},
+		}
+		if err := s.send(out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ircSession) lookupRoom(ctx context.Context, channel string) (uuid.UUID, bool) {
+	name, found := strings.CutPrefix(channel, channelPrefix)
+	if !found || name == "" {
+		return uuid.Nil, false
+	}
+
+	s.lock.Lock()
+	id, ok := s.roomIds[channel]
+	s.lock.Unlock()
+	if ok {
+		return id, true
+	}
+
+	room, err := s.gateway.roomService.GetByName(ctx, name)
+	if err == nil {
+		if actual, ok := s.channelOf(ctx, room.Id, room.Name); ok && actual == channel {
+			return room.Id, true
+		}
+	}
+
+	if !strings.Contains(name, "_") {
+		return uuid.Nil, false
+	}
+
+	// The name of the room may have been mapped to this channel.
+	rooms, err := s.gateway.roomService.List(ctx)
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	for _, room := range rooms {
+		if actual, ok := s.channelOf(ctx, room.Id, room.Name); ok && actual == channel {
+			return room.Id, true
+		}
+	}
+
+	return uuid.Nil, false
+}
+
+// channelOf returns the channel of the room, or false when it is not
+// exposed. The name of the room is mapped to a valid channel name but the
+// room which already has this name keeps it: the mapped room is hidden.
+func (s *ircSession) channelOf(ctx context.Context, id uuid.UUID, name string) (string, bool) {
+	s.lock.Lock()
+	channel, ok := s.roomChannels[id]
+	s.lock.Unlock()
+	if ok {
+		return channel, channel != ""
+	}
+
+	channel = ircChannelName(name)
+	if channel != channelPrefix+name {
+		_, err := s.gateway.roomService.GetByName(ctx, strings.TrimPrefix(channel, channelPrefix))
+		if err == nil {
+			channel = ""
+		} else if !errors.IsErrorWithCode(err, db.NoMatchingRows) {
+			return "", false
+		}
+	}
+
+	return s.rememberRoom(id, channel)
+}
+
+// roomChannel falls back to the id of the room when it can't be fetched so
+// that the message is still delivered. It returns an empty string when the
+// room is not exposed.
+func (s *ircSession) roomChannel(ctx context.Context, id uuid.UUID) string {
+	s.lock.Lock()
+	channel, ok := s.roomChannels[id]
+	s.lock.Unlock()
+	if ok {
+		return channel
+	}
+
+	room, err := s.gateway.roomService.Get(ctx, id)
+	if err != nil {
+		return channelPrefix + id.String()
+	}
+
+	channel, _ = s.channelOf(ctx, room.Id, room.Name)
+	return channel
+}
+
+func (s *ircSession) userNick(ctx context.Context, id uuid.UUID) string {
+	s.lock.Lock()
+	nick, ok := s.userNicks[id]
+	s.lock.Unlock()
+	if ok {
+		return nick
+	}
+
+	user, err := s.gateway.userService.Get(ctx, id)
+	if err != nil {
+		return ircNick(id.String())
+	}
+
+	nick = ircNick(user.Name)
+	s.rememberUser(user.Id, nick)
+	return nick
+}
+
+// rememberRoom caches the channel of the room. When the names of two rooms
+// are mapped to the same channel, the first one keeps it.
+func (s *ircSession) rememberRoom(id uuid.UUID, channel string) (string, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if other, ok := s.roomIds[channel]; ok && other != id {
+		channel = ""
+	}
+
+	s.roomChannels[id] = channel
+	if channel != "" {
+		s.roomIds[channel] = id
+	}
+
+	return channel, channel != ""
+}
+
+func (s *ircSession) rememberUser(id uuid.UUID, nick string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.userNicks[id] = nick
+}
+
+func (s *ircSession) setJoined(room uuid.UUID, joined bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if joined {
+		s.joined[room] = true
+	} else {
+		delete(s.joined, room)
+	}
+}
+
+func (s *ircSession) isJoined(room uuid.UUID) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.joined[room]
+}
+
+func (s *ircSession) joinedRooms() []uuid.UUID {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	out := make([]uuid.UUID, 0, len(s.joined))
+	for room := range s.joined {
+		out = append(out, room)
+	}
+
+	return out
+}
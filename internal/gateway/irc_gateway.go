@@ -0,0 +1,222 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/clients"
+	"github.com/google/uuid"
+)
+
+type IrcConfig struct {
+	Enabled bool
+	Port    uint16
+	// ServerName prefixes the replies sent by the server.
+	ServerName string
+}
+
+type ircGatewayImpl struct {
+	serverName          string
+	messageService      service.MessageService
+	registrationService service.RegistrationService
+	roomService         service.RoomService
+	userService         service.UserService
+}
+
+// NewIrcGateway lets IRC clients chat in the rooms: the nickname of a
+// client is the name of a user and the channels are the rooms prefixed
+// with a '#'.
+func NewIrcGateway(config IrcConfig, services service.Services) Gateway {
+	g := &ircGatewayImpl{
+		serverName:          config.ServerName,
+		messageService:      services.Message,
+		registrationService: services.Registration,
+		roomService:         services.Room,
+		userService:         services.User,
+	}
+
+	return newListener(config.Enabled, config.Port, g.serve)
+}
+
+func (g *ircGatewayImpl) serve(ctx context.Context, conn net.Conn) {
+	s := newIrcSession(g, conn)
+	lines := bufio.NewScanner(conn)
+
+	if !s.register(ctx, lines) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		s.joinRegisteredRooms(ctx)
+		s.readLinesUntilClosed(ctx, lines)
+	}()
+
+	err := g.messageService.ServeEvents(ctx, s.user, s.generateEventCallback(ctx))
+	if err != nil {
+		s.send(ircMessage{Command: "ERROR", Params: []string{ircServeError(err)}})
+	}
+
+	// Unblocks the read loop.
+	conn.Close()
+	wg.Wait()
+}
+
+func ircServeError(err error) string {
+	if errors.IsErrorWithCode(err, service.ErrShuttingDown) {
+		return "Server is shutting down"
+	} else if errors.IsErrorWithCode(err, clients.ErrClientAlreadyRegistered) {
+		return "User is already connected"
+	}
+
+	return "Internal server error"
+}
+
+// ircSession holds the state of a connected IRC client. The channels of the
+// rooms and the nicknames of the users are cached as the events only
+// reference their ids. An empty channel marks a room that is not exposed.
+type ircSession struct {
+	gateway *ircGatewayImpl
+	w       *lineWriter
+
+	nick string
+	user uuid.UUID
+
+	lock         sync.Mutex
+	roomIds      map[string]uuid.UUID
+	roomChannels map[uuid.UUID]string
+	userNicks    map[uuid.UUID]string
+	joined       map[uuid.UUID]bool
+}
+
+func newIrcSession(g *ircGatewayImpl, conn net.Conn) *ircSession {
+	return &ircSession{
+		gateway: g,
+		w:       &lineWriter{conn: conn},
+
+		roomIds:      make(map[string]uuid.UUID),
+		roomChannels: make(map[uuid.UUID]string),
+		userNicks:    make(map[uuid.UUID]string),
+		joined:       make(map[uuid.UUID]bool),
+	}
+}
+
+// register waits for the client to send its nickname and user. The other
+// commands are rejected until then.
+func (s *ircSession) register(ctx context.Context, lines *bufio.Scanner) bool {
+	userReceived := false
+
+	for lines.Scan() {
+		msg, ok := parseIrcMessage(lines.Text())
+		if !ok {
+			continue
+		}
+
+		switch msg.Command {
+		case "NICK":
+			s.identify(ctx, msg)
+		case "USER":
+			userReceived = true
+		case "PING":
+			s.pong(msg)
+		case "QUIT":
+			s.quit()
+			return false
+		case "CAP":
+			// Clients negotiating capabilities go on without them when the
+			// command is unknown.
+			s.reply("421", msg.Command, "Unknown command")
+		default:
+			s.reply("451", "You have not registered")
+		}
+
+		if s.user != uuid.Nil && userReceived {
+			welcome := fmt.Sprintf("Welcome to %s, %s", s.gateway.serverName, s.nick)
+			return s.reply("001", welcome) == nil
+		}
+	}
+
+	return false
+}
+
+func (s *ircSession) identify(ctx context.Context, msg ircMessage) {
+	if len(msg.Params) == 0 {
+		s.reply("431", "No nickname given")
+		return
+	}
+
+	nick := msg.Params[0]
+	if ircNick(nick) != nick {
+		// The users whose name is not a valid nickname can't connect.
+		s.reply("432", ircNick(nick), "Erroneous nickname")
+		return
+	}
+
+	user, err := s.gateway.userService.GetByName(ctx, nick)
+	if errors.IsErrorWithCode(err, db.NoMatchingRows) {
+		s.reply("432", nick, "Unknown user")
+		return
+	} else if err != nil {
+		s.reply("432", nick, "Failed to identify user")
+		return
+	}
+
+	s.nick, s.user = user.Name, user.Id
+	s.rememberUser(user.Id, nick)
+}
+
+func (s *ircSession) readLinesUntilClosed(ctx context.Context, lines *bufio.Scanner) {
+	for lines.Scan() {
+		msg, ok := parseIrcMessage(lines.Text())
+		if !ok {
+			continue
+		}
+
+		if !s.handleMessage(ctx, msg) {
+			return
+		}
+	}
+}
+
+// send writes a message to the client. The events and the replies can be
+// sent concurrently: the writer prevents them from being interleaved.
+func (s *ircSession) send(msg ircMessage) error {
+	_, err := s.w.Write([]byte(msg.String() + "\r\n"))
+	return err
+}
+
+// reply sends a numeric reply: its first parameter is the nickname of the
+// client, or '*' when it is not known yet.
+func (s *ircSession) reply(code string, params ...string) error {
+	target := s.nick
+	if target == "" {
+		target = "*"
+	}
+
+	msg := ircMessage{
+		Prefix:  s.gateway.serverName,
+		Command: code,
+		Params:  append([]string{target}, params...),
+	}
+	return s.send(msg)
+}
+
+func (s *ircSession) prefix() string {
+	return userPrefix(s.nick, s.gateway.serverName)
+}
+
+func userPrefix(nick string, server string) string {
+	return fmt.Sprintf("%s!%s@%s", nick, nick, server)
+}
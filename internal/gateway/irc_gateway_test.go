@@ -0,0 +1,538 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ircTestUser = communication.UserDtoResponse{
+		Id:   uuid.MustParse("f2d9ce22-179d-431c-b63d-43d5a8ab5e18"),
+		Name: "alice",
+	}
+	ircTestOtherUser = communication.UserDtoResponse{
+		Id:   uuid.MustParse("a8a4ab5b-8d8f-4d2c-9b49-7be3c2a4f26d"),
+		Name: "bob",
+	}
+	ircTestRoom = communication.RoomDtoResponse{
+		Id:   uuid.MustParse("111838db-a871-47be-9149-c974fd356316"),
+		Name: "general",
+	}
+	ircTestOtherRoom = communication.RoomDtoResponse{
+		Id:   uuid.MustParse("7c0a3c27-4c1d-44b4-8f4f-4c8b0e0f7c11"),
+		Name: "random",
+	}
+)
+
+func TestUnit_IrcGateway_WhenNickIsUnknown_ExpectError(t *testing.T) {
+	client, cleanup := newTestIrcClient(t, newMockIrcServices())
+	defer cleanup()
+
+	client.send("NICK carol")
+	assert.Equal(t, ":chat-server 432 * carol :Unknown user", client.read())
+
+	client.send("JOIN #general")
+	assert.Equal(t, ":chat-server 451 * :You have not registered", client.read())
+}
+
+func TestUnit_IrcGateway_Register_ExpectWelcomeAndJoinedRooms(t *testing.T) {
+	client, cleanup := newTestIrcClient(t, newMockIrcServices())
+	defer cleanup()
+
+	client.register()
+
+	assert.Equal(t, ":alice!alice@chat-server JOIN #general", client.read())
+	assert.Equal(t, ":chat-server 331 alice #general :No topic is set", client.read())
+	assert.Equal(t, ":chat-server 353 alice = #general :alice bob", client.read())
+	assert.Equal(t, ":chat-server 366 alice #general :End of /NAMES list", client.read())
+}
+
+func TestUnit_IrcGateway_Ping(t *testing.T) {
+	client, cleanup := newTestIrcClient(t, newMockIrcServices())
+	defer cleanup()
+
+	client.send("PING token")
+
+	assert.Equal(t, ":chat-server PONG chat-server token", client.read())
+}
+
+func TestUnit_IrcGateway_Join(t *testing.T) {
+	services := newMockIrcServices()
+	client, cleanup := newTestIrcClient(t, services)
+	defer cleanup()
+	client.registerAndSkipJoinedRooms()
+
+	client.send("JOIN #random,#unknown")
+
+	assert.Equal(t, ":alice!alice@chat-server JOIN #random", client.read())
+	assert.Equal(t, ":chat-server 331 alice #random :No topic is set", client.read())
+	assert.Equal(t, ":chat-server 353 alice = #random bob", client.read())
+	assert.Equal(t, ":chat-server 366 alice #random :End of /NAMES list", client.read())
+	assert.Equal(t, ":chat-server 403 alice #unknown :No such channel", client.read())
+	assert.Equal(t, []uuid.UUID{ircTestOtherRoom.Id}, services.registration.registered)
+}
+
+func TestUnit_IrcGateway_Part(t *testing.T) {
+	services := newMockIrcServices()
+	client, cleanup := newTestIrcClient(t, services)
+	defer cleanup()
+	client.registerAndSkipJoinedRooms()
+
+	client.send("PART #random")
+	assert.Equal(t, ":chat-server 442 alice #random :You're not on that channel", client.read())
+
+	client.send("PART #general")
+	assert.Equal(t, ":alice!alice@chat-server PART #general", client.read())
+	assert.Equal(t, []uuid.UUID{ircTestRoom.Id}, services.registration.unregistered)
+}
+
+func TestUnit_IrcGateway_Privmsg_ExpectMessagePosted(t *testing.T) {
+	services := newMockIrcServices()
+	client, cleanup := newTestIrcClient(t, services)
+	defer cleanup()
+	client.registerAndSkipJoinedRooms()
+
+	client.send("PRIVMSG #general :Hello there")
+	client.send("PRIVMSG bob :Hi")
+	assert.Equal(t, ":chat-server 401 alice bob :No such nick/channel", client.read())
+
+	expected := []communication.MessageDtoRequest{
+		{
+			User:    ircTestUser.Id,
+			Room:    ircTestRoom.Id,
+			Message: "Hello there",
+		},
+	}
+	assert.Equal(t, expected, services.message.postedMessages())
+}
+
+func TestUnit_IrcGateway_Privmsg_WhenPostFails_ExpectError(t *testing.T) {
+	services := newMockIrcServices()
+	services.message.err = errors.NewCode(service.ErrUserNotInRoom)
+	client, cleanup := newTestIrcClient(t, services)
+	defer cleanup()
+	client.registerAndSkipJoinedRooms()
+
+	client.send("PRIVMSG #random :Hello")
+
+	assert.Equal(t, ":chat-server 404 alice #random :Cannot send to channel", client.read())
+}
+
+func TestUnit_IrcGateway_List(t *testing.T) {
+	client, cleanup := newTestIrcClient(t, newMockIrcServices())
+	defer cleanup()
+	client.registerAndSkipJoinedRooms()
+
+	client.send("LIST")
+
+	assert.Equal(t, ":chat-server 321 alice Channel :Users  Name", client.read())
+	assert.Equal(t, ":chat-server 322 alice #general 2 :", client.read())
+	assert.Equal(t, ":chat-server 322 alice #random 1 :", client.read())
+	assert.Equal(t, ":chat-server 323 alice :End of /LIST", client.read())
+}
+
+func TestUnit_IrcGateway_Topic(t *testing.T) {
+	client, cleanup := newTestIrcClient(t, newMockIrcServices())
+	defer cleanup()
+	client.registerAndSkipJoinedRooms()
+
+	client.send("TOPIC #general")
+	assert.Equal(t, ":chat-server 331 alice #general :No topic is set", client.read())
+
+	client.send("TOPIC #general :New topic")
+	assert.Equal(t, ":chat-server 482 alice #general :Setting the topic is not supported", client.read())
+}
+
+func TestUnit_IrcGateway_WhenCommandIsUnknown_ExpectError(t *testing.T) {
+	client, cleanup := newTestIrcClient(t, newMockIrcServices())
+	defer cleanup()
+	client.registerAndSkipJoinedRooms()
+
+	client.send("WHOIS bob")
+
+	assert.Equal(t, ":chat-server 421 alice WHOIS :Unknown command", client.read())
+}
+
+func TestUnit_IrcGateway_ReceivesMessagesAsPrivmsg(t *testing.T) {
+	services := newMockIrcServices()
+	client, cleanup := newTestIrcClient(t, services)
+	defer cleanup()
+	client.registerAndSkipJoinedRooms()
+
+	callback := services.message.waitForCallback(t)
+	own := persistence.Message{
+		Id:       uuid.New(),
+		ChatUser: ircTestUser.Id,
+		Room:     ircTestRoom.Id,
+		Message:  "Not echoed",
+	}
+	err := callback(events.FromMessage(own))
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	msg := persistence.Message{
+		Id:       uuid.New(),
+		ChatUser: ircTestOtherUser.Id,
+		Room:     ircTestOtherRoom.Id,
+		Message:  "Hello\nWorld",
+	}
+	err = callback(events.FromMessage(msg))
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, ":bob!bob@chat-server PRIVMSG #random Hello", client.read())
+	assert.Equal(t, ":bob!bob@chat-server PRIVMSG #random World", client.read())
+}
+
+func TestUnit_IrcGateway_WhenMessageHasControlCharacters_ExpectStripped(t *testing.T) {
+	services := newMockIrcServices()
+	client, cleanup := newTestIrcClient(t, services)
+	defer cleanup()
+	client.registerAndSkipJoinedRooms()
+
+	callback := services.message.waitForCallback(t)
+	msg := persistence.Message{
+		Id:       uuid.New(),
+		ChatUser: ircTestOtherUser.Id,
+		Room:     ircTestRoom.Id,
+		Message:  "Hello\rQUIT\r\n\x00\r\nWor\x00ld\x07",
+	}
+	err := callback(events.FromMessage(msg))
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, ":bob!bob@chat-server PRIVMSG #general Hello", client.read())
+	assert.Equal(t, ":bob!bob@chat-server PRIVMSG #general QUIT", client.read())
+	assert.Equal(t, ":bob!bob@chat-server PRIVMSG #general World", client.read())
+}
+
+func TestUnit_IrcGateway_WhenNamesAreNotValid_ExpectMapped(t *testing.T) {
+	services := newMockIrcServices()
+	spacedUser := communication.UserDtoResponse{Id: uuid.New(), Name: "bob smith"}
+	spacedRoom := communication.RoomDtoResponse{Id: uuid.New(), Name: "off topic"}
+	services.room.rooms = append(services.room.rooms, spacedRoom)
+	services.room.users = append(services.room.users, spacedUser)
+	services.user.users = append(services.user.users, spacedUser)
+	client, cleanup := newTestIrcClient(t, services)
+	defer cleanup()
+
+	client.register()
+	assert.Equal(t, ":alice!alice@chat-server JOIN #general", client.read())
+	assert.Equal(t, ":chat-server 331 alice #general :No topic is set", client.read())
+	assert.Equal(t, ":chat-server 353 alice = #general :alice bob bob_smith", client.read())
+	assert.Equal(t, ":chat-server 366 alice #general :End of /NAMES list", client.read())
+
+	client.send("JOIN #off_topic")
+	assert.Equal(t, ":alice!alice@chat-server JOIN #off_topic", client.read())
+	assert.Equal(t, ":chat-server 331 alice #off_topic :No topic is set", client.read())
+	assert.Equal(t, ":chat-server 353 alice = #off_topic :bob bob_smith", client.read())
+	assert.Equal(t, ":chat-server 366 alice #off_topic :End of /NAMES list", client.read())
+	assert.Equal(t, []uuid.UUID{spacedRoom.Id}, services.registration.registered)
+
+	callback := services.message.waitForCallback(t)
+	msg := persistence.Message{
+		Id:       uuid.New(),
+		ChatUser: spacedUser.Id,
+		Room:     spacedRoom.Id,
+		Message:  "Hello",
+	}
+	err := callback(events.FromMessage(msg))
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, ":bob_smith!bob_smith@chat-server PRIVMSG #off_topic Hello", client.read())
+}
+
+func TestUnit_IrcGateway_WhenMappedNameIsTaken_ExpectRoomHidden(t *testing.T) {
+	services := newMockIrcServices()
+	spacedRoom := communication.RoomDtoResponse{Id: uuid.New(), Name: "general room"}
+	takenRoom := communication.RoomDtoResponse{Id: uuid.New(), Name: "general_room"}
+	services.room.rooms = append(services.room.rooms, spacedRoom, takenRoom)
+	client, cleanup := newTestIrcClient(t, services)
+	defer cleanup()
+	client.registerAndSkipJoinedRooms()
+
+	client.send("LIST")
+
+	assert.Equal(t, ":chat-server 321 alice Channel :Users  Name", client.read())
+	assert.Equal(t, ":chat-server 322 alice #general 2 :", client.read())
+	assert.Equal(t, ":chat-server 322 alice #random 1 :", client.read())
+	assert.Equal(t, ":chat-server 322 alice #general_room 1 :", client.read())
+	assert.Equal(t, ":chat-server 323 alice :End of /LIST", client.read())
+}
+
+func TestUnit_IrcGateway_WhenNickIsNotValid_ExpectError(t *testing.T) {
+	client, cleanup := newTestIrcClient(t, newMockIrcServices())
+	defer cleanup()
+
+	client.send("NICK :bob smith")
+
+	assert.Equal(t, ":chat-server 432 * bob_smith :Erroneous nickname", client.read())
+}
+
+func TestUnit_IrcGateway_Quit_ExpectConnectionClosed(t *testing.T) {
+	client, cleanup := newTestIrcClient(t, newMockIrcServices())
+	defer cleanup()
+	client.registerAndSkipJoinedRooms()
+
+	client.send("QUIT :Bye")
+
+	assert.Equal(t, "ERROR :Closing link", client.read())
+	assert.False(t, client.lines.Scan())
+}
+
+type mockIrcServices struct {
+	message      *mockIrcMessageService
+	registration *mockIrcRegistrationService
+	room         *mockIrcRoomService
+	user         *mockIrcUserService
+}
+
+func newMockIrcServices() mockIrcServices {
+	users := []communication.UserDtoResponse{ircTestUser, ircTestOtherUser}
+	return mockIrcServices{
+		message:      &mockIrcMessageService{callbacks: make(chan messages.MessageCallback[events.Event], 1)},
+		registration: &mockIrcRegistrationService{},
+		room: &mockIrcRoomService{
+			rooms: []communication.RoomDtoResponse{ircTestRoom, ircTestOtherRoom},
+			users: users,
+		},
+		user: &mockIrcUserService{users: users},
+	}
+}
+
+func newTestIrcClient(t *testing.T, services mockIrcServices) (*ircTestClient, func()) {
+	g := &ircGatewayImpl{
+		serverName:          "chat-server",
+		messageService:      services.message,
+		registrationService: services.registration,
+		roomService:         services.room,
+		userService:         services.user,
+	}
+	l := newListener(true, 0, g.serve)
+	wg := asyncStartListenerAndAssertNoError(t, l)
+
+	conn := dialTestListener(t, l)
+	client := &ircTestClient{
+		t:     t,
+		conn:  conn,
+		lines: bufio.NewScanner(conn),
+	}
+
+	cleanup := func() {
+		conn.Close()
+		err := l.Stop()
+		wg.Wait()
+		assert.Nil(t, err, "Actual err: %v", err)
+	}
+
+	return client, cleanup
+}
+
+type ircTestClient struct {
+	t     *testing.T
+	conn  net.Conn
+	lines *bufio.Scanner
+}
+
+func (c *ircTestClient) send(line string) {
+	_, err := fmt.Fprintf(c.conn, "%s\r\n", line)
+	assert.Nil(c.t, err, "Actual err: %v", err)
+}
+
+func (c *ircTestClient) read() string {
+	err := c.conn.SetReadDeadline(time.Now().Add(time.Second))
+	assert.Nil(c.t, err, "Actual err: %v", err)
+
+	assert.True(c.t, c.lines.Scan(), "Actual err: %v", c.lines.Err())
+	return strings.TrimSuffix(c.lines.Text(), "\r")
+}
+
+func (c *ircTestClient) register() {
+	c.send("NICK alice")
+	c.send("USER alice 0 * :Alice")
+	assert.Equal(c.t, ":chat-server 001 alice :Welcome to chat-server, alice", c.read())
+}
+
+func (c *ircTestClient) registerAndSkipJoinedRooms() {
+	c.register()
+	// JOIN, topic and the two lines of the names of the general room.
+	for range 4 {
+		c.read()
+	}
+}
+
+type mockIrcMessageService struct {
+	service.MessageService
+
+	err       error
+	callbacks chan messages.MessageCallback[events.Event]
+
+	lock   sync.Mutex
+	posted []communication.MessageDtoRequest
+}
+
+func (m *mockIrcMessageService) PostMessage(
+	_ context.Context, messageDto communication.MessageDtoRequest,
+) (communication.MessageStatusDtoResponse, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.posted = append(m.posted, messageDto)
+	return communication.MessageStatusDtoResponse{}, m.err
+}
+
+func (m *mockIrcMessageService) postedMessages() []communication.MessageDtoRequest {
+	// Wait for the commands to be processed
+	time.Sleep(50 * time.Millisecond)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.posted
+}
+
+func (m *mockIrcMessageService) ServeEvents(
+	ctx context.Context,
+	_ uuid.UUID,
+	callback messages.MessageCallback[events.Event],
+) error {
+	m.callbacks <- callback
+	<-ctx.Done()
+	return nil
+}
+
+func (m *mockIrcMessageService) waitForCallback(t *testing.T) messages.MessageCallback[events.Event] {
+	select {
+	case callback := <-m.callbacks:
+		return callback
+	case <-time.After(time.Second):
+		assert.Fail(t, "Events are not served")
+		return nil
+	}
+}
+
+type mockIrcRegistrationService struct {
+	service.RegistrationService
+
+	registered   []uuid.UUID
+	unregistered []uuid.UUID
+}
+
+func (m *mockIrcRegistrationService) RegisterUserInRoom(
+	_ context.Context, _ uuid.UUID, room uuid.UUID,
+) error {
+	m.registered = append(m.registered, room)
+	return nil
+}
+
+func (m *mockIrcRegistrationService) UnregisterUserInRoom(
+	_ context.Context, _ uuid.UUID, room uuid.UUID,
+) error {
+	m.unregistered = append(m.unregistered, room)
+	return nil
+}
+
+type mockIrcUserService struct {
+	service.UserService
+
+	users []communication.UserDtoResponse
+}
+
+func (m *mockIrcUserService) Get(
+	_ context.Context, id uuid.UUID,
+) (communication.UserDtoResponse, error) {
+	for _, user := range m.users {
+		if user.Id == id {
+			return user, nil
+		}
+	}
+
+	return communication.UserDtoResponse{}, errors.NewCode(db.NoMatchingRows)
+}
+
+func (m *mockIrcUserService) GetByName(
+	_ context.Context, name string,
+) (communication.UserDtoResponse, error) {
+	for _, user := range m.users {
+		if user.Name == name {
+			return user, nil
+		}
+	}
+
+	return communication.UserDtoResponse{}, errors.NewCode(db.NoMatchingRows)
+}
+
+func (m *mockIrcUserService) ListForUser(
+	_ context.Context, _ uuid.UUID,
+) ([]communication.RoomDtoResponse, error) {
+	return []communication.RoomDtoResponse{ircTestRoom}, nil
+}
+
+type mockIrcRoomService struct {
+	service.RoomService
+
+	rooms []communication.RoomDtoResponse
+	users []communication.UserDtoResponse
+}
+
+func (m *mockIrcRoomService) Get(
+	_ context.Context, id uuid.UUID,
+) (communication.RoomDtoResponse, error) {
+	for _, room := range m.rooms {
+		if room.Id == id {
+			return room, nil
+		}
+	}
+
+	return communication.RoomDtoResponse{}, errors.NewCode(db.NoMatchingRows)
+}
+
+func (m *mockIrcRoomService) GetByName(
+	_ context.Context, name string,
+) (communication.RoomDtoResponse, error) {
+	for _, room := range m.rooms {
+		if room.Name == name {
+			return room, nil
+		}
+	}
+
+	return communication.RoomDtoResponse{}, errors.NewCode(db.NoMatchingRows)
+}
+
+func (m *mockIrcRoomService) List(_ context.Context) ([]communication.RoomDtoResponse, error) {
+	return m.rooms, nil
+}
+
+func (m *mockIrcRoomService) CountUsers(
+	_ context.Context,
+) (map[uuid.UUID]int, error) {
+	out := map[uuid.UUID]int{
+		ircTestRoom.Id: 2,
+	}
+	for _, room := range m.rooms[1:] {
+		out[room.Id] = 1
+	}
+	return out, nil
+}
+
+func (m *mockIrcRoomService) ListUserForRoom(
+	_ context.Context, room uuid.UUID,
+) ([]communication.UserDtoResponse, error) {
+	if room == ircTestRoom.Id {
+		return m.users, nil
+	}
+
+	return m.users[1:], nil
+}
@@ -0,0 +1,77 @@
+package gateway
+
+import (
+	"strings"
+)
+
+// ircMessage is a line of the IRC protocol as described in RFC 1459:
+// an optional prefix, a command and up to 15 parameters, the last of which
+// can contain spaces when introduced by a colon.
+type ircMessage struct {
+	Prefix  string
+	Command string
+	Params  []string
+}
+
+func parseIrcMessage(line string) (ircMessage, bool) {
+	var out ircMessage
+
+	line = strings.TrimRight(line, "\r\n")
+	if strings.HasPrefix(line, ":") {
+		prefix, rest, found := strings.Cut(line[1:], " ")
+		if !found {
+			return ircMessage{}, false
+		}
+		out.Prefix, line = prefix, rest
+	}
+
+	for line != "" {
+		line = strings.TrimLeft(line, " ")
+		if line == "" {
+			break
+		}
+
+		if out.Command != "" && strings.HasPrefix(line, ":") {
+			out.Params = append(out.Params, line[1:])
+			break
+		}
+
+		var word string
+		word, line, _ = strings.Cut(line, " ")
+		if out.Command == "" {
+			out.Command = strings.ToUpper(word)
+		} else {
+			out.Params = append(out.Params, word)
+		}
+	}
+
+	return out, out.Command != ""
+}
+
+func (m ircMessage) String() string {
+	var out strings.Builder
+
+	if m.Prefix != "" {
+		out.WriteString(":")
+		out.WriteString(m.Prefix)
+		out.WriteString(" ")
+	}
+
+	out.WriteString(m.Command)
+
+	for id, param := range m.Params {
+		out.WriteString(" ")
+
+		last := id == len(m.Params)-1
+		if last && needsTrailingColon(param) {
+			out.WriteString(":")
+		}
+		out.WriteString(param)
+	}
+
+	return out.String()
+}
+
+func needsTrailingColon(param string) bool {
+	return param == "" || strings.Contains(param, " ") || strings.HasPrefix(param, ":")
+}
@@ -0,0 +1,119 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_ParseIrcMessage(t *testing.T) {
+	type testCase struct {
+		line     string
+		expected ircMessage
+	}
+
+	testCases := map[string]testCase{
+		"commandOnly": {
+			line:     "QUIT",
+			expected: ircMessage{Command: "QUIT"},
+		},
+		"lowerCaseCommand": {
+			line:     "ping server",
+			expected: ircMessage{Command: "PING", Params: []string{"server"}},
+		},
+		"withTrailing": {
+			line: "PRIVMSG #room :Hello there",
+			expected: ircMessage{
+				Command: "PRIVMSG",
+				Params:  []string{"#room", "Hello there"},
+			},
+		},
+		"withPrefix": {
+			line: ":nick!user@host JOIN #room\r\n",
+			expected: ircMessage{
+				Prefix:  "nick!user@host",
+				Command: "JOIN",
+				Params:  []string{"#room"},
+			},
+		},
+		"emptyTrailing": {
+			line: "TOPIC #room :",
+			expected: ircMessage{
+				Command: "TOPIC",
+				Params:  []string{"#room", ""},
+			},
+		},
+		"extraSpaces": {
+			line: "USER  guest 0  * :Real name",
+			expected: ircMessage{
+				Command: "USER",
+				Params:  []string{"guest", "0", "*", "Real name"},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, ok := parseIrcMessage(testCase.line)
+
+			assert.True(t, ok)
+			assert.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestUnit_ParseIrcMessage_WhenNoCommand_ExpectFailure(t *testing.T) {
+	for _, line := range []string{"", "   ", ":prefix-only", ":prefix "} {
+		_, ok := parseIrcMessage(line)
+		assert.False(t, ok, "Line: %q", line)
+	}
+}
+
+func TestUnit_IrcMessage_String(t *testing.T) {
+	type testCase struct {
+		msg      ircMessage
+		expected string
+	}
+
+	testCases := map[string]testCase{
+		"commandOnly": {
+			msg:      ircMessage{Command: "PONG"},
+			expected: "PONG",
+		},
+		"withPrefix": {
+			msg: ircMessage{
+				Prefix:  "chat-server",
+				Command: "001",
+				Params:  []string{"nick", "Welcome nick"},
+			},
+			expected: ":chat-server 001 nick :Welcome nick",
+		},
+		"singleWordLastParam": {
+			msg: ircMessage{
+				Command: "JOIN",
+				Params:  []string{"#room"},
+			},
+			expected: "JOIN #room",
+		},
+		"emptyLastParam": {
+			msg: ircMessage{
+				Command: "PRIVMSG",
+				Params:  []string{"#room", ""},
+			},
+			expected: "PRIVMSG #room :",
+		},
+		"lastParamStartingWithColon": {
+			msg: ircMessage{
+				Command: "PRIVMSG",
+				Params:  []string{"#room", ":)"},
+			},
+			expected: "PRIVMSG #room ::)",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, testCase.msg.String())
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package gateway
+
+import (
+	"strings"
+	"unicode"
+)
+
+const nickSpecialCharacters = "[]\\`_^{|}"
+
+// ircChannelName maps the name of a room to a channel: the characters that
+// can't appear in a channel name are replaced by '_'.
+func ircChannelName(room string) string {
+	return channelPrefix + strings.Map(func(r rune) rune {
+		if r == ' ' || r == ',' || r == ':' || unicode.IsControl(r) {
+			return '_'
+		}
+		return r
+	}, room)
+}
+
+// ircNick maps the name of a user to a nickname: the characters that can't
+// appear in a nickname are replaced by '_', which is also prepended when the
+// name does not start with a letter or a special character.
+func ircNick(user string) string {
+	nick := strings.Map(func(r rune) rune {
+		if isNickCharacter(r) {
+			return r
+		}
+		return '_'
+	}, user)
+
+	if nick == "" || nick[0] == '-' || (nick[0] >= '0' && nick[0] <= '9') {
+		nick = "_" + nick
+	}
+
+	return nick
+}
+
+func isNickCharacter(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+		return true
+	default:
+		return strings.ContainsRune(nickSpecialCharacters, r)
+	}
+}
+
+// ircLines splits a message in the lines sent to the clients. The control
+// characters are removed: a client could take them for the end of a line.
+func ircLines(message string) []string {
+	isLineBreak := func(r rune) bool {
+		return r == '\r' || r == '\n'
+	}
+
+	var lines []string
+	for _, line := range strings.FieldsFunc(message, isLineBreak) {
+		line = strings.Map(func(r rune) rune {
+			if unicode.IsControl(r) {
+				return -1
+			}
+			return r
+		}, line)
+
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,58 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_IrcChannelName(t *testing.T) {
+	testCases := map[string]string{
+		"general":      "#general",
+		"off topic":    "#off_topic",
+		"a,b:c":        "#a_b_c",
+		"bell\x07room": "#bell_room",
+		"café":         "#café",
+	}
+
+	for room, expected := range testCases {
+		t.Run(room, func(t *testing.T) {
+			assert.Equal(t, expected, ircChannelName(room))
+		})
+	}
+}
+
+func TestUnit_IrcNick(t *testing.T) {
+	testCases := map[string]string{
+		"alice":     "alice",
+		"bob smith": "bob_smith",
+		"[away]|x":  "[away]|x",
+		"élodie":    "_lodie",
+		"1337":      "_1337",
+		"-dash":     "_-dash",
+		"":          "_",
+	}
+
+	for user, expected := range testCases {
+		t.Run(user, func(t *testing.T) {
+			assert.Equal(t, expected, ircNick(user))
+		})
+	}
+}
+
+func TestUnit_IrcLines(t *testing.T) {
+	testCases := map[string][]string{
+		"Hello":              {"Hello"},
+		"Hello\nWorld":       {"Hello", "World"},
+		"Hello\r\nWorld\r\n": {"Hello", "World"},
+		"Hello\rQUIT":        {"Hello", "QUIT"},
+		"Nul\x00\x01byte":    {"Nulbyte"},
+		"\x00\r\n\n":         nil,
+	}
+
+	for message, expected := range testCases {
+		t.Run(message, func(t *testing.T) {
+			assert.Equal(t, expected, ircLines(message))
+		})
+	}
+}
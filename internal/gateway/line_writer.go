@@ -0,0 +1,40 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+)
+
+const writeTimeout = 5 * time.Second
+
+// lineWriter prevents the events and the replies sent to the client from
+// being interleaved.
+type lineWriter struct {
+	lock sync.Mutex
+	conn net.Conn
+}
+
+func (w *lineWriter) Write(data []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	// A client not reading its data should not block the events sent to
+	// the other clients.
+	if err := w.conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return 0, err
+	}
+
+	return w.conn.Write(data)
+}
+
+func (w *lineWriter) writeLine(value any) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(payload, '\n'))
+	return err
+}
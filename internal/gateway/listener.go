@@ -0,0 +1,159 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+)
+
+type Gateway interface {
+	Start() error
+	Stop() error
+}
+
+// connectionHandler serves a connection until it is closed or the context
+// is cancelled. The connection is closed once it returns.
+type connectionHandler func(ctx context.Context, conn net.Conn)
+
+// listenerImpl accepts the connections on a TCP port and serves each of
+// them in its own goroutine. When disabled it does not listen at all and
+// simply waits to be stopped.
+type listenerImpl struct {
+	enabled bool
+	port    uint16
+	handler connectionHandler
+
+	running atomic.Bool
+	stopped atomic.Bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	lock     sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+}
+
+func newListener(enabled bool, port uint16, handler connectionHandler) *listenerImpl {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &listenerImpl{
+		enabled: enabled,
+		port:    port,
+		handler: handler,
+
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}, 1),
+
+		conns: make(map[net.Conn]struct{}),
+	}
+}
+
+func (l *listenerImpl) Start() error {
+	if !l.running.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	defer func() {
+		l.done <- struct{}{}
+	}()
+
+	if !l.enabled {
+		<-l.ctx.Done()
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", l.port))
+	if err != nil {
+		return errors.WrapCode(err, ErrListenFailed)
+	}
+
+	l.lock.Lock()
+	l.listener = listener
+	l.lock.Unlock()
+
+	// The gateway may have been stopped before the listener was created.
+	if l.ctx.Err() != nil {
+		listener.Close()
+	}
+
+	var wg sync.WaitGroup
+
+	for {
+		conn, acceptErr := listener.Accept()
+		if acceptErr != nil {
+			if l.ctx.Err() == nil {
+				err = errors.WrapCode(acceptErr, ErrAcceptFailed)
+			}
+			break
+		}
+
+		l.track(conn)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer l.untrack(conn)
+			defer conn.Close()
+
+			l.handler(l.ctx, conn)
+		}()
+	}
+
+	// Connections waiting for data from the client would not notice that
+	// the context is cancelled.
+	l.closeConnections()
+	wg.Wait()
+
+	return err
+}
+
+func (l *listenerImpl) Stop() error {
+	if !l.stopped.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	l.cancel()
+
+	l.lock.Lock()
+	if l.listener != nil {
+		l.listener.Close()
+	}
+	l.lock.Unlock()
+
+	// A component stopped before it started returns as soon as it starts:
+	// there is nothing to wait for.
+	if l.running.Load() {
+		<-l.done
+	}
+
+	return nil
+}
+
+func (l *listenerImpl) track(conn net.Conn) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.conns[conn] = struct{}{}
+}
+
+func (l *listenerImpl) untrack(conn net.Conn) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	delete(l.conns, conn)
+}
+
+func (l *listenerImpl) closeConnections() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for conn := range l.conns {
+		conn.Close()
+	}
+}
@@ -0,0 +1,84 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_Listener_WhenDisabled_ExpectStopsWithoutListening(t *testing.T) {
+	l := newListener(false, 0, nil)
+
+	wg := asyncStartListenerAndAssertNoError(t, l)
+
+	assert.Nil(t, l.listener)
+
+	err := l.Stop()
+	wg.Wait()
+	assert.Nil(t, err, "Actual err: %v", err)
+}
+
+func TestUnit_Listener_WhenStoppedBeforeStart_ExpectStartReturns(t *testing.T) {
+	l := newListener(true, 0, nil)
+
+	err := l.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- l.Start()
+	}()
+
+	select {
+	case err = <-done:
+		assert.Nil(t, err, "Actual err: %v", err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "Listener started after being stopped")
+	}
+}
+
+func TestUnit_Listener_ServesConnections(t *testing.T) {
+	handler := func(_ context.Context, conn net.Conn) {
+		conn.Write([]byte("hello\n"))
+	}
+	l := newListener(true, 0, handler)
+
+	wg := asyncStartListenerAndAssertNoError(t, l)
+
+	conn := dialTestListener(t, l)
+	defer conn.Close()
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, "hello\n", line)
+
+	err = l.Stop()
+	wg.Wait()
+	assert.Nil(t, err, "Actual err: %v", err)
+}
+
+func TestUnit_Listener_WhenStopped_ExpectIdleConnectionsClosed(t *testing.T) {
+	handler := func(_ context.Context, conn net.Conn) {
+		// Blocks until the connection is closed.
+		bufio.NewReader(conn).ReadString('\n')
+	}
+	l := newListener(true, 0, handler)
+
+	wg := asyncStartListenerAndAssertNoError(t, l)
+
+	conn := dialTestListener(t, l)
+	defer conn.Close()
+	// Wait for the connection to be accepted
+	time.Sleep(50 * time.Millisecond)
+
+	err := l.Stop()
+	wg.Wait()
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	_, err = bufio.NewReader(conn).ReadString('\n')
+	assert.NotNil(t, err)
+}
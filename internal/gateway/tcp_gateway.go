@@ -0,0 +1,176 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/internal/frames"
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/clients"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/google/uuid"
+)
+
+type TcpConfig struct {
+	Enabled bool
+	Port    uint16
+}
+
+type tcpGatewayImpl struct {
+	messageService      service.MessageService
+	registrationService service.RegistrationService
+}
+
+// NewTcpGateway serves the clients speaking a newline-delimited JSON
+// protocol: each line sent by the client is a frame answered by a reply
+// and the events are sent as one JSON object per line.
+func NewTcpGateway(config TcpConfig, services service.Services) Gateway {
+	g := &tcpGatewayImpl{
+		messageService:      services.Message,
+		registrationService: services.Registration,
+	}
+
+	return newListener(config.Enabled, config.Port, g.serve)
+}
+
+func (g *tcpGatewayImpl) serve(ctx context.Context, conn net.Conn) {
+	w := &lineWriter{conn: conn}
+	lines := bufio.NewScanner(conn)
+
+	user, ok := g.authenticate(lines, w)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		g.readLinesUntilClosed(ctx, lines, w, user)
+	}()
+
+	err := g.messageService.ServeTcp(ctx, user, w)
+	if err != nil {
+		// This reply does not answer any frame: it tells the client why
+		// the connection is about to be closed.
+		reply := communication.ReplyDtoResponse{
+			Type: communication.ReplyFrame,
+		}
+		reply.Status, reply.Data = serveError(err)
+		w.writeLine(reply)
+	}
+
+	// Unblocks the read loop.
+	conn.Close()
+	wg.Wait()
+}
+
+// authenticate waits for the client to send an auth frame. The other
+// frames are rejected until then.
+func (g *tcpGatewayImpl) authenticate(
+	lines *bufio.Scanner, w *lineWriter,
+) (uuid.UUID, bool) {
+	for lines.Scan() {
+		reply, user := handleAuthLine(lines.Bytes())
+
+		if err := w.writeLine(reply); err != nil {
+			return uuid.Nil, false
+		}
+		if user != uuid.Nil {
+			return user, true
+		}
+	}
+
+	return uuid.Nil, false
+}
+
+func handleAuthLine(data []byte) (communication.ReplyDtoResponse, uuid.UUID) {
+	out := communication.ReplyDtoResponse{
+		Type: communication.ReplyFrame,
+	}
+
+	var frame communication.FrameDtoRequest
+	err := json.Unmarshal(data, &frame)
+	if err != nil {
+		out.Status, out.Data = http.StatusBadRequest, "Invalid frame syntax"
+		return out, uuid.Nil
+	}
+
+	out.Ref = frame.Ref
+	if frame.Type != communication.AuthFrame {
+		out.Status, out.Data = http.StatusUnauthorized, "Authentication required"
+		return out, uuid.Nil
+	}
+
+	var authDtoRequest communication.AuthDtoRequest
+	err = json.Unmarshal(frame.Data, &authDtoRequest)
+	if err != nil || authDtoRequest.User == uuid.Nil {
+		out.Status, out.Data = http.StatusBadRequest, "Invalid user id"
+		return out, uuid.Nil
+	}
+
+	out.Status = http.StatusNoContent
+	return out, authDtoRequest.User
+}
+
+func (g *tcpGatewayImpl) readLinesUntilClosed(
+	ctx context.Context,
+	lines *bufio.Scanner,
+	w *lineWriter,
+	user uuid.UUID,
+) {
+	for lines.Scan() {
+		reply := g.handleLine(ctx, user, lines.Bytes())
+
+		if err := w.writeLine(reply); err != nil {
+			return
+		}
+	}
+}
+
+func (g *tcpGatewayImpl) handleLine(
+	ctx context.Context, user uuid.UUID, data []byte,
+) communication.ReplyDtoResponse {
+	out := communication.ReplyDtoResponse{
+		Type: communication.ReplyFrame,
+	}
+
+	var frame communication.FrameDtoRequest
+	err := json.Unmarshal(data, &frame)
+	if err != nil {
+		out.Status, out.Data = http.StatusBadRequest, "Invalid frame syntax"
+		return out
+	}
+
+	out.Ref = frame.Ref
+
+	switch frame.Type {
+	case communication.AuthFrame:
+		out.Status, out.Data = http.StatusBadRequest, "Already authenticated"
+	case communication.JoinFrame:
+		out.Status, out.Data = frames.DispatchJoin(ctx, user, g.registrationService, frame)
+	default:
+		out.Status, out.Data = frames.Dispatch(ctx, user, g.messageService, frame)
+	}
+
+	return out
+}
+
+func serveError(err error) (int, any) {
+	if errors.IsErrorWithCode(err, service.ErrShuttingDown) {
+		return http.StatusServiceUnavailable, "Server is shutting down"
+	} else if errors.IsErrorWithCode(err, clients.ErrClientAlreadyRegistered) {
+		return http.StatusConflict, "User is already connected"
+	}
+
+	return http.StatusInternalServerError, err
+}
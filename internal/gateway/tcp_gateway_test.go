@@ -0,0 +1,153 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/internal/service"
+	"github.com/Knoblauchpilze/chat-server/pkg/clients"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_HandleAuthLine(t *testing.T) {
+	user := uuid.New()
+
+	out, actual := handleAuthLine(fmt.Appendf(nil, `{"ref":"1","type":"auth","data":{"user":"%s"}}`, user))
+
+	expected := communication.ReplyDtoResponse{
+		Ref:    "1",
+		Type:   communication.ReplyFrame,
+		Status: http.StatusNoContent,
+	}
+	assert.Equal(t, expected, out)
+	assert.Equal(t, user, actual)
+}
+
+func TestUnit_HandleAuthLine_WhenFrameIsNotAuth_ExpectUnauthorized(t *testing.T) {
+	out, user := handleAuthLine([]byte(`{"ref":"1","type":"message","data":{}}`))
+
+	expected := communication.ReplyDtoResponse{
+		Ref:    "1",
+		Type:   communication.ReplyFrame,
+		Status: http.StatusUnauthorized,
+		Data:   "Authentication required",
+	}
+	assert.Equal(t, expected, out)
+	assert.Equal(t, uuid.Nil, user)
+}
+
+func TestUnit_HandleAuthLine_WhenUserIsMissing_ExpectBadRequest(t *testing.T) {
+	out, user := handleAuthLine([]byte(`{"ref":"1","type":"auth","data":{}}`))
+
+	expected := communication.ReplyDtoResponse{
+		Ref:    "1",
+		Type:   communication.ReplyFrame,
+		Status: http.StatusBadRequest,
+		Data:   "Invalid user id",
+	}
+	assert.Equal(t, expected, out)
+	assert.Equal(t, uuid.Nil, user)
+}
+
+func TestUnit_HandleAuthLine_WhenFrameHasWrongSyntax_ExpectBadRequest(t *testing.T) {
+	out, user := handleAuthLine([]byte("not-a-frame"))
+
+	expected := communication.ReplyDtoResponse{
+		Type:   communication.ReplyFrame,
+		Status: http.StatusBadRequest,
+		Data:   "Invalid frame syntax",
+	}
+	assert.Equal(t, expected, out)
+	assert.Equal(t, uuid.Nil, user)
+}
+
+func TestUnit_TcpGateway_HandleLine_WhenAlreadyAuthenticated_ExpectBadRequest(t *testing.T) {
+	g := &tcpGatewayImpl{}
+
+	out := g.handleLine(context.Background(), uuid.New(), []byte(`{"ref":"2","type":"auth","data":{}}`))
+
+	expected := communication.ReplyDtoResponse{
+		Ref:    "2",
+		Type:   communication.ReplyFrame,
+		Status: http.StatusBadRequest,
+		Data:   "Already authenticated",
+	}
+	assert.Equal(t, expected, out)
+}
+
+func TestIT_TcpGateway_AuthJoinAndReceiveEvents(t *testing.T) {
+	dbConn := newTestDbConnection(t)
+	defer dbConn.Close(context.Background())
+	repos := repositories.New(dbConn)
+	manager := clients.NewManager(time.Minute, clients.PollPolicy{}, repos)
+	opts := service.MessageServiceOpts{
+		DbConn:                 dbConn,
+		Repos:                  repos,
+		Manager:                manager,
+		Dispatcher:             manager,
+		ClientMessageQueueSize: 1,
+	}
+	services := service.Services{
+		Message:      service.NewMessageService(opts),
+		Registration: service.NewRegistrationService(dbConn, repos, manager),
+	}
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+
+	g := &tcpGatewayImpl{
+		messageService:      services.Message,
+		registrationService: services.Registration,
+	}
+	l := newListener(true, 0, g.serve)
+	wg := asyncStartListenerAndAssertNoError(t, l)
+
+	conn := dialTestListener(t, l)
+	defer conn.Close()
+	lines := bufio.NewScanner(conn)
+
+	send := func(line string) string {
+		_, err := fmt.Fprintln(conn, line)
+		assert.Nil(t, err, "Actual err: %v", err)
+		assert.True(t, lines.Scan(), "Actual err: %v", lines.Err())
+		return lines.Text()
+	}
+
+	reply := send(`{"ref":"1","type":"join","data":{}}`)
+	assert.Equal(t, `{"ref":"1","type":"reply","status":401,"data":"Authentication required"}`, reply)
+
+	reply = send(fmt.Sprintf(`{"ref":"2","type":"auth","data":{"user":"%s"}}`, user.Id))
+	assert.Equal(t, `{"ref":"2","type":"reply","status":204}`, reply)
+
+	reply = send(fmt.Sprintf(`{"ref":"3","type":"join","data":{"room":"%s"}}`, room.Id))
+	assert.Equal(t, `{"ref":"3","type":"reply","status":204}`, reply)
+
+	// The event and the reply can be received in any order.
+	_, err := fmt.Fprintf(conn, `{"ref":"4","type":"typing","data":{"room":"%s"}}`+"\n", room.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	var typingReply, typingEvent string
+	for range 2 {
+		assert.True(t, lines.Scan(), "Actual err: %v", lines.Err())
+		if strings.Contains(lines.Text(), `"type":"reply"`) {
+			typingReply = lines.Text()
+		} else {
+			typingEvent = lines.Text()
+		}
+	}
+
+	assert.Equal(t, `{"ref":"4","type":"reply","status":204}`, typingReply)
+	expectedData := fmt.Sprintf(`"type":"typing","data":{"room":"%s","user":"%s"}`, room.Id, user.Id)
+	assert.Contains(t, typingEvent, expectedData)
+
+	err = l.Stop()
+	wg.Wait()
+	assert.Nil(t, err, "Actual err: %v", err)
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/Knoblauchpilze/chat-server/pkg/storage"
+	"github.com/Knoblauchpilze/chat-server/pkg/thumbnails"
+	"github.com/google/uuid"
+)
+
+// https://pkg.go.dev/net/http#DetectContentType
+const mimeSniffLength = 512
+
+var thumbnailMimeTypes = []string{"image/png", "image/jpeg", "image/gif"}
+
+type AttachmentService interface {
+	Upload(ctx context.Context, attachmentDto communication.AttachmentDtoRequest, data io.Reader) (communication.AttachmentDtoResponse, error)
+	Download(ctx context.Context, user uuid.UUID, id uuid.UUID) (communication.AttachmentDtoResponse, io.ReadCloser, error)
+	DownloadThumbnail(ctx context.Context, user uuid.UUID, id uuid.UUID) (io.ReadCloser, error)
+}
+
+type AttachmentServiceOpts struct {
+	Repos            repositories.Repositories
+	Store            storage.BlobStore
+	MaxSizeInBytes   int64
+	AllowedMimeTypes []string
+	Thumbnails       thumbnails.Generator
+}
+
+type attachmentServiceImpl struct {
+	attachmentRepo repositories.AttachmentRepository
+	roomRepo       repositories.RoomRepository
+
+	store            storage.BlobStore
+	maxSizeInBytes   int64
+	allowedMimeTypes []string
+	thumbnails       thumbnails.Generator
+}
+
+func NewAttachmentService(opts AttachmentServiceOpts) AttachmentService {
+	return &attachmentServiceImpl{
+		attachmentRepo:   opts.Repos.Attachment,
+		roomRepo:         opts.Repos.Room,
+		store:            opts.Store,
+		maxSizeInBytes:   opts.MaxSizeInBytes,
+		allowedMimeTypes: opts.AllowedMimeTypes,
+		thumbnails:       opts.Thumbnails,
+	}
+}
+
+func (s *attachmentServiceImpl) Upload(
+	ctx context.Context, attachmentDto communication.AttachmentDtoRequest, data io.Reader,
+) (communication.AttachmentDtoResponse, error) {
+	attachment := communication.FromAttachmentDtoRequest(attachmentDto)
+
+	if attachment.Name == "" {
+		return communication.AttachmentDtoResponse{}, errors.NewCode(ErrInvalidName)
+	}
+
+	registered, err := s.roomRepo.UserInRoom(ctx, attachment.ChatUser, attachment.Room)
+	if err != nil {
+		return communication.AttachmentDtoResponse{}, err
+	}
+	if !registered {
+		return communication.AttachmentDtoResponse{}, errors.NewCode(ErrUserNotInRoom)
+	}
+
+	header := make([]byte, mimeSniffLength)
+	n, err := io.ReadFull(data, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return communication.AttachmentDtoResponse{}, err
+	}
+	header = header[:n]
+
+	attachment.MimeType = http.DetectContentType(header)
+	if !s.isAllowed(attachment.MimeType) {
+		return communication.AttachmentDtoResponse{}, errors.NewCode(ErrUnsupportedMimeType)
+	}
+
+	// Read one byte more than allowed so that we can detect oversized files
+	// without having to trust the size announced by the client.
+	content := io.LimitReader(
+		io.MultiReader(bytes.NewReader(header), data), s.maxSizeInBytes+1,
+	)
+
+	key := attachment.Id.String()
+	attachment.Size, err = s.store.Put(ctx, key, content)
+	if err == nil && attachment.Size > s.maxSizeInBytes {
+		err = errors.NewCode(ErrAttachmentTooLarge)
+	}
+	if err != nil {
+		s.store.Delete(ctx, key)
+		return communication.AttachmentDtoResponse{}, err
+	}
+
+	created, err := s.attachmentRepo.Create(ctx, attachment)
+	if err != nil {
+		s.store.Delete(ctx, key)
+		return communication.AttachmentDtoResponse{}, err
+	}
+
+	if s.thumbnails != nil && supportsThumbnail(created.MimeType) {
+		s.thumbnails.Enqueue(created)
+	}
+
+	out := communication.ToAttachmentDtoResponse(created)
+	return out, nil
+}
+
+func (s *attachmentServiceImpl) Download(
+	ctx context.Context, user uuid.UUID, id uuid.UUID,
+) (communication.AttachmentDtoResponse, io.ReadCloser, error) {
+	attachment, err := s.attachmentRepo.Get(ctx, id)
+	if err != nil {
+		return communication.AttachmentDtoResponse{}, nil, err
+	}
+
+	registered, err := s.roomRepo.UserInRoom(ctx, user, attachment.Room)
+	if err != nil {
+		return communication.AttachmentDtoResponse{}, nil, err
+	}
+	if !registered {
+		return communication.AttachmentDtoResponse{}, nil, errors.NewCode(ErrUserNotInRoom)
+	}
+
+	data, err := s.store.Get(ctx, attachment.Id.String())
+	if err != nil {
+		return communication.AttachmentDtoResponse{}, nil, err
+	}
+
+	out := communication.ToAttachmentDtoResponse(attachment)
+	return out, data, nil
+}
+
+func (s *attachmentServiceImpl) DownloadThumbnail(
+	ctx context.Context, user uuid.UUID, id uuid.UUID,
+) (io.ReadCloser, error) {
+	attachment, err := s.attachmentRepo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	registered, err := s.roomRepo.UserInRoom(ctx, user, attachment.Room)
+	if err != nil {
+		return nil, err
+	}
+	if !registered {
+		return nil, errors.NewCode(ErrUserNotInRoom)
+	}
+
+	if attachment.Thumbnail == nil {
+		return nil, errors.NewCode(ErrNoThumbnail)
+	}
+
+	return s.store.Get(ctx, *attachment.Thumbnail)
+}
+
+func (s *attachmentServiceImpl) isAllowed(mimeType string) bool {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return false
+	}
+
+	return slices.Contains(s.allowedMimeTypes, mediaType)
+}
+
+func supportsThumbnail(mimeType string) bool {
+	return slices.Contains(thumbnailMimeTypes, mimeType)
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/Knoblauchpilze/chat-server/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var testPngHeader = []byte("\x89PNG\x0D\x0A\x1A\x0A")
+
+func TestIT_AttachmentService_Upload(t *testing.T) {
+	service, dbConn, store := newTestAttachmentService(t, 1024)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	attachmentDtoRequest := communication.AttachmentDtoRequest{
+		User: user.Id,
+		Room: room.Id,
+		Name: "my-file.png",
+	}
+
+	out, err := service.Upload(
+		context.Background(), attachmentDtoRequest, bytes.NewReader(testPngHeader),
+	)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, user.Id, out.User)
+	assert.Equal(t, room.Id, out.Room)
+	assert.Equal(t, "my-file.png", out.Name)
+	assert.Equal(t, "image/png", out.MimeType)
+	assert.Equal(t, int64(len(testPngHeader)), out.Size)
+	assertAttachmentExists(t, dbConn, out.Id)
+	assert.Equal(t, testPngHeader, readTestBlob(t, store, out.Id.String()))
+}
+
+func TestIT_AttachmentService_Upload_WhenNameIsEmpty_ExpectError(t *testing.T) {
+	service, dbConn, _ := newTestAttachmentService(t, 1024)
+	defer dbConn.Close(context.Background())
+
+	attachmentDtoRequest := communication.AttachmentDtoRequest{
+		User: uuid.New(),
+		Room: uuid.New(),
+	}
+
+	_, err := service.Upload(
+		context.Background(), attachmentDtoRequest, bytes.NewReader(testPngHeader),
+	)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrInvalidName),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_AttachmentService_Upload_WhenUserNotInRoom_ExpectError(t *testing.T) {
+	service, dbConn, _ := newTestAttachmentService(t, 1024)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+
+	attachmentDtoRequest := communication.AttachmentDtoRequest{
+		User: user.Id,
+		Room: room.Id,
+		Name: "my-file.png",
+	}
+
+	_, err := service.Upload(
+		context.Background(), attachmentDtoRequest, bytes.NewReader(testPngHeader),
+	)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrUserNotInRoom),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_AttachmentService_Upload_WhenMimeTypeIsNotAllowed_ExpectError(t *testing.T) {
+	service, dbConn, _ := newTestAttachmentService(t, 1024)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	attachmentDtoRequest := communication.AttachmentDtoRequest{
+		User: user.Id,
+		Room: room.Id,
+		Name: "my-file.html",
+	}
+
+	data := []byte("<html><body>hello</body></html>")
+	_, err := service.Upload(
+		context.Background(), attachmentDtoRequest, bytes.NewReader(data),
+	)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrUnsupportedMimeType),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_AttachmentService_Upload_WhenAttachmentIsTooLarge_ExpectError(t *testing.T) {
+	service, dbConn, _ := newTestAttachmentService(t, 4)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	attachmentDtoRequest := communication.AttachmentDtoRequest{
+		User: user.Id,
+		Room: room.Id,
+		Name: "my-file.png",
+	}
+
+	_, err := service.Upload(
+		context.Background(), attachmentDtoRequest, bytes.NewReader(testPngHeader),
+	)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrAttachmentTooLarge),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_AttachmentService_Download(t *testing.T) {
+	service, dbConn, store := newTestAttachmentService(t, 1024)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	attachment := insertTestAttachment(t, dbConn, store, user.Id, room.Id)
+
+	out, data, err := service.Download(context.Background(), user.Id, attachment.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	defer data.Close()
+
+	assert.Equal(t, communication.ToAttachmentDtoResponse(attachment), out)
+	actual, err := io.ReadAll(data)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, testPngHeader, actual)
+}
+
+func TestIT_AttachmentService_Download_WhenAttachmentDoesNotExist_ExpectError(t *testing.T) {
+	service, dbConn, _ := newTestAttachmentService(t, 1024)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+
+	_, _, err := service.Download(context.Background(), user.Id, uuid.New())
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_AttachmentService_Download_WhenUserNotInRoom_ExpectError(t *testing.T) {
+	service, dbConn, store := newTestAttachmentService(t, 1024)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	other := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	attachment := insertTestAttachment(t, dbConn, store, user.Id, room.Id)
+
+	_, _, err := service.Download(context.Background(), other.Id, attachment.Id)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrUserNotInRoom),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_AttachmentService_Upload_WhenImage_ExpectThumbnailRequested(t *testing.T) {
+	dbConn := newTestDbConnection(t)
+	defer dbConn.Close(context.Background())
+	store, err := storage.NewFilesystemStore(t.TempDir())
+	assert.Nil(t, err, "Actual err: %v", err)
+	generator := &mockThumbnailGenerator{}
+	opts := AttachmentServiceOpts{
+		Repos:            repositories.New(dbConn),
+		Store:            store,
+		MaxSizeInBytes:   1024,
+		AllowedMimeTypes: []string{"image/png", "text/plain"},
+		Thumbnails:       generator,
+	}
+	service := NewAttachmentService(opts)
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	image, err := service.Upload(
+		context.Background(),
+		communication.AttachmentDtoRequest{User: user.Id, Room: room.Id, Name: "my-file.png"},
+		bytes.NewReader(testPngHeader),
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	_, err = service.Upload(
+		context.Background(),
+		communication.AttachmentDtoRequest{User: user.Id, Room: room.Id, Name: "my-file.txt"},
+		bytes.NewReader([]byte("some text")),
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, 1, len(generator.attachments))
+	assert.Equal(t, image.Id, generator.attachments[0].Id)
+}
+
+func TestIT_AttachmentService_DownloadThumbnail(t *testing.T) {
+	service, dbConn, store := newTestAttachmentService(t, 1024)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	attachment := insertTestAttachment(t, dbConn, store, user.Id, room.Id)
+	insertTestThumbnail(t, dbConn, store, attachment.Id)
+
+	data, err := service.DownloadThumbnail(context.Background(), user.Id, attachment.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	defer data.Close()
+
+	actual, err := io.ReadAll(data)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, testPngHeader, actual)
+}
+
+func TestIT_AttachmentService_DownloadThumbnail_WhenNotGenerated_ExpectError(t *testing.T) {
+	service, dbConn, store := newTestAttachmentService(t, 1024)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	attachment := insertTestAttachment(t, dbConn, store, user.Id, room.Id)
+
+	_, err := service.DownloadThumbnail(context.Background(), user.Id, attachment.Id)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrNoThumbnail),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_AttachmentService_DownloadThumbnail_WhenUserNotInRoom_ExpectError(t *testing.T) {
+	service, dbConn, store := newTestAttachmentService(t, 1024)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	other := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	attachment := insertTestAttachment(t, dbConn, store, user.Id, room.Id)
+	insertTestThumbnail(t, dbConn, store, attachment.Id)
+
+	_, err := service.DownloadThumbnail(context.Background(), other.Id, attachment.Id)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrUserNotInRoom),
+		"Actual err: %v",
+		err,
+	)
+}
+
+type mockThumbnailGenerator struct {
+	attachments []persistence.Attachment
+}
+
+func (m *mockThumbnailGenerator) Start() error { return nil }
+func (m *mockThumbnailGenerator) Stop() error  { return nil }
+
+func (m *mockThumbnailGenerator) Enqueue(attachment persistence.Attachment) {
+	m.attachments = append(m.attachments, attachment)
+}
+
+func (m *mockThumbnailGenerator) TryEnqueue(attachment persistence.Attachment) bool {
+	m.Enqueue(attachment)
+	return true
+}
+
+func newTestAttachmentService(
+	t *testing.T, maxSizeInBytes int64,
+) (AttachmentService, db.Connection, storage.BlobStore) {
+	dbConn := newTestDbConnection(t)
+
+	store, err := storage.NewFilesystemStore(t.TempDir())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	opts := AttachmentServiceOpts{
+		Repos:            repositories.New(dbConn),
+		Store:            store,
+		MaxSizeInBytes:   maxSizeInBytes,
+		AllowedMimeTypes: []string{"image/png"},
+	}
+
+	return NewAttachmentService(opts), dbConn, store
+}
+
+func insertTestAttachment(
+	t *testing.T,
+	conn db.Connection,
+	store storage.BlobStore,
+	user uuid.UUID,
+	room uuid.UUID,
+) persistence.Attachment {
+	repo := repositories.NewAttachmentRepository(conn)
+
+	attachment := persistence.Attachment{
+		Id:       uuid.New(),
+		ChatUser: user,
+		Room:     room,
+		Name:     "my-file-" + uuid.NewString(),
+		MimeType: "image/png",
+		Size:     int64(len(testPngHeader)),
+	}
+
+	if store != nil {
+		_, err := store.Put(
+			context.Background(), attachment.Id.String(), bytes.NewReader(testPngHeader),
+		)
+		assert.Nil(t, err, "Actual err: %v", err)
+	}
+
+	out, err := repo.Create(context.Background(), attachment)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return out
+}
+
+func insertTestThumbnail(
+	t *testing.T, conn db.Connection, store storage.BlobStore, attachment uuid.UUID,
+) {
+	repo := repositories.NewAttachmentRepository(conn)
+
+	key := attachment.String() + "-thumbnail"
+	_, err := store.Put(context.Background(), key, bytes.NewReader(testPngHeader))
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	err = repo.UpdateThumbnail(context.Background(), attachment, 1, 1, key)
+	assert.Nil(t, err, "Actual err: %v", err)
+}
+
+func assertAttachmentExists(t *testing.T, conn db.Connection, id uuid.UUID) {
+	value, err := db.QueryOne[uuid.UUID](
+		context.Background(),
+		conn,
+		"SELECT id FROM attachment WHERE id = $1",
+		id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, id, value)
+}
+
+func assertAttachmentDoesNotExist(t *testing.T, conn db.Connection, id uuid.UUID) {
+	value, err := db.QueryOne[int](
+		context.Background(),
+		conn,
+		"SELECT COUNT(id) FROM attachment WHERE id = $1",
+		id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Zero(t, value)
+}
+
+func attachTestAttachmentToMessage(
+	t *testing.T, conn db.Connection, attachment uuid.UUID, msg uuid.UUID,
+) {
+	repo := repositories.NewAttachmentRepository(conn)
+
+	err := repo.AttachToMessage(context.Background(), msg, []uuid.UUID{attachment})
+	assert.Nil(t, err, "Actual err: %v", err)
+}
+
+func readTestBlob(t *testing.T, store storage.BlobStore, key string) []byte {
+	reader, err := store.Get(context.Background(), key)
+	assert.Nil(t, err, "Actual err: %v", err)
+	defer reader.Close()
+
+	out, err := io.ReadAll(reader)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return out
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
+)
+
+type DeadLetterService interface {
+	List(ctx context.Context) ([]communication.DeadLetterDtoResponse, error)
+	Replay(ctx context.Context, id uuid.UUID) (communication.MessageStatusDtoResponse, error)
+	Discard(ctx context.Context, id uuid.UUID) error
+}
+
+type deadLetterServiceImpl struct {
+	conn           db.Connection
+	deadLetterRepo repositories.DeadLetterRepository
+	statusRepo     repositories.MessageStatusRepository
+	outboxRepo     repositories.OutboxRepository
+
+	relay messages.OutboxRelay
+}
+
+func NewDeadLetterService(
+	conn db.Connection,
+	repos repositories.Repositories,
+	relay messages.OutboxRelay,
+) DeadLetterService {
+	return &deadLetterServiceImpl{
+		conn:           conn,
+		deadLetterRepo: repos.DeadLetter,
+		statusRepo:     repos.MessageStatus,
+		outboxRepo:     repos.Outbox,
+		relay:          relay,
+	}
+}
+
+func (s *deadLetterServiceImpl) List(
+	ctx context.Context,
+) ([]communication.DeadLetterDtoResponse, error) {
+	letters, err := s.deadLetterRepo.List(ctx)
+	if err != nil {
+		return []communication.DeadLetterDtoResponse{}, err
+	}
+
+	out := make([]communication.DeadLetterDtoResponse, 0, len(letters))
+	for _, letter := range letters {
+		dto := communication.ToDeadLetterDtoResponse(letter)
+		out = append(out, dto)
+	}
+
+	return out, nil
+}
+
+func (s *deadLetterServiceImpl) Replay(
+	ctx context.Context, id uuid.UUID,
+) (communication.MessageStatusDtoResponse, error) {
+	status, err := s.moveToOutbox(ctx, id)
+	if err != nil {
+		return communication.MessageStatusDtoResponse{}, err
+	}
+
+	s.relay.Notify()
+
+	return communication.ToMessageStatusDtoResponse(status), nil
+}
+
+// moveToOutbox removes the letter and writes the message back to the
+// outbox in a single transaction: concurrent replays can't dispatch the
+// message twice and the letter is kept if the message can't be queued.
+// If it fails again, a new letter will be created by the processor.
+func (s *deadLetterServiceImpl) moveToOutbox(
+	ctx context.Context, id uuid.UUID,
+) (persistence.MessageStatus, error) {
+	tx, err := s.conn.BeginTx(ctx)
+	if err != nil {
+		return persistence.MessageStatus{}, err
+	}
+	defer tx.Close(ctx)
+
+	letter, err := s.deadLetterRepo.Delete(ctx, tx, id)
+	if err != nil {
+		return persistence.MessageStatus{}, err
+	}
+
+	status, err := s.statusRepo.Requeue(ctx, tx, letter.Id, letter.Room)
+	if err != nil {
+		return persistence.MessageStatus{}, err
+	}
+
+	// The attachments which were removed in the meantime are skipped
+	// by the outbox relay.
+	entry := persistence.OutboxEntry{
+		Id:          letter.Id,
+		ChatUser:    letter.ChatUser,
+		Room:        letter.Room,
+		Message:     letter.Message,
+		Attachments: letter.Attachments,
+		ExpiresAt:   letter.ExpiresAt,
+	}
+	_, err = s.outboxRepo.Requeue(ctx, tx, entry)
+	if err != nil {
+		return persistence.MessageStatus{}, err
+	}
+
+	return status, nil
+}
+
+func (s *deadLetterServiceImpl) Discard(ctx context.Context, id uuid.UUID) error {
+	tx, err := s.conn.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Close(ctx)
+
+	_, err = s.deadLetterRepo.Delete(ctx, tx, id)
+	return err
+}
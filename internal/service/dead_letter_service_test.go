@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_DeadLetterService_List(t *testing.T) {
+	service, conn, _ := newTestDeadLetterService(t)
+	defer conn.Close(context.Background())
+	letter := insertTestDeadLetter(t, conn, uuid.New(), uuid.New())
+
+	out, err := service.List(context.Background())
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	ids := make([]uuid.UUID, 0, len(out))
+	for _, dto := range out {
+		ids = append(ids, dto.Id)
+	}
+	assert.Contains(t, ids, letter.Id)
+}
+
+func TestIT_DeadLetterService_Replay(t *testing.T) {
+	service, conn, mock := newTestDeadLetterService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	letter := insertTestDeadLetter(t, conn, user.Id, room.Id)
+
+	out, err := service.Replay(context.Background(), letter.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, letter.Id, out.Id)
+	assert.Equal(t, persistence.MessageQueued, out.Status)
+	assert.Equal(t, 1, mock.notified)
+	actual := getTestOutboxEntry(t, conn, letter.Id)
+	assert.Equal(t, letter.ChatUser, actual.ChatUser)
+	assert.Equal(t, letter.Room, actual.Room)
+	assert.Equal(t, letter.Message, actual.Message)
+	assert.Nil(t, actual.DeliveredAt)
+	assertDeadLetterDoesNotExist(t, conn, letter.Id)
+}
+
+func TestIT_DeadLetterService_Replay_WithAttachment_ExpectAttachmentWrittenToOutbox(t *testing.T) {
+	service, conn, _ := newTestDeadLetterService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	attachment := insertTestAttachment(t, conn, nil, user.Id, room.Id)
+	letter := insertTestDeadLetter(t, conn, user.Id, room.Id, attachment.Id)
+
+	_, err := service.Replay(context.Background(), letter.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	actual := getTestOutboxEntry(t, conn, letter.Id)
+	assert.Equal(t, []uuid.UUID{attachment.Id}, actual.Attachments)
+}
+
+func TestIT_DeadLetterService_Replay_WhenLetterDoesNotExist_ExpectError(t *testing.T) {
+	service, conn, mock := newTestDeadLetterService(t)
+	defer conn.Close(context.Background())
+
+	_, err := service.Replay(context.Background(), uuid.New())
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+	assert.Zero(t, mock.notified)
+}
+
+func TestIT_DeadLetterService_Discard(t *testing.T) {
+	service, conn, _ := newTestDeadLetterService(t)
+	defer conn.Close(context.Background())
+	letter := insertTestDeadLetter(t, conn, uuid.New(), uuid.New())
+
+	err := service.Discard(context.Background(), letter.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assertDeadLetterDoesNotExist(t, conn, letter.Id)
+}
+
+func TestIT_DeadLetterService_Discard_WhenLetterDoesNotExist_ExpectError(t *testing.T) {
+	service, conn, _ := newTestDeadLetterService(t)
+	defer conn.Close(context.Background())
+
+	err := service.Discard(context.Background(), uuid.New())
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func newTestDeadLetterService(
+	t *testing.T,
+) (DeadLetterService, db.Connection, *mockRelay) {
+	conn := newTestDbConnection(t)
+	mock := &mockRelay{}
+	return NewDeadLetterService(conn, repositories.New(conn), mock), conn, mock
+}
+
+func insertTestDeadLetter(
+	t *testing.T,
+	conn db.Connection,
+	user uuid.UUID,
+	room uuid.UUID,
+	attachments ...uuid.UUID,
+) persistence.DeadLetter {
+	repo := repositories.NewDeadLetterRepository(conn)
+
+	letter := persistence.DeadLetter{
+		Id:          uuid.New(),
+		ChatUser:    user,
+		Room:        room,
+		Message:     "Dead " + uuid.NewString(),
+		Attachments: attachments,
+		Reason:      "some reason",
+		Attempts:    1,
+	}
+	out, err := repo.Create(context.Background(), letter)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return out
+}
+
+func assertDeadLetterDoesNotExist(t *testing.T, conn db.Connection, id uuid.UUID) {
+	value, err := db.QueryOne[int](
+		context.Background(),
+		conn,
+		"SELECT COUNT(*) FROM dead_letter WHERE id = $1",
+		id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Zero(t, value)
+}
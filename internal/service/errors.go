@@ -9,4 +9,18 @@ const (
 	ErrEmptyMessage            errors.ErrorCode = 401
 	ErrUserNotInRoom           errors.ErrorCode = 402
 	ErrLeavingRoomIsNotAllowed errors.ErrorCode = 403
+	ErrAttachmentTooLarge      errors.ErrorCode = 404
+	ErrUnsupportedMimeType     errors.ErrorCode = 405
+	ErrInvalidAttachment       errors.ErrorCode = 406
+	ErrNoThumbnail             errors.ErrorCode = 407
+	ErrNotModerator            errors.ErrorCode = 408
+	ErrTooManyPins             errors.ErrorCode = 409
+	ErrInvalidSendAt           errors.ErrorCode = 410
+	ErrNotMessageAuthor        errors.ErrorCode = 411
+	ErrInvalidTtl              errors.ErrorCode = 412
+	ErrInvalidIdempotencyKey   errors.ErrorCode = 413
+	ErrShuttingDown            errors.ErrorCode = 414
+	ErrInvalidSequenceRange    errors.ErrorCode = 415
+	ErrMessageNotInRoom        errors.ErrorCode = 416
+	ErrInvalidEventType        errors.ErrorCode = 417
 )
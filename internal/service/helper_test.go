@@ -7,6 +7,7 @@ import (
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/postgresql"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
 	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
 	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
 	"github.com/google/uuid"
@@ -25,6 +26,26 @@ func newTestDbConnection(t *testing.T) db.Connection {
 	return conn
 }
 
+type mockMembershipDispatcher struct {
+	messages.Dispatcher
+
+	registered   []uuid.UUID
+	unregistered []uuid.UUID
+	deleted      []uuid.UUID
+}
+
+func (m *mockMembershipDispatcher) OnRegistration(user uuid.UUID, room uuid.UUID) {
+	m.registered = append(m.registered, user)
+}
+
+func (m *mockMembershipDispatcher) OnUnregistration(user uuid.UUID, room uuid.UUID) {
+	m.unregistered = append(m.unregistered, user)
+}
+
+func (m *mockMembershipDispatcher) OnRoomDeletion(room uuid.UUID) {
+	m.deleted = append(m.deleted, room)
+}
+
 func getRoomId(t *testing.T, conn db.Connection, name string) uuid.UUID {
 	sqlQuery := `SELECT id FROM room WHERE name = $1`
 
@@ -73,6 +94,33 @@ func registerUserByNameInRoom(t *testing.T, conn db.Connection, user string, roo
 	assert.Equal(t, int64(1), count)
 }
 
+func setUserAsModerator(t *testing.T, conn db.Connection, user uuid.UUID, room uuid.UUID) {
+	sqlQuery := `UPDATE room_user SET moderator = true WHERE room = $1 AND chat_user = $2`
+
+	count, err := conn.Exec(
+		context.Background(),
+		sqlQuery,
+		room,
+		user,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, int64(1), count)
+}
+
+func assertUserIsModerator(
+	t *testing.T, conn db.Connection, user uuid.UUID, room uuid.UUID,
+) {
+	value, err := db.QueryOne[bool](
+		context.Background(),
+		conn,
+		"SELECT moderator FROM room_user WHERE chat_user = $1 AND room = $2",
+		user,
+		room,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.True(t, value)
+}
+
 func assertUserRegisteredInRoom(
 	t *testing.T, conn db.Connection, user uuid.UUID, room string,
 ) {
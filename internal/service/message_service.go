@@ -2,60 +2,334 @@ package service
 
 import (
 	"context"
+	"io"
 	"net/http"
+	"slices"
+	"sync/atomic"
+	"time"
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/process"
 	"github.com/Knoblauchpilze/chat-server/pkg/clients"
 	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/encoders"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
 	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
 	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
 	"github.com/google/uuid"
 )
 
+const maxIdempotencyKeyLength = 255
+
 type MessageService interface {
-	PostMessage(ctx context.Context, messageDto communication.MessageDtoRequest) error
-	ServeClient(ctx context.Context, user uuid.UUID, response http.ResponseWriter) error
+	PostMessage(ctx context.Context, messageDto communication.MessageDtoRequest) (communication.MessageStatusDtoResponse, error)
+	ScheduleMessage(ctx context.Context, messageDto communication.MessageDtoRequest) (communication.ScheduledMessageDtoResponse, error)
+	GetStatus(ctx context.Context, id uuid.UUID) (communication.MessageStatusDtoResponse, error)
+	Typing(ctx context.Context, typingDto communication.TypingDtoRequest) error
+	MarkAsRead(ctx context.Context, markerDto communication.ReadMarkerDtoRequest) error
+	// ServeClient sends the events selected by the filter. The rooms of the
+	// filter should be rooms of the user. When snapshot is set, the state of
+	// the rooms is sent before any other event.
+	ServeClient(
+		ctx context.Context,
+		user uuid.UUID,
+		response http.ResponseWriter,
+		filter clients.Filter,
+		encoder encoders.Encoder,
+		snapshot bool,
+	) error
+	// ServeTcp sends the events as JSON lines to the writer, which should
+	// be safe for concurrent use.
+	ServeTcp(ctx context.Context, user uuid.UUID, w io.Writer) error
+	// ServeEvents hands the events to the callback, for the transports
+	// which need to translate them to their own protocol.
+	ServeEvents(ctx context.Context, user uuid.UUID, callback messages.MessageCallback[events.Event]) error
+	Poll(ctx context.Context, user uuid.UUID, since clients.Cursor) (communication.PollDtoResponse, error)
+	// StopAccepting rejects the messages and subscriptions received
+	// afterwards. It is used when the server shuts down.
+	StopAccepting()
 }
 
 type MessageServiceOpts struct {
 	DbConn                 db.Connection
 	Repos                  repositories.Repositories
-	Processor              messages.Processor
+	Relay                  messages.OutboxRelay
 	Manager                clients.Manager
+	Dispatcher             messages.Dispatcher
 	ClientMessageQueueSize int
+	IdempotencyWindow      time.Duration
 }
 
 type messageServiceImpl struct {
-	conn     db.Connection
-	roomRepo repositories.RoomRepository
+	conn           db.Connection
+	roomRepo       repositories.RoomRepository
+	attachmentRepo repositories.AttachmentRepository
+	scheduledRepo  repositories.ScheduledMessageRepository
+	keyRepo        repositories.IdempotencyKeyRepository
+	statusRepo     repositories.MessageStatusRepository
+	outboxRepo     repositories.OutboxRepository
+	messageRepo    repositories.MessageRepository
+	readMarkerRepo repositories.ReadMarkerRepository
+	snapshotRepo   repositories.SnapshotRepository
 
-	processor              messages.Processor
+	relay                  messages.OutboxRelay
 	manager                clients.Manager
+	dispatcher             messages.Dispatcher
 	clientMessageQueueSize int
+	idempotencyWindow      time.Duration
+
+	shuttingDown atomic.Bool
 }
 
 func NewMessageService(opts MessageServiceOpts) MessageService {
 	return &messageServiceImpl{
 		conn:                   opts.DbConn,
 		roomRepo:               opts.Repos.Room,
-		processor:              opts.Processor,
+		attachmentRepo:         opts.Repos.Attachment,
+		scheduledRepo:          opts.Repos.ScheduledMessage,
+		keyRepo:                opts.Repos.IdempotencyKey,
+		statusRepo:             opts.Repos.MessageStatus,
+		outboxRepo:             opts.Repos.Outbox,
+		messageRepo:            opts.Repos.Message,
+		readMarkerRepo:         opts.Repos.ReadMarker,
+		snapshotRepo:           opts.Repos.Snapshot,
+		relay:                  opts.Relay,
 		manager:                opts.Manager,
+		dispatcher:             opts.Dispatcher,
 		clientMessageQueueSize: opts.ClientMessageQueueSize,
+		idempotencyWindow:      opts.IdempotencyWindow,
 	}
 }
 
 func (s *messageServiceImpl) PostMessage(
 	ctx context.Context, messageDto communication.MessageDtoRequest,
-) error {
+) (communication.MessageStatusDtoResponse, error) {
+	if s.shuttingDown.Load() {
+		return communication.MessageStatusDtoResponse{}, errors.NewCode(ErrShuttingDown)
+	}
+
+	// Scheduled messages go through ScheduleMessage: posting them would
+	// silently send them right away.
+	if messageDto.SendAt != nil {
+		return communication.MessageStatusDtoResponse{}, errors.NewCode(ErrInvalidSendAt)
+	}
+
 	message := communication.FromMessageDtoRequest(messageDto)
 
+	if message.Message == "" && len(messageDto.Attachments) == 0 {
+		return communication.MessageStatusDtoResponse{}, errors.NewCode(ErrEmptyMessage)
+	}
+	if !isValidTtl(messageDto.Ttl) {
+		return communication.MessageStatusDtoResponse{}, errors.NewCode(ErrInvalidTtl)
+	}
+	if len(message.IdempotencyKey) > maxIdempotencyKeyLength {
+		return communication.MessageStatusDtoResponse{}, errors.NewCode(ErrInvalidIdempotencyKey)
+	}
+
+	if err := s.checkUserInRoom(ctx, message.ChatUser, message.Room); err != nil {
+		return communication.MessageStatusDtoResponse{}, err
+	}
+
+	for _, id := range messageDto.Attachments {
+		attachment, err := s.attachmentRepo.Get(ctx, id)
+		if errors.IsErrorWithCode(err, db.NoMatchingRows) {
+			return communication.MessageStatusDtoResponse{}, errors.NewCode(ErrInvalidAttachment)
+		} else if err != nil {
+			return communication.MessageStatusDtoResponse{}, err
+		}
+
+		valid := attachment.ChatUser == message.ChatUser &&
+			attachment.Room == message.Room &&
+			attachment.Message == nil
+		if !valid {
+			return communication.MessageStatusDtoResponse{}, errors.NewCode(ErrInvalidAttachment)
+		}
+
+		message.Attachments = append(message.Attachments, attachment)
+	}
+
+	status, duplicate, err := s.writeToOutbox(ctx, message)
+	if err != nil {
+		return communication.MessageStatusDtoResponse{}, err
+	}
+
+	// The message was already accepted: the client gets the same answer
+	// as for the first request.
+	if duplicate != nil {
+		status, err = s.statusRepo.Get(ctx, *duplicate)
+		if err != nil {
+			return communication.MessageStatusDtoResponse{}, err
+		}
+	} else {
+		s.relay.Notify()
+	}
+
+	return communication.ToMessageStatusDtoResponse(status), nil
+}
+
+// writeToOutbox durably stores the message before acknowledging it: it
+// will be handed to the processor by the outbox relay. The idempotency
+// key is claimed in the same transaction so that it is released if the
+// message can't be stored. When the key was already claimed, nothing is
+// written and the message which claimed it is returned.
+func (s *messageServiceImpl) writeToOutbox(
+	ctx context.Context, message persistence.Message,
+) (persistence.MessageStatus, *uuid.UUID, error) {
+	tx, err := s.conn.BeginTx(ctx)
+	if err != nil {
+		return persistence.MessageStatus{}, nil, err
+	}
+	defer tx.Close(ctx)
+
+	if message.IdempotencyKey != "" {
+		claimed, err := s.claimIdempotencyKey(ctx, tx, message)
+		if err != nil {
+			return persistence.MessageStatus{}, nil, err
+		}
+		if claimed.Message != message.Id {
+			return persistence.MessageStatus{}, &claimed.Message, nil
+		}
+	}
+
+	status := persistence.MessageStatus{
+		Message: message.Id,
+		Room:    message.Room,
+		Status:  persistence.MessageQueued,
+	}
+	status, err = s.statusRepo.Create(ctx, tx, status)
+	if err != nil {
+		return persistence.MessageStatus{}, nil, err
+	}
+
+	entry := persistence.OutboxEntry{
+		Id:             message.Id,
+		ChatUser:       message.ChatUser,
+		Room:           message.Room,
+		Message:        message.Message,
+		ExpiresAt:      message.ExpiresAt,
+		IdempotencyKey: message.IdempotencyKey,
+	}
+	for _, attachment := range message.Attachments {
+		entry.Attachments = append(entry.Attachments, attachment.Id)
+	}
+
+	_, err = s.outboxRepo.Create(ctx, tx, entry)
+	if err != nil {
+		return persistence.MessageStatus{}, nil, err
+	}
+
+	return status, nil, nil
+}
+
+func (s *messageServiceImpl) claimIdempotencyKey(
+	ctx context.Context, tx db.Transaction, message persistence.Message,
+) (persistence.IdempotencyKey, error) {
+	key := persistence.IdempotencyKey{
+		ChatUser: message.ChatUser,
+		Key:      message.IdempotencyKey,
+		Message:  message.Id,
+	}
+
+	notBefore := time.Now().Add(-s.idempotencyWindow)
+	return s.keyRepo.Claim(ctx, tx, key, notBefore)
+}
+
+func (s *messageServiceImpl) GetStatus(
+	ctx context.Context, id uuid.UUID,
+) (communication.MessageStatusDtoResponse, error) {
+	status, err := s.statusRepo.Get(ctx, id)
+	if err != nil {
+		return communication.MessageStatusDtoResponse{}, err
+	}
+
+	return communication.ToMessageStatusDtoResponse(status), nil
+}
+
+func (s *messageServiceImpl) ScheduleMessage(
+	ctx context.Context, messageDto communication.MessageDtoRequest,
+) (communication.ScheduledMessageDtoResponse, error) {
+	if s.shuttingDown.Load() {
+		return communication.ScheduledMessageDtoResponse{}, errors.NewCode(ErrShuttingDown)
+	}
+	if messageDto.SendAt == nil || !messageDto.SendAt.After(time.Now()) {
+		return communication.ScheduledMessageDtoResponse{}, errors.NewCode(ErrInvalidSendAt)
+	}
+
+	message := communication.FromScheduledMessageDtoRequest(messageDto)
+
 	if message.Message == "" {
-		return errors.NewCode(ErrEmptyMessage)
+		return communication.ScheduledMessageDtoResponse{}, errors.NewCode(ErrEmptyMessage)
+	}
+	// Attachments are linked to a message when it is persisted: this would
+	// mean keeping them dangling until the message is sent.
+	if len(messageDto.Attachments) > 0 {
+		return communication.ScheduledMessageDtoResponse{}, errors.NewCode(ErrInvalidAttachment)
+	}
+	// Scheduled messages only expire according to the default of the room.
+	if messageDto.Ttl != nil {
+		return communication.ScheduledMessageDtoResponse{}, errors.NewCode(ErrInvalidTtl)
+	}
+
+	if err := s.checkUserInRoom(ctx, message.ChatUser, message.Room); err != nil {
+		return communication.ScheduledMessageDtoResponse{}, err
 	}
 
-	registered, err := s.roomRepo.UserInRoom(ctx, message.ChatUser, message.Room)
+	created, err := s.scheduledRepo.Create(ctx, message)
+	if err != nil {
+		return communication.ScheduledMessageDtoResponse{}, err
+	}
+
+	return communication.ToScheduledMessageDtoResponse(created), nil
+}
+
+func (s *messageServiceImpl) Typing(
+	ctx context.Context, typingDto communication.TypingDtoRequest,
+) error {
+	if err := s.checkUserInRoom(ctx, typingDto.User, typingDto.Room); err != nil {
+		return err
+	}
+
+	typing := communication.ToTypingDtoResponse(typingDto)
+	return s.dispatcher.BroadcastEvent(events.FromTyping(typing))
+}
+
+func (s *messageServiceImpl) MarkAsRead(
+	ctx context.Context, markerDto communication.ReadMarkerDtoRequest,
+) error {
+	if err := s.checkUserInRoom(ctx, markerDto.User, markerDto.Room); err != nil {
+		return err
+	}
+
+	msg, err := s.messageRepo.Get(ctx, markerDto.Message)
+	if errors.IsErrorWithCode(err, db.NoMatchingRows) {
+		return errors.NewCode(ErrMessageNotInRoom)
+	} else if err != nil {
+		return err
+	}
+	if msg.Room != markerDto.Room {
+		return errors.NewCode(ErrMessageNotInRoom)
+	}
+
+	readMarker := persistence.ReadMarker{
+		Room:     msg.Room,
+		ChatUser: markerDto.User,
+		Message:  msg.Id,
+		Sequence: msg.Sequence,
+	}
+	if err := s.readMarkerRepo.Upsert(ctx, readMarker); err != nil {
+		return err
+	}
+
+	marker := communication.ToReadMarkerDtoResponse(markerDto)
+	return s.dispatcher.BroadcastEvent(events.FromReadMarker(marker))
+}
+
+func (s *messageServiceImpl) checkUserInRoom(
+	ctx context.Context, user uuid.UUID, room uuid.UUID,
+) error {
+	registered, err := s.roomRepo.UserInRoom(ctx, user, room)
 	if err != nil {
 		return err
 	}
@@ -63,25 +337,116 @@ func (s *messageServiceImpl) PostMessage(
 		return errors.NewCode(ErrUserNotInRoom)
 	}
 
-	s.processor.Enqueue(message)
-
 	return nil
 }
 
 func (s *messageServiceImpl) ServeClient(
-	ctx context.Context, user uuid.UUID, response http.ResponseWriter,
+	ctx context.Context,
+	user uuid.UUID,
+	response http.ResponseWriter,
+	filter clients.Filter,
+	encoder encoders.Encoder,
+	snapshot bool,
 ) error {
+	if err := s.validateFilter(ctx, user, filter); err != nil {
+		return err
+	}
+
+	var initial clients.InitialEventCallback
+	if snapshot {
+		initial = func() (events.Event, error) {
+			return s.snapshotEvent(ctx, user, filter)
+		}
+	}
+
 	// TODO: We could add some ping/pong mechanism. This could serve as a base
 	// for idle checking
-	client, err := clients.New(s.clientMessageQueueSize, user, response)
+	client, err := clients.New(s.clientMessageQueueSize, user, response, encoder, initial)
 	if err != nil {
 		return err
 	}
 
-	if err := s.manager.OnConnect(user, client); err != nil {
+	return s.serve(ctx, user, client, filter)
+}
+
+// snapshotEvent is computed once the client is registered: the events
+// received in the meantime are sent after it and can be deduplicated by
+// the client thanks to the sequence of the rooms.
+func (s *messageServiceImpl) snapshotEvent(
+	ctx context.Context, user uuid.UUID, filter clients.Filter,
+) (events.Event, error) {
+	tx, err := s.conn.BeginTx(ctx)
+	if err != nil {
+		return events.Event{}, err
+	}
+	defer tx.Close(ctx)
+
+	rooms, err := s.snapshotRepo.ListForUser(ctx, tx, user)
+	if err != nil {
+		return events.Event{}, err
+	}
+
+	if len(filter.Rooms) > 0 {
+		rooms = slices.DeleteFunc(rooms, func(room persistence.RoomSnapshot) bool {
+			return !slices.Contains(filter.Rooms, room.Room.Id)
+		})
+	}
+
+	return events.FromSnapshot(communication.ToSnapshotDtoResponse(rooms)), nil
+}
+
+func (s *messageServiceImpl) validateFilter(
+	ctx context.Context, user uuid.UUID, filter clients.Filter,
+) error {
+	for _, t := range filter.Types {
+		if !events.IsSubscribable(t) {
+			return errors.NewCode(ErrInvalidEventType)
+		}
+	}
+
+	for _, room := range filter.Rooms {
+		if err := s.checkUserInRoom(ctx, user, room); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *messageServiceImpl) ServeTcp(
+	ctx context.Context, user uuid.UUID, w io.Writer,
+) error {
+	client := clients.NewTcp(s.clientMessageQueueSize, user, w)
+	return s.serve(ctx, user, client, clients.Filter{})
+}
+
+func (s *messageServiceImpl) ServeEvents(
+	ctx context.Context,
+	user uuid.UUID,
+	callback messages.MessageCallback[events.Event],
+) error {
+	callbacks := messages.Callbacks[events.Event]{
+		Message: callback,
+	}
+	client := messages.NewProcessor(s.clientMessageQueueSize, callbacks)
+	return s.serve(ctx, user, client, clients.Filter{})
+}
+
+// serve registers the client with the manager and sends it the events
+// until either the context is cancelled or the client fails.
+func (s *messageServiceImpl) serve(
+	ctx context.Context, user uuid.UUID, client clients.Client, filter clients.Filter,
+) error {
+	// Clients registered after the manager stopped would never be closed.
+	if s.shuttingDown.Load() {
+		return errors.NewCode(ErrShuttingDown)
+	}
+
+	if err := s.manager.OnConnect(ctx, user, client, filter); err != nil {
 		return err
 	}
 
+	var err error
 	done := process.SafeRunAsync(client.Start)
 
 	select {
@@ -94,3 +459,36 @@ func (s *messageServiceImpl) ServeClient(
 
 	return err
 }
+
+func (s *messageServiceImpl) Poll(
+	ctx context.Context, user uuid.UUID, since clients.Cursor,
+) (communication.PollDtoResponse, error) {
+	if s.shuttingDown.Load() {
+		return communication.PollDtoResponse{}, errors.NewCode(ErrShuttingDown)
+	}
+
+	result, err := s.manager.Poll(ctx, user, since)
+	if err != nil {
+		return communication.PollDtoResponse{}, err
+	}
+
+	out := communication.PollDtoResponse{
+		Cursor: result.Cursor.String(),
+		Resync: result.Resync,
+		Events: make([]communication.EventDtoResponse, 0, len(result.Events)),
+	}
+	for _, event := range result.Events {
+		dto := communication.EventDtoResponse{
+			Id:   event.Id,
+			Type: string(event.Type),
+			Data: event.Data,
+		}
+		out.Events = append(out.Events, dto)
+	}
+
+	return out, nil
+}
+
+func (s *messageServiceImpl) StopAccepting() {
+	s.shuttingDown.Store(true)
+}
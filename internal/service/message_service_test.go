@@ -8,6 +8,8 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -16,6 +18,8 @@ import (
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
 	"github.com/Knoblauchpilze/chat-server/pkg/clients"
 	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/encoders"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
 	"github.com/Knoblauchpilze/chat-server/pkg/messages"
 	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
 	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
@@ -24,8 +28,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestIT_MessageService_PostMessage_SendsMessageToProcessor(t *testing.T) {
-	mock := &mockProcessor{}
+func TestIT_MessageService_PostMessage_WritesMessageToOutbox(t *testing.T) {
+	mock := &mockRelay{}
 	service, dbConn := newTestMessageService(t, mock, nil)
 	defer dbConn.Close(context.Background())
 	user := insertTestUser(t, dbConn)
@@ -38,19 +42,43 @@ func TestIT_MessageService_PostMessage_SendsMessageToProcessor(t *testing.T) {
 		Message: fmt.Sprintf("%s says hello to %s", user.Name, room.Id),
 	}
 
-	err := service.PostMessage(context.Background(), messageDtoRequest)
+	out, err := service.PostMessage(context.Background(), messageDtoRequest)
 
 	assert.Nil(t, err, "Actual err: %v", err)
-	assert.Len(t, mock.enqueued, 1)
-	actual := mock.enqueued[0]
+	assert.Equal(t, 1, mock.notified)
+	actual := getTestOutboxEntry(t, dbConn, out.Id)
 	assert.Equal(t, messageDtoRequest.User, actual.ChatUser)
 	assert.Equal(t, messageDtoRequest.Room, actual.Room)
 	assert.Equal(t, messageDtoRequest.Message, actual.Message)
+	assert.Nil(t, actual.DeliveredAt)
+}
+
+func TestIT_MessageService_PostMessage_ReturnsQueuedStatus(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:    user.Id,
+		Room:    room.Id,
+		Message: "hello there",
+	}
+
+	out, err := service.PostMessage(context.Background(), messageDtoRequest)
 
+	assert.Nil(t, err, "Actual err: %v", err)
+	actual := getTestOutboxEntry(t, dbConn, out.Id)
+	assert.Equal(t, actual.Id, out.Id)
+	assert.Equal(t, room.Id, out.Room)
+	assert.Equal(t, persistence.MessageQueued, out.Status)
+	assert.Nil(t, out.Reason)
 }
 
 func TestIT_MessageService_PostMessage_InvalidName(t *testing.T) {
-	mock := &mockProcessor{}
+	mock := &mockRelay{}
 	service, dbConn := newTestMessageService(t, mock, nil)
 	defer dbConn.Close(context.Background())
 	messageDtoRequest := communication.MessageDtoRequest{
@@ -59,7 +87,7 @@ func TestIT_MessageService_PostMessage_InvalidName(t *testing.T) {
 		Message: "",
 	}
 
-	err := service.PostMessage(context.Background(), messageDtoRequest)
+	_, err := service.PostMessage(context.Background(), messageDtoRequest)
 
 	assert.True(
 		t,
@@ -70,7 +98,7 @@ func TestIT_MessageService_PostMessage_InvalidName(t *testing.T) {
 }
 
 func TestIT_MessageService_PostMessage_WhenUserNotInRoom_ExpectError(t *testing.T) {
-	mock := &mockProcessor{}
+	mock := &mockRelay{}
 	service, dbConn := newTestMessageService(t, mock, nil)
 	defer dbConn.Close(context.Background())
 	messageDtoRequest := communication.MessageDtoRequest{
@@ -79,7 +107,7 @@ func TestIT_MessageService_PostMessage_WhenUserNotInRoom_ExpectError(t *testing.
 		Message: "hello there",
 	}
 
-	err := service.PostMessage(context.Background(), messageDtoRequest)
+	_, err := service.PostMessage(context.Background(), messageDtoRequest)
 
 	assert.True(
 		t,
@@ -89,15 +117,615 @@ func TestIT_MessageService_PostMessage_WhenUserNotInRoom_ExpectError(t *testing.
 	)
 }
 
+func TestIT_MessageService_PostMessage_WhenShuttingDown_ExpectError(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:    user.Id,
+		Room:    room.Id,
+		Message: "hello there",
+	}
+
+	service.StopAccepting()
+	_, err := service.PostMessage(context.Background(), messageDtoRequest)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrShuttingDown),
+		"Actual err: %v",
+		err,
+	)
+	assert.Equal(t, 0, mock.notified)
+}
+
+func TestIT_MessageService_PostMessage_WithTtl_ExpectExpirationWrittenToOutbox(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	ttl := 60
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:    user.Id,
+		Room:    room.Id,
+		Message: "this will self-destruct",
+		Ttl:     &ttl,
+	}
+
+	out, err := service.PostMessage(context.Background(), messageDtoRequest)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	actual := getTestOutboxEntry(t, dbConn, out.Id)
+	assert.NotNil(t, actual.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *actual.ExpiresAt, 5*time.Second)
+}
+
+func TestIT_MessageService_PostMessage_WithInvalidTtl_ExpectError(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+
+	ttl := 0
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:    uuid.New(),
+		Room:    uuid.New(),
+		Message: "hello there",
+		Ttl:     &ttl,
+	}
+
+	_, err := service.PostMessage(context.Background(), messageDtoRequest)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrInvalidTtl),
+		"Actual err: %v",
+		err,
+	)
+	assert.Zero(t, mock.notified)
+}
+
+func TestIT_MessageService_PostMessage_WithSendAt_ExpectError(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+
+	sendAt := time.Now().Add(time.Hour)
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:    uuid.New(),
+		Room:    uuid.New(),
+		Message: "hello there",
+		SendAt:  &sendAt,
+	}
+
+	_, err := service.PostMessage(context.Background(), messageDtoRequest)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrInvalidSendAt),
+		"Actual err: %v",
+		err,
+	)
+	assert.Zero(t, mock.notified)
+}
+
+func TestIT_MessageService_PostMessage_WithIdempotencyKey_ExpectKeyWrittenToOutbox(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:           user.Id,
+		Room:           room.Id,
+		Message:        "hello there",
+		IdempotencyKey: uuid.NewString(),
+	}
+
+	out, err := service.PostMessage(context.Background(), messageDtoRequest)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	actual := getTestOutboxEntry(t, dbConn, out.Id)
+	assert.Equal(t, messageDtoRequest.IdempotencyKey, actual.IdempotencyKey)
+}
+
+func TestIT_MessageService_PostMessage_WhenIdempotencyKeyIsReused_ExpectMessageSentOnce(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:           user.Id,
+		Room:           room.Id,
+		Message:        "hello there",
+		IdempotencyKey: uuid.NewString(),
+	}
+
+	first, err := service.PostMessage(context.Background(), messageDtoRequest)
+	assert.Nil(t, err, "Actual err: %v", err)
+	second, err := service.PostMessage(context.Background(), messageDtoRequest)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, 1, mock.notified)
+	assert.Equal(t, first, second)
+}
+
+func TestIT_MessageService_PostMessage_WhenIdempotencyKeyIsUsedByAnotherUser_ExpectMessageSent(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+	user1 := insertTestUser(t, dbConn)
+	user2 := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user1.Id, room.Id)
+	registerUserInRoom(t, dbConn, user2.Id, room.Id)
+
+	key := uuid.NewString()
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:           user1.Id,
+		Room:           room.Id,
+		Message:        "hello there",
+		IdempotencyKey: key,
+	}
+	_, err := service.PostMessage(context.Background(), messageDtoRequest)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	messageDtoRequest.User = user2.Id
+	_, err = service.PostMessage(context.Background(), messageDtoRequest)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, 2, mock.notified)
+}
+
+func TestIT_MessageService_PostMessage_WhenIdempotencyKeyIsTooLong_ExpectError(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:           uuid.New(),
+		Room:           uuid.New(),
+		Message:        "hello there",
+		IdempotencyKey: strings.Repeat("a", 256),
+	}
+
+	_, err := service.PostMessage(context.Background(), messageDtoRequest)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrInvalidIdempotencyKey),
+		"Actual err: %v",
+		err,
+	)
+	assert.Zero(t, mock.notified)
+}
+
+func TestIT_MessageService_PostMessage_WithAttachment_ExpectAttachmentWrittenToOutbox(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	attachment := insertTestAttachment(t, dbConn, nil, user.Id, room.Id)
+
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:        user.Id,
+		Room:        room.Id,
+		Attachments: []uuid.UUID{attachment.Id},
+	}
+
+	out, err := service.PostMessage(context.Background(), messageDtoRequest)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	actual := getTestOutboxEntry(t, dbConn, out.Id)
+	assert.Equal(t, []uuid.UUID{attachment.Id}, actual.Attachments)
+}
+
+func TestIT_MessageService_PostMessage_WhenAttachmentDoesNotExist_ExpectError(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:        user.Id,
+		Room:        room.Id,
+		Message:     "hello there",
+		Attachments: []uuid.UUID{uuid.New()},
+	}
+
+	_, err := service.PostMessage(context.Background(), messageDtoRequest)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrInvalidAttachment),
+		"Actual err: %v",
+		err,
+	)
+	assert.Zero(t, mock.notified)
+}
+
+func TestIT_MessageService_PostMessage_WhenAttachmentBelongsToAnotherUser_ExpectError(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	other := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	registerUserInRoom(t, dbConn, other.Id, room.Id)
+	attachment := insertTestAttachment(t, dbConn, nil, other.Id, room.Id)
+
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:        user.Id,
+		Room:        room.Id,
+		Message:     "hello there",
+		Attachments: []uuid.UUID{attachment.Id},
+	}
+
+	_, err := service.PostMessage(context.Background(), messageDtoRequest)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrInvalidAttachment),
+		"Actual err: %v",
+		err,
+	)
+	assert.Zero(t, mock.notified)
+}
+
+func TestIT_MessageService_GetStatus(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:    user.Id,
+		Room:    room.Id,
+		Message: "hello there",
+	}
+	posted, err := service.PostMessage(context.Background(), messageDtoRequest)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual, err := service.GetStatus(context.Background(), posted.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, posted, actual)
+}
+
+func TestIT_MessageService_GetStatus_WhenMessageDoesNotExist_ExpectError(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+
+	_, err := service.GetStatus(context.Background(), uuid.New())
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_MessageService_ScheduleMessage(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	sendAt := time.Now().Add(time.Hour)
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:    user.Id,
+		Room:    room.Id,
+		Message: fmt.Sprintf("%s will say hello to %s", user.Name, room.Id),
+		SendAt:  &sendAt,
+	}
+
+	out, err := service.ScheduleMessage(context.Background(), messageDtoRequest)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Zero(t, mock.notified)
+	assert.Equal(t, messageDtoRequest.User, out.User)
+	assert.Equal(t, messageDtoRequest.Room, out.Room)
+	assert.Equal(t, messageDtoRequest.Message, out.Message)
+	assert.True(t, sendAt.Round(time.Millisecond).Equal(out.SendAt.Round(time.Millisecond)))
+	assertScheduledMessageExists(t, dbConn, out.Id)
+}
+
+func TestIT_MessageService_ScheduleMessage_WhenSendAtIsInThePast_ExpectError(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+	sendAt := time.Now().Add(-time.Minute)
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:    uuid.New(),
+		Room:    uuid.New(),
+		Message: "hello there",
+		SendAt:  &sendAt,
+	}
+
+	_, err := service.ScheduleMessage(context.Background(), messageDtoRequest)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrInvalidSendAt),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_MessageService_ScheduleMessage_WhenUserNotInRoom_ExpectError(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+	sendAt := time.Now().Add(time.Hour)
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:    uuid.New(),
+		Room:    uuid.New(),
+		Message: "hello there",
+		SendAt:  &sendAt,
+	}
+
+	_, err := service.ScheduleMessage(context.Background(), messageDtoRequest)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrUserNotInRoom),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_MessageService_ScheduleMessage_WithAttachment_ExpectError(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	attachment := insertTestAttachment(t, dbConn, nil, user.Id, room.Id)
+
+	sendAt := time.Now().Add(time.Hour)
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:        user.Id,
+		Room:        room.Id,
+		Message:     "hello there",
+		Attachments: []uuid.UUID{attachment.Id},
+		SendAt:      &sendAt,
+	}
+
+	_, err := service.ScheduleMessage(context.Background(), messageDtoRequest)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrInvalidAttachment),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_MessageService_ScheduleMessage_WithTtl_ExpectError(t *testing.T) {
+	mock := &mockRelay{}
+	service, dbConn := newTestMessageService(t, mock, nil)
+	defer dbConn.Close(context.Background())
+
+	sendAt := time.Now().Add(time.Hour)
+	ttl := 60
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:    uuid.New(),
+		Room:    uuid.New(),
+		Message: "hello there",
+		SendAt:  &sendAt,
+		Ttl:     &ttl,
+	}
+
+	_, err := service.ScheduleMessage(context.Background(), messageDtoRequest)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrInvalidTtl),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_MessageService_Typing_ExpectEventBroadcast(t *testing.T) {
+	service, dbConn, dispatcher := newTestMessageServiceWithDispatcher(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	typing := communication.TypingDtoRequest{
+		User: user.Id,
+		Room: room.Id,
+	}
+	err := service.Typing(context.Background(), typing)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, 1, len(dispatcher.events))
+	assert.Equal(t, events.Typing, dispatcher.events[0].Type)
+	assert.Equal(t, room.Id, dispatcher.events[0].Room)
+	expected := communication.TypingDtoResponse{
+		Room: room.Id,
+		User: user.Id,
+	}
+	assert.Equal(t, expected, dispatcher.events[0].Data)
+}
+
+func TestIT_MessageService_Typing_WhenUserNotInRoom_ExpectError(t *testing.T) {
+	service, dbConn, dispatcher := newTestMessageServiceWithDispatcher(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+
+	typing := communication.TypingDtoRequest{
+		User: user.Id,
+		Room: room.Id,
+	}
+	err := service.Typing(context.Background(), typing)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrUserNotInRoom),
+		"Actual err: %v",
+		err,
+	)
+	assert.Empty(t, dispatcher.events)
+}
+
+func TestIT_MessageService_MarkAsRead_ExpectEventBroadcast(t *testing.T) {
+	service, dbConn, dispatcher := newTestMessageServiceWithDispatcher(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	msg := insertTestMessage(t, dbConn, user.Id, room.Id)
+
+	marker := communication.ReadMarkerDtoRequest{
+		User:    user.Id,
+		Room:    room.Id,
+		Message: msg.Id,
+	}
+	err := service.MarkAsRead(context.Background(), marker)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, 1, len(dispatcher.events))
+	assert.Equal(t, events.Read, dispatcher.events[0].Type)
+	assert.Equal(t, room.Id, dispatcher.events[0].Room)
+	expected := communication.ReadMarkerDtoResponse{
+		Room:    room.Id,
+		User:    user.Id,
+		Message: msg.Id,
+	}
+	assert.Equal(t, expected, dispatcher.events[0].Data)
+}
+
+func TestIT_MessageService_MarkAsRead_ExpectMarkerPersisted(t *testing.T) {
+	service, dbConn, _ := newTestMessageServiceWithDispatcher(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	msg := insertTestMessage(t, dbConn, user.Id, room.Id)
+
+	marker := communication.ReadMarkerDtoRequest{
+		User:    user.Id,
+		Room:    room.Id,
+		Message: msg.Id,
+	}
+	err := service.MarkAsRead(context.Background(), marker)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	repo := repositories.NewReadMarkerRepository(dbConn)
+	actual, err := repo.Get(context.Background(), user.Id, room.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, msg.Id, actual.Message)
+	assert.Equal(t, msg.Sequence, actual.Sequence)
+}
+
+func TestIT_MessageService_MarkAsRead_WhenUserNotInRoom_ExpectError(t *testing.T) {
+	service, dbConn, dispatcher := newTestMessageServiceWithDispatcher(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	author := insertTestUser(t, dbConn)
+	registerUserInRoom(t, dbConn, author.Id, room.Id)
+	msg := insertTestMessage(t, dbConn, author.Id, room.Id)
+
+	marker := communication.ReadMarkerDtoRequest{
+		User:    user.Id,
+		Room:    room.Id,
+		Message: msg.Id,
+	}
+	err := service.MarkAsRead(context.Background(), marker)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrUserNotInRoom),
+		"Actual err: %v",
+		err,
+	)
+	assert.Empty(t, dispatcher.events)
+}
+
+func TestIT_MessageService_MarkAsRead_WhenMessageIsInAnotherRoom_ExpectError(t *testing.T) {
+	service, dbConn, dispatcher := newTestMessageServiceWithDispatcher(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	otherRoom := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, otherRoom.Id)
+	msg := insertTestMessage(t, dbConn, user.Id, otherRoom.Id)
+
+	marker := communication.ReadMarkerDtoRequest{
+		User:    user.Id,
+		Room:    room.Id,
+		Message: msg.Id,
+	}
+	err := service.MarkAsRead(context.Background(), marker)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrMessageNotInRoom),
+		"Actual err: %v",
+		err,
+	)
+	assert.Empty(t, dispatcher.events)
+}
+
+func TestIT_MessageService_MarkAsRead_WhenMessageDoesNotExist_ExpectError(t *testing.T) {
+	service, dbConn, dispatcher := newTestMessageServiceWithDispatcher(t)
+	defer dbConn.Close(context.Background())
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	marker := communication.ReadMarkerDtoRequest{
+		User:    user.Id,
+		Room:    room.Id,
+		Message: uuid.New(),
+	}
+	err := service.MarkAsRead(context.Background(), marker)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrMessageNotInRoom),
+		"Actual err: %v",
+		err,
+	)
+	assert.Empty(t, dispatcher.events)
+}
+
 func TestIT_MessageService_ServeClient_WhenContextTerminates_ExpectStops(t *testing.T) {
 	dbConn := newTestDbConnection(t)
 	defer dbConn.Close(context.Background())
 	repos := repositories.New(dbConn)
-	manager := clients.NewManager(repos)
+	manager := clients.NewManager(time.Minute, clients.PollPolicy{}, repos)
 	opts := MessageServiceOpts{
 		DbConn:                 dbConn,
 		Repos:                  repositories.New(dbConn),
-		Processor:              nil,
+		Relay:                  nil,
 		Manager:                manager,
 		ClientMessageQueueSize: 1,
 	}
@@ -109,21 +737,49 @@ func TestIT_MessageService_ServeClient_WhenContextTerminates_ExpectStops(t *test
 	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
 	defer cancel()
 
-	err := service.ServeClient(ctx, uuid.New(), response)
+	err := service.ServeClient(ctx, uuid.New(), response, clients.Filter{}, encoders.Json, false)
 
 	assert.Nil(t, err, "Actual err: %v", err)
 }
 
+func TestIT_MessageService_ServeClient_WhenShuttingDown_ExpectError(t *testing.T) {
+	dbConn := newTestDbConnection(t)
+	defer dbConn.Close(context.Background())
+	repos := repositories.New(dbConn)
+	manager := clients.NewManager(time.Minute, clients.PollPolicy{}, repos)
+	opts := MessageServiceOpts{
+		DbConn:                 dbConn,
+		Repos:                  repos,
+		Relay:                  nil,
+		Manager:                manager,
+		ClientMessageQueueSize: 1,
+	}
+	service := NewMessageService(opts)
+
+	rec := httptest.NewRecorder()
+	response := echo.NewResponse(rec, slog.Default())
+
+	service.StopAccepting()
+	err := service.ServeClient(context.Background(), uuid.New(), response, clients.Filter{}, encoders.Json, false)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrShuttingDown),
+		"Actual err: %v",
+		err,
+	)
+}
+
 func TestIT_MessageService_ServeClient_WhenMessageEnqueued_ExpectClientReceivesIt(t *testing.T) {
 	dbConn := newTestDbConnection(t)
 	defer dbConn.Close(context.Background())
 	repos := repositories.New(dbConn)
-	manager := clients.NewManager(repos)
-	processor := messages.NewMessageProcessor(1, manager, repos)
+	manager := clients.NewManager(time.Minute, clients.PollPolicy{}, repos)
+	processor := messages.NewMessageProcessor(1, messages.RetryPolicy{MaxAttempts: 1}, manager, repos)
 	opts := MessageServiceOpts{
 		DbConn:                 dbConn,
 		Repos:                  repos,
-		Processor:              processor,
+		Relay:                  nil,
 		Manager:                manager,
 		ClientMessageQueueSize: 1,
 	}
@@ -189,16 +845,54 @@ data: %s
 	)
 }
 
+func TestIT_MessageService_ServeClient_WhenSnapshotRequested_ExpectSnapshotSentFirst(t *testing.T) {
+	dbConn := newTestDbConnection(t)
+	defer dbConn.Close(context.Background())
+	repos := repositories.New(dbConn)
+	manager := clients.NewManager(time.Minute, clients.PollPolicy{}, repos)
+	opts := MessageServiceOpts{
+		DbConn:                 dbConn,
+		Repos:                  repos,
+		Relay:                  nil,
+		Manager:                manager,
+		ClientMessageQueueSize: 1,
+	}
+	service := NewMessageService(opts)
+
+	user1 := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user1.Id, room.Id)
+	user2 := insertTestUser(t, dbConn)
+	registerUserInRoom(t, dbConn, user2.Id, room.Id)
+	msg := insertTestMessage(t, dbConn, user2.Id, room.Id)
+
+	rec := httptest.NewRecorder()
+	response := echo.NewResponse(rec, slog.Default())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := service.ServeClient(ctx, user1.Id, response, clients.Filter{}, encoders.Json, true)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "event: snapshot\n")
+	assert.Contains(t, body, fmt.Sprintf(`"id":"%s"`, msg.Id))
+	assert.Contains(t, body, `"unread_count":1`)
+	assert.Contains(t, body, fmt.Sprintf(`"sequence":%d`, msg.Sequence))
+}
+
 func TestIT_MessageService_ServeClient_WhenMessageFromClientReceived_ExpectClientReceivesIt(t *testing.T) {
 	dbConn := newTestDbConnection(t)
 	defer dbConn.Close(context.Background())
 	repos := repositories.New(dbConn)
-	manager := clients.NewManager(repos)
-	processor := messages.NewMessageProcessor(1, manager, repos)
+	manager := clients.NewManager(time.Minute, clients.PollPolicy{}, repos)
+	processor := messages.NewMessageProcessor(1, messages.RetryPolicy{MaxAttempts: 1}, manager, repos)
 	opts := MessageServiceOpts{
 		DbConn:                 dbConn,
 		Repos:                  repos,
-		Processor:              processor,
+		Relay:                  nil,
 		Manager:                manager,
 		ClientMessageQueueSize: 1,
 	}
@@ -262,9 +956,65 @@ data: %s
 	)
 }
 
+func TestIT_MessageService_Poll_WhenShuttingDown_ExpectError(t *testing.T) {
+	service, dbConn := newTestMessageService(t, nil, nil)
+	defer dbConn.Close(context.Background())
+
+	service.StopAccepting()
+	_, err := service.Poll(context.Background(), uuid.New(), clients.Cursor{})
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrShuttingDown),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_MessageService_Poll_ExpectEventsConverted(t *testing.T) {
+	dbConn := newTestDbConnection(t)
+	defer dbConn.Close(context.Background())
+	repos := repositories.New(dbConn)
+	policy := clients.PollPolicy{BufferSize: 10, Timeout: time.Second, Retention: time.Minute}
+	manager := clients.NewManager(time.Minute, policy, repos)
+	opts := MessageServiceOpts{
+		DbConn:                 dbConn,
+		Repos:                  repos,
+		Manager:                manager,
+		ClientMessageQueueSize: 1,
+	}
+	service := NewMessageService(opts)
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	msg := persistence.Message{Id: uuid.New(), ChatUser: user.Id, Room: room.Id, Message: "Hello"}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		manager.Broadcast(msg)
+	}()
+
+	out, err := service.Poll(context.Background(), user.Id, clients.Cursor{})
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f-]+:1$`), out.Cursor)
+	expected := communication.PollDtoResponse{
+		Cursor: out.Cursor,
+		Events: []communication.EventDtoResponse{
+			{
+				Id:   msg.Id,
+				Type: "message",
+				Data: communication.ToMessageDtoResponse(msg),
+			},
+		},
+	}
+	assert.Equal(t, expected, out)
+}
+
 func newTestMessageService(
 	t *testing.T,
-	processor messages.Processor,
+	relay messages.OutboxRelay,
 	manager clients.Manager,
 ) (MessageService, db.Connection) {
 	dbConn := newTestDbConnection(t)
@@ -272,17 +1022,35 @@ func newTestMessageService(
 	opts := MessageServiceOpts{
 		DbConn:                 dbConn,
 		Repos:                  repositories.New(dbConn),
-		Processor:              processor,
+		Relay:                  relay,
 		Manager:                manager,
+		Dispatcher:             manager,
 		ClientMessageQueueSize: 1,
+		IdempotencyWindow:      time.Minute,
 	}
 
 	return NewMessageService(opts), dbConn
 }
 
+func newTestMessageServiceWithDispatcher(
+	t *testing.T,
+) (MessageService, db.Connection, *mockEventDispatcher) {
+	dbConn := newTestDbConnection(t)
+	dispatcher := &mockEventDispatcher{}
+
+	opts := MessageServiceOpts{
+		DbConn:                 dbConn,
+		Repos:                  repositories.New(dbConn),
+		Dispatcher:             dispatcher,
+		ClientMessageQueueSize: 1,
+	}
+
+	return NewMessageService(opts), dbConn, dispatcher
+}
+
 func asyncStartMessageProcessorAndAssertNoError(
 	t *testing.T,
-	processor messages.Processor,
+	processor messages.Processor[persistence.Message],
 ) *sync.WaitGroup {
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -322,7 +1090,7 @@ func asyncServeClientAndAssertNoError(
 			}
 		}()
 
-		err := service.ServeClient(ctx, client, response)
+		err := service.ServeClient(ctx, client, response, clients.Filter{}, encoders.Json, false)
 		assert.Nil(t, err, "Actual err: %v", err)
 	}()
 
@@ -331,18 +1099,40 @@ func asyncServeClientAndAssertNoError(
 	return &wg
 }
 
-type mockProcessor struct {
-	enqueued []persistence.Message
+type mockRelay struct {
+	notified int
 }
 
-func (m *mockProcessor) Start() error {
+func (m *mockRelay) Start() error {
 	return nil
 }
 
-func (m *mockProcessor) Stop() error {
+func (m *mockRelay) Stop() error {
 	return nil
 }
 
-func (m *mockProcessor) Enqueue(msg persistence.Message) {
-	m.enqueued = append(m.enqueued, msg)
+func (m *mockRelay) Notify() {
+	m.notified++
+}
+
+func getTestOutboxEntry(
+	t *testing.T, conn db.Connection, id uuid.UUID,
+) persistence.OutboxEntry {
+	repo := repositories.NewOutboxRepository(conn)
+
+	entry, err := repo.Get(context.Background(), id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return entry
+}
+
+func assertOutboxEntryDoesNotExist(t *testing.T, conn db.Connection, id uuid.UUID) {
+	value, err := db.QueryOne[int](
+		context.Background(),
+		conn,
+		"SELECT COUNT(id) FROM outbox WHERE id = $1",
+		id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Zero(t, value)
 }
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
+)
+
+type PinService interface {
+	Pin(ctx context.Context, user uuid.UUID, room uuid.UUID, message uuid.UUID) (communication.PinDtoResponse, error)
+	Unpin(ctx context.Context, user uuid.UUID, room uuid.UUID, message uuid.UUID) error
+	ListForRoom(ctx context.Context, room uuid.UUID) ([]communication.PinDtoResponse, error)
+}
+
+type PinServiceOpts struct {
+	DbConn         db.Connection
+	Repos          repositories.Repositories
+	Dispatcher     messages.Dispatcher
+	MaxPinsPerRoom int
+}
+
+type pinServiceImpl struct {
+	conn        db.Connection
+	messageRepo repositories.MessageRepository
+	pinRepo     repositories.PinRepository
+	roomRepo    repositories.RoomRepository
+
+	dispatcher     messages.Dispatcher
+	maxPinsPerRoom int
+}
+
+func NewPinService(opts PinServiceOpts) PinService {
+	return &pinServiceImpl{
+		conn:           opts.DbConn,
+		messageRepo:    opts.Repos.Message,
+		pinRepo:        opts.Repos.Pin,
+		roomRepo:       opts.Repos.Room,
+		dispatcher:     opts.Dispatcher,
+		maxPinsPerRoom: opts.MaxPinsPerRoom,
+	}
+}
+
+func (s *pinServiceImpl) Pin(
+	ctx context.Context, user uuid.UUID, room uuid.UUID, message uuid.UUID,
+) (communication.PinDtoResponse, error) {
+	if err := s.checkIsModerator(ctx, user, room); err != nil {
+		return communication.PinDtoResponse{}, err
+	}
+
+	msg, err := s.getMessageInRoom(ctx, room, message)
+	if err != nil {
+		return communication.PinDtoResponse{}, err
+	}
+
+	pin := persistence.Pin{
+		Room:     room,
+		Message:  message,
+		PinnedBy: user,
+	}
+	created, err := s.pinInTransaction(ctx, pin)
+	if err != nil {
+		return communication.PinDtoResponse{}, err
+	}
+
+	out := communication.ToPinDtoResponse(created, msg)
+
+	// The pin is persisted at this point: clients missing the event
+	// will still see it when listing the pins of the room.
+	s.dispatcher.BroadcastEvent(events.FromPin(out))
+
+	return out, nil
+}
+
+// pinInTransaction locks the room so that concurrent pins can't
+// exceed the number of pins allowed in it.
+func (s *pinServiceImpl) pinInTransaction(
+	ctx context.Context, pin persistence.Pin,
+) (persistence.Pin, error) {
+	tx, err := s.conn.BeginTx(ctx)
+	if err != nil {
+		return persistence.Pin{}, err
+	}
+	defer tx.Close(ctx)
+
+	if err := s.roomRepo.Lock(ctx, tx, pin.Room); err != nil {
+		return persistence.Pin{}, err
+	}
+
+	count, err := s.pinRepo.CountForRoom(ctx, tx, pin.Room)
+	if err != nil {
+		return persistence.Pin{}, err
+	}
+	if count >= s.maxPinsPerRoom {
+		return persistence.Pin{}, errors.NewCode(ErrTooManyPins)
+	}
+
+	return s.pinRepo.Create(ctx, tx, pin)
+}
+
+func (s *pinServiceImpl) Unpin(
+	ctx context.Context, user uuid.UUID, room uuid.UUID, message uuid.UUID,
+) error {
+	if err := s.checkIsModerator(ctx, user, room); err != nil {
+		return err
+	}
+
+	if err := s.pinRepo.Delete(ctx, room, message); err != nil {
+		return err
+	}
+
+	unpin := communication.UnpinDtoResponse{
+		Room:       room,
+		Message:    message,
+		UnpinnedBy: user,
+	}
+	s.dispatcher.BroadcastEvent(events.FromUnpin(unpin))
+
+	return nil
+}
+
+func (s *pinServiceImpl) ListForRoom(
+	ctx context.Context, room uuid.UUID,
+) ([]communication.PinDtoResponse, error) {
+	pins, err := s.pinRepo.ListForRoom(ctx, room)
+	if err != nil {
+		return []communication.PinDtoResponse{}, err
+	}
+
+	out := make([]communication.PinDtoResponse, 0, len(pins))
+	for _, pinned := range pins {
+		out = append(out, communication.ToPinDtoResponse(pinned.Pin, pinned.Message))
+	}
+
+	return out, nil
+}
+
+func (s *pinServiceImpl) checkIsModerator(
+	ctx context.Context, user uuid.UUID, room uuid.UUID,
+) error {
+	moderator, err := s.roomRepo.UserIsModerator(ctx, user, room)
+	if err != nil {
+		return err
+	}
+	if !moderator {
+		return errors.NewCode(ErrNotModerator)
+	}
+
+	return nil
+}
+
+func (s *pinServiceImpl) getMessageInRoom(
+	ctx context.Context, room uuid.UUID, message uuid.UUID,
+) (persistence.Message, error) {
+	msg, err := s.messageRepo.Get(ctx, message)
+	if err != nil {
+		return persistence.Message{}, err
+	}
+
+	if msg.Room != room {
+		return persistence.Message{}, errors.NewCode(db.NoMatchingRows)
+	}
+
+	return msg, nil
+}
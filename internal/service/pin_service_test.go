@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_PinService_Pin(t *testing.T) {
+	service, conn, dispatcher := newTestPinService(t, 2)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	setUserAsModerator(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+
+	out, err := service.Pin(context.Background(), user.Id, room.Id, msg.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, room.Id, out.Room)
+	assert.Equal(t, msg.Id, out.Message.Id)
+	assert.Equal(t, user.Id, out.PinnedBy)
+	assert.Equal(t, 1, len(dispatcher.events))
+	assert.Equal(t, events.Pin, dispatcher.events[0].Type)
+	assert.Equal(t, room.Id, dispatcher.events[0].Room)
+	assert.Equal(t, out, dispatcher.events[0].Data)
+}
+
+func TestIT_PinService_Pin_WhenUserIsNotModerator_ExpectError(t *testing.T) {
+	service, conn, dispatcher := newTestPinService(t, 2)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+
+	_, err := service.Pin(context.Background(), user.Id, room.Id, msg.Id)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrNotModerator),
+		"Actual err: %v",
+		err,
+	)
+	assert.Empty(t, dispatcher.events)
+}
+
+func TestIT_PinService_Pin_WhenMessageIsInAnotherRoom_ExpectError(t *testing.T) {
+	service, conn, _ := newTestPinService(t, 2)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	other := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	registerUserInRoom(t, conn, user.Id, other.Id)
+	setUserAsModerator(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, other.Id)
+
+	_, err := service.Pin(context.Background(), user.Id, room.Id, msg.Id)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_PinService_Pin_WhenAlreadyPinned_ExpectError(t *testing.T) {
+	service, conn, _ := newTestPinService(t, 2)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	setUserAsModerator(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+
+	_, err := service.Pin(context.Background(), user.Id, room.Id, msg.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	_, err = service.Pin(context.Background(), user.Id, room.Id, msg.Id)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, repositories.ErrMessageAlreadyPinned),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_PinService_Pin_WhenTooManyPins_ExpectError(t *testing.T) {
+	service, conn, _ := newTestPinService(t, 1)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	setUserAsModerator(t, conn, user.Id, room.Id)
+	msg1 := insertTestMessage(t, conn, user.Id, room.Id)
+	msg2 := insertTestMessage(t, conn, user.Id, room.Id)
+
+	_, err := service.Pin(context.Background(), user.Id, room.Id, msg1.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	_, err = service.Pin(context.Background(), user.Id, room.Id, msg2.Id)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrTooManyPins),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_PinService_Unpin(t *testing.T) {
+	service, conn, dispatcher := newTestPinService(t, 2)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	setUserAsModerator(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+	insertTestPin(t, conn, user.Id, room.Id, msg.Id)
+
+	err := service.Unpin(context.Background(), user.Id, room.Id, msg.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, 1, len(dispatcher.events))
+	assert.Equal(t, events.Unpin, dispatcher.events[0].Type)
+	expected := communication.UnpinDtoResponse{
+		Room:       room.Id,
+		Message:    msg.Id,
+		UnpinnedBy: user.Id,
+	}
+	assert.Equal(t, expected, dispatcher.events[0].Data)
+}
+
+func TestIT_PinService_Unpin_WhenNotPinned_ExpectError(t *testing.T) {
+	service, conn, dispatcher := newTestPinService(t, 2)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	setUserAsModerator(t, conn, user.Id, room.Id)
+
+	err := service.Unpin(context.Background(), user.Id, room.Id, uuid.New())
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+	assert.Empty(t, dispatcher.events)
+}
+
+func TestIT_PinService_ListForRoom(t *testing.T) {
+	service, conn, _ := newTestPinService(t, 2)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg1 := insertTestMessage(t, conn, user.Id, room.Id)
+	msg2 := insertTestMessage(t, conn, user.Id, room.Id)
+	pin2 := insertTestPin(t, conn, user.Id, room.Id, msg2.Id)
+	pin1 := insertTestPin(t, conn, user.Id, room.Id, msg1.Id)
+
+	actual, err := service.ListForRoom(context.Background(), room.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	expected := []communication.PinDtoResponse{
+		communication.ToPinDtoResponse(pin2, msg2),
+		communication.ToPinDtoResponse(pin1, msg1),
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestIT_PinService_Pin_WhenPinnedConcurrently_ExpectLimitEnforced(t *testing.T) {
+	service, conn, _ := newTestPinService(t, 1)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	setUserAsModerator(t, conn, user.Id, room.Id)
+	msgs := []persistence.Message{
+		insertTestMessage(t, conn, user.Id, room.Id),
+		insertTestMessage(t, conn, user.Id, room.Id),
+		insertTestMessage(t, conn, user.Id, room.Id),
+	}
+
+	var wg sync.WaitGroup
+	for _, msg := range msgs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			service.Pin(context.Background(), user.Id, room.Id, msg.Id)
+		}()
+	}
+	wg.Wait()
+
+	actual, err := service.ListForRoom(context.Background(), room.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, 1, len(actual))
+}
+
+func TestIT_PinService_ListForRoom_WhenMessageExpired_ExpectSkipped(t *testing.T) {
+	service, conn, _ := newTestPinService(t, 2)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+	expired := insertTestMessage(t, conn, user.Id, room.Id)
+	pin := insertTestPin(t, conn, user.Id, room.Id, msg.Id)
+	insertTestPin(t, conn, user.Id, room.Id, expired.Id)
+	_, err := conn.Exec(
+		context.Background(),
+		"UPDATE message SET expires_at = $2 WHERE id = $1",
+		expired.Id,
+		time.Now().Add(-time.Minute),
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual, err := service.ListForRoom(context.Background(), room.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	expected := []communication.PinDtoResponse{
+		communication.ToPinDtoResponse(pin, msg),
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func newTestPinService(
+	t *testing.T, maxPinsPerRoom int,
+) (PinService, db.Connection, *mockEventDispatcher) {
+	conn := newTestDbConnection(t)
+	dispatcher := &mockEventDispatcher{}
+
+	opts := PinServiceOpts{
+		DbConn:         conn,
+		Repos:          repositories.New(conn),
+		Dispatcher:     dispatcher,
+		MaxPinsPerRoom: maxPinsPerRoom,
+	}
+
+	return NewPinService(opts), conn, dispatcher
+}
+
+type mockEventDispatcher struct {
+	messages.Dispatcher
+
+	events []events.Event
+}
+
+func (m *mockEventDispatcher) BroadcastEvent(event events.Event) error {
+	m.events = append(m.events, event)
+	return nil
+}
+
+func insertTestPin(
+	t *testing.T,
+	conn db.Connection,
+	user uuid.UUID,
+	room uuid.UUID,
+	msg uuid.UUID,
+) persistence.Pin {
+	repo := repositories.NewPinRepository(conn)
+
+	pin := persistence.Pin{
+		Room:     room,
+		Message:  msg,
+		PinnedBy: user,
+	}
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	out, err := repo.Create(context.Background(), tx, pin)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return out
+}
@@ -2,9 +2,11 @@ package service
 
 import (
 	"context"
+	"slices"
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
 	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
 	"github.com/google/uuid"
 )
@@ -12,22 +14,41 @@ import (
 type RegistrationService interface {
 	RegisterUserInRoom(ctx context.Context, user uuid.UUID, room uuid.UUID) error
 	UnregisterUserInRoom(ctx context.Context, user uuid.UUID, room uuid.UUID) error
+	SetModerator(ctx context.Context, caller uuid.UUID, user uuid.UUID, room uuid.UUID, moderator bool) error
 }
 
 type registrationServiceImpl struct {
-	conn  db.Connection
-	repos repositories.Repositories
+	conn       db.Connection
+	repos      repositories.Repositories
+	dispatcher messages.Dispatcher
 }
 
-func NewRegistrationService(conn db.Connection, repos repositories.Repositories) RegistrationService {
+func NewRegistrationService(
+	conn db.Connection, repos repositories.Repositories, dispatcher messages.Dispatcher,
+) RegistrationService {
 	return &registrationServiceImpl{
-		conn:  conn,
-		repos: repos,
+		conn:       conn,
+		repos:      repos,
+		dispatcher: dispatcher,
 	}
 }
 
 func (s *registrationServiceImpl) RegisterUserInRoom(
 	ctx context.Context, user uuid.UUID, room uuid.UUID,
+) error {
+	if err := s.registerInTransaction(ctx, user, room); err != nil {
+		return err
+	}
+
+	// The registration is committed at this point: the user can receive
+	// the messages of the room.
+	s.dispatcher.OnRegistration(user, room)
+
+	return nil
+}
+
+func (s *registrationServiceImpl) registerInTransaction(
+	ctx context.Context, user uuid.UUID, room uuid.UUID,
 ) error {
 	tx, err := s.conn.BeginTx(ctx)
 	if err != nil {
@@ -51,6 +72,18 @@ func (s *registrationServiceImpl) UnregisterUserInRoom(
 		return errors.NewCode(ErrLeavingRoomIsNotAllowed)
 	}
 
+	if err := s.unregisterInTransaction(ctx, user, room); err != nil {
+		return err
+	}
+
+	s.dispatcher.OnUnregistration(user, room)
+
+	return nil
+}
+
+func (s *registrationServiceImpl) unregisterInTransaction(
+	ctx context.Context, user uuid.UUID, room uuid.UUID,
+) error {
 	tx, err := s.conn.BeginTx(ctx)
 	if err != nil {
 		return err
@@ -64,3 +97,52 @@ func (s *registrationServiceImpl) UnregisterUserInRoom(
 
 	return s.repos.Registration.DeleteFromRoom(ctx, tx, room, user)
 }
+
+func (s *registrationServiceImpl) SetModerator(
+	ctx context.Context, caller uuid.UUID, user uuid.UUID, room uuid.UUID, moderator bool,
+) error {
+	tx, err := s.conn.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Close(ctx)
+
+	// Lock the room so that the moderators can't change while the
+	// permissions of the caller are verified.
+	if err := s.repos.Room.Lock(ctx, tx, room); err != nil {
+		return err
+	}
+
+	moderators, err := s.repos.Registration.ListModerators(ctx, tx, room)
+	if err != nil {
+		return err
+	}
+	if err := s.checkCanModerate(ctx, caller, room, moderators); err != nil {
+		return err
+	}
+
+	return s.repos.Registration.SetModerator(ctx, tx, room, user, moderator)
+}
+
+// checkCanModerate verifies that the caller is a moderator of the room.
+// Rooms do not have an owner: as long as a room does not have moderators
+// any of its members can appoint the first one.
+func (s *registrationServiceImpl) checkCanModerate(
+	ctx context.Context, caller uuid.UUID, room uuid.UUID, moderators []uuid.UUID,
+) error {
+	if slices.Contains(moderators, caller) {
+		return nil
+	}
+
+	if len(moderators) == 0 {
+		registered, err := s.repos.Room.UserInRoom(ctx, caller, room)
+		if err != nil {
+			return err
+		}
+		if registered {
+			return nil
+		}
+	}
+
+	return errors.NewCode(ErrNotModerator)
+}
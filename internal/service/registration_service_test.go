@@ -23,6 +23,29 @@ func TestIT_RegistrationService_RegisterUserInRoom(t *testing.T) {
 	assertUserRegisteredInRoom(t, conn, user.Id, room.Name)
 }
 
+func TestIT_RegistrationService_RegisterUserInRoom_ExpectDispatcherNotified(t *testing.T) {
+	service, conn, dispatcher := newTestRegistrationServiceWithDispatcher(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+
+	err := service.RegisterUserInRoom(context.Background(), user.Id, room.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, []uuid.UUID{user.Id}, dispatcher.registered)
+}
+
+func TestIT_RegistrationService_RegisterUserInRoom_WhenFailing_ExpectDispatcherNotNotified(t *testing.T) {
+	service, conn, dispatcher := newTestRegistrationServiceWithDispatcher(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+
+	err := service.RegisterUserInRoom(context.Background(), uuid.New(), room.Id)
+
+	assert.NotNil(t, err)
+	assert.Empty(t, dispatcher.registered)
+}
+
 func TestIT_RegistrationService_RegisterUserInRoom_WhenUserDoesNotExist_ExpectError(t *testing.T) {
 	service, conn := newTestRegistrationService(t)
 	defer conn.Close(context.Background())
@@ -92,6 +115,19 @@ func TestIT_RegistrationService_UnregisterUserInRoom(t *testing.T) {
 	assertUserRegisteredInRoom(t, conn, user2.Id, room1.Name)
 }
 
+func TestIT_RegistrationService_UnregisterUserInRoom_ExpectDispatcherNotified(t *testing.T) {
+	service, conn, dispatcher := newTestRegistrationServiceWithDispatcher(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+
+	err := service.UnregisterUserInRoom(context.Background(), user.Id, room.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, []uuid.UUID{user.Id}, dispatcher.unregistered)
+}
+
 func TestIT_RegistrationService_ShouldNotUnregisterFromGeneralRoom(t *testing.T) {
 	service, conn := newTestRegistrationService(t)
 	defer conn.Close(context.Background())
@@ -161,8 +197,102 @@ func TestIT_RegistrationService_UnregisterUserInRoom_UpdateMessagesInRoom(t *tes
 	assertMessageOwner(t, conn, msg2.Id, user2.Name)
 }
 
+func TestIT_RegistrationService_SetModerator(t *testing.T) {
+	service, conn := newTestRegistrationService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+
+	err := service.SetModerator(context.Background(), user.Id, user.Id, room.Id, true)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assertUserIsModerator(t, conn, user.Id, room.Id)
+}
+
+func TestIT_RegistrationService_SetModerator_WhenCallerIsModerator_ExpectSuccess(t *testing.T) {
+	service, conn := newTestRegistrationService(t)
+	defer conn.Close(context.Background())
+	moderator := insertTestUser(t, conn)
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, moderator.Id, room.Id)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	setUserAsModerator(t, conn, moderator.Id, room.Id)
+
+	err := service.SetModerator(context.Background(), moderator.Id, user.Id, room.Id, true)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assertUserIsModerator(t, conn, user.Id, room.Id)
+}
+
+func TestIT_RegistrationService_SetModerator_WhenCallerIsNotModerator_ExpectError(t *testing.T) {
+	service, conn := newTestRegistrationService(t)
+	defer conn.Close(context.Background())
+	moderator := insertTestUser(t, conn)
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, moderator.Id, room.Id)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	setUserAsModerator(t, conn, moderator.Id, room.Id)
+
+	err := service.SetModerator(context.Background(), user.Id, user.Id, room.Id, true)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrNotModerator),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_RegistrationService_SetModerator_WhenCallerNotRegistered_ExpectError(t *testing.T) {
+	service, conn := newTestRegistrationService(t)
+	defer conn.Close(context.Background())
+	caller := insertTestUser(t, conn)
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+
+	err := service.SetModerator(context.Background(), caller.Id, user.Id, room.Id, true)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrNotModerator),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_RegistrationService_SetModerator_WhenUserNotRegistered_ExpectError(t *testing.T) {
+	service, conn := newTestRegistrationService(t)
+	defer conn.Close(context.Background())
+	moderator := insertTestUser(t, conn)
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, moderator.Id, room.Id)
+	setUserAsModerator(t, conn, moderator.Id, room.Id)
+
+	err := service.SetModerator(context.Background(), moderator.Id, user.Id, room.Id, true)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, repositories.ErrUserNotRegisteredInRoom),
+		"Actual err: %v",
+		err,
+	)
+}
+
 func newTestRegistrationService(t *testing.T) (RegistrationService, db.Connection) {
+	service, conn, _ := newTestRegistrationServiceWithDispatcher(t)
+	return service, conn
+}
+
+func newTestRegistrationServiceWithDispatcher(
+	t *testing.T,
+) (RegistrationService, db.Connection, *mockMembershipDispatcher) {
 	conn := newTestDbConnection(t)
 	repos := repositories.New(conn)
-	return NewRegistrationService(conn, repos), conn
+	dispatcher := &mockMembershipDispatcher{}
+	return NewRegistrationService(conn, repos, dispatcher), conn, dispatcher
 }
@@ -6,6 +6,8 @@ import (
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
 	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
 	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
 	"github.com/google/uuid"
 )
@@ -13,21 +15,30 @@ import (
 type RoomService interface {
 	Create(ctx context.Context, roomDto communication.RoomDtoRequest) (communication.RoomDtoResponse, error)
 	Get(ctx context.Context, id uuid.UUID) (communication.RoomDtoResponse, error)
+	GetByName(ctx context.Context, name string) (communication.RoomDtoResponse, error)
 	List(ctx context.Context) ([]communication.RoomDtoResponse, error)
 	ListUserForRoom(ctx context.Context, room uuid.UUID) ([]communication.UserDtoResponse, error)
-	ListMessageForRoom(ctx context.Context, room uuid.UUID) ([]communication.MessageDtoResponse, error)
+	// CountUsers returns the number of users of each room. Rooms without
+	// users are not part of the result.
+	CountUsers(ctx context.Context) (map[uuid.UUID]int, error)
+	ListMessageForRoom(ctx context.Context, room uuid.UUID, from int64, to int64) ([]communication.MessageDtoResponse, error)
+	UpdateMessageTtl(ctx context.Context, id uuid.UUID, ttlDto communication.RoomMessageTtlDtoRequest) (communication.RoomDtoResponse, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type roomServiceImpl struct {
-	conn  db.Connection
-	repos repositories.Repositories
+	conn       db.Connection
+	repos      repositories.Repositories
+	dispatcher messages.Dispatcher
 }
 
-func NewRoomService(conn db.Connection, repos repositories.Repositories) RoomService {
+func NewRoomService(
+	conn db.Connection, repos repositories.Repositories, dispatcher messages.Dispatcher,
+) RoomService {
 	return &roomServiceImpl{
-		conn:  conn,
-		repos: repos,
+		conn:       conn,
+		repos:      repos,
+		dispatcher: dispatcher,
 	}
 }
 
@@ -39,6 +50,9 @@ func (s *roomServiceImpl) Create(
 	if room.Name == "" {
 		return communication.RoomDtoResponse{}, errors.NewCode(ErrInvalidName)
 	}
+	if !isValidTtl(room.MessageTtl) {
+		return communication.RoomDtoResponse{}, errors.NewCode(ErrInvalidTtl)
+	}
 
 	tx, err := s.conn.BeginTx(ctx)
 	if err != nil {
@@ -74,6 +88,18 @@ func (s *roomServiceImpl) Get(
 	return out, nil
 }
 
+func (s *roomServiceImpl) GetByName(
+	ctx context.Context, name string,
+) (communication.RoomDtoResponse, error) {
+	room, err := s.repos.Room.GetByName(ctx, name)
+	if err != nil {
+		return communication.RoomDtoResponse{}, err
+	}
+
+	out := communication.ToRoomDtoResponse(room)
+	return out, nil
+}
+
 func (s *roomServiceImpl) List(
 	ctx context.Context,
 ) ([]communication.RoomDtoResponse, error) {
@@ -107,16 +133,49 @@ func (s *roomServiceImpl) ListUserForRoom(
 	return out, nil
 }
 
+func (s *roomServiceImpl) CountUsers(
+	ctx context.Context,
+) (map[uuid.UUID]int, error) {
+	counts, err := s.repos.Room.CountMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[uuid.UUID]int, len(counts))
+	for _, count := range counts {
+		out[count.Room] = count.Count
+	}
+
+	return out, nil
+}
+
 func (s *roomServiceImpl) ListMessageForRoom(
-	ctx context.Context, room uuid.UUID,
+	ctx context.Context, room uuid.UUID, from int64, to int64,
 ) ([]communication.MessageDtoResponse, error) {
-	messages, err := s.repos.Message.ListForRoom(ctx, room)
+	if from < 0 || to < 0 || (to > 0 && from > to) {
+		return []communication.MessageDtoResponse{}, errors.NewCode(ErrInvalidSequenceRange)
+	}
+
+	sequences := repositories.SequenceRange{From: from, To: to}
+	messages, err := s.repos.Message.ListForRoom(ctx, room, sequences)
 	if err != nil {
 		return []communication.MessageDtoResponse{}, err
 	}
 
+	attachments, err := s.repos.Attachment.ListForRoom(ctx, room)
+	if err != nil {
+		return []communication.MessageDtoResponse{}, err
+	}
+
+	attachmentsByMessage := make(map[uuid.UUID][]persistence.Attachment)
+	for _, attachment := range attachments {
+		message := *attachment.Message
+		attachmentsByMessage[message] = append(attachmentsByMessage[message], attachment)
+	}
+
 	out := make([]communication.MessageDtoResponse, 0)
 	for _, message := range messages {
+		message.Attachments = attachmentsByMessage[message.Id]
 		dto := communication.ToMessageDtoResponse(message)
 		out = append(out, dto)
 	}
@@ -124,8 +183,37 @@ func (s *roomServiceImpl) ListMessageForRoom(
 	return out, nil
 }
 
+func (s *roomServiceImpl) UpdateMessageTtl(
+	ctx context.Context, id uuid.UUID, ttlDto communication.RoomMessageTtlDtoRequest,
+) (communication.RoomDtoResponse, error) {
+	if !isValidTtl(ttlDto.MessageTtl) {
+		return communication.RoomDtoResponse{}, errors.NewCode(ErrInvalidTtl)
+	}
+
+	room, err := s.repos.Room.UpdateMessageTtl(ctx, id, ttlDto.MessageTtl)
+	if err != nil {
+		return communication.RoomDtoResponse{}, err
+	}
+
+	return communication.ToRoomDtoResponse(room), nil
+}
+
 func (s *roomServiceImpl) Delete(
 	ctx context.Context, id uuid.UUID,
+) error {
+	if err := s.deleteInTransaction(ctx, id); err != nil {
+		return err
+	}
+
+	// The room is gone at this point: its members don't need to be
+	// tracked anymore.
+	s.dispatcher.OnRoomDeletion(id)
+
+	return nil
+}
+
+func (s *roomServiceImpl) deleteInTransaction(
+	ctx context.Context, id uuid.UUID,
 ) error {
 	tx, err := s.conn.BeginTx(ctx)
 	if err != nil {
@@ -133,11 +221,42 @@ func (s *roomServiceImpl) Delete(
 	}
 	defer tx.Close(ctx)
 
+	err = s.repos.Pin.DeleteForRoom(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.repos.ScheduledMessage.DeleteForRoom(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	// TODO: The blobs of the attachments are not removed from the storage
+	err = s.repos.Attachment.DeleteForRoom(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
 	err = s.repos.Message.DeleteForRoom(ctx, tx, id)
 	if err != nil {
 		return err
 	}
 
+	err = s.repos.MessageStatus.DeleteForRoom(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.repos.DeadLetter.DeleteForRoom(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.repos.Outbox.DeleteForRoom(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
 	err = s.repos.Registration.DeleteForRoom(ctx, tx, id)
 	if err != nil {
 		return err
@@ -150,3 +269,7 @@ func (s *roomServiceImpl) Delete(
 
 	return nil
 }
+
+func isValidTtl(ttl *int) bool {
+	return ttl == nil || *ttl > 0
+}
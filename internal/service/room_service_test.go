@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/pgx"
@@ -65,6 +66,25 @@ func TestIT_RoomService_Create_InvalidName(t *testing.T) {
 	)
 }
 
+func TestIT_RoomService_Create_InvalidMessageTtl(t *testing.T) {
+	ttl := 0
+	roomDtoRequest := communication.RoomDtoRequest{
+		Name:       fmt.Sprintf("my-room-%s", uuid.New()),
+		MessageTtl: &ttl,
+	}
+
+	service, conn := newTestRoomService(t)
+	defer conn.Close(context.Background())
+	_, err := service.Create(context.Background(), roomDtoRequest)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrInvalidTtl),
+		"Actual err: %v",
+		err,
+	)
+}
+
 func TestIT_RoomService_Create_WhenRoomWithSameNameAlreadyExists_ExpectFailure(t *testing.T) {
 	service, conn := newTestRoomService(t)
 	defer conn.Close(context.Background())
@@ -110,6 +130,32 @@ func TestIT_RoomService_Get_WhenRoomDoesNotExist_ExpectFailure(t *testing.T) {
 	)
 }
 
+func TestIT_RoomService_GetByName(t *testing.T) {
+	service, conn := newTestRoomService(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+
+	actual, err := service.GetByName(context.Background(), room.Name)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	expected := communication.ToRoomDtoResponse(room)
+	assert.Equal(t, expected, actual)
+}
+
+func TestIT_RoomService_GetByName_WhenRoomDoesNotExist_ExpectFailure(t *testing.T) {
+	service, conn := newTestRoomService(t)
+	defer conn.Close(context.Background())
+
+	_, err := service.GetByName(context.Background(), "my-non-existent-room")
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
 func TestIT_RoomService_List(t *testing.T) {
 	service, conn := newTestRoomService(t)
 	defer conn.Close(context.Background())
@@ -158,7 +204,7 @@ func TestIT_RoomService_ListMessageForRoom_WhenNoMessageInRoom_ExpectEmptyList(t
 	service, conn := newTestRoomService(t)
 	defer conn.Close(context.Background())
 
-	actual, err := service.ListMessageForRoom(context.Background(), uuid.New())
+	actual, err := service.ListMessageForRoom(context.Background(), uuid.New(), 0, 0)
 
 	assert.Nil(t, err, "Actual err: %v", err)
 	assert.Equal(t, []communication.MessageDtoResponse{}, actual)
@@ -182,7 +228,7 @@ func TestIT_RoomService_ListMessageForRoom(t *testing.T) {
 	msg2 := insertTestMessage(t, conn, user2.Id, room1.Id)
 	insertTestMessage(t, conn, user3.Id, room2.Id)
 
-	actual, err := service.ListMessageForRoom(context.Background(), room1.Id)
+	actual, err := service.ListMessageForRoom(context.Background(), room1.Id, 0, 0)
 
 	assert.Nil(t, err, "Actual err: %v", err)
 	expected := []communication.MessageDtoResponse{
@@ -192,6 +238,112 @@ func TestIT_RoomService_ListMessageForRoom(t *testing.T) {
 	assert.ElementsMatch(t, expected, actual)
 }
 
+func TestIT_RoomService_ListMessageForRoom_IncludesAttachments(t *testing.T) {
+	service, conn := newTestRoomService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+	attachment := insertTestAttachment(t, conn, nil, user.Id, room.Id)
+	attachTestAttachmentToMessage(t, conn, attachment.Id, msg.Id)
+
+	actual, err := service.ListMessageForRoom(context.Background(), room.Id, 0, 0)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Len(t, actual, 1)
+	assert.Len(t, actual[0].Attachments, 1)
+	assert.Equal(t, attachment.Id, actual[0].Attachments[0].Id)
+}
+
+func TestIT_RoomService_ListMessageForRoom_WithSequenceRange(t *testing.T) {
+	service, conn := newTestRoomService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	insertTestMessage(t, conn, user.Id, room.Id)
+	msg2 := insertTestMessage(t, conn, user.Id, room.Id)
+	insertTestMessage(t, conn, user.Id, room.Id)
+
+	actual, err := service.ListMessageForRoom(
+		context.Background(), room.Id, msg2.Sequence, msg2.Sequence,
+	)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	expected := []communication.MessageDtoResponse{
+		communication.ToMessageDtoResponse(msg2),
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestIT_RoomService_ListMessageForRoom_InvalidSequenceRange(t *testing.T) {
+	service, conn := newTestRoomService(t)
+	defer conn.Close(context.Background())
+
+	_, err := service.ListMessageForRoom(context.Background(), uuid.New(), 4, 2)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrInvalidSequenceRange),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_RoomService_UpdateMessageTtl(t *testing.T) {
+	service, conn := newTestRoomService(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+
+	ttl := 3600
+	ttlDtoRequest := communication.RoomMessageTtlDtoRequest{
+		MessageTtl: &ttl,
+	}
+	out, err := service.UpdateMessageTtl(context.Background(), room.Id, ttlDtoRequest)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, room.Id, out.Id)
+	assert.Equal(t, &ttl, out.MessageTtl)
+}
+
+func TestIT_RoomService_UpdateMessageTtl_InvalidTtl(t *testing.T) {
+	service, conn := newTestRoomService(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+
+	ttl := -1
+	ttlDtoRequest := communication.RoomMessageTtlDtoRequest{
+		MessageTtl: &ttl,
+	}
+	_, err := service.UpdateMessageTtl(context.Background(), room.Id, ttlDtoRequest)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrInvalidTtl),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_RoomService_UpdateMessageTtl_WhenRoomDoesNotExist_ExpectFailure(t *testing.T) {
+	service, conn := newTestRoomService(t)
+	defer conn.Close(context.Background())
+
+	ttl := 3600
+	ttlDtoRequest := communication.RoomMessageTtlDtoRequest{
+		MessageTtl: &ttl,
+	}
+	_, err := service.UpdateMessageTtl(context.Background(), uuid.New(), ttlDtoRequest)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
 func TestIT_RoomService_Delete(t *testing.T) {
 	service, conn := newTestRoomService(t)
 	defer conn.Close(context.Background())
@@ -203,6 +355,17 @@ func TestIT_RoomService_Delete(t *testing.T) {
 	assertRoomDoesNotExist(t, conn, room.Id)
 }
 
+func TestIT_RoomService_Delete_ExpectDispatcherNotified(t *testing.T) {
+	service, conn, dispatcher := newTestRoomServiceWithDispatcher(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+
+	err := service.Delete(context.Background(), room.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, []uuid.UUID{room.Id}, dispatcher.deleted)
+}
+
 func TestIT_RoomService_Delete_WhenRoomDoesNotExist_ExpectSuccess(t *testing.T) {
 	nonExistingId := uuid.MustParse("00000000-0000-1221-0000-000000000000")
 
@@ -227,6 +390,74 @@ func TestIT_RoomService_Delete_DeleteRoomMessages(t *testing.T) {
 	assertMessageDoesNotExist(t, conn, msg.Id)
 }
 
+func TestIT_RoomService_Delete_DeleteRoomPins(t *testing.T) {
+	service, conn := newTestRoomService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+	insertTestPin(t, conn, user.Id, room.Id, msg.Id)
+
+	err := service.Delete(context.Background(), room.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assertMessageDoesNotExist(t, conn, msg.Id)
+}
+
+func TestIT_RoomService_Delete_DeleteRoomScheduledMessages(t *testing.T) {
+	service, conn := newTestRoomService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestScheduledMessage(t, conn, user.Id, room.Id, time.Now().Add(time.Hour))
+
+	err := service.Delete(context.Background(), room.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assertScheduledMessageDoesNotExist(t, conn, msg.Id)
+}
+
+func TestIT_RoomService_Delete_DeleteRoomOutboxEntries(t *testing.T) {
+	service, conn := newTestRoomService(t)
+	defer conn.Close(context.Background())
+	messageService, messageConn := newTestMessageService(t, &mockRelay{}, nil)
+	defer messageConn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+
+	messageDtoRequest := communication.MessageDtoRequest{
+		User:    user.Id,
+		Room:    room.Id,
+		Message: "hello there",
+	}
+	posted, err := messageService.PostMessage(context.Background(), messageDtoRequest)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	err = service.Delete(context.Background(), room.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assertOutboxEntryDoesNotExist(t, conn, posted.Id)
+}
+
+func TestIT_RoomService_Delete_DeleteRoomAttachments(t *testing.T) {
+	service, conn := newTestRoomService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+	attachment := insertTestAttachment(t, conn, nil, user.Id, room.Id)
+	attachTestAttachmentToMessage(t, conn, attachment.Id, msg.Id)
+
+	err := service.Delete(context.Background(), room.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assertAttachmentDoesNotExist(t, conn, attachment.Id)
+}
+
 func TestIT_RoomService_Delete_DeleteRegisteredUser(t *testing.T) {
 	service, conn := newTestRoomService(t)
 	defer conn.Close(context.Background())
@@ -241,9 +472,17 @@ func TestIT_RoomService_Delete_DeleteRegisteredUser(t *testing.T) {
 }
 
 func newTestRoomService(t *testing.T) (RoomService, db.Connection) {
+	service, conn, _ := newTestRoomServiceWithDispatcher(t)
+	return service, conn
+}
+
+func newTestRoomServiceWithDispatcher(
+	t *testing.T,
+) (RoomService, db.Connection, *mockMembershipDispatcher) {
 	conn := newTestDbConnection(t)
 	repos := repositories.New(conn)
-	return NewRoomService(conn, repos), conn
+	dispatcher := &mockMembershipDispatcher{}
+	return NewRoomService(conn, repos, dispatcher), conn, dispatcher
 }
 
 func insertTestRoom(t *testing.T, conn db.Connection) persistence.Room {
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
+)
+
+type ScheduledMessageService interface {
+	ListForRoom(ctx context.Context, room uuid.UUID) ([]communication.ScheduledMessageDtoResponse, error)
+	Update(ctx context.Context, id uuid.UUID, messageDto communication.ScheduledMessageDtoRequest) (communication.ScheduledMessageDtoResponse, error)
+	Cancel(ctx context.Context, user uuid.UUID, id uuid.UUID) error
+}
+
+type scheduledMessageServiceImpl struct {
+	scheduledRepo repositories.ScheduledMessageRepository
+}
+
+func NewScheduledMessageService(repos repositories.Repositories) ScheduledMessageService {
+	return &scheduledMessageServiceImpl{
+		scheduledRepo: repos.ScheduledMessage,
+	}
+}
+
+func (s *scheduledMessageServiceImpl) ListForRoom(
+	ctx context.Context, room uuid.UUID,
+) ([]communication.ScheduledMessageDtoResponse, error) {
+	messages, err := s.scheduledRepo.ListForRoom(ctx, room)
+	if err != nil {
+		return []communication.ScheduledMessageDtoResponse{}, err
+	}
+
+	out := make([]communication.ScheduledMessageDtoResponse, 0, len(messages))
+	for _, message := range messages {
+		dto := communication.ToScheduledMessageDtoResponse(message)
+		out = append(out, dto)
+	}
+
+	return out, nil
+}
+
+func (s *scheduledMessageServiceImpl) Update(
+	ctx context.Context,
+	id uuid.UUID,
+	messageDto communication.ScheduledMessageDtoRequest,
+) (communication.ScheduledMessageDtoResponse, error) {
+	message, err := s.getOwnedMessage(ctx, messageDto.User, id)
+	if err != nil {
+		return communication.ScheduledMessageDtoResponse{}, err
+	}
+
+	if messageDto.Message != nil {
+		if *messageDto.Message == "" {
+			return communication.ScheduledMessageDtoResponse{}, errors.NewCode(ErrEmptyMessage)
+		}
+		message.Message = *messageDto.Message
+	}
+	if messageDto.SendAt != nil {
+		if !messageDto.SendAt.After(time.Now()) {
+			return communication.ScheduledMessageDtoResponse{}, errors.NewCode(ErrInvalidSendAt)
+		}
+		message.SendAt = messageDto.SendAt.UTC()
+	}
+
+	// The scheduler might pick the message between the fetch and the
+	// update: in this case the update fails as the message is gone.
+	updated, err := s.scheduledRepo.Update(ctx, message)
+	if err != nil {
+		return communication.ScheduledMessageDtoResponse{}, err
+	}
+
+	return communication.ToScheduledMessageDtoResponse(updated), nil
+}
+
+func (s *scheduledMessageServiceImpl) Cancel(
+	ctx context.Context, user uuid.UUID, id uuid.UUID,
+) error {
+	if _, err := s.getOwnedMessage(ctx, user, id); err != nil {
+		return err
+	}
+
+	return s.scheduledRepo.Delete(ctx, id)
+}
+
+func (s *scheduledMessageServiceImpl) getOwnedMessage(
+	ctx context.Context, user uuid.UUID, id uuid.UUID,
+) (persistence.ScheduledMessage, error) {
+	message, err := s.scheduledRepo.Get(ctx, id)
+	if err != nil {
+		return persistence.ScheduledMessage{}, err
+	}
+
+	if message.ChatUser != user {
+		return persistence.ScheduledMessage{}, errors.NewCode(ErrNotMessageAuthor)
+	}
+
+	return message, nil
+}
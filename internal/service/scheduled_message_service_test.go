@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_ScheduledMessageService_ListForRoom(t *testing.T) {
+	service, conn := newTestScheduledMessageService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	later := insertTestScheduledMessage(t, conn, user.Id, room.Id, time.Now().Add(2*time.Hour))
+	sooner := insertTestScheduledMessage(t, conn, user.Id, room.Id, time.Now().Add(time.Hour))
+
+	out, err := service.ListForRoom(context.Background(), room.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, 2, len(out))
+	assert.Equal(t, sooner.Id, out[0].Id)
+	assert.Equal(t, later.Id, out[1].Id)
+}
+
+func TestIT_ScheduledMessageService_ListForRoom_WhenNoMessages_ExpectEmptyList(t *testing.T) {
+	service, conn := newTestScheduledMessageService(t)
+	defer conn.Close(context.Background())
+
+	out, err := service.ListForRoom(context.Background(), uuid.New())
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, []communication.ScheduledMessageDtoResponse{}, out)
+}
+
+func TestIT_ScheduledMessageService_Update(t *testing.T) {
+	service, conn := newTestScheduledMessageService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	msg := insertTestScheduledMessage(t, conn, user.Id, room.Id, time.Now().Add(time.Hour))
+
+	message := "updated message"
+	sendAt := time.Now().Add(2 * time.Hour).UTC().Truncate(time.Microsecond)
+	dto := communication.ScheduledMessageDtoRequest{
+		User:    user.Id,
+		Message: &message,
+		SendAt:  &sendAt,
+	}
+
+	out, err := service.Update(context.Background(), msg.Id, dto)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, msg.Id, out.Id)
+	assert.Equal(t, message, out.Message)
+	assert.Equal(t, sendAt, out.SendAt)
+}
+
+func TestIT_ScheduledMessageService_Update_WhenOnlyMessageIsProvided_ExpectSendAtUnchanged(t *testing.T) {
+	service, conn := newTestScheduledMessageService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	msg := insertTestScheduledMessage(t, conn, user.Id, room.Id, time.Now().Add(time.Hour))
+
+	message := "updated message"
+	dto := communication.ScheduledMessageDtoRequest{
+		User:    user.Id,
+		Message: &message,
+	}
+
+	out, err := service.Update(context.Background(), msg.Id, dto)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, message, out.Message)
+	assert.Equal(t, msg.SendAt, out.SendAt)
+}
+
+func TestIT_ScheduledMessageService_Update_WhenUserIsNotTheAuthor_ExpectError(t *testing.T) {
+	service, conn := newTestScheduledMessageService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	msg := insertTestScheduledMessage(t, conn, user.Id, room.Id, time.Now().Add(time.Hour))
+
+	message := "updated message"
+	dto := communication.ScheduledMessageDtoRequest{
+		User:    uuid.New(),
+		Message: &message,
+	}
+
+	_, err := service.Update(context.Background(), msg.Id, dto)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrNotMessageAuthor),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_ScheduledMessageService_Update_WhenSendAtIsInThePast_ExpectError(t *testing.T) {
+	service, conn := newTestScheduledMessageService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	msg := insertTestScheduledMessage(t, conn, user.Id, room.Id, time.Now().Add(time.Hour))
+
+	sendAt := time.Now().Add(-time.Hour)
+	dto := communication.ScheduledMessageDtoRequest{
+		User:   user.Id,
+		SendAt: &sendAt,
+	}
+
+	_, err := service.Update(context.Background(), msg.Id, dto)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrInvalidSendAt),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_ScheduledMessageService_Update_WhenMessageIsEmpty_ExpectError(t *testing.T) {
+	service, conn := newTestScheduledMessageService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	msg := insertTestScheduledMessage(t, conn, user.Id, room.Id, time.Now().Add(time.Hour))
+
+	message := ""
+	dto := communication.ScheduledMessageDtoRequest{
+		User:    user.Id,
+		Message: &message,
+	}
+
+	_, err := service.Update(context.Background(), msg.Id, dto)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrEmptyMessage),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_ScheduledMessageService_Cancel(t *testing.T) {
+	service, conn := newTestScheduledMessageService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	msg := insertTestScheduledMessage(t, conn, user.Id, room.Id, time.Now().Add(time.Hour))
+
+	err := service.Cancel(context.Background(), user.Id, msg.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assertScheduledMessageDoesNotExist(t, conn, msg.Id)
+}
+
+func TestIT_ScheduledMessageService_Cancel_WhenUserIsNotTheAuthor_ExpectError(t *testing.T) {
+	service, conn := newTestScheduledMessageService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	msg := insertTestScheduledMessage(t, conn, user.Id, room.Id, time.Now().Add(time.Hour))
+
+	err := service.Cancel(context.Background(), uuid.New(), msg.Id)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrNotMessageAuthor),
+		"Actual err: %v",
+		err,
+	)
+	assertScheduledMessageExists(t, conn, msg.Id)
+}
+
+func TestIT_ScheduledMessageService_Cancel_WhenMessageDoesNotExist_ExpectError(t *testing.T) {
+	service, conn := newTestScheduledMessageService(t)
+	defer conn.Close(context.Background())
+
+	err := service.Cancel(context.Background(), uuid.New(), uuid.New())
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func newTestScheduledMessageService(t *testing.T) (ScheduledMessageService, db.Connection) {
+	conn := newTestDbConnection(t)
+	return NewScheduledMessageService(repositories.New(conn)), conn
+}
+
+func insertTestScheduledMessage(
+	t *testing.T,
+	conn db.Connection,
+	user uuid.UUID,
+	room uuid.UUID,
+	sendAt time.Time,
+) persistence.ScheduledMessage {
+	repo := repositories.NewScheduledMessageRepository(conn)
+
+	msg := persistence.ScheduledMessage{
+		Id:       uuid.New(),
+		ChatUser: user,
+		Room:     room,
+		Message:  "my-scheduled-message-" + uuid.NewString(),
+		SendAt:   sendAt.UTC().Truncate(time.Microsecond),
+	}
+	out, err := repo.Create(context.Background(), msg)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return out
+}
+
+func assertScheduledMessageExists(t *testing.T, conn db.Connection, id uuid.UUID) {
+	value, err := db.QueryOne[int](
+		context.Background(),
+		conn,
+		"SELECT COUNT(id) FROM scheduled_message WHERE id = $1",
+		id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, 1, value)
+}
+
+func assertScheduledMessageDoesNotExist(t *testing.T, conn db.Connection, id uuid.UUID) {
+	value, err := db.QueryOne[int](
+		context.Background(),
+		conn,
+		"SELECT COUNT(id) FROM scheduled_message WHERE id = $1",
+		id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Zero(t, value)
+}
@@ -1,8 +1,12 @@
 package service
 
 type Services struct {
-	Registration RegistrationService
-	Room         RoomService
-	User         UserService
-	Message      MessageService
+	Attachment       AttachmentService
+	DeadLetter       DeadLetterService
+	Pin              PinService
+	ScheduledMessage ScheduledMessageService
+	Registration     RegistrationService
+	Room             RoomService
+	User             UserService
+	Message          MessageService
 }
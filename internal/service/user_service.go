@@ -119,6 +119,30 @@ func (s *userServiceImpl) Delete(
 		return err
 	}
 
+	err = s.repos.Attachment.UpdateAttachmentsOwner(
+		ctx, tx, id, ghostUserName,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = s.repos.Pin.UpdatePinsOwner(ctx, tx, id, ghostUserName)
+	if err != nil {
+		return err
+	}
+
+	// Scheduled messages are not sent yet so they are dropped instead
+	// of being attributed to the ghost user.
+	err = s.repos.ScheduledMessage.DeleteForUser(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.repos.IdempotencyKey.DeleteForUser(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
 	err = s.repos.User.DeleteFromRooms(ctx, tx, id)
 	if err != nil {
 		return err
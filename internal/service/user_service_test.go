@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/pgx"
@@ -231,6 +232,71 @@ func TestIT_UserService_Delete_UpdatesMessagesOwnershipToGhost(t *testing.T) {
 	assertMessageOwner(t, conn, msg.Id, "ghost")
 }
 
+func TestIT_UserService_Delete_UpdatesPinsOwnershipToGhost(t *testing.T) {
+	service, conn := newTestUserService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	registerUserByNameInRoom(t, conn, "ghost", room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+	insertTestPin(t, conn, user.Id, room.Id, msg.Id)
+
+	err := service.Delete(context.Background(), user.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	pinnedBy, err := db.QueryOne[string](
+		context.Background(),
+		conn,
+		`SELECT cu.name FROM pinned_message AS pm
+			LEFT JOIN chat_user AS cu ON pm.pinned_by = cu.id
+			WHERE pm.message = $1`,
+		msg.Id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, "ghost", pinnedBy)
+}
+
+func TestIT_UserService_Delete_DeletesScheduledMessages(t *testing.T) {
+	service, conn := newTestUserService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestScheduledMessage(t, conn, user.Id, room.Id, time.Now().Add(time.Hour))
+
+	err := service.Delete(context.Background(), user.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assertScheduledMessageDoesNotExist(t, conn, msg.Id)
+}
+
+func TestIT_UserService_Delete_DeletesIdempotencyKeys(t *testing.T) {
+	service, conn := newTestUserService(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	_, err := conn.Exec(
+		context.Background(),
+		`INSERT INTO idempotency_key (chat_user, key, message) VALUES ($1, $2, $3)`,
+		user.Id,
+		uuid.NewString(),
+		uuid.New(),
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	err = service.Delete(context.Background(), user.Id)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	value, err := db.QueryOne[int](
+		context.Background(),
+		conn,
+		"SELECT COUNT(*) FROM idempotency_key WHERE chat_user = $1",
+		user.Id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Zero(t, value)
+}
+
 func TestIT_UserService_Delete_DoesNotChangeOwnershipOfOtherMessagesInTheRoom(t *testing.T) {
 	service, conn := newTestUserService(t)
 	defer conn.Close(context.Background())
@@ -0,0 +1,49 @@
+package bus
+
+import (
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/postgresql"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+)
+
+// Bus carries the messages and events from the components producing them
+// to the clients connected to the server. Publishing on the bus delivers
+// to the local dispatcher of every instance of the server.
+type Bus interface {
+	Start() error
+	Stop() error
+
+	messages.Dispatcher
+}
+
+const (
+	InMemory = "memory"
+	Postgres = "postgres"
+	Nats     = "nats"
+)
+
+type Config struct {
+	Backend string
+	Nats    NatsConfig
+}
+
+// New creates the bus defined by the configuration. The database is used
+// by the `postgres` backend.
+func New(
+	config Config,
+	database postgresql.Config,
+	conn db.Connection,
+	local messages.Dispatcher,
+) (Bus, error) {
+	switch config.Backend {
+	case InMemory:
+		return NewInMemoryBus(local), nil
+	case Postgres:
+		return NewPostgresBus(database, conn, local), nil
+	case Nats:
+		return NewNatsBus(config.Nats, local)
+	default:
+		return nil, errors.NewCode(ErrUnknownBackend)
+	}
+}
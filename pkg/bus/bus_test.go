@@ -0,0 +1,33 @@
+package bus
+
+import (
+	"testing"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_New_WhenInMemory_ExpectInMemoryBus(t *testing.T) {
+	b, err := New(Config{Backend: InMemory}, dbTestConfig, nil, &mockDispatcher{})
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.IsType(t, &inMemoryBusImpl{}, b)
+}
+
+func TestUnit_New_WhenPostgres_ExpectPostgresBus(t *testing.T) {
+	b, err := New(Config{Backend: Postgres}, dbTestConfig, nil, &mockDispatcher{})
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.IsType(t, &postgresBusImpl{}, b)
+}
+
+func TestUnit_New_WhenBackendIsUnknown_ExpectError(t *testing.T) {
+	_, err := New(Config{Backend: "kafka"}, dbTestConfig, nil, &mockDispatcher{})
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrUnknownBackend),
+		"Actual err: %v",
+		err,
+	)
+}
@@ -0,0 +1,156 @@
+package bus
+
+import (
+	"encoding/json"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type envelopeKind string
+
+const (
+	broadcastEvent  envelopeKind = "broadcast_event"
+	broadcastExcept envelopeKind = "broadcast_except"
+	sendTo          envelopeKind = "send_to"
+	registration    envelopeKind = "registration"
+	unregistration  envelopeKind = "unregistration"
+	roomDeletion    envelopeKind = "room_deletion"
+	flush           envelopeKind = "flush"
+)
+
+// envelope is the representation of a call to the dispatcher exchanged
+// between the instances of the server.
+type envelope struct {
+	Kind envelopeKind `json:"kind"`
+
+	// Target is the user for `broadcast_except`, `send_to`, `registration`
+	// and `unregistration` envelopes and the instance which sent a `flush`
+	// envelope.
+	Target uuid.UUID `json:"target,omitzero"`
+	// Room is only set for the envelopes describing a membership change.
+	Room    uuid.UUID            `json:"room,omitzero"`
+	Message *persistence.Message `json:"message,omitempty"`
+	Event   *wireEvent           `json:"event,omitempty"`
+}
+
+// wireEvent keeps the data of the event as it was serialized: it is decoded
+// when delivered so that the receivers get the same data as with a local
+// dispatcher.
+type wireEvent struct {
+	Id   uuid.UUID       `json:"id"`
+	Type events.Type     `json:"type"`
+	Room uuid.UUID       `json:"room"`
+	Data json.RawMessage `json:"data"`
+}
+
+func newEventEnvelope(event events.Event) (envelope, error) {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return envelope{}, errors.WrapCode(err, ErrEnvelopeEncoding)
+	}
+
+	out := envelope{
+		Kind: broadcastEvent,
+		Event: &wireEvent{
+			Id:   event.Id,
+			Type: event.Type,
+			Room: event.Room,
+			Data: data,
+		},
+	}
+	return out, nil
+}
+
+func (e envelope) encode() ([]byte, error) {
+	out, err := json.Marshal(e)
+	if err != nil {
+		return nil, errors.WrapCode(err, ErrEnvelopeEncoding)
+	}
+
+	return out, nil
+}
+
+func decodeEnvelope(payload []byte) (envelope, error) {
+	var out envelope
+	if err := json.Unmarshal(payload, &out); err != nil {
+		return out, errors.WrapCode(err, ErrEnvelopeDecoding)
+	}
+
+	return out, nil
+}
+
+// receive hands the payload received by an instance to its dispatcher.
+// The flush envelopes are not delivered: the instance which sent them is
+// notified through the flushed channel.
+func receive(
+	payload []byte,
+	instance uuid.UUID,
+	flushed chan struct{},
+	local messages.Dispatcher,
+) {
+	in, err := decodeEnvelope(payload)
+	if err != nil {
+		return
+	}
+
+	if in.Kind == flush {
+		if in.Target == instance {
+			select {
+			case flushed <- struct{}{}:
+			default:
+			}
+		}
+		return
+	}
+
+	// Voluntarily ignore errors: the messages are persisted and can be
+	// fetched from the history of the room.
+	in.deliver(local)
+}
+
+// deliver performs the call described by the envelope on the dispatcher.
+func (e envelope) deliver(local messages.Dispatcher) error {
+	switch e.Kind {
+	case broadcastEvent:
+		if e.Event == nil {
+			return errors.NewCode(ErrEnvelopeDecoding)
+		}
+		data, err := events.DecodeData(e.Event.Type, e.Event.Data)
+		if err != nil {
+			return errors.WrapCode(err, ErrEnvelopeDecoding)
+		}
+		event := events.Event{
+			Id:   e.Event.Id,
+			Type: e.Event.Type,
+			Room: e.Event.Room,
+			Data: data,
+		}
+		return local.BroadcastEvent(event)
+	case broadcastExcept:
+		if e.Message == nil {
+			return errors.NewCode(ErrEnvelopeDecoding)
+		}
+		return local.BroadcastExcept(e.Target, *e.Message)
+	case sendTo:
+		if e.Message == nil {
+			return errors.NewCode(ErrEnvelopeDecoding)
+		}
+		local.SendTo(e.Target, *e.Message)
+		return nil
+	case registration:
+		local.OnRegistration(e.Target, e.Room)
+		return nil
+	case unregistration:
+		local.OnUnregistration(e.Target, e.Room)
+		return nil
+	case roomDeletion:
+		local.OnRoomDeletion(e.Room)
+		return nil
+	default:
+		return errors.NewCode(ErrUnknownEnvelopeKind)
+	}
+}
@@ -0,0 +1,169 @@
+package bus
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_Envelope_WhenEvent_ExpectSameDataDelivered(t *testing.T) {
+	msg := persistence.Message{
+		Id:        uuid.New(),
+		ChatUser:  uuid.New(),
+		Room:      uuid.New(),
+		Message:   "hello",
+		CreatedAt: time.Date(2024, 11, 12, 19, 9, 36, 0, time.UTC),
+	}
+	event := events.FromMessage(msg)
+
+	local := roundTripEnvelope(t, func() (envelope, error) {
+		return newEventEnvelope(event)
+	})
+
+	assert.Len(t, local.events, 1)
+	actual := local.events[0]
+	assert.Equal(t, event.Id, actual.Id)
+	assert.Equal(t, event.Type, actual.Type)
+	assert.Equal(t, event.Room, actual.Room)
+
+	expectedData, err := json.Marshal(event.Data)
+	assert.Nil(t, err, "Actual err: %v", err)
+	actualData, err := json.Marshal(actual.Data)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.JSONEq(t, string(expectedData), string(actualData))
+}
+
+func TestUnit_Envelope_WhenEvent_ExpectDataDecoded(t *testing.T) {
+	room := uuid.New()
+	msg := persistence.Message{
+		Id:        uuid.New(),
+		ChatUser:  uuid.New(),
+		Room:      room,
+		Message:   "hello",
+		Sequence:  4,
+		CreatedAt: time.Date(2024, 11, 12, 19, 9, 36, 0, time.UTC),
+	}
+	typing := communication.TypingDtoResponse{
+		Room: room,
+		User: msg.ChatUser,
+	}
+
+	testCases := map[string]events.Event{
+		"message": events.FromMessage(msg),
+		"typing":  events.FromTyping(typing),
+	}
+
+	for name, event := range testCases {
+		t.Run(name, func(t *testing.T) {
+			local := roundTripEnvelope(t, func() (envelope, error) {
+				return newEventEnvelope(event)
+			})
+
+			assert.Len(t, local.events, 1)
+			assert.Equal(t, event.Data, local.events[0].Data)
+		})
+	}
+}
+
+func TestUnit_Envelope_WhenBroadcastExcept_ExpectDelivered(t *testing.T) {
+	user := uuid.New()
+	msg := persistence.Message{
+		Id:        uuid.New(),
+		Room:      uuid.New(),
+		Message:   "hello",
+		CreatedAt: time.Date(2024, 11, 12, 19, 9, 36, 0, time.UTC),
+	}
+
+	local := roundTripEnvelope(t, func() (envelope, error) {
+		return envelope{Kind: broadcastExcept, Target: user, Message: &msg}, nil
+	})
+
+	assert.Equal(t, []uuid.UUID{user}, local.targets)
+	assert.Equal(t, []persistence.Message{msg}, local.messages)
+}
+
+func TestUnit_Envelope_WhenSendTo_ExpectDelivered(t *testing.T) {
+	user := uuid.New()
+	msg := persistence.Message{Id: uuid.New(), Message: "hello"}
+
+	local := roundTripEnvelope(t, func() (envelope, error) {
+		return envelope{Kind: sendTo, Target: user, Message: &msg}, nil
+	})
+
+	assert.Equal(t, []uuid.UUID{user}, local.targets)
+	assert.Equal(t, []persistence.Message{msg}, local.messages)
+}
+
+func TestUnit_Envelope_WhenRegistration_ExpectDelivered(t *testing.T) {
+	user := uuid.New()
+	room := uuid.New()
+
+	local := roundTripEnvelope(t, func() (envelope, error) {
+		return envelope{Kind: registration, Target: user, Room: room}, nil
+	})
+
+	assert.Equal(t, []uuid.UUID{user}, local.targets)
+	assert.Equal(t, []uuid.UUID{room}, local.rooms)
+}
+
+func TestUnit_Envelope_WhenRoomDeletion_ExpectDelivered(t *testing.T) {
+	room := uuid.New()
+
+	local := roundTripEnvelope(t, func() (envelope, error) {
+		return envelope{Kind: roomDeletion, Room: room}, nil
+	})
+
+	assert.Nil(t, local.targets)
+	assert.Equal(t, []uuid.UUID{room}, local.rooms)
+}
+
+func TestUnit_Envelope_WhenKindIsUnknown_ExpectError(t *testing.T) {
+	in, err := decodeEnvelope([]byte(`{"kind": "unknown"}`))
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	err = in.deliver(&mockDispatcher{})
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrUnknownEnvelopeKind),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestUnit_Envelope_WhenPayloadIsInvalid_ExpectError(t *testing.T) {
+	_, err := decodeEnvelope([]byte("not-json"))
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrEnvelopeDecoding),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func roundTripEnvelope(
+	t *testing.T, generate func() (envelope, error),
+) *mockDispatcher {
+	out, err := generate()
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	payload, err := out.encode()
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	in, err := decodeEnvelope(payload)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	local := &mockDispatcher{}
+	err = in.deliver(local)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return local
+}
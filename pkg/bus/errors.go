@@ -0,0 +1,10 @@
+package bus
+
+import "github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+
+const (
+	ErrEnvelopeEncoding    errors.ErrorCode = 901
+	ErrEnvelopeDecoding    errors.ErrorCode = 902
+	ErrUnknownEnvelopeKind errors.ErrorCode = 903
+	ErrUnknownBackend      errors.ErrorCode = 904
+)
@@ -0,0 +1,101 @@
+package bus
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/postgresql"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var dbTestConfig = postgresql.NewConfigForLocalhost(
+	"db_chat_server",
+	"chat_server_manager",
+	"manager_password",
+)
+
+type mockDispatcher struct {
+	lock sync.Mutex
+
+	events   []events.Event
+	messages []persistence.Message
+	targets  []uuid.UUID
+	rooms    []uuid.UUID
+	err      error
+}
+
+func (m *mockDispatcher) Broadcast(msg persistence.Message) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.messages = append(m.messages, msg)
+	return m.err
+}
+
+func (m *mockDispatcher) BroadcastExcept(id uuid.UUID, msg persistence.Message) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.targets = append(m.targets, id)
+	m.messages = append(m.messages, msg)
+	return m.err
+}
+
+func (m *mockDispatcher) SendTo(id uuid.UUID, msg persistence.Message) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.targets = append(m.targets, id)
+	m.messages = append(m.messages, msg)
+}
+
+func (m *mockDispatcher) BroadcastEvent(event events.Event) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.events = append(m.events, event)
+	return m.err
+}
+
+func (m *mockDispatcher) OnRegistration(user uuid.UUID, room uuid.UUID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.targets = append(m.targets, user)
+	m.rooms = append(m.rooms, room)
+}
+
+func (m *mockDispatcher) OnUnregistration(user uuid.UUID, room uuid.UUID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.targets = append(m.targets, user)
+	m.rooms = append(m.rooms, room)
+}
+
+func (m *mockDispatcher) OnRoomDeletion(room uuid.UUID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.rooms = append(m.rooms, room)
+}
+
+func (m *mockDispatcher) receivedEvents() []events.Event {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]events.Event(nil), m.events...)
+}
+
+func asyncStartBusAndAssertNoError(t *testing.T, b Bus) *sync.WaitGroup {
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		err := b.Start()
+		assert.Nil(t, err, "Actual err: %v", err)
+	}()
+
+	// Wait a bit for the bus to start
+	time.Sleep(100 * time.Millisecond)
+
+	return &wg
+}
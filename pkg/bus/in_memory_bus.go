@@ -0,0 +1,84 @@
+package bus
+
+import (
+	"sync/atomic"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type inMemoryBusImpl struct {
+	running atomic.Bool
+	stopped atomic.Bool
+	quit    chan struct{}
+	done    chan struct{}
+
+	local messages.Dispatcher
+}
+
+// NewInMemoryBus hands the messages directly to the local dispatcher. It
+// is only suitable when a single instance of the server is running.
+func NewInMemoryBus(local messages.Dispatcher) Bus {
+	return &inMemoryBusImpl{
+		quit: make(chan struct{}, 1),
+		done: make(chan struct{}, 1),
+
+		local: local,
+	}
+}
+
+func (b *inMemoryBusImpl) Start() error {
+	if !b.running.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	<-b.quit
+	b.done <- struct{}{}
+
+	return nil
+}
+
+func (b *inMemoryBusImpl) Stop() error {
+	if !b.stopped.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	close(b.quit)
+	// A component stopped before it started returns as soon as it starts:
+	// there is nothing to wait for.
+	if b.running.Load() {
+		<-b.done
+	}
+
+	return nil
+}
+
+func (b *inMemoryBusImpl) Broadcast(msg persistence.Message) error {
+	return b.local.Broadcast(msg)
+}
+
+func (b *inMemoryBusImpl) BroadcastExcept(id uuid.UUID, msg persistence.Message) error {
+	return b.local.BroadcastExcept(id, msg)
+}
+
+func (b *inMemoryBusImpl) SendTo(id uuid.UUID, msg persistence.Message) {
+	b.local.SendTo(id, msg)
+}
+
+func (b *inMemoryBusImpl) BroadcastEvent(event events.Event) error {
+	return b.local.BroadcastEvent(event)
+}
+
+func (b *inMemoryBusImpl) OnRegistration(user uuid.UUID, room uuid.UUID) {
+	b.local.OnRegistration(user, room)
+}
+
+func (b *inMemoryBusImpl) OnUnregistration(user uuid.UUID, room uuid.UUID) {
+	b.local.OnUnregistration(user, room)
+}
+
+func (b *inMemoryBusImpl) OnRoomDeletion(room uuid.UUID) {
+	b.local.OnRoomDeletion(room)
+}
@@ -0,0 +1,86 @@
+package bus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_InMemoryBus_StartStop(t *testing.T) {
+	b := NewInMemoryBus(&mockDispatcher{})
+
+	wg := asyncStartBusAndAssertNoError(t, b)
+
+	err := b.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+}
+
+func TestUnit_InMemoryBus_WhenStoppedBeforeStart_ExpectStartReturns(t *testing.T) {
+	b := NewInMemoryBus(&mockDispatcher{})
+
+	err := b.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- b.Start()
+	}()
+
+	select {
+	case err = <-done:
+		assert.Nil(t, err, "Actual err: %v", err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "Bus started after being stopped")
+	}
+}
+
+func TestUnit_InMemoryBus_Broadcast_ExpectForwardedToLocalDispatcher(t *testing.T) {
+	local := &mockDispatcher{}
+	b := NewInMemoryBus(local)
+
+	msg := persistence.Message{Id: uuid.New(), Room: uuid.New()}
+	err := b.Broadcast(msg)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, []persistence.Message{msg}, local.messages)
+}
+
+func TestUnit_InMemoryBus_BroadcastEvent_ExpectForwardedToLocalDispatcher(t *testing.T) {
+	local := &mockDispatcher{}
+	b := NewInMemoryBus(local)
+
+	event := events.FromMessage(persistence.Message{Id: uuid.New()})
+	err := b.BroadcastEvent(event)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, []events.Event{event}, local.events)
+}
+
+func TestUnit_InMemoryBus_SendTo_ExpectForwardedToLocalDispatcher(t *testing.T) {
+	local := &mockDispatcher{}
+	b := NewInMemoryBus(local)
+
+	user := uuid.New()
+	msg := persistence.Message{Id: uuid.New()}
+	b.SendTo(user, msg)
+
+	assert.Equal(t, []uuid.UUID{user}, local.targets)
+	assert.Equal(t, []persistence.Message{msg}, local.messages)
+}
+
+func TestUnit_InMemoryBus_OnRegistration_ExpectForwardedToLocalDispatcher(t *testing.T) {
+	local := &mockDispatcher{}
+	b := NewInMemoryBus(local)
+
+	user := uuid.New()
+	room := uuid.New()
+	b.OnRegistration(user, room)
+
+	assert.Equal(t, []uuid.UUID{user}, local.targets)
+	assert.Equal(t, []uuid.UUID{room}, local.rooms)
+}
@@ -0,0 +1,161 @@
+package bus
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+)
+
+type NatsConfig struct {
+	Url string
+	// SubjectPrefix allows several deployments to share a NATS cluster.
+	SubjectPrefix string
+}
+
+type natsBusImpl struct {
+	running atomic.Bool
+	stopped atomic.Bool
+	quit    chan struct{}
+	flushed chan struct{}
+	done    chan struct{}
+
+	// Identifies this instance so that it recognizes its own flush.
+	instance uuid.UUID
+	prefix   string
+	conn     *nats.Conn
+	local    messages.Dispatcher
+}
+
+// NewNatsBus publishes the messages on a subject per room and hands the
+// ones received from any room to the local dispatcher. Every instance of
+// the server subscribes to all the subjects so each of them receives each
+// message once, including the one which published it.
+func NewNatsBus(config NatsConfig, local messages.Dispatcher) (Bus, error) {
+	conn, err := nats.Connect(config.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &natsBusImpl{
+		quit:    make(chan struct{}, 1),
+		flushed: make(chan struct{}, 1),
+		done:    make(chan struct{}, 1),
+
+		instance: uuid.New(),
+		prefix:   config.SubjectPrefix,
+		conn:     conn,
+		local:    local,
+	}
+	return b, nil
+}
+
+func (b *natsBusImpl) Start() error {
+	if !b.running.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	defer func() {
+		b.conn.Close()
+		b.done <- struct{}{}
+	}()
+
+	// The messages of a subscription are handled one at a time and in the
+	// order they were published.
+	sub, err := b.conn.Subscribe(b.prefix+".>", func(msg *nats.Msg) {
+		receive(msg.Data, b.instance, b.flushed, b.local)
+	})
+	if err != nil {
+		return err
+	}
+
+	<-b.quit
+
+	return sub.Unsubscribe()
+}
+
+func (b *natsBusImpl) Stop() error {
+	if !b.stopped.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	if !b.running.Load() {
+		// The bus returns as soon as it starts.
+		close(b.quit)
+		return nil
+	}
+
+	// The flush is published on the same connection as the messages: once
+	// it is received, they were delivered to the clients.
+	flushSubject := b.subject("instances", b.instance)
+	if err := b.publish(flushSubject, envelope{Kind: flush, Target: b.instance}); err == nil {
+		select {
+		case <-b.flushed:
+		case <-time.After(flushTimeout):
+		}
+	}
+
+	close(b.quit)
+	<-b.done
+
+	return nil
+}
+
+func (b *natsBusImpl) Broadcast(msg persistence.Message) error {
+	return b.BroadcastEvent(events.FromMessage(msg))
+}
+
+func (b *natsBusImpl) BroadcastExcept(id uuid.UUID, msg persistence.Message) error {
+	out := envelope{Kind: broadcastExcept, Target: id, Message: &msg}
+	return b.publish(b.subject("rooms", msg.Room), out)
+}
+
+func (b *natsBusImpl) SendTo(id uuid.UUID, msg persistence.Message) {
+	// Voluntarily ignore errors: the dispatcher does not report them.
+	out := envelope{Kind: sendTo, Target: id, Message: &msg}
+	b.publish(b.subject("users", id), out)
+}
+
+func (b *natsBusImpl) BroadcastEvent(event events.Event) error {
+	out, err := newEventEnvelope(event)
+	if err != nil {
+		return err
+	}
+
+	return b.publish(b.subject("rooms", event.Room), out)
+}
+
+// The membership changes are published on the subject of the room: they
+// are received in order with the messages of the room.
+func (b *natsBusImpl) OnRegistration(user uuid.UUID, room uuid.UUID) {
+	out := envelope{Kind: registration, Target: user, Room: room}
+	b.publish(b.subject("rooms", room), out)
+}
+
+func (b *natsBusImpl) OnUnregistration(user uuid.UUID, room uuid.UUID) {
+	out := envelope{Kind: unregistration, Target: user, Room: room}
+	b.publish(b.subject("rooms", room), out)
+}
+
+func (b *natsBusImpl) OnRoomDeletion(room uuid.UUID) {
+	out := envelope{Kind: roomDeletion, Room: room}
+	b.publish(b.subject("rooms", room), out)
+}
+
+func (b *natsBusImpl) subject(kind string, id uuid.UUID) string {
+	return fmt.Sprintf("%s.%s.%s", b.prefix, kind, id)
+}
+
+func (b *natsBusImpl) publish(subject string, out envelope) error {
+	payload, err := out.encode()
+	if err != nil {
+		return err
+	}
+
+	return b.conn.Publish(subject, payload)
+}
@@ -0,0 +1,177 @@
+package bus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_NatsBus_WhenServerIsNotReachable_ExpectError(t *testing.T) {
+	config := NatsConfig{
+		Url:           "nats://localhost:1",
+		SubjectPrefix: "chat",
+	}
+
+	_, err := NewNatsBus(config, &mockDispatcher{})
+
+	assert.NotNil(t, err)
+}
+
+func TestIT_NatsBus_WhenEventPublished_ExpectEachInstanceReceivesItOnce(t *testing.T) {
+	srv := newTestNatsServer(t)
+	defer srv.Shutdown()
+
+	first, firstBus := newTestNatsBus(t, srv)
+	second, secondBus := newTestNatsBus(t, srv)
+
+	wgFirst := asyncStartBusAndAssertNoError(t, firstBus)
+	wgSecond := asyncStartBusAndAssertNoError(t, secondBus)
+
+	event := events.FromMessage(persistence.Message{Id: uuid.New(), Room: uuid.New()})
+	err := firstBus.BroadcastEvent(event)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	// Stopping the buses waits for the messages to be delivered.
+	err = firstBus.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	time.Sleep(50 * time.Millisecond)
+	err = secondBus.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wgFirst.Wait()
+	wgSecond.Wait()
+
+	for _, local := range []*mockDispatcher{first, second} {
+		received := local.receivedEvents()
+		assert.Len(t, received, 1)
+		assert.Equal(t, event.Id, received[0].Id)
+		assert.Equal(t, event.Room, received[0].Room)
+	}
+}
+
+func TestIT_NatsBus_ExpectMessagesPublishedOnSubjectOfRoom(t *testing.T) {
+	srv := newTestNatsServer(t)
+	defer srv.Shutdown()
+
+	conn, err := nats.Connect(srv.ClientURL())
+	assert.Nil(t, err, "Actual err: %v", err)
+	defer conn.Close()
+
+	room := uuid.New()
+	sub, err := conn.SubscribeSync("chat-test.rooms." + room.String())
+	assert.Nil(t, err, "Actual err: %v", err)
+	err = conn.Flush()
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	_, b := newTestNatsBus(t, srv)
+	err = b.Broadcast(persistence.Message{Id: uuid.New(), Room: room})
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	msg, err := sub.NextMsg(time.Second)
+	assert.Nil(t, err, "Actual err: %v", err)
+	in, err := decodeEnvelope(msg.Data)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, broadcastEvent, in.Kind)
+	assert.Equal(t, room, in.Event.Room)
+}
+
+func TestIT_NatsBus_WhenStopped_ExpectPendingMessagesDelivered(t *testing.T) {
+	srv := newTestNatsServer(t)
+	defer srv.Shutdown()
+
+	local, b := newTestNatsBus(t, srv)
+
+	wg := asyncStartBusAndAssertNoError(t, b)
+
+	room := uuid.New()
+	for range 10 {
+		event := events.FromMessage(persistence.Message{Id: uuid.New(), Room: room})
+		err := b.BroadcastEvent(event)
+		assert.Nil(t, err, "Actual err: %v", err)
+	}
+
+	start := time.Now()
+	err := b.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Less(t, time.Since(start), flushTimeout)
+	assert.Len(t, local.receivedEvents(), 10)
+}
+
+func TestIT_NatsBus_SendTo_ExpectDelivered(t *testing.T) {
+	srv := newTestNatsServer(t)
+	defer srv.Shutdown()
+
+	local, b := newTestNatsBus(t, srv)
+
+	wg := asyncStartBusAndAssertNoError(t, b)
+
+	user := uuid.New()
+	msg := persistence.Message{Id: uuid.New(), Room: uuid.New(), Message: "hello"}
+	b.SendTo(user, msg)
+
+	err := b.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Equal(t, []uuid.UUID{user}, local.targets)
+	assert.Equal(t, []persistence.Message{msg}, local.messages)
+}
+
+func TestIT_NatsBus_OnUnregistration_ExpectDelivered(t *testing.T) {
+	srv := newTestNatsServer(t)
+	defer srv.Shutdown()
+
+	local, b := newTestNatsBus(t, srv)
+
+	wg := asyncStartBusAndAssertNoError(t, b)
+
+	user := uuid.New()
+	room := uuid.New()
+	b.OnUnregistration(user, room)
+
+	err := b.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Equal(t, []uuid.UUID{user}, local.targets)
+	assert.Equal(t, []uuid.UUID{room}, local.rooms)
+}
+
+func newTestNatsServer(t *testing.T) *server.Server {
+	opts := &server.Options{
+		Host:   "127.0.0.1",
+		Port:   server.RANDOM_PORT,
+		NoLog:  true,
+		NoSigs: true,
+	}
+
+	srv, err := server.NewServer(opts)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatalf("NATS server did not start")
+	}
+
+	return srv
+}
+
+func newTestNatsBus(t *testing.T, srv *server.Server) (*mockDispatcher, Bus) {
+	config := NatsConfig{
+		Url:           srv.ClientURL(),
+		SubjectPrefix: "chat-test",
+	}
+
+	local := &mockDispatcher{}
+	b, err := NewNatsBus(config, local)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return local, b
+}
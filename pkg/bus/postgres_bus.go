@@ -0,0 +1,394 @@
+package bus
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/postgresql"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	jpgx "github.com/jackc/pgx/v5"
+)
+
+const channel = "chat_events"
+
+// https://www.postgresql.org/docs/current/sql-notify.html
+const maxPayloadSize = 8000
+
+// maxInlinePayloadSize leaves room for the id of the envelope in the
+// notification. Larger envelopes are fetched from the database.
+const maxInlinePayloadSize = maxPayloadSize - 32
+
+const reconnectDelay = time.Second
+const flushTimeout = time.Second
+
+// The envelopes are kept long enough for an instance to catch up with the
+// ones published while it was reconnecting.
+const envelopeRetention = 10 * time.Minute
+const cleanupInterval = time.Minute
+
+// The ids of the envelopes are assigned when they are inserted but they
+// become visible when committed: an envelope can be committed after one
+// with a larger id. Catching up starts this many ids before the last one
+// received, which bounds the number of envelopes published concurrently.
+const catchUpMargin = 1024
+
+type postgresBusImpl struct {
+	running atomic.Bool
+	stopped atomic.Bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	flushed chan struct{}
+	done    chan struct{}
+
+	// Identifies this instance so that it recognizes its own flush.
+	instance uuid.UUID
+	config   postgresql.Config
+	conn     db.Connection
+	local    messages.Dispatcher
+
+	cleanupInterval time.Duration
+	retention       time.Duration
+
+	// first is the id of the most recent envelope when the bus started,
+	// last the one of the most recent envelope received and received the
+	// ids delivered within the catch up margin. They are only accessed by
+	// the listening loop.
+	first    int64
+	last     int64
+	received map[int64]struct{}
+}
+
+type storedEnvelope struct {
+	Id      int64
+	Payload string
+}
+
+// NewPostgresBus stores the envelopes in the database and publishes them
+// with `NOTIFY`, handing the ones received from the channel to the local
+// dispatcher. Every instance of the server listening on the channel
+// receives each message once, including the one which published it.
+func NewPostgresBus(
+	config postgresql.Config, conn db.Connection, local messages.Dispatcher,
+) Bus {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &postgresBusImpl{
+		ctx:     ctx,
+		cancel:  cancel,
+		flushed: make(chan struct{}, 1),
+		done:    make(chan struct{}, 1),
+
+		instance: uuid.New(),
+		config:   config,
+		conn:     conn,
+		local:    local,
+
+		cleanupInterval: cleanupInterval,
+		retention:       envelopeRetention,
+
+		received: make(map[int64]struct{}),
+	}
+}
+
+func (b *postgresBusImpl) Start() error {
+	if !b.running.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	defer func() {
+		b.done <- struct{}{}
+	}()
+
+	// Stopped before it started.
+	if b.ctx.Err() != nil {
+		return nil
+	}
+
+	listener, err := b.listen()
+	if err != nil {
+		return err
+	}
+
+	// Only the envelopes published from now on are delivered.
+	b.first, err = db.QueryOne[int64](b.ctx, b.conn, getLastEnvelopeIdSqlTemplate)
+	if err != nil {
+		listener.Close(context.Background())
+		return err
+	}
+	b.last = b.first
+
+	// The cleanup does not depend on the traffic of the channel.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		b.deleteExpiredEnvelopesUntilStopped()
+	}()
+	defer wg.Wait()
+
+	for listener != nil {
+		listener = b.receive(listener)
+	}
+
+	return nil
+}
+
+func (b *postgresBusImpl) Stop() error {
+	if !b.stopped.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	if !b.running.Load() {
+		// The bus returns as soon as it starts.
+		b.cancel()
+		return nil
+	}
+
+	// Notifications are received in the order they were committed: once
+	// the flush is received, the messages published before it were
+	// delivered to the clients.
+	if err := b.publish(envelope{Kind: flush, Target: b.instance}); err == nil {
+		select {
+		case <-b.flushed:
+		case <-time.After(flushTimeout):
+		}
+	}
+
+	b.cancel()
+	<-b.done
+
+	return nil
+}
+
+func (b *postgresBusImpl) Broadcast(msg persistence.Message) error {
+	return b.BroadcastEvent(events.FromMessage(msg))
+}
+
+func (b *postgresBusImpl) BroadcastExcept(id uuid.UUID, msg persistence.Message) error {
+	return b.publish(envelope{Kind: broadcastExcept, Target: id, Message: &msg})
+}
+
+func (b *postgresBusImpl) SendTo(id uuid.UUID, msg persistence.Message) {
+	// Voluntarily ignore errors: the dispatcher does not report them.
+	b.publish(envelope{Kind: sendTo, Target: id, Message: &msg})
+}
+
+func (b *postgresBusImpl) BroadcastEvent(event events.Event) error {
+	out, err := newEventEnvelope(event)
+	if err != nil {
+		return err
+	}
+
+	return b.publish(out)
+}
+
+// The membership changes are published to all the instances: each of them
+// keeps track of the members of the rooms for its own clients.
+func (b *postgresBusImpl) OnRegistration(user uuid.UUID, room uuid.UUID) {
+	b.publish(envelope{Kind: registration, Target: user, Room: room})
+}
+
+func (b *postgresBusImpl) OnUnregistration(user uuid.UUID, room uuid.UUID) {
+	b.publish(envelope{Kind: unregistration, Target: user, Room: room})
+}
+
+func (b *postgresBusImpl) OnRoomDeletion(room uuid.UUID) {
+	b.publish(envelope{Kind: roomDeletion, Room: room})
+}
+
+// The notification holds the id of the stored envelope, followed by the
+// envelope itself when it is small enough.
+const publishEnvelopeSqlTemplate = `
+WITH stored AS (
+	INSERT INTO bus_event (payload)
+		VALUES ($2)
+		RETURNING id
+)
+SELECT
+	pg_notify(
+		$1,
+		CASE
+			WHEN octet_length($2) <= $3 THEN id::text || ':' || $2
+			ELSE id::text
+		END
+	)
+FROM
+	stored`
+
+func (b *postgresBusImpl) publish(out envelope) error {
+	payload, err := out.encode()
+	if err != nil {
+		return err
+	}
+
+	_, err = b.conn.Exec(
+		context.Background(),
+		publishEnvelopeSqlTemplate,
+		channel,
+		string(payload),
+		maxInlinePayloadSize,
+	)
+	return err
+}
+
+func (b *postgresBusImpl) listen() (*jpgx.Conn, error) {
+	listener, err := jpgx.Connect(b.ctx, b.config.ToConnectionString())
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = listener.Exec(b.ctx, "LISTEN "+channel)
+	if err != nil {
+		listener.Close(context.Background())
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+// receive waits for the next notification and delivers it. It returns
+// the connection to use for the next notification, which is nil when
+// the bus is stopped.
+func (b *postgresBusImpl) receive(listener *jpgx.Conn) *jpgx.Conn {
+	notification, err := listener.WaitForNotification(b.ctx)
+	if err == nil {
+		b.deliver(notification.Payload)
+		return listener
+	}
+
+	listener.Close(context.Background())
+
+	for {
+		select {
+		case <-b.ctx.Done():
+			return nil
+		case <-time.After(reconnectDelay):
+		}
+
+		listener, err = b.listen()
+		if err == nil {
+			b.catchUp()
+			return listener
+		}
+	}
+}
+
+const getEnvelopeSqlTemplate = `
+SELECT
+	payload
+FROM
+	bus_event
+WHERE
+	id = $1`
+
+func (b *postgresBusImpl) deliver(notification string) {
+	rawId, payload, inline := strings.Cut(notification, ":")
+	id, err := strconv.ParseInt(rawId, 10, 64)
+	if err != nil {
+		return
+	}
+
+	if !b.markReceived(id) {
+		return
+	}
+
+	if !inline {
+		payload, err = db.QueryOne[string](b.ctx, b.conn, getEnvelopeSqlTemplate, id)
+		if err != nil {
+			return
+		}
+	}
+
+	receive([]byte(payload), b.instance, b.flushed, b.local)
+}
+
+const getLastEnvelopeIdSqlTemplate = `
+SELECT
+	COALESCE(MAX(id), 0)
+FROM
+	bus_event`
+
+const listEnvelopesSqlTemplate = `
+SELECT
+	id,
+	payload
+FROM
+	bus_event
+WHERE
+	id > $1
+ORDER BY
+	id`
+
+// catchUp delivers the envelopes published while the instance was not
+// listening. It is called once listening again so that none is missed:
+// the ones also received as notifications are not delivered twice.
+func (b *postgresBusImpl) catchUp() {
+	from := max(b.first, b.last-catchUpMargin)
+	stored, err := db.QueryAll[storedEnvelope](
+		b.ctx, b.conn, listEnvelopesSqlTemplate, from,
+	)
+	if err != nil {
+		// The clients can still fetch the messages from the history of
+		// the room.
+		return
+	}
+
+	for _, entry := range stored {
+		if b.markReceived(entry.Id) {
+			receive([]byte(entry.Payload), b.instance, b.flushed, b.local)
+		}
+	}
+}
+
+// markReceived returns false when the envelope was already delivered. The
+// ids older than the catch up margin are forgotten as they are not listed
+// again.
+func (b *postgresBusImpl) markReceived(id int64) bool {
+	if _, ok := b.received[id]; ok {
+		return false
+	}
+
+	b.received[id] = struct{}{}
+	b.last = max(b.last, id)
+
+	// Pruning in bulk keeps the cost per envelope constant.
+	if len(b.received) > 2*catchUpMargin {
+		for received := range b.received {
+			if received <= b.last-catchUpMargin {
+				delete(b.received, received)
+			}
+		}
+	}
+
+	return true
+}
+
+const deleteExpiredEnvelopesSqlTemplate = `
+DELETE FROM
+	bus_event
+WHERE
+	created_at < $1`
+
+func (b *postgresBusImpl) deleteExpiredEnvelopesUntilStopped() {
+	ticker := time.NewTicker(b.cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+			// Voluntarily ignore errors: the envelopes will be removed at
+			// the next attempt.
+			b.conn.Exec(b.ctx, deleteExpiredEnvelopesSqlTemplate, time.Now().Add(-b.retention))
+		}
+	}
+}
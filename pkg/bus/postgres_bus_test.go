@@ -0,0 +1,255 @@
+package bus
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_PostgresBus_WhenNotificationHoldsEnvelope_ExpectDelivered(t *testing.T) {
+	local := &mockDispatcher{}
+	b := NewPostgresBus(dbTestConfig, nil, local).(*postgresBusImpl)
+
+	event := events.FromMessage(persistence.Message{Id: uuid.New(), Room: uuid.New()})
+	payload := encodeTestEnvelope(t, event)
+	b.deliver("12:" + string(payload))
+
+	received := local.receivedEvents()
+	assert.Len(t, received, 1)
+	assert.Equal(t, event.Id, received[0].Id)
+	assert.Equal(t, int64(12), b.last)
+}
+
+func TestUnit_PostgresBus_WhenEnvelopeWasCaughtUp_ExpectNotDeliveredTwice(t *testing.T) {
+	local := &mockDispatcher{}
+	b := NewPostgresBus(dbTestConfig, nil, local).(*postgresBusImpl)
+	b.received = map[int64]struct{}{12: {}}
+
+	event := events.FromMessage(persistence.Message{Id: uuid.New(), Room: uuid.New()})
+	payload := encodeTestEnvelope(t, event)
+	b.deliver("12:" + string(payload))
+
+	assert.Empty(t, local.receivedEvents())
+}
+
+func TestUnit_PostgresBus_WhenEnvelopeIsOlderThanLastReceived_ExpectDelivered(t *testing.T) {
+	local := &mockDispatcher{}
+	b := NewPostgresBus(dbTestConfig, nil, local).(*postgresBusImpl)
+	b.last = 12
+
+	event := events.FromMessage(persistence.Message{Id: uuid.New(), Room: uuid.New()})
+	payload := encodeTestEnvelope(t, event)
+	b.deliver("11:" + string(payload))
+
+	assert.Len(t, local.receivedEvents(), 1)
+	assert.Equal(t, int64(12), b.last)
+}
+
+func TestUnit_PostgresBus_WhenManyEnvelopesReceived_ExpectOnlyMarginKept(t *testing.T) {
+	b := NewPostgresBus(dbTestConfig, nil, &mockDispatcher{}).(*postgresBusImpl)
+
+	for id := range int64(3 * catchUpMargin) {
+		assert.True(t, b.markReceived(id+1))
+	}
+
+	assert.LessOrEqual(t, len(b.received), 2*catchUpMargin)
+	assert.Contains(t, b.received, int64(3*catchUpMargin))
+	assert.NotContains(t, b.received, int64(1))
+}
+
+func TestIT_PostgresBus_WhenEventPublished_ExpectEachInstanceReceivesItOnce(t *testing.T) {
+	conn := newTestDbConnection(t)
+	defer conn.Close(context.Background())
+
+	first := &mockDispatcher{}
+	firstBus := NewPostgresBus(dbTestConfig, conn, first)
+	second := &mockDispatcher{}
+	secondBus := NewPostgresBus(dbTestConfig, conn, second)
+
+	wgFirst := asyncStartBusAndAssertNoError(t, firstBus)
+	wgSecond := asyncStartBusAndAssertNoError(t, secondBus)
+
+	event := events.FromMessage(persistence.Message{Id: uuid.New(), Room: uuid.New()})
+	err := firstBus.BroadcastEvent(event)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	// Stopping the buses waits for the notifications to be delivered.
+	err = firstBus.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	err = secondBus.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wgFirst.Wait()
+	wgSecond.Wait()
+
+	for _, local := range []*mockDispatcher{first, second} {
+		received := local.receivedEvents()
+		assert.Len(t, received, 1)
+		assert.Equal(t, event.Id, received[0].Id)
+		assert.Equal(t, event.Room, received[0].Room)
+	}
+}
+
+func TestIT_PostgresBus_WhenStopped_ExpectPendingNotificationsDelivered(t *testing.T) {
+	conn := newTestDbConnection(t)
+	defer conn.Close(context.Background())
+
+	local := &mockDispatcher{}
+	b := NewPostgresBus(dbTestConfig, conn, local)
+
+	wg := asyncStartBusAndAssertNoError(t, b)
+
+	for range 10 {
+		event := events.FromMessage(persistence.Message{Id: uuid.New()})
+		err := b.BroadcastEvent(event)
+		assert.Nil(t, err, "Actual err: %v", err)
+	}
+
+	start := time.Now()
+	err := b.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Less(t, time.Since(start), flushTimeout)
+	assert.Len(t, local.receivedEvents(), 10)
+}
+
+func TestIT_PostgresBus_WhenEventIsTooLargeForNotification_ExpectReceived(t *testing.T) {
+	conn := newTestDbConnection(t)
+	defer conn.Close(context.Background())
+
+	local := &mockDispatcher{}
+	b := NewPostgresBus(dbTestConfig, conn, local)
+
+	wg := asyncStartBusAndAssertNoError(t, b)
+
+	msg := persistence.Message{
+		Id:      uuid.New(),
+		Room:    uuid.New(),
+		Message: strings.Repeat("a", maxPayloadSize),
+	}
+	err := b.Broadcast(msg)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	err = b.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	received := local.receivedEvents()
+	assert.Len(t, received, 1)
+	assert.Equal(t, msg.Id, received[0].Id)
+}
+
+func TestIT_PostgresBus_WhenReconnecting_ExpectMissedEnvelopesDelivered(t *testing.T) {
+	conn := newTestDbConnection(t)
+	defer conn.Close(context.Background())
+
+	local := &mockDispatcher{}
+	b := NewPostgresBus(dbTestConfig, conn, local).(*postgresBusImpl)
+
+	b.first, _ = db.QueryOne[int64](context.Background(), conn, getLastEnvelopeIdSqlTemplate)
+	b.last = b.first
+	event := events.FromMessage(persistence.Message{Id: uuid.New(), Room: uuid.New()})
+	err := b.BroadcastEvent(event)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	b.catchUp()
+
+	ids := make([]uuid.UUID, 0)
+	for _, received := range local.receivedEvents() {
+		ids = append(ids, received.Id)
+	}
+	assert.Contains(t, ids, event.Id)
+	assert.Contains(t, b.received, b.last)
+}
+
+const reserveEnvelopeIdSqlTemplate = `SELECT nextval(pg_get_serial_sequence('bus_event', 'id'))`
+const insertEnvelopeWithIdSqlTemplate = `INSERT INTO bus_event (id, payload) VALUES ($1, $2)`
+
+func TestIT_PostgresBus_WhenEnvelopeCommittedAfterNewerOne_ExpectCaughtUp(t *testing.T) {
+	conn := newTestDbConnection(t)
+	defer conn.Close(context.Background())
+
+	local := &mockDispatcher{}
+	b := NewPostgresBus(dbTestConfig, conn, local).(*postgresBusImpl)
+	b.first, _ = db.QueryOne[int64](context.Background(), conn, getLastEnvelopeIdSqlTemplate)
+	b.last = b.first
+
+	// The first envelope gets its id but is committed after the second
+	// one was received.
+	late, err := db.QueryOne[int64](context.Background(), conn, reserveEnvelopeIdSqlTemplate)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	early := events.FromMessage(persistence.Message{Id: uuid.New(), Room: uuid.New()})
+	err = b.BroadcastEvent(early)
+	assert.Nil(t, err, "Actual err: %v", err)
+	earlyId, err := db.QueryOne[int64](context.Background(), conn, getLastEnvelopeIdSqlTemplate)
+	assert.Nil(t, err, "Actual err: %v", err)
+	b.deliver(strconv.FormatInt(earlyId, 10))
+
+	lateEvent := events.FromMessage(persistence.Message{Id: uuid.New(), Room: uuid.New()})
+	payload := encodeTestEnvelope(t, lateEvent)
+	_, err = conn.Exec(context.Background(), insertEnvelopeWithIdSqlTemplate, late, string(payload))
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	b.catchUp()
+
+	received := local.receivedEvents()
+	assert.Len(t, received, 2)
+	assert.Equal(t, early.Id, received[0].Id)
+	assert.Equal(t, lateEvent.Id, received[1].Id)
+}
+
+const envelopeExistsSqlTemplate = `SELECT EXISTS (SELECT 1 FROM bus_event WHERE id = $1)`
+
+func TestIT_PostgresBus_WhenPublishingContinuously_ExpectExpiredEnvelopesDeleted(t *testing.T) {
+	conn := newTestDbConnection(t)
+	defer conn.Close(context.Background())
+
+	b := NewPostgresBus(dbTestConfig, conn, &mockDispatcher{}).(*postgresBusImpl)
+	b.cleanupInterval = 50 * time.Millisecond
+	b.retention = 200 * time.Millisecond
+
+	wg := asyncStartBusAndAssertNoError(t, b)
+
+	err := b.BroadcastEvent(events.FromMessage(persistence.Message{Id: uuid.New()}))
+	assert.Nil(t, err, "Actual err: %v", err)
+	oldest, err := db.QueryOne[int64](context.Background(), conn, getLastEnvelopeIdSqlTemplate)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	// The notifications never leave the bus idle for a full interval.
+	for range 50 {
+		err = b.BroadcastEvent(events.FromMessage(persistence.Message{Id: uuid.New()}))
+		assert.Nil(t, err, "Actual err: %v", err)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	err = b.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	exists, err := db.QueryOne[bool](context.Background(), conn, envelopeExistsSqlTemplate, oldest)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.False(t, exists)
+}
+
+func encodeTestEnvelope(t *testing.T, event events.Event) []byte {
+	out, err := newEventEnvelope(event)
+	assert.Nil(t, err, "Actual err: %v", err)
+	payload, err := out.encode()
+	assert.Nil(t, err, "Actual err: %v", err)
+	return payload
+}
+
+func newTestDbConnection(t *testing.T) db.Connection {
+	conn, err := db.New(context.Background(), dbTestConfig)
+	assert.Nil(t, err, "Actual err: %v", err)
+	return conn
+}
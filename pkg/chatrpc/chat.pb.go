@@ -0,0 +1,2159 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: chat.proto
+
+package chatrpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EmptyDtoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmptyDtoResponse) Reset() {
+	*x = EmptyDtoResponse{}
+	mi := &file_chat_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmptyDtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmptyDtoResponse) ProtoMessage() {}
+
+func (x *EmptyDtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmptyDtoResponse.ProtoReflect.Descriptor instead.
+func (*EmptyDtoResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{0}
+}
+
+type IdDtoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IdDtoRequest) Reset() {
+	*x = IdDtoRequest{}
+	mi := &file_chat_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IdDtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IdDtoRequest) ProtoMessage() {}
+
+func (x *IdDtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IdDtoRequest.ProtoReflect.Descriptor instead.
+func (*IdDtoRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IdDtoRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type NameDtoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NameDtoRequest) Reset() {
+	*x = NameDtoRequest{}
+	mi := &file_chat_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NameDtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NameDtoRequest) ProtoMessage() {}
+
+func (x *NameDtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NameDtoRequest.ProtoReflect.Descriptor instead.
+func (*NameDtoRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *NameDtoRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type UserIdDtoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	User          string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserIdDtoRequest) Reset() {
+	*x = UserIdDtoRequest{}
+	mi := &file_chat_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserIdDtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserIdDtoRequest) ProtoMessage() {}
+
+func (x *UserIdDtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserIdDtoRequest.ProtoReflect.Descriptor instead.
+func (*UserIdDtoRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UserIdDtoRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UserIdDtoRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type UserDtoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ApiUser       string                 `protobuf:"bytes,2,opt,name=api_user,json=apiUser,proto3" json:"api_user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserDtoRequest) Reset() {
+	*x = UserDtoRequest{}
+	mi := &file_chat_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserDtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserDtoRequest) ProtoMessage() {}
+
+func (x *UserDtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserDtoRequest.ProtoReflect.Descriptor instead.
+func (*UserDtoRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UserDtoRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UserDtoRequest) GetApiUser() string {
+	if x != nil {
+		return x.ApiUser
+	}
+	return ""
+}
+
+type UserDtoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ApiUser       string                 `protobuf:"bytes,3,opt,name=api_user,json=apiUser,proto3" json:"api_user,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserDtoResponse) Reset() {
+	*x = UserDtoResponse{}
+	mi := &file_chat_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserDtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserDtoResponse) ProtoMessage() {}
+
+func (x *UserDtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserDtoResponse.ProtoReflect.Descriptor instead.
+func (*UserDtoResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UserDtoResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UserDtoResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UserDtoResponse) GetApiUser() string {
+	if x != nil {
+		return x.ApiUser
+	}
+	return ""
+}
+
+func (x *UserDtoResponse) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type UsersDtoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*UserDtoResponse     `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UsersDtoResponse) Reset() {
+	*x = UsersDtoResponse{}
+	mi := &file_chat_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsersDtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsersDtoResponse) ProtoMessage() {}
+
+func (x *UsersDtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsersDtoResponse.ProtoReflect.Descriptor instead.
+func (*UsersDtoResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UsersDtoResponse) GetUsers() []*UserDtoResponse {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type RoomDtoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MessageTtl    *int32                 `protobuf:"varint,2,opt,name=message_ttl,json=messageTtl,proto3,oneof" json:"message_ttl,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomDtoRequest) Reset() {
+	*x = RoomDtoRequest{}
+	mi := &file_chat_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomDtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomDtoRequest) ProtoMessage() {}
+
+func (x *RoomDtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomDtoRequest.ProtoReflect.Descriptor instead.
+func (*RoomDtoRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RoomDtoRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RoomDtoRequest) GetMessageTtl() int32 {
+	if x != nil && x.MessageTtl != nil {
+		return *x.MessageTtl
+	}
+	return 0
+}
+
+type RoomDtoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	MessageTtl    *int32                 `protobuf:"varint,3,opt,name=message_ttl,json=messageTtl,proto3,oneof" json:"message_ttl,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomDtoResponse) Reset() {
+	*x = RoomDtoResponse{}
+	mi := &file_chat_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomDtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomDtoResponse) ProtoMessage() {}
+
+func (x *RoomDtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomDtoResponse.ProtoReflect.Descriptor instead.
+func (*RoomDtoResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RoomDtoResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RoomDtoResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RoomDtoResponse) GetMessageTtl() int32 {
+	if x != nil && x.MessageTtl != nil {
+		return *x.MessageTtl
+	}
+	return 0
+}
+
+func (x *RoomDtoResponse) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type RoomsDtoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rooms         []*RoomDtoResponse     `protobuf:"bytes,1,rep,name=rooms,proto3" json:"rooms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomsDtoResponse) Reset() {
+	*x = RoomsDtoResponse{}
+	mi := &file_chat_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomsDtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomsDtoResponse) ProtoMessage() {}
+
+func (x *RoomsDtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomsDtoResponse.ProtoReflect.Descriptor instead.
+func (*RoomsDtoResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RoomsDtoResponse) GetRooms() []*RoomDtoResponse {
+	if x != nil {
+		return x.Rooms
+	}
+	return nil
+}
+
+type RoomMessagesDtoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Room          string                 `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`
+	From          int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomMessagesDtoRequest) Reset() {
+	*x = RoomMessagesDtoRequest{}
+	mi := &file_chat_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomMessagesDtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomMessagesDtoRequest) ProtoMessage() {}
+
+func (x *RoomMessagesDtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomMessagesDtoRequest.ProtoReflect.Descriptor instead.
+func (*RoomMessagesDtoRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RoomMessagesDtoRequest) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+func (x *RoomMessagesDtoRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *RoomMessagesDtoRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+type RegistrationDtoRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Room      string                 `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`
+	User      string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Moderator bool                   `protobuf:"varint,3,opt,name=moderator,proto3" json:"moderator,omitempty"`
+	// caller is the user changing the moderators of the room, it is only
+	// used by SetModerator.
+	Caller        string `protobuf:"bytes,4,opt,name=caller,proto3" json:"caller,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegistrationDtoRequest) Reset() {
+	*x = RegistrationDtoRequest{}
+	mi := &file_chat_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegistrationDtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegistrationDtoRequest) ProtoMessage() {}
+
+func (x *RegistrationDtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegistrationDtoRequest.ProtoReflect.Descriptor instead.
+func (*RegistrationDtoRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RegistrationDtoRequest) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+func (x *RegistrationDtoRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *RegistrationDtoRequest) GetModerator() bool {
+	if x != nil {
+		return x.Moderator
+	}
+	return false
+}
+
+func (x *RegistrationDtoRequest) GetCaller() string {
+	if x != nil {
+		return x.Caller
+	}
+	return ""
+}
+
+type MessageDtoRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	User        string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Room        string                 `protobuf:"bytes,2,opt,name=room,proto3" json:"room,omitempty"`
+	Message     string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Attachments []string               `protobuf:"bytes,4,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	SendAt      *string                `protobuf:"bytes,5,opt,name=send_at,json=sendAt,proto3,oneof" json:"send_at,omitempty"`
+	// ttl is the lifetime of the message in seconds.
+	Ttl            *int32 `protobuf:"varint,6,opt,name=ttl,proto3,oneof" json:"ttl,omitempty"`
+	IdempotencyKey string `protobuf:"bytes,7,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MessageDtoRequest) Reset() {
+	*x = MessageDtoRequest{}
+	mi := &file_chat_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageDtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageDtoRequest) ProtoMessage() {}
+
+func (x *MessageDtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageDtoRequest.ProtoReflect.Descriptor instead.
+func (*MessageDtoRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *MessageDtoRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *MessageDtoRequest) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+func (x *MessageDtoRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *MessageDtoRequest) GetAttachments() []string {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+func (x *MessageDtoRequest) GetSendAt() string {
+	if x != nil && x.SendAt != nil {
+		return *x.SendAt
+	}
+	return ""
+}
+
+func (x *MessageDtoRequest) GetTtl() int32 {
+	if x != nil && x.Ttl != nil {
+		return *x.Ttl
+	}
+	return 0
+}
+
+func (x *MessageDtoRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type MessageDtoResponse struct {
+	state          protoimpl.MessageState   `protogen:"open.v1"`
+	Id             string                   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	User           string                   `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Room           string                   `protobuf:"bytes,3,opt,name=room,proto3" json:"room,omitempty"`
+	Message        string                   `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	Attachments    []*AttachmentDtoResponse `protobuf:"bytes,5,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	Sequence       int64                    `protobuf:"varint,6,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	CreatedAt      string                   `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt      *string                  `protobuf:"bytes,8,opt,name=expires_at,json=expiresAt,proto3,oneof" json:"expires_at,omitempty"`
+	IdempotencyKey string                   `protobuf:"bytes,9,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MessageDtoResponse) Reset() {
+	*x = MessageDtoResponse{}
+	mi := &file_chat_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageDtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageDtoResponse) ProtoMessage() {}
+
+func (x *MessageDtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageDtoResponse.ProtoReflect.Descriptor instead.
+func (*MessageDtoResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MessageDtoResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MessageDtoResponse) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *MessageDtoResponse) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+func (x *MessageDtoResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *MessageDtoResponse) GetAttachments() []*AttachmentDtoResponse {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+func (x *MessageDtoResponse) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *MessageDtoResponse) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *MessageDtoResponse) GetExpiresAt() string {
+	if x != nil && x.ExpiresAt != nil {
+		return *x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *MessageDtoResponse) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type MessagesDtoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*MessageDtoResponse  `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessagesDtoResponse) Reset() {
+	*x = MessagesDtoResponse{}
+	mi := &file_chat_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessagesDtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessagesDtoResponse) ProtoMessage() {}
+
+func (x *MessagesDtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessagesDtoResponse.ProtoReflect.Descriptor instead.
+func (*MessagesDtoResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MessagesDtoResponse) GetMessages() []*MessageDtoResponse {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type MessageStatusDtoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Room          string                 `protobuf:"bytes,2,opt,name=room,proto3" json:"room,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Reason        *string                `protobuf:"bytes,4,opt,name=reason,proto3,oneof" json:"reason,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageStatusDtoResponse) Reset() {
+	*x = MessageStatusDtoResponse{}
+	mi := &file_chat_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageStatusDtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageStatusDtoResponse) ProtoMessage() {}
+
+func (x *MessageStatusDtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageStatusDtoResponse.ProtoReflect.Descriptor instead.
+func (*MessageStatusDtoResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *MessageStatusDtoResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MessageStatusDtoResponse) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+func (x *MessageStatusDtoResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *MessageStatusDtoResponse) GetReason() string {
+	if x != nil && x.Reason != nil {
+		return *x.Reason
+	}
+	return ""
+}
+
+func (x *MessageStatusDtoResponse) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *MessageStatusDtoResponse) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type TypingDtoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Room          string                 `protobuf:"bytes,2,opt,name=room,proto3" json:"room,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TypingDtoRequest) Reset() {
+	*x = TypingDtoRequest{}
+	mi := &file_chat_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TypingDtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypingDtoRequest) ProtoMessage() {}
+
+func (x *TypingDtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypingDtoRequest.ProtoReflect.Descriptor instead.
+func (*TypingDtoRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *TypingDtoRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *TypingDtoRequest) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+type ReadMarkerDtoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Room          string                 `protobuf:"bytes,2,opt,name=room,proto3" json:"room,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadMarkerDtoRequest) Reset() {
+	*x = ReadMarkerDtoRequest{}
+	mi := &file_chat_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadMarkerDtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadMarkerDtoRequest) ProtoMessage() {}
+
+func (x *ReadMarkerDtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadMarkerDtoRequest.ProtoReflect.Descriptor instead.
+func (*ReadMarkerDtoRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ReadMarkerDtoRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ReadMarkerDtoRequest) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+func (x *ReadMarkerDtoRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ScheduledMessageDtoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	User          string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Room          string                 `protobuf:"bytes,3,opt,name=room,proto3" json:"room,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	SendAt        string                 `protobuf:"bytes,5,opt,name=send_at,json=sendAt,proto3" json:"send_at,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledMessageDtoResponse) Reset() {
+	*x = ScheduledMessageDtoResponse{}
+	mi := &file_chat_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledMessageDtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledMessageDtoResponse) ProtoMessage() {}
+
+func (x *ScheduledMessageDtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledMessageDtoResponse.ProtoReflect.Descriptor instead.
+func (*ScheduledMessageDtoResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ScheduledMessageDtoResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ScheduledMessageDtoResponse) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ScheduledMessageDtoResponse) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+func (x *ScheduledMessageDtoResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ScheduledMessageDtoResponse) GetSendAt() string {
+	if x != nil {
+		return x.SendAt
+	}
+	return ""
+}
+
+func (x *ScheduledMessageDtoResponse) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *ScheduledMessageDtoResponse) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type ScheduledMessagesDtoResponse struct {
+	state         protoimpl.MessageState         `protogen:"open.v1"`
+	Messages      []*ScheduledMessageDtoResponse `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledMessagesDtoResponse) Reset() {
+	*x = ScheduledMessagesDtoResponse{}
+	mi := &file_chat_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledMessagesDtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledMessagesDtoResponse) ProtoMessage() {}
+
+func (x *ScheduledMessagesDtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledMessagesDtoResponse.ProtoReflect.Descriptor instead.
+func (*ScheduledMessagesDtoResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ScheduledMessagesDtoResponse) GetMessages() []*ScheduledMessageDtoResponse {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type ScheduledMessageUpdateDtoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	User          string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Message       *string                `protobuf:"bytes,3,opt,name=message,proto3,oneof" json:"message,omitempty"`
+	SendAt        *string                `protobuf:"bytes,4,opt,name=send_at,json=sendAt,proto3,oneof" json:"send_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledMessageUpdateDtoRequest) Reset() {
+	*x = ScheduledMessageUpdateDtoRequest{}
+	mi := &file_chat_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledMessageUpdateDtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledMessageUpdateDtoRequest) ProtoMessage() {}
+
+func (x *ScheduledMessageUpdateDtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledMessageUpdateDtoRequest.ProtoReflect.Descriptor instead.
+func (*ScheduledMessageUpdateDtoRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ScheduledMessageUpdateDtoRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ScheduledMessageUpdateDtoRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ScheduledMessageUpdateDtoRequest) GetMessage() string {
+	if x != nil && x.Message != nil {
+		return *x.Message
+	}
+	return ""
+}
+
+func (x *ScheduledMessageUpdateDtoRequest) GetSendAt() string {
+	if x != nil && x.SendAt != nil {
+		return *x.SendAt
+	}
+	return ""
+}
+
+type PinDtoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Room          string                 `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	User          string                 `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PinDtoRequest) Reset() {
+	*x = PinDtoRequest{}
+	mi := &file_chat_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinDtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinDtoRequest) ProtoMessage() {}
+
+func (x *PinDtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinDtoRequest.ProtoReflect.Descriptor instead.
+func (*PinDtoRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PinDtoRequest) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+func (x *PinDtoRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *PinDtoRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type PinDtoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Room          string                 `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`
+	Message       *MessageDtoResponse    `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	PinnedBy      string                 `protobuf:"bytes,3,opt,name=pinned_by,json=pinnedBy,proto3" json:"pinned_by,omitempty"`
+	PinnedAt      string                 `protobuf:"bytes,4,opt,name=pinned_at,json=pinnedAt,proto3" json:"pinned_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PinDtoResponse) Reset() {
+	*x = PinDtoResponse{}
+	mi := &file_chat_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinDtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinDtoResponse) ProtoMessage() {}
+
+func (x *PinDtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinDtoResponse.ProtoReflect.Descriptor instead.
+func (*PinDtoResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PinDtoResponse) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+func (x *PinDtoResponse) GetMessage() *MessageDtoResponse {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *PinDtoResponse) GetPinnedBy() string {
+	if x != nil {
+		return x.PinnedBy
+	}
+	return ""
+}
+
+func (x *PinDtoResponse) GetPinnedAt() string {
+	if x != nil {
+		return x.PinnedAt
+	}
+	return ""
+}
+
+type PinsDtoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pins          []*PinDtoResponse      `protobuf:"bytes,1,rep,name=pins,proto3" json:"pins,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PinsDtoResponse) Reset() {
+	*x = PinsDtoResponse{}
+	mi := &file_chat_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinsDtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinsDtoResponse) ProtoMessage() {}
+
+func (x *PinsDtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinsDtoResponse.ProtoReflect.Descriptor instead.
+func (*PinsDtoResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *PinsDtoResponse) GetPins() []*PinDtoResponse {
+	if x != nil {
+		return x.Pins
+	}
+	return nil
+}
+
+type AttachmentUploadDtoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Room          string                 `protobuf:"bytes,2,opt,name=room,proto3" json:"room,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Data          []byte                 `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachmentUploadDtoRequest) Reset() {
+	*x = AttachmentUploadDtoRequest{}
+	mi := &file_chat_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachmentUploadDtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachmentUploadDtoRequest) ProtoMessage() {}
+
+func (x *AttachmentUploadDtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachmentUploadDtoRequest.ProtoReflect.Descriptor instead.
+func (*AttachmentUploadDtoRequest) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *AttachmentUploadDtoRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *AttachmentUploadDtoRequest) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+func (x *AttachmentUploadDtoRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AttachmentUploadDtoRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type AttachmentDtoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	User          string                 `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Room          string                 `protobuf:"bytes,3,opt,name=room,proto3" json:"room,omitempty"`
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	MimeType      string                 `protobuf:"bytes,5,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	Size          int64                  `protobuf:"varint,6,opt,name=size,proto3" json:"size,omitempty"`
+	Width         *int32                 `protobuf:"varint,7,opt,name=width,proto3,oneof" json:"width,omitempty"`
+	Height        *int32                 `protobuf:"varint,8,opt,name=height,proto3,oneof" json:"height,omitempty"`
+	HasThumbnail  bool                   `protobuf:"varint,9,opt,name=has_thumbnail,json=hasThumbnail,proto3" json:"has_thumbnail,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachmentDtoResponse) Reset() {
+	*x = AttachmentDtoResponse{}
+	mi := &file_chat_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachmentDtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachmentDtoResponse) ProtoMessage() {}
+
+func (x *AttachmentDtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachmentDtoResponse.ProtoReflect.Descriptor instead.
+func (*AttachmentDtoResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *AttachmentDtoResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AttachmentDtoResponse) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *AttachmentDtoResponse) GetRoom() string {
+	if x != nil {
+		return x.Room
+	}
+	return ""
+}
+
+func (x *AttachmentDtoResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AttachmentDtoResponse) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *AttachmentDtoResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *AttachmentDtoResponse) GetWidth() int32 {
+	if x != nil && x.Width != nil {
+		return *x.Width
+	}
+	return 0
+}
+
+func (x *AttachmentDtoResponse) GetHeight() int32 {
+	if x != nil && x.Height != nil {
+		return *x.Height
+	}
+	return 0
+}
+
+func (x *AttachmentDtoResponse) GetHasThumbnail() bool {
+	if x != nil {
+		return x.HasThumbnail
+	}
+	return false
+}
+
+func (x *AttachmentDtoResponse) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type AttachmentContentDtoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attachment    *AttachmentDtoResponse `protobuf:"bytes,1,opt,name=attachment,proto3" json:"attachment,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachmentContentDtoResponse) Reset() {
+	*x = AttachmentContentDtoResponse{}
+	mi := &file_chat_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachmentContentDtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachmentContentDtoResponse) ProtoMessage() {}
+
+func (x *AttachmentContentDtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachmentContentDtoResponse.ProtoReflect.Descriptor instead.
+func (*AttachmentContentDtoResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *AttachmentContentDtoResponse) GetAttachment() *AttachmentDtoResponse {
+	if x != nil {
+		return x.Attachment
+	}
+	return nil
+}
+
+func (x *AttachmentContentDtoResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ThumbnailDtoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThumbnailDtoResponse) Reset() {
+	*x = ThumbnailDtoResponse{}
+	mi := &file_chat_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThumbnailDtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThumbnailDtoResponse) ProtoMessage() {}
+
+func (x *ThumbnailDtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThumbnailDtoResponse.ProtoReflect.Descriptor instead.
+func (*ThumbnailDtoResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ThumbnailDtoResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type EventDtoResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type  string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// data depends on the type of the event, see the SSE events of the
+	// REST API.
+	Data          *structpb.Value `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventDtoResponse) Reset() {
+	*x = EventDtoResponse{}
+	mi := &file_chat_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventDtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventDtoResponse) ProtoMessage() {}
+
+func (x *EventDtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventDtoResponse.ProtoReflect.Descriptor instead.
+func (*EventDtoResponse) Descriptor() ([]byte, []int) {
+	return file_chat_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *EventDtoResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *EventDtoResponse) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *EventDtoResponse) GetData() *structpb.Value {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+var File_chat_proto protoreflect.FileDescriptor
+
+const file_chat_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"chat.proto\x12\x04chat\x1a\x1cgoogle/protobuf/struct.proto\"\x12\n" +
+	"\x10EmptyDtoResponse\"\x1e\n" +
+	"\fIdDtoRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"$\n" +
+	"\x0eNameDtoRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"6\n" +
+	"\x10UserIdDtoRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\"?\n" +
+	"\x0eUserDtoRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x19\n" +
+	"\bapi_user\x18\x02 \x01(\tR\aapiUser\"o\n" +
+	"\x0fUserDtoResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x19\n" +
+	"\bapi_user\x18\x03 \x01(\tR\aapiUser\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\"?\n" +
+	"\x10UsersDtoResponse\x12+\n" +
+	"\x05users\x18\x01 \x03(\v2\x15.chat.UserDtoResponseR\x05users\"Z\n" +
+	"\x0eRoomDtoRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12$\n" +
+	"\vmessage_ttl\x18\x02 \x01(\x05H\x00R\n" +
+	"messageTtl\x88\x01\x01B\x0e\n" +
+	"\f_message_ttl\"\x8a\x01\n" +
+	"\x0fRoomDtoResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12$\n" +
+	"\vmessage_ttl\x18\x03 \x01(\x05H\x00R\n" +
+	"messageTtl\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAtB\x0e\n" +
+	"\f_message_ttl\"?\n" +
+	"\x10RoomsDtoResponse\x12+\n" +
+	"\x05rooms\x18\x01 \x03(\v2\x15.chat.RoomDtoResponseR\x05rooms\"P\n" +
+	"\x16RoomMessagesDtoRequest\x12\x12\n" +
+	"\x04room\x18\x01 \x01(\tR\x04room\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\"v\n" +
+	"\x16RegistrationDtoRequest\x12\x12\n" +
+	"\x04room\x18\x01 \x01(\tR\x04room\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x1c\n" +
+	"\tmoderator\x18\x03 \x01(\bR\tmoderator\x12\x16\n" +
+	"\x06caller\x18\x04 \x01(\tR\x06caller\"\xe9\x01\n" +
+	"\x11MessageDtoRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12\x12\n" +
+	"\x04room\x18\x02 \x01(\tR\x04room\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12 \n" +
+	"\vattachments\x18\x04 \x03(\tR\vattachments\x12\x1c\n" +
+	"\asend_at\x18\x05 \x01(\tH\x00R\x06sendAt\x88\x01\x01\x12\x15\n" +
+	"\x03ttl\x18\x06 \x01(\x05H\x01R\x03ttl\x88\x01\x01\x12'\n" +
+	"\x0fidempotency_key\x18\a \x01(\tR\x0eidempotencyKeyB\n" +
+	"\n" +
+	"\b_send_atB\x06\n" +
+	"\x04_ttl\"\xbc\x02\n" +
+	"\x12MessageDtoResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x12\n" +
+	"\x04room\x18\x03 \x01(\tR\x04room\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12=\n" +
+	"\vattachments\x18\x05 \x03(\v2\x1b.chat.AttachmentDtoResponseR\vattachments\x12\x1a\n" +
+	"\bsequence\x18\x06 \x01(\x03R\bsequence\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\x12\"\n" +
+	"\n" +
+	"expires_at\x18\b \x01(\tH\x00R\texpiresAt\x88\x01\x01\x12'\n" +
+	"\x0fidempotency_key\x18\t \x01(\tR\x0eidempotencyKeyB\r\n" +
+	"\v_expires_at\"K\n" +
+	"\x13MessagesDtoResponse\x124\n" +
+	"\bmessages\x18\x01 \x03(\v2\x18.chat.MessageDtoResponseR\bmessages\"\xbc\x01\n" +
+	"\x18MessageStatusDtoResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04room\x18\x02 \x01(\tR\x04room\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1b\n" +
+	"\x06reason\x18\x04 \x01(\tH\x00R\x06reason\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\tR\tupdatedAtB\t\n" +
+	"\a_reason\":\n" +
+	"\x10TypingDtoRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12\x12\n" +
+	"\x04room\x18\x02 \x01(\tR\x04room\"X\n" +
+	"\x14ReadMarkerDtoRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12\x12\n" +
+	"\x04room\x18\x02 \x01(\tR\x04room\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xc6\x01\n" +
+	"\x1bScheduledMessageDtoResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x12\n" +
+	"\x04room\x18\x03 \x01(\tR\x04room\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12\x17\n" +
+	"\asend_at\x18\x05 \x01(\tR\x06sendAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\tR\tupdatedAt\"]\n" +
+	"\x1cScheduledMessagesDtoResponse\x12=\n" +
+	"\bmessages\x18\x01 \x03(\v2!.chat.ScheduledMessageDtoResponseR\bmessages\"\x9b\x01\n" +
+	" ScheduledMessageUpdateDtoRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x1d\n" +
+	"\amessage\x18\x03 \x01(\tH\x00R\amessage\x88\x01\x01\x12\x1c\n" +
+	"\asend_at\x18\x04 \x01(\tH\x01R\x06sendAt\x88\x01\x01B\n" +
+	"\n" +
+	"\b_messageB\n" +
+	"\n" +
+	"\b_send_at\"Q\n" +
+	"\rPinDtoRequest\x12\x12\n" +
+	"\x04room\x18\x01 \x01(\tR\x04room\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x12\n" +
+	"\x04user\x18\x03 \x01(\tR\x04user\"\x92\x01\n" +
+	"\x0ePinDtoResponse\x12\x12\n" +
+	"\x04room\x18\x01 \x01(\tR\x04room\x122\n" +
+	"\amessage\x18\x02 \x01(\v2\x18.chat.MessageDtoResponseR\amessage\x12\x1b\n" +
+	"\tpinned_by\x18\x03 \x01(\tR\bpinnedBy\x12\x1b\n" +
+	"\tpinned_at\x18\x04 \x01(\tR\bpinnedAt\";\n" +
+	"\x0fPinsDtoResponse\x12(\n" +
+	"\x04pins\x18\x01 \x03(\v2\x14.chat.PinDtoResponseR\x04pins\"l\n" +
+	"\x1aAttachmentUploadDtoRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12\x12\n" +
+	"\x04room\x18\x02 \x01(\tR\x04room\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\fR\x04data\"\xa5\x02\n" +
+	"\x15AttachmentDtoResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04user\x18\x02 \x01(\tR\x04user\x12\x12\n" +
+	"\x04room\x18\x03 \x01(\tR\x04room\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x1b\n" +
+	"\tmime_type\x18\x05 \x01(\tR\bmimeType\x12\x12\n" +
+	"\x04size\x18\x06 \x01(\x03R\x04size\x12\x19\n" +
+	"\x05width\x18\a \x01(\x05H\x00R\x05width\x88\x01\x01\x12\x1b\n" +
+	"\x06height\x18\b \x01(\x05H\x01R\x06height\x88\x01\x01\x12#\n" +
+	"\rhas_thumbnail\x18\t \x01(\bR\fhasThumbnail\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\tR\tcreatedAtB\b\n" +
+	"\x06_widthB\t\n" +
+	"\a_height\"o\n" +
+	"\x1cAttachmentContentDtoResponse\x12;\n" +
+	"\n" +
+	"attachment\x18\x01 \x01(\v2\x1b.chat.AttachmentDtoResponseR\n" +
+	"attachment\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"*\n" +
+	"\x14ThumbnailDtoResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"b\n" +
+	"\x10EventDtoResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12*\n" +
+	"\x04data\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\x04data2\xcd\x0f\n" +
+	"\vChatService\x129\n" +
+	"\n" +
+	"CreateUser\x12\x14.chat.UserDtoRequest\x1a\x15.chat.UserDtoResponse\x124\n" +
+	"\aGetUser\x12\x12.chat.IdDtoRequest\x1a\x15.chat.UserDtoResponse\x12<\n" +
+	"\rGetUserByName\x12\x14.chat.NameDtoRequest\x1a\x15.chat.UserDtoResponse\x12>\n" +
+	"\x10ListRoomsForUser\x12\x12.chat.IdDtoRequest\x1a\x16.chat.RoomsDtoResponse\x128\n" +
+	"\n" +
+	"DeleteUser\x12\x12.chat.IdDtoRequest\x1a\x16.chat.EmptyDtoResponse\x129\n" +
+	"\n" +
+	"CreateRoom\x12\x14.chat.RoomDtoRequest\x1a\x15.chat.RoomDtoResponse\x124\n" +
+	"\aGetRoom\x12\x12.chat.IdDtoRequest\x1a\x15.chat.RoomDtoResponse\x12;\n" +
+	"\tListRooms\x12\x16.chat.EmptyDtoResponse\x1a\x16.chat.RoomsDtoResponse\x12>\n" +
+	"\x10ListUsersForRoom\x12\x12.chat.IdDtoRequest\x1a\x16.chat.UsersDtoResponse\x12N\n" +
+	"\x13ListMessagesForRoom\x12\x1c.chat.RoomMessagesDtoRequest\x1a\x19.chat.MessagesDtoResponse\x128\n" +
+	"\n" +
+	"DeleteRoom\x12\x12.chat.IdDtoRequest\x1a\x16.chat.EmptyDtoResponse\x12J\n" +
+	"\x12RegisterUserInRoom\x12\x1c.chat.RegistrationDtoRequest\x1a\x16.chat.EmptyDtoResponse\x12L\n" +
+	"\x14UnregisterUserInRoom\x12\x1c.chat.RegistrationDtoRequest\x1a\x16.chat.EmptyDtoResponse\x12D\n" +
+	"\fSetModerator\x12\x1c.chat.RegistrationDtoRequest\x1a\x16.chat.EmptyDtoResponse\x12F\n" +
+	"\vPostMessage\x12\x17.chat.MessageDtoRequest\x1a\x1e.chat.MessageStatusDtoResponse\x12M\n" +
+	"\x0fScheduleMessage\x12\x17.chat.MessageDtoRequest\x1a!.chat.ScheduledMessageDtoResponse\x12F\n" +
+	"\x10GetMessageStatus\x12\x12.chat.IdDtoRequest\x1a\x1e.chat.MessageStatusDtoResponse\x128\n" +
+	"\x06Typing\x12\x16.chat.TypingDtoRequest\x1a\x16.chat.EmptyDtoResponse\x12@\n" +
+	"\n" +
+	"MarkAsRead\x12\x1a.chat.ReadMarkerDtoRequest\x1a\x16.chat.EmptyDtoResponse\x12V\n" +
+	"\x1cListScheduledMessagesForRoom\x12\x12.chat.IdDtoRequest\x1a\".chat.ScheduledMessagesDtoResponse\x12c\n" +
+	"\x16UpdateScheduledMessage\x12&.chat.ScheduledMessageUpdateDtoRequest\x1a!.chat.ScheduledMessageDtoResponse\x12H\n" +
+	"\x16CancelScheduledMessage\x12\x16.chat.UserIdDtoRequest\x1a\x16.chat.EmptyDtoResponse\x127\n" +
+	"\n" +
+	"PinMessage\x12\x13.chat.PinDtoRequest\x1a\x14.chat.PinDtoResponse\x12;\n" +
+	"\fUnpinMessage\x12\x13.chat.PinDtoRequest\x1a\x16.chat.EmptyDtoResponse\x12<\n" +
+	"\x0fListPinsForRoom\x12\x12.chat.IdDtoRequest\x1a\x15.chat.PinsDtoResponse\x12Q\n" +
+	"\x10UploadAttachment\x12 .chat.AttachmentUploadDtoRequest\x1a\x1b.chat.AttachmentDtoResponse\x12P\n" +
+	"\x12DownloadAttachment\x12\x16.chat.UserIdDtoRequest\x1a\".chat.AttachmentContentDtoResponse\x12G\n" +
+	"\x11DownloadThumbnail\x12\x16.chat.UserIdDtoRequest\x1a\x1a.chat.ThumbnailDtoResponse\x129\n" +
+	"\tSubscribe\x12\x12.chat.IdDtoRequest\x1a\x16.chat.EventDtoResponse0\x01B3Z1github.com/Knoblauchpilze/chat-server/pkg/chatrpcb\x06proto3"
+
+var (
+	file_chat_proto_rawDescOnce sync.Once
+	file_chat_proto_rawDescData []byte
+)
+
+func file_chat_proto_rawDescGZIP() []byte {
+	file_chat_proto_rawDescOnce.Do(func() {
+		file_chat_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_chat_proto_rawDesc), len(file_chat_proto_rawDesc)))
+	})
+	return file_chat_proto_rawDescData
+}
+
+var file_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
+var file_chat_proto_goTypes = []any{
+	(*EmptyDtoResponse)(nil),                 // 0: chat.EmptyDtoResponse
+	(*IdDtoRequest)(nil),                     // 1: chat.IdDtoRequest
+	(*NameDtoRequest)(nil),                   // 2: chat.NameDtoRequest
+	(*UserIdDtoRequest)(nil),                 // 3: chat.UserIdDtoRequest
+	(*UserDtoRequest)(nil),                   // 4: chat.UserDtoRequest
+	(*UserDtoResponse)(nil),                  // 5: chat.UserDtoResponse
+	(*UsersDtoResponse)(nil),                 // 6: chat.UsersDtoResponse
+	(*RoomDtoRequest)(nil),                   // 7: chat.RoomDtoRequest
+	(*RoomDtoResponse)(nil),                  // 8: chat.RoomDtoResponse
+	(*RoomsDtoResponse)(nil),                 // 9: chat.RoomsDtoResponse
+	(*RoomMessagesDtoRequest)(nil),           // 10: chat.RoomMessagesDtoRequest
+	(*RegistrationDtoRequest)(nil),           // 11: chat.RegistrationDtoRequest
+	(*MessageDtoRequest)(nil),                // 12: chat.MessageDtoRequest
+	(*MessageDtoResponse)(nil),               // 13: chat.MessageDtoResponse
+	(*MessagesDtoResponse)(nil),              // 14: chat.MessagesDtoResponse
+	(*MessageStatusDtoResponse)(nil),         // 15: chat.MessageStatusDtoResponse
+	(*TypingDtoRequest)(nil),                 // 16: chat.TypingDtoRequest
+	(*ReadMarkerDtoRequest)(nil),             // 17: chat.ReadMarkerDtoRequest
+	(*ScheduledMessageDtoResponse)(nil),      // 18: chat.ScheduledMessageDtoResponse
+	(*ScheduledMessagesDtoResponse)(nil),     // 19: chat.ScheduledMessagesDtoResponse
+	(*ScheduledMessageUpdateDtoRequest)(nil), // 20: chat.ScheduledMessageUpdateDtoRequest
+	(*PinDtoRequest)(nil),                    // 21: chat.PinDtoRequest
+	(*PinDtoResponse)(nil),                   // 22: chat.PinDtoResponse
+	(*PinsDtoResponse)(nil),                  // 23: chat.PinsDtoResponse
+	(*AttachmentUploadDtoRequest)(nil),       // 24: chat.AttachmentUploadDtoRequest
+	(*AttachmentDtoResponse)(nil),            // 25: chat.AttachmentDtoResponse
+	(*AttachmentContentDtoResponse)(nil),     // 26: chat.AttachmentContentDtoResponse
+	(*ThumbnailDtoResponse)(nil),             // 27: chat.ThumbnailDtoResponse
+	(*EventDtoResponse)(nil),                 // 28: chat.EventDtoResponse
+	(*structpb.Value)(nil),                   // 29: google.protobuf.Value
+}
+var file_chat_proto_depIdxs = []int32{
+	5,  // 0: chat.UsersDtoResponse.users:type_name -> chat.UserDtoResponse
+	8,  // 1: chat.RoomsDtoResponse.rooms:type_name -> chat.RoomDtoResponse
+	25, // 2: chat.MessageDtoResponse.attachments:type_name -> chat.AttachmentDtoResponse
+	13, // 3: chat.MessagesDtoResponse.messages:type_name -> chat.MessageDtoResponse
+	18, // 4: chat.ScheduledMessagesDtoResponse.messages:type_name -> chat.ScheduledMessageDtoResponse
+	13, // 5: chat.PinDtoResponse.message:type_name -> chat.MessageDtoResponse
+	22, // 6: chat.PinsDtoResponse.pins:type_name -> chat.PinDtoResponse
+	25, // 7: chat.AttachmentContentDtoResponse.attachment:type_name -> chat.AttachmentDtoResponse
+	29, // 8: chat.EventDtoResponse.data:type_name -> google.protobuf.Value
+	4,  // 9: chat.ChatService.CreateUser:input_type -> chat.UserDtoRequest
+	1,  // 10: chat.ChatService.GetUser:input_type -> chat.IdDtoRequest
+	2,  // 11: chat.ChatService.GetUserByName:input_type -> chat.NameDtoRequest
+	1,  // 12: chat.ChatService.ListRoomsForUser:input_type -> chat.IdDtoRequest
+	1,  // 13: chat.ChatService.DeleteUser:input_type -> chat.IdDtoRequest
+	7,  // 14: chat.ChatService.CreateRoom:input_type -> chat.RoomDtoRequest
+	1,  // 15: chat.ChatService.GetRoom:input_type -> chat.IdDtoRequest
+	0,  // 16: chat.ChatService.ListRooms:input_type -> chat.EmptyDtoResponse
+	1,  // 17: chat.ChatService.ListUsersForRoom:input_type -> chat.IdDtoRequest
+	10, // 18: chat.ChatService.ListMessagesForRoom:input_type -> chat.RoomMessagesDtoRequest
+	1,  // 19: chat.ChatService.DeleteRoom:input_type -> chat.IdDtoRequest
+	11, // 20: chat.ChatService.RegisterUserInRoom:input_type -> chat.RegistrationDtoRequest
+	11, // 21: chat.ChatService.UnregisterUserInRoom:input_type -> chat.RegistrationDtoRequest
+	11, // 22: chat.ChatService.SetModerator:input_type -> chat.RegistrationDtoRequest
+	12, // 23: chat.ChatService.PostMessage:input_type -> chat.MessageDtoRequest
+	12, // 24: chat.ChatService.ScheduleMessage:input_type -> chat.MessageDtoRequest
+	1,  // 25: chat.ChatService.GetMessageStatus:input_type -> chat.IdDtoRequest
+	16, // 26: chat.ChatService.Typing:input_type -> chat.TypingDtoRequest
+	17, // 27: chat.ChatService.MarkAsRead:input_type -> chat.ReadMarkerDtoRequest
+	1,  // 28: chat.ChatService.ListScheduledMessagesForRoom:input_type -> chat.IdDtoRequest
+	20, // 29: chat.ChatService.UpdateScheduledMessage:input_type -> chat.ScheduledMessageUpdateDtoRequest
+	3,  // 30: chat.ChatService.CancelScheduledMessage:input_type -> chat.UserIdDtoRequest
+	21, // 31: chat.ChatService.PinMessage:input_type -> chat.PinDtoRequest
+	21, // 32: chat.ChatService.UnpinMessage:input_type -> chat.PinDtoRequest
+	1,  // 33: chat.ChatService.ListPinsForRoom:input_type -> chat.IdDtoRequest
+	24, // 34: chat.ChatService.UploadAttachment:input_type -> chat.AttachmentUploadDtoRequest
+	3,  // 35: chat.ChatService.DownloadAttachment:input_type -> chat.UserIdDtoRequest
+	3,  // 36: chat.ChatService.DownloadThumbnail:input_type -> chat.UserIdDtoRequest
+	1,  // 37: chat.ChatService.Subscribe:input_type -> chat.IdDtoRequest
+	5,  // 38: chat.ChatService.CreateUser:output_type -> chat.UserDtoResponse
+	5,  // 39: chat.ChatService.GetUser:output_type -> chat.UserDtoResponse
+	5,  // 40: chat.ChatService.GetUserByName:output_type -> chat.UserDtoResponse
+	9,  // 41: chat.ChatService.ListRoomsForUser:output_type -> chat.RoomsDtoResponse
+	0,  // 42: chat.ChatService.DeleteUser:output_type -> chat.EmptyDtoResponse
+	8,  // 43: chat.ChatService.CreateRoom:output_type -> chat.RoomDtoResponse
+	8,  // 44: chat.ChatService.GetRoom:output_type -> chat.RoomDtoResponse
+	9,  // 45: chat.ChatService.ListRooms:output_type -> chat.RoomsDtoResponse
+	6,  // 46: chat.ChatService.ListUsersForRoom:output_type -> chat.UsersDtoResponse
+	14, // 47: chat.ChatService.ListMessagesForRoom:output_type -> chat.MessagesDtoResponse
+	0,  // 48: chat.ChatService.DeleteRoom:output_type -> chat.EmptyDtoResponse
+	0,  // 49: chat.ChatService.RegisterUserInRoom:output_type -> chat.EmptyDtoResponse
+	0,  // 50: chat.ChatService.UnregisterUserInRoom:output_type -> chat.EmptyDtoResponse
+	0,  // 51: chat.ChatService.SetModerator:output_type -> chat.EmptyDtoResponse
+	15, // 52: chat.ChatService.PostMessage:output_type -> chat.MessageStatusDtoResponse
+	18, // 53: chat.ChatService.ScheduleMessage:output_type -> chat.ScheduledMessageDtoResponse
+	15, // 54: chat.ChatService.GetMessageStatus:output_type -> chat.MessageStatusDtoResponse
+	0,  // 55: chat.ChatService.Typing:output_type -> chat.EmptyDtoResponse
+	0,  // 56: chat.ChatService.MarkAsRead:output_type -> chat.EmptyDtoResponse
+	19, // 57: chat.ChatService.ListScheduledMessagesForRoom:output_type -> chat.ScheduledMessagesDtoResponse
+	18, // 58: chat.ChatService.UpdateScheduledMessage:output_type -> chat.ScheduledMessageDtoResponse
+	0,  // 59: chat.ChatService.CancelScheduledMessage:output_type -> chat.EmptyDtoResponse
+	22, // 60: chat.ChatService.PinMessage:output_type -> chat.PinDtoResponse
+	0,  // 61: chat.ChatService.UnpinMessage:output_type -> chat.EmptyDtoResponse
+	23, // 62: chat.ChatService.ListPinsForRoom:output_type -> chat.PinsDtoResponse
+	25, // 63: chat.ChatService.UploadAttachment:output_type -> chat.AttachmentDtoResponse
+	26, // 64: chat.ChatService.DownloadAttachment:output_type -> chat.AttachmentContentDtoResponse
+	27, // 65: chat.ChatService.DownloadThumbnail:output_type -> chat.ThumbnailDtoResponse
+	28, // 66: chat.ChatService.Subscribe:output_type -> chat.EventDtoResponse
+	38, // [38:67] is the sub-list for method output_type
+	9,  // [9:38] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_chat_proto_init() }
+func file_chat_proto_init() {
+	if File_chat_proto != nil {
+		return
+	}
+	file_chat_proto_msgTypes[7].OneofWrappers = []any{}
+	file_chat_proto_msgTypes[8].OneofWrappers = []any{}
+	file_chat_proto_msgTypes[12].OneofWrappers = []any{}
+	file_chat_proto_msgTypes[13].OneofWrappers = []any{}
+	file_chat_proto_msgTypes[15].OneofWrappers = []any{}
+	file_chat_proto_msgTypes[20].OneofWrappers = []any{}
+	file_chat_proto_msgTypes[25].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_chat_proto_rawDesc), len(file_chat_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   29,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_chat_proto_goTypes,
+		DependencyIndexes: file_chat_proto_depIdxs,
+		MessageInfos:      file_chat_proto_msgTypes,
+	}.Build()
+	File_chat_proto = out.File
+	file_chat_proto_goTypes = nil
+	file_chat_proto_depIdxs = nil
+}
@@ -0,0 +1,1205 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: chat.proto
+
+package chatrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ChatService_CreateUser_FullMethodName                   = "/chat.ChatService/CreateUser"
+	ChatService_GetUser_FullMethodName                      = "/chat.ChatService/GetUser"
+	ChatService_GetUserByName_FullMethodName                = "/chat.ChatService/GetUserByName"
+	ChatService_ListRoomsForUser_FullMethodName             = "/chat.ChatService/ListRoomsForUser"
+	ChatService_DeleteUser_FullMethodName                   = "/chat.ChatService/DeleteUser"
+	ChatService_CreateRoom_FullMethodName                   = "/chat.ChatService/CreateRoom"
+	ChatService_GetRoom_FullMethodName                      = "/chat.ChatService/GetRoom"
+	ChatService_ListRooms_FullMethodName                    = "/chat.ChatService/ListRooms"
+	ChatService_ListUsersForRoom_FullMethodName             = "/chat.ChatService/ListUsersForRoom"
+	ChatService_ListMessagesForRoom_FullMethodName          = "/chat.ChatService/ListMessagesForRoom"
+	ChatService_DeleteRoom_FullMethodName                   = "/chat.ChatService/DeleteRoom"
+	ChatService_RegisterUserInRoom_FullMethodName           = "/chat.ChatService/RegisterUserInRoom"
+	ChatService_UnregisterUserInRoom_FullMethodName         = "/chat.ChatService/UnregisterUserInRoom"
+	ChatService_SetModerator_FullMethodName                 = "/chat.ChatService/SetModerator"
+	ChatService_PostMessage_FullMethodName                  = "/chat.ChatService/PostMessage"
+	ChatService_ScheduleMessage_FullMethodName              = "/chat.ChatService/ScheduleMessage"
+	ChatService_GetMessageStatus_FullMethodName             = "/chat.ChatService/GetMessageStatus"
+	ChatService_Typing_FullMethodName                       = "/chat.ChatService/Typing"
+	ChatService_MarkAsRead_FullMethodName                   = "/chat.ChatService/MarkAsRead"
+	ChatService_ListScheduledMessagesForRoom_FullMethodName = "/chat.ChatService/ListScheduledMessagesForRoom"
+	ChatService_UpdateScheduledMessage_FullMethodName       = "/chat.ChatService/UpdateScheduledMessage"
+	ChatService_CancelScheduledMessage_FullMethodName       = "/chat.ChatService/CancelScheduledMessage"
+	ChatService_PinMessage_FullMethodName                   = "/chat.ChatService/PinMessage"
+	ChatService_UnpinMessage_FullMethodName                 = "/chat.ChatService/UnpinMessage"
+	ChatService_ListPinsForRoom_FullMethodName              = "/chat.ChatService/ListPinsForRoom"
+	ChatService_UploadAttachment_FullMethodName             = "/chat.ChatService/UploadAttachment"
+	ChatService_DownloadAttachment_FullMethodName           = "/chat.ChatService/DownloadAttachment"
+	ChatService_DownloadThumbnail_FullMethodName            = "/chat.ChatService/DownloadThumbnail"
+	ChatService_Subscribe_FullMethodName                    = "/chat.ChatService/Subscribe"
+)
+
+// ChatServiceClient is the client API for ChatService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// The chat service exposes the REST API of the server over gRPC.
+//
+// The messages mirror the JSON DTOs of the REST API: identifiers are UUIDs
+// and times are RFC 3339 strings. The Go stubs in this package are
+// generated from this file, see generate.go.
+type ChatServiceClient interface {
+	CreateUser(ctx context.Context, in *UserDtoRequest, opts ...grpc.CallOption) (*UserDtoResponse, error)
+	GetUser(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*UserDtoResponse, error)
+	GetUserByName(ctx context.Context, in *NameDtoRequest, opts ...grpc.CallOption) (*UserDtoResponse, error)
+	ListRoomsForUser(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*RoomsDtoResponse, error)
+	DeleteUser(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error)
+	CreateRoom(ctx context.Context, in *RoomDtoRequest, opts ...grpc.CallOption) (*RoomDtoResponse, error)
+	GetRoom(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*RoomDtoResponse, error)
+	ListRooms(ctx context.Context, in *EmptyDtoResponse, opts ...grpc.CallOption) (*RoomsDtoResponse, error)
+	ListUsersForRoom(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*UsersDtoResponse, error)
+	ListMessagesForRoom(ctx context.Context, in *RoomMessagesDtoRequest, opts ...grpc.CallOption) (*MessagesDtoResponse, error)
+	DeleteRoom(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error)
+	RegisterUserInRoom(ctx context.Context, in *RegistrationDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error)
+	UnregisterUserInRoom(ctx context.Context, in *RegistrationDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error)
+	SetModerator(ctx context.Context, in *RegistrationDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error)
+	// PostMessage rejects messages defining a send_at: they should be sent
+	// with ScheduleMessage.
+	PostMessage(ctx context.Context, in *MessageDtoRequest, opts ...grpc.CallOption) (*MessageStatusDtoResponse, error)
+	ScheduleMessage(ctx context.Context, in *MessageDtoRequest, opts ...grpc.CallOption) (*ScheduledMessageDtoResponse, error)
+	GetMessageStatus(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*MessageStatusDtoResponse, error)
+	Typing(ctx context.Context, in *TypingDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error)
+	MarkAsRead(ctx context.Context, in *ReadMarkerDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error)
+	ListScheduledMessagesForRoom(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*ScheduledMessagesDtoResponse, error)
+	UpdateScheduledMessage(ctx context.Context, in *ScheduledMessageUpdateDtoRequest, opts ...grpc.CallOption) (*ScheduledMessageDtoResponse, error)
+	CancelScheduledMessage(ctx context.Context, in *UserIdDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error)
+	PinMessage(ctx context.Context, in *PinDtoRequest, opts ...grpc.CallOption) (*PinDtoResponse, error)
+	UnpinMessage(ctx context.Context, in *PinDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error)
+	ListPinsForRoom(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*PinsDtoResponse, error)
+	UploadAttachment(ctx context.Context, in *AttachmentUploadDtoRequest, opts ...grpc.CallOption) (*AttachmentDtoResponse, error)
+	DownloadAttachment(ctx context.Context, in *UserIdDtoRequest, opts ...grpc.CallOption) (*AttachmentContentDtoResponse, error)
+	DownloadThumbnail(ctx context.Context, in *UserIdDtoRequest, opts ...grpc.CallOption) (*ThumbnailDtoResponse, error)
+	Subscribe(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EventDtoResponse], error)
+}
+
+type chatServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewChatServiceClient(cc grpc.ClientConnInterface) ChatServiceClient {
+	return &chatServiceClient{cc}
+}
+
+func (c *chatServiceClient) CreateUser(ctx context.Context, in *UserDtoRequest, opts ...grpc.CallOption) (*UserDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) GetUser(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*UserDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) GetUserByName(ctx context.Context, in *NameDtoRequest, opts ...grpc.CallOption) (*UserDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_GetUserByName_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ListRoomsForUser(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*RoomsDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoomsDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_ListRoomsForUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) DeleteUser(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmptyDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_DeleteUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) CreateRoom(ctx context.Context, in *RoomDtoRequest, opts ...grpc.CallOption) (*RoomDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoomDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_CreateRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) GetRoom(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*RoomDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoomDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_GetRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ListRooms(ctx context.Context, in *EmptyDtoResponse, opts ...grpc.CallOption) (*RoomsDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoomsDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_ListRooms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ListUsersForRoom(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*UsersDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UsersDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_ListUsersForRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ListMessagesForRoom(ctx context.Context, in *RoomMessagesDtoRequest, opts ...grpc.CallOption) (*MessagesDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MessagesDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_ListMessagesForRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) DeleteRoom(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmptyDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_DeleteRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) RegisterUserInRoom(ctx context.Context, in *RegistrationDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmptyDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_RegisterUserInRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) UnregisterUserInRoom(ctx context.Context, in *RegistrationDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmptyDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_UnregisterUserInRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) SetModerator(ctx context.Context, in *RegistrationDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmptyDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_SetModerator_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) PostMessage(ctx context.Context, in *MessageDtoRequest, opts ...grpc.CallOption) (*MessageStatusDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MessageStatusDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_PostMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ScheduleMessage(ctx context.Context, in *MessageDtoRequest, opts ...grpc.CallOption) (*ScheduledMessageDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScheduledMessageDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_ScheduleMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) GetMessageStatus(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*MessageStatusDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MessageStatusDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_GetMessageStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) Typing(ctx context.Context, in *TypingDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmptyDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_Typing_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) MarkAsRead(ctx context.Context, in *ReadMarkerDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmptyDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_MarkAsRead_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ListScheduledMessagesForRoom(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*ScheduledMessagesDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScheduledMessagesDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_ListScheduledMessagesForRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) UpdateScheduledMessage(ctx context.Context, in *ScheduledMessageUpdateDtoRequest, opts ...grpc.CallOption) (*ScheduledMessageDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScheduledMessageDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_UpdateScheduledMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) CancelScheduledMessage(ctx context.Context, in *UserIdDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmptyDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_CancelScheduledMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) PinMessage(ctx context.Context, in *PinDtoRequest, opts ...grpc.CallOption) (*PinDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PinDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_PinMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) UnpinMessage(ctx context.Context, in *PinDtoRequest, opts ...grpc.CallOption) (*EmptyDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmptyDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_UnpinMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ListPinsForRoom(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (*PinsDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PinsDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_ListPinsForRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) UploadAttachment(ctx context.Context, in *AttachmentUploadDtoRequest, opts ...grpc.CallOption) (*AttachmentDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AttachmentDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_UploadAttachment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) DownloadAttachment(ctx context.Context, in *UserIdDtoRequest, opts ...grpc.CallOption) (*AttachmentContentDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AttachmentContentDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_DownloadAttachment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) DownloadThumbnail(ctx context.Context, in *UserIdDtoRequest, opts ...grpc.CallOption) (*ThumbnailDtoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ThumbnailDtoResponse)
+	err := c.cc.Invoke(ctx, ChatService_DownloadThumbnail_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) Subscribe(ctx context.Context, in *IdDtoRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EventDtoResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChatService_ServiceDesc.Streams[0], ChatService_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[IdDtoRequest, EventDtoResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChatService_SubscribeClient = grpc.ServerStreamingClient[EventDtoResponse]
+
+// ChatServiceServer is the server API for ChatService service.
+// All implementations must embed UnimplementedChatServiceServer
+// for forward compatibility.
+//
+// The chat service exposes the REST API of the server over gRPC.
+//
+// The messages mirror the JSON DTOs of the REST API: identifiers are UUIDs
+// and times are RFC 3339 strings. The Go stubs in this package are
+// generated from this file, see generate.go.
+type ChatServiceServer interface {
+	CreateUser(context.Context, *UserDtoRequest) (*UserDtoResponse, error)
+	GetUser(context.Context, *IdDtoRequest) (*UserDtoResponse, error)
+	GetUserByName(context.Context, *NameDtoRequest) (*UserDtoResponse, error)
+	ListRoomsForUser(context.Context, *IdDtoRequest) (*RoomsDtoResponse, error)
+	DeleteUser(context.Context, *IdDtoRequest) (*EmptyDtoResponse, error)
+	CreateRoom(context.Context, *RoomDtoRequest) (*RoomDtoResponse, error)
+	GetRoom(context.Context, *IdDtoRequest) (*RoomDtoResponse, error)
+	ListRooms(context.Context, *EmptyDtoResponse) (*RoomsDtoResponse, error)
+	ListUsersForRoom(context.Context, *IdDtoRequest) (*UsersDtoResponse, error)
+	ListMessagesForRoom(context.Context, *RoomMessagesDtoRequest) (*MessagesDtoResponse, error)
+	DeleteRoom(context.Context, *IdDtoRequest) (*EmptyDtoResponse, error)
+	RegisterUserInRoom(context.Context, *RegistrationDtoRequest) (*EmptyDtoResponse, error)
+	UnregisterUserInRoom(context.Context, *RegistrationDtoRequest) (*EmptyDtoResponse, error)
+	SetModerator(context.Context, *RegistrationDtoRequest) (*EmptyDtoResponse, error)
+	// PostMessage rejects messages defining a send_at: they should be sent
+	// with ScheduleMessage.
+	PostMessage(context.Context, *MessageDtoRequest) (*MessageStatusDtoResponse, error)
+	ScheduleMessage(context.Context, *MessageDtoRequest) (*ScheduledMessageDtoResponse, error)
+	GetMessageStatus(context.Context, *IdDtoRequest) (*MessageStatusDtoResponse, error)
+	Typing(context.Context, *TypingDtoRequest) (*EmptyDtoResponse, error)
+	MarkAsRead(context.Context, *ReadMarkerDtoRequest) (*EmptyDtoResponse, error)
+	ListScheduledMessagesForRoom(context.Context, *IdDtoRequest) (*ScheduledMessagesDtoResponse, error)
+	UpdateScheduledMessage(context.Context, *ScheduledMessageUpdateDtoRequest) (*ScheduledMessageDtoResponse, error)
+	CancelScheduledMessage(context.Context, *UserIdDtoRequest) (*EmptyDtoResponse, error)
+	PinMessage(context.Context, *PinDtoRequest) (*PinDtoResponse, error)
+	UnpinMessage(context.Context, *PinDtoRequest) (*EmptyDtoResponse, error)
+	ListPinsForRoom(context.Context, *IdDtoRequest) (*PinsDtoResponse, error)
+	UploadAttachment(context.Context, *AttachmentUploadDtoRequest) (*AttachmentDtoResponse, error)
+	DownloadAttachment(context.Context, *UserIdDtoRequest) (*AttachmentContentDtoResponse, error)
+	DownloadThumbnail(context.Context, *UserIdDtoRequest) (*ThumbnailDtoResponse, error)
+	Subscribe(*IdDtoRequest, grpc.ServerStreamingServer[EventDtoResponse]) error
+	mustEmbedUnimplementedChatServiceServer()
+}
+
+// UnimplementedChatServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedChatServiceServer struct{}
+
+func (UnimplementedChatServiceServer) CreateUser(context.Context, *UserDtoRequest) (*UserDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedChatServiceServer) GetUser(context.Context, *IdDtoRequest) (*UserDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedChatServiceServer) GetUserByName(context.Context, *NameDtoRequest) (*UserDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUserByName not implemented")
+}
+func (UnimplementedChatServiceServer) ListRoomsForUser(context.Context, *IdDtoRequest) (*RoomsDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListRoomsForUser not implemented")
+}
+func (UnimplementedChatServiceServer) DeleteUser(context.Context, *IdDtoRequest) (*EmptyDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedChatServiceServer) CreateRoom(context.Context, *RoomDtoRequest) (*RoomDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateRoom not implemented")
+}
+func (UnimplementedChatServiceServer) GetRoom(context.Context, *IdDtoRequest) (*RoomDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetRoom not implemented")
+}
+func (UnimplementedChatServiceServer) ListRooms(context.Context, *EmptyDtoResponse) (*RoomsDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListRooms not implemented")
+}
+func (UnimplementedChatServiceServer) ListUsersForRoom(context.Context, *IdDtoRequest) (*UsersDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListUsersForRoom not implemented")
+}
+func (UnimplementedChatServiceServer) ListMessagesForRoom(context.Context, *RoomMessagesDtoRequest) (*MessagesDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListMessagesForRoom not implemented")
+}
+func (UnimplementedChatServiceServer) DeleteRoom(context.Context, *IdDtoRequest) (*EmptyDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteRoom not implemented")
+}
+func (UnimplementedChatServiceServer) RegisterUserInRoom(context.Context, *RegistrationDtoRequest) (*EmptyDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RegisterUserInRoom not implemented")
+}
+func (UnimplementedChatServiceServer) UnregisterUserInRoom(context.Context, *RegistrationDtoRequest) (*EmptyDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UnregisterUserInRoom not implemented")
+}
+func (UnimplementedChatServiceServer) SetModerator(context.Context, *RegistrationDtoRequest) (*EmptyDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetModerator not implemented")
+}
+func (UnimplementedChatServiceServer) PostMessage(context.Context, *MessageDtoRequest) (*MessageStatusDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PostMessage not implemented")
+}
+func (UnimplementedChatServiceServer) ScheduleMessage(context.Context, *MessageDtoRequest) (*ScheduledMessageDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ScheduleMessage not implemented")
+}
+func (UnimplementedChatServiceServer) GetMessageStatus(context.Context, *IdDtoRequest) (*MessageStatusDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetMessageStatus not implemented")
+}
+func (UnimplementedChatServiceServer) Typing(context.Context, *TypingDtoRequest) (*EmptyDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Typing not implemented")
+}
+func (UnimplementedChatServiceServer) MarkAsRead(context.Context, *ReadMarkerDtoRequest) (*EmptyDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method MarkAsRead not implemented")
+}
+func (UnimplementedChatServiceServer) ListScheduledMessagesForRoom(context.Context, *IdDtoRequest) (*ScheduledMessagesDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListScheduledMessagesForRoom not implemented")
+}
+func (UnimplementedChatServiceServer) UpdateScheduledMessage(context.Context, *ScheduledMessageUpdateDtoRequest) (*ScheduledMessageDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateScheduledMessage not implemented")
+}
+func (UnimplementedChatServiceServer) CancelScheduledMessage(context.Context, *UserIdDtoRequest) (*EmptyDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelScheduledMessage not implemented")
+}
+func (UnimplementedChatServiceServer) PinMessage(context.Context, *PinDtoRequest) (*PinDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PinMessage not implemented")
+}
+func (UnimplementedChatServiceServer) UnpinMessage(context.Context, *PinDtoRequest) (*EmptyDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UnpinMessage not implemented")
+}
+func (UnimplementedChatServiceServer) ListPinsForRoom(context.Context, *IdDtoRequest) (*PinsDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListPinsForRoom not implemented")
+}
+func (UnimplementedChatServiceServer) UploadAttachment(context.Context, *AttachmentUploadDtoRequest) (*AttachmentDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UploadAttachment not implemented")
+}
+func (UnimplementedChatServiceServer) DownloadAttachment(context.Context, *UserIdDtoRequest) (*AttachmentContentDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DownloadAttachment not implemented")
+}
+func (UnimplementedChatServiceServer) DownloadThumbnail(context.Context, *UserIdDtoRequest) (*ThumbnailDtoResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DownloadThumbnail not implemented")
+}
+func (UnimplementedChatServiceServer) Subscribe(*IdDtoRequest, grpc.ServerStreamingServer[EventDtoResponse]) error {
+	return status.Error(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedChatServiceServer) mustEmbedUnimplementedChatServiceServer() {}
+func (UnimplementedChatServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeChatServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ChatServiceServer will
+// result in compilation errors.
+type UnsafeChatServiceServer interface {
+	mustEmbedUnimplementedChatServiceServer()
+}
+
+func RegisterChatServiceServer(s grpc.ServiceRegistrar, srv ChatServiceServer) {
+	// If the following call panics, it indicates UnimplementedChatServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ChatService_ServiceDesc, srv)
+}
+
+func _ChatService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).CreateUser(ctx, req.(*UserDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).GetUser(ctx, req.(*IdDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_GetUserByName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NameDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).GetUserByName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_GetUserByName_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).GetUserByName(ctx, req.(*NameDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ListRoomsForUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ListRoomsForUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ListRoomsForUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ListRoomsForUser(ctx, req.(*IdDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_DeleteUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).DeleteUser(ctx, req.(*IdDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_CreateRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoomDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).CreateRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_CreateRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).CreateRoom(ctx, req.(*RoomDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_GetRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).GetRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_GetRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).GetRoom(ctx, req.(*IdDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ListRooms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyDtoResponse)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ListRooms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ListRooms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ListRooms(ctx, req.(*EmptyDtoResponse))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ListUsersForRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ListUsersForRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ListUsersForRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ListUsersForRoom(ctx, req.(*IdDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ListMessagesForRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoomMessagesDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ListMessagesForRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ListMessagesForRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ListMessagesForRoom(ctx, req.(*RoomMessagesDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_DeleteRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).DeleteRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_DeleteRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).DeleteRoom(ctx, req.(*IdDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_RegisterUserInRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegistrationDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).RegisterUserInRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_RegisterUserInRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).RegisterUserInRoom(ctx, req.(*RegistrationDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_UnregisterUserInRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegistrationDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).UnregisterUserInRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_UnregisterUserInRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).UnregisterUserInRoom(ctx, req.(*RegistrationDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_SetModerator_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegistrationDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).SetModerator(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_SetModerator_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).SetModerator(ctx, req.(*RegistrationDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_PostMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MessageDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).PostMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_PostMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).PostMessage(ctx, req.(*MessageDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ScheduleMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MessageDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ScheduleMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ScheduleMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ScheduleMessage(ctx, req.(*MessageDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_GetMessageStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).GetMessageStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_GetMessageStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).GetMessageStatus(ctx, req.(*IdDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_Typing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TypingDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).Typing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_Typing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).Typing(ctx, req.(*TypingDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_MarkAsRead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadMarkerDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).MarkAsRead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_MarkAsRead_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).MarkAsRead(ctx, req.(*ReadMarkerDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ListScheduledMessagesForRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ListScheduledMessagesForRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ListScheduledMessagesForRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ListScheduledMessagesForRoom(ctx, req.(*IdDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_UpdateScheduledMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduledMessageUpdateDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).UpdateScheduledMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_UpdateScheduledMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).UpdateScheduledMessage(ctx, req.(*ScheduledMessageUpdateDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_CancelScheduledMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIdDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).CancelScheduledMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_CancelScheduledMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).CancelScheduledMessage(ctx, req.(*UserIdDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_PinMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PinDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).PinMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_PinMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).PinMessage(ctx, req.(*PinDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_UnpinMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PinDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).UnpinMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_UnpinMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).UnpinMessage(ctx, req.(*PinDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ListPinsForRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).ListPinsForRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_ListPinsForRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).ListPinsForRoom(ctx, req.(*IdDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_UploadAttachment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttachmentUploadDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).UploadAttachment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_UploadAttachment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).UploadAttachment(ctx, req.(*AttachmentUploadDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_DownloadAttachment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIdDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).DownloadAttachment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_DownloadAttachment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).DownloadAttachment(ctx, req.(*UserIdDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_DownloadThumbnail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIdDtoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).DownloadThumbnail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_DownloadThumbnail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).DownloadThumbnail(ctx, req.(*UserIdDtoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(IdDtoRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChatServiceServer).Subscribe(m, &grpc.GenericServerStream[IdDtoRequest, EventDtoResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChatService_SubscribeServer = grpc.ServerStreamingServer[EventDtoResponse]
+
+// ChatService_ServiceDesc is the grpc.ServiceDesc for ChatService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ChatService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "chat.ChatService",
+	HandlerType: (*ChatServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateUser",
+			Handler:    _ChatService_CreateUser_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _ChatService_GetUser_Handler,
+		},
+		{
+			MethodName: "GetUserByName",
+			Handler:    _ChatService_GetUserByName_Handler,
+		},
+		{
+			MethodName: "ListRoomsForUser",
+			Handler:    _ChatService_ListRoomsForUser_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _ChatService_DeleteUser_Handler,
+		},
+		{
+			MethodName: "CreateRoom",
+			Handler:    _ChatService_CreateRoom_Handler,
+		},
+		{
+			MethodName: "GetRoom",
+			Handler:    _ChatService_GetRoom_Handler,
+		},
+		{
+			MethodName: "ListRooms",
+			Handler:    _ChatService_ListRooms_Handler,
+		},
+		{
+			MethodName: "ListUsersForRoom",
+			Handler:    _ChatService_ListUsersForRoom_Handler,
+		},
+		{
+			MethodName: "ListMessagesForRoom",
+			Handler:    _ChatService_ListMessagesForRoom_Handler,
+		},
+		{
+			MethodName: "DeleteRoom",
+			Handler:    _ChatService_DeleteRoom_Handler,
+		},
+		{
+			MethodName: "RegisterUserInRoom",
+			Handler:    _ChatService_RegisterUserInRoom_Handler,
+		},
+		{
+			MethodName: "UnregisterUserInRoom",
+			Handler:    _ChatService_UnregisterUserInRoom_Handler,
+		},
+		{
+			MethodName: "SetModerator",
+			Handler:    _ChatService_SetModerator_Handler,
+		},
+		{
+			MethodName: "PostMessage",
+			Handler:    _ChatService_PostMessage_Handler,
+		},
+		{
+			MethodName: "ScheduleMessage",
+			Handler:    _ChatService_ScheduleMessage_Handler,
+		},
+		{
+			MethodName: "GetMessageStatus",
+			Handler:    _ChatService_GetMessageStatus_Handler,
+		},
+		{
+			MethodName: "Typing",
+			Handler:    _ChatService_Typing_Handler,
+		},
+		{
+			MethodName: "MarkAsRead",
+			Handler:    _ChatService_MarkAsRead_Handler,
+		},
+		{
+			MethodName: "ListScheduledMessagesForRoom",
+			Handler:    _ChatService_ListScheduledMessagesForRoom_Handler,
+		},
+		{
+			MethodName: "UpdateScheduledMessage",
+			Handler:    _ChatService_UpdateScheduledMessage_Handler,
+		},
+		{
+			MethodName: "CancelScheduledMessage",
+			Handler:    _ChatService_CancelScheduledMessage_Handler,
+		},
+		{
+			MethodName: "PinMessage",
+			Handler:    _ChatService_PinMessage_Handler,
+		},
+		{
+			MethodName: "UnpinMessage",
+			Handler:    _ChatService_UnpinMessage_Handler,
+		},
+		{
+			MethodName: "ListPinsForRoom",
+			Handler:    _ChatService_ListPinsForRoom_Handler,
+		},
+		{
+			MethodName: "UploadAttachment",
+			Handler:    _ChatService_UploadAttachment_Handler,
+		},
+		{
+			MethodName: "DownloadAttachment",
+			Handler:    _ChatService_DownloadAttachment_Handler,
+		},
+		{
+			MethodName: "DownloadThumbnail",
+			Handler:    _ChatService_DownloadThumbnail_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _ChatService_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "chat.proto",
+}
@@ -0,0 +1,6 @@
+// Package chatrpc holds the client and server stubs of the gRPC API,
+// generated from chat.proto with buf and the protoc-gen-go and
+// protoc-gen-go-grpc plugins.
+package chatrpc
+
+//go:generate buf generate
@@ -4,32 +4,47 @@ import (
 	"net/http"
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/encoders"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
 	"github.com/Knoblauchpilze/chat-server/pkg/messages"
-	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
 	"github.com/google/uuid"
 )
 
-type Client messages.Processor
+type Client messages.Processor[events.Event]
 
+// InitialEventCallback produces an event sent before all the others. It
+// is called once the client is registered so that the events happening
+// in the meantime are sent after it.
+type InitialEventCallback func() (events.Event, error)
+
+// New creates a client streaming the events through SSE. The initial
+// event is optional.
 func New(
 	messageQueueSize int,
 	_ uuid.UUID,
 	rw http.ResponseWriter,
+	encoder encoders.Encoder,
+	initial InitialEventCallback,
 ) (Client, error) {
 	_, ok := rw.(http.Flusher)
 	if !ok {
 		return nil, errors.NewCode(ErrUnsupportedConnection)
 	}
 
-	callbacks := messages.Callbacks{
-		Start:   generateStartCallback(rw),
-		Message: generateMessageCallback(rw),
+	send := generateMessageCallback(rw, encoder)
+	callbacks := messages.Callbacks[events.Event]{
+		Start:   generateStartCallback(rw, send, initial),
+		Message: send,
 	}
 
 	return messages.NewProcessor(messageQueueSize, callbacks), nil
 }
 
-func generateStartCallback(rw http.ResponseWriter) messages.StartCallback {
+func generateStartCallback(
+	rw http.ResponseWriter,
+	send messages.MessageCallback[events.Event],
+	initial InitialEventCallback,
+) messages.StartCallback {
 	flusher := rw.(http.Flusher)
 
 	return func() error {
@@ -40,16 +55,27 @@ func generateStartCallback(rw http.ResponseWriter) messages.StartCallback {
 
 		// https://github.com/tmaxmax/go-sse/blob/e429bb3114f36f65a121c25918e1131b8de6affe/session.go#L69
 		flusher.Flush()
-		return nil
+
+		if initial == nil {
+			return nil
+		}
+
+		event, err := initial()
+		if err != nil {
+			return err
+		}
+
+		return send(event)
 	}
 }
-
-func generateMessageCallback(rw http.ResponseWriter) messages.MessageCallback {
+func generateMessageCallback(
+	rw http.ResponseWriter, encoder encoders.Encoder,
+) messages.MessageCallback[events.Event] {
 	// We verify in New that this conversion will succeed
 	flusher := rw.(http.Flusher)
 
-	return func(msg persistence.Message) error {
-		e, err := fromMessage(msg)
+	return func(event events.Event) error {
+		e, err := fromEvent(event, encoder)
 		if err != nil {
 			return errors.WrapCode(err, ErrSseStreamFailed)
 		}
@@ -3,10 +3,15 @@ package clients
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/encoders"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
 	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -14,7 +19,7 @@ import (
 
 func TestUnit_Client_CorrectlySetsSseHeaders(t *testing.T) {
 	rec := httptest.NewRecorder()
-	client, err := New(1, uuid.New(), rec)
+	client, err := New(1, uuid.New(), rec, encoders.Json, nil)
 	assert.Nil(t, err, "Actual err: %v", err)
 
 	wg := asyncStartClientAndAssertNoError(t, client)
@@ -37,7 +42,7 @@ func TestUnit_Client_CorrectlySetsSseHeaders(t *testing.T) {
 
 func TestUnit_Client_SendsMessageAsSse(t *testing.T) {
 	rec := httptest.NewRecorder()
-	client, err := New(1, uuid.New(), rec)
+	client, err := New(1, uuid.New(), rec, encoders.Json, nil)
 	assert.Nil(t, err, "Actual err: %v", err)
 
 	wg := asyncStartClientAndAssertNoError(t, client)
@@ -47,9 +52,10 @@ func TestUnit_Client_SendsMessageAsSse(t *testing.T) {
 		ChatUser:  uuid.MustParse("f2d9ce22-179d-431c-b63d-43d5a8ab5e18"),
 		Room:      uuid.MustParse("111838db-a871-47be-9149-c974fd356316"),
 		Message:   "Hello",
+		Sequence:  3,
 		CreatedAt: time.Date(2025, 5, 4, 20, 56, 16, 0, time.UTC),
 	}
-	client.Enqueue(msg)
+	client.Enqueue(events.FromMessage(msg))
 
 	// Wait for the message to be sent
 	time.Sleep(50 * time.Millisecond)
@@ -61,12 +67,65 @@ func TestUnit_Client_SendsMessageAsSse(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 	actual := rec.Body.String()
 	expected := `id: 8f102c70-8eba-4094-bd4d-7f70d71b21f2
-data: {"id":"8f102c70-8eba-4094-bd4d-7f70d71b21f2","user":"f2d9ce22-179d-431c-b63d-43d5a8ab5e18","room":"111838db-a871-47be-9149-c974fd356316","message":"Hello","created_at":"2025-05-04T20:56:16Z"}
+data: {"id":"8f102c70-8eba-4094-bd4d-7f70d71b21f2","user":"f2d9ce22-179d-431c-b63d-43d5a8ab5e18","room":"111838db-a871-47be-9149-c974fd356316","message":"Hello","sequence":3,"created_at":"2025-05-04T20:56:16Z"}
 
 `
 	assert.Equal(t, expected, actual)
 }
 
+func TestUnit_Client_SendsInitialEventFirst(t *testing.T) {
+	rec := httptest.NewRecorder()
+	initial := events.Event{
+		Id:   uuid.MustParse("3cdfb4ea-d372-443e-92c0-6eea2f7cd2f0"),
+		Type: events.Snapshot,
+		Data: communication.SnapshotDtoResponse{
+			Rooms: []communication.RoomSnapshotDtoResponse{},
+		},
+	}
+	callback := func() (events.Event, error) {
+		return initial, nil
+	}
+	client, err := New(1, uuid.New(), rec, encoders.Json, callback)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	wg := asyncStartClientAndAssertNoError(t, client)
+
+	client.Enqueue(events.Event{
+		Id:   uuid.MustParse("8f102c70-8eba-4094-bd4d-7f70d71b21f2"),
+		Type: events.Typing,
+		Data: communication.TypingDtoResponse{},
+	})
+
+	// Wait for the message to be sent
+	time.Sleep(50 * time.Millisecond)
+
+	err = client.Stop()
+	wg.Wait()
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual := rec.Body.String()
+	expectedPrefix := `id: 3cdfb4ea-d372-443e-92c0-6eea2f7cd2f0
+data: {"rooms":[]}
+event: snapshot
+
+id: 8f102c70-8eba-4094-bd4d-7f70d71b21f2
+`
+	assert.True(t, strings.HasPrefix(actual, expectedPrefix), "Actual body: %s", actual)
+}
+
+func TestUnit_Client_WhenInitialEventFails_ExpectError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	expectedErr := errors.NewCode(ErrSseStreamFailed)
+	callback := func() (events.Event, error) {
+		return events.Event{}, expectedErr
+	}
+	client, err := New(1, uuid.New(), rec, encoders.Json, callback)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	wg := asyncStartClientAndAssertError(t, client, expectedErr)
+	wg.Wait()
+}
+
 func asyncStartClientAndAssertNoError(
 	t *testing.T, client Client,
 ) *sync.WaitGroup {
@@ -0,0 +1,148 @@
+package clients
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/encoders"
+)
+
+type Compression string
+
+const (
+	NoCompression Compression = ""
+	Gzip          Compression = "gzip"
+	// Deflate is the zlib format, as defined for the HTTP content coding.
+	Deflate Compression = "deflate"
+)
+
+// supportedCompressions are ordered by preference when the client accepts
+// several of them with the same quality.
+var supportedCompressions = []Compression{Gzip, Deflate}
+
+// CompressionFromAcceptEncoding returns the preferred compression among
+// the ones of an `Accept-Encoding` header.
+func CompressionFromAcceptEncoding(header string) Compression {
+	qualities := make(map[Compression]float64)
+	wildcard, hasWildcard := 0.0, false
+
+	for _, part := range strings.Split(header, ",") {
+		coding, quality := encoders.ParseQualityValue(part)
+		if coding == "*" {
+			wildcard, hasWildcard = quality, true
+			continue
+		}
+		qualities[Compression(coding)] = quality
+	}
+
+	best, bestQuality := NoCompression, 0.0
+	for _, compression := range supportedCompressions {
+		quality, ok := qualities[compression]
+		if !ok && hasWildcard {
+			quality = wildcard
+		}
+
+		if quality > bestQuality {
+			best, bestQuality = compression, quality
+		}
+	}
+
+	return best
+}
+
+type CompressedResponseWriter interface {
+	http.ResponseWriter
+	http.Flusher
+	io.Closer
+}
+
+type compressor interface {
+	io.Writer
+	Flush() error
+	Close() error
+}
+
+// compressedResponseWriter compresses the body of the response. Flushing
+// it flushes the compressor first so that the client can decompress all
+// the data sent so far: the stream is flushed after each event.
+type compressedResponseWriter struct {
+	rw          http.ResponseWriter
+	compression Compression
+	compressor  compressor
+
+	wroteHeader bool
+}
+
+// NewCompressedResponseWriter should be closed once the response is fully
+// written. Nothing is compressed if nothing is written to it, so that the
+// response writer can still be used, for example to report an error.
+func NewCompressedResponseWriter(
+	rw http.ResponseWriter, compression Compression,
+) CompressedResponseWriter {
+	return &compressedResponseWriter{
+		rw:          rw,
+		compression: compression,
+	}
+}
+
+func (w *compressedResponseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *compressedResponseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	header := w.rw.Header()
+	header.Set("Content-Encoding", string(w.compression))
+	header.Add("Vary", "Accept-Encoding")
+	header.Del("Content-Length")
+
+	if w.compression == Deflate {
+		w.compressor = zlib.NewWriter(w.rw)
+	} else {
+		w.compressor = gzip.NewWriter(w.rw)
+	}
+
+	w.rw.WriteHeader(statusCode)
+}
+
+func (w *compressedResponseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.compressor.Write(data)
+}
+
+func (w *compressedResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	// A failure is reported by the next write.
+	w.compressor.Flush()
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *compressedResponseWriter) Close() error {
+	if !w.wroteHeader {
+		return nil
+	}
+
+	err := w.compressor.Close()
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return err
+}
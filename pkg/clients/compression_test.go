@@ -0,0 +1,152 @@
+package clients
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/encoders"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_CompressionFromAcceptEncoding(t *testing.T) {
+	type testCase struct {
+		header   string
+		expected Compression
+	}
+
+	testCases := map[string]testCase{
+		"empty": {
+			header:   "",
+			expected: NoCompression,
+		},
+		"gzip": {
+			header:   "gzip",
+			expected: Gzip,
+		},
+		"deflate": {
+			header:   "deflate",
+			expected: Deflate,
+		},
+		"unsupported": {
+			header:   "br",
+			expected: NoCompression,
+		},
+		"preferGzipOnTie": {
+			header:   "deflate, gzip, br",
+			expected: Gzip,
+		},
+		"quality": {
+			header:   "gzip;q=0.5, deflate",
+			expected: Deflate,
+		},
+		"wildcard": {
+			header:   "*",
+			expected: Gzip,
+		},
+		"wildcardWithExclusion": {
+			header:   "gzip;q=0, *",
+			expected: Deflate,
+		},
+		"notAcceptable": {
+			header:   "gzip;q=0",
+			expected: NoCompression,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := CompressionFromAcceptEncoding(testCase.header)
+
+			assert.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestUnit_CompressedResponseWriter_WhenNothingWritten_ExpectEmptyResponse(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rw := NewCompressedResponseWriter(rec, Gzip)
+
+	err := rw.Close()
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Empty(t, rec.Body.Bytes())
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+}
+
+func TestUnit_Client_WhenCompressed_ExpectEventDecompressedBeforeClose(t *testing.T) {
+	type testCase struct {
+		compression Compression
+		newReader   func(r io.Reader) (io.Reader, error)
+	}
+
+	testCases := map[string]testCase{
+		"gzip": {
+			compression: Gzip,
+			newReader: func(r io.Reader) (io.Reader, error) {
+				return gzip.NewReader(r)
+			},
+		},
+		"deflate": {
+			compression: Deflate,
+			newReader: func(r io.Reader) (io.Reader, error) {
+				return zlib.NewReader(r)
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			rw := NewCompressedResponseWriter(rec, testCase.compression)
+			client, err := New(1, uuid.New(), rw, encoders.Json, nil)
+			assert.Nil(t, err, "Actual err: %v", err)
+
+			wg := asyncStartClientAndAssertNoError(t, client)
+
+			msg := persistence.Message{
+				Id:        uuid.MustParse("8f102c70-8eba-4094-bd4d-7f70d71b21f2"),
+				ChatUser:  uuid.MustParse("f2d9ce22-179d-431c-b63d-43d5a8ab5e18"),
+				Room:      uuid.MustParse("111838db-a871-47be-9149-c974fd356316"),
+				Message:   "Hello",
+				Sequence:  3,
+				CreatedAt: time.Date(2025, 5, 4, 20, 56, 16, 0, time.UTC),
+			}
+			client.Enqueue(events.FromMessage(msg))
+
+			// Wait for the message to be sent
+			time.Sleep(50 * time.Millisecond)
+
+			err = client.Stop()
+			wg.Wait()
+			assert.Nil(t, err, "Actual err: %v", err)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(testCase.compression), rec.Header().Get("Content-Encoding"))
+			assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+
+			// The writer is not closed yet: the event should be readable
+			// thanks to the flush.
+			expected := `id: 8f102c70-8eba-4094-bd4d-7f70d71b21f2
+data: {"id":"8f102c70-8eba-4094-bd4d-7f70d71b21f2","user":"f2d9ce22-179d-431c-b63d-43d5a8ab5e18","room":"111838db-a871-47be-9149-c974fd356316","message":"Hello","sequence":3,"created_at":"2025-05-04T20:56:16Z"}
+
+`
+			reader, err := testCase.newReader(rec.Body)
+			assert.Nil(t, err, "Actual err: %v", err)
+			actual := make([]byte, len(expected))
+			_, err = io.ReadFull(reader, actual)
+			assert.Nil(t, err, "Actual err: %v", err)
+			assert.Equal(t, expected, string(actual))
+
+			err = rw.Close()
+			assert.Nil(t, err, "Actual err: %v", err)
+		})
+	}
+}
@@ -7,5 +7,8 @@ const (
 	ErrSseStreamFailed         errors.ErrorCode = 501
 	ErrUnsupportedConnection   errors.ErrorCode = 502
 	ErrClientAlreadyRegistered errors.ErrorCode = 503
-	ErrBroadcastFailure        errors.ErrorCode = 504
+	ErrMembershipFailure       errors.ErrorCode = 505
+	ErrWebSocketFailed         errors.ErrorCode = 506
+	ErrTcpWriteFailed          errors.ErrorCode = 507
+	ErrInvalidCursor           errors.ErrorCode = 508
 )
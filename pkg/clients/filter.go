@@ -0,0 +1,31 @@
+package clients
+
+import (
+	"slices"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/google/uuid"
+)
+
+// Filter selects the events sent to a client among the ones of its rooms.
+// An empty list of rooms or types selects all of them. The shutdown event
+// is always sent.
+type Filter struct {
+	Rooms []uuid.UUID
+	Types []events.Type
+}
+
+func (f Filter) accepts(event events.Event) bool {
+	if event.Type == events.Shutdown {
+		return true
+	}
+
+	if len(f.Rooms) > 0 && !slices.Contains(f.Rooms, event.Room) {
+		return false
+	}
+	if len(f.Types) > 0 && !slices.Contains(f.Types, event.Type) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,68 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_Filter_Accepts(t *testing.T) {
+	room := uuid.New()
+	message := events.FromMessage(persistence.Message{Room: room})
+	typing := events.FromTyping(communication.TypingDtoResponse{Room: room})
+	otherRoom := events.FromMessage(persistence.Message{Room: uuid.New()})
+
+	type testCase struct {
+		filter   Filter
+		event    events.Event
+		expected bool
+	}
+
+	testCases := map[string]testCase{
+		"empty": {
+			filter:   Filter{},
+			event:    otherRoom,
+			expected: true,
+		},
+		"matchingRoom": {
+			filter:   Filter{Rooms: []uuid.UUID{room}},
+			event:    message,
+			expected: true,
+		},
+		"otherRoom": {
+			filter:   Filter{Rooms: []uuid.UUID{room}},
+			event:    otherRoom,
+			expected: false,
+		},
+		"matchingType": {
+			filter:   Filter{Types: []events.Type{events.Message}},
+			event:    message,
+			expected: true,
+		},
+		"otherType": {
+			filter:   Filter{Types: []events.Type{events.Message}},
+			event:    typing,
+			expected: false,
+		},
+		"matchingRoomButOtherType": {
+			filter:   Filter{Rooms: []uuid.UUID{room}, Types: []events.Type{events.Message}},
+			event:    typing,
+			expected: false,
+		},
+		"shutdown": {
+			filter:   Filter{Rooms: []uuid.UUID{room}, Types: []events.Type{events.Message}},
+			event:    events.FromShutdown(),
+			expected: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, testCase.filter.accepts(testCase.event))
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package clients
+
+import (
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/google/uuid"
+)
+
+// jsonEvent carries the same data as the SSE stream for the transports
+// sending events as objects: the type is always set as there is no
+// default handler for untyped events.
+type jsonEvent struct {
+	Id   uuid.UUID   `json:"id"`
+	Type events.Type `json:"type"`
+	Data any         `json:"data"`
+}
+
+func toJsonEvent(event events.Event) jsonEvent {
+	return jsonEvent{
+		Id:   event.Id,
+		Type: event.Type,
+		Data: event.Data,
+	}
+}
@@ -2,10 +2,13 @@ package clients
 
 import (
 	"context"
+	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
 	"github.com/Knoblauchpilze/chat-server/pkg/messages"
 	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
 	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
@@ -16,31 +19,56 @@ type Manager interface {
 	Start() error
 	Stop() error
 
-	OnConnect(id uuid.UUID, client Client) error
+	// OnConnect registers the client of the user. Only the events accepted
+	// by the filter are enqueued.
+	OnConnect(ctx context.Context, id uuid.UUID, client Client, filter Filter) error
 	OnDisconnect(id uuid.UUID)
 
+	// Poll registers the user as a long-polling client if needed and
+	// returns the events received after the cursor.
+	Poll(ctx context.Context, id uuid.UUID, cursor Cursor) (PollResult, error)
+
 	messages.Dispatcher
 }
 
 type managerImpl struct {
 	running atomic.Bool
+	stopped atomic.Bool
 	quit    chan struct{}
 	done    chan struct{}
 
-	userRepo repositories.UserRepository
+	reconciliationInterval time.Duration
+	poll                   PollPolicy
+	roomRepo               repositories.RoomRepository
 
-	lock    sync.RWMutex
-	clients map[uuid.UUID]Client
+	lock       sync.RWMutex
+	clients    map[uuid.UUID]Client
+	filters    map[uuid.UUID]Filter
+	membership membership
 }
 
-func NewManager(repos repositories.Repositories) Manager {
+// NewManager keeps track of the rooms of the connected users in memory so
+// that broadcasting a message does not require to query the database. The
+// index is kept up to date by the membership notifications and reconciled
+// with the database at the given interval to recover from missed ones.
+// The events of the long-polling clients are buffered following the poll
+// policy.
+func NewManager(
+	reconciliationInterval time.Duration,
+	poll PollPolicy,
+	repos repositories.Repositories,
+) Manager {
 	return &managerImpl{
 		quit: make(chan struct{}, 1),
 		done: make(chan struct{}, 1),
 
-		userRepo: repos.User,
+		reconciliationInterval: reconciliationInterval,
+		poll:                   poll,
+		roomRepo:               repos.Room,
 
-		clients: make(map[uuid.UUID]Client),
+		clients:    make(map[uuid.UUID]Client),
+		filters:    make(map[uuid.UUID]Filter),
+		membership: newMembership(),
 	}
 }
 
@@ -49,50 +77,81 @@ func (m *managerImpl) Start() error {
 		return nil
 	}
 
-	<-m.quit
 	defer func() {
 		m.done <- struct{}{}
 	}()
 
-	var err error
+	m.reconcileUntilQuit()
+
+	m.lock.Lock()
+	clients := make([]Client, 0, len(m.clients))
+	for _, client := range m.clients {
+		clients = append(clients, client)
+	}
 
-	func() {
-		m.lock.Lock()
-		defer m.lock.Unlock()
+	clear(m.clients)
+	clear(m.filters)
+	m.membership = newMembership()
+	m.lock.Unlock()
 
-		for _, client := range m.clients {
-			clientErr := client.Stop()
-			if clientErr != nil && err == nil {
-				err = clientErr
-			}
+	var err error
+	for _, client := range clients {
+		// Stopping the client sends the events still in its queue so
+		// this one is the last one received. A client whose queue is
+		// full does not get it rather than delaying the shutdown.
+		client.TryEnqueue(events.FromShutdown())
+
+		clientErr := client.Stop()
+		if clientErr != nil && err == nil {
+			err = clientErr
 		}
-
-		clear(m.clients)
-	}()
+	}
 
 	return err
 }
 
 func (m *managerImpl) Stop() error {
-	if !m.running.CompareAndSwap(true, false) {
+	if !m.stopped.CompareAndSwap(false, true) {
 		return nil
 	}
 
-	m.quit <- struct{}{}
-	<-m.done
+	close(m.quit)
+	// A component stopped before it started returns as soon as it starts:
+	// there is nothing to wait for.
+	if m.running.Load() {
+		<-m.done
+	}
 
 	return nil
 }
 
-func (m *managerImpl) OnConnect(id uuid.UUID, client Client) error {
+func (m *managerImpl) OnConnect(
+	ctx context.Context, id uuid.UUID, client Client, filter Filter,
+) error {
+	// Registrations happening between this query and the insertion of the
+	// client are picked up by the next reconciliation.
+	memberships, err := m.roomRepo.ListMemberships(ctx, []uuid.UUID{id})
+	if err != nil {
+		return errors.WrapCode(err, ErrMembershipFailure)
+	}
+
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
-	if _, ok := m.clients[id]; ok {
-		return errors.NewCode(ErrClientAlreadyRegistered)
+	if existing, ok := m.clients[id]; ok {
+		// A user switching from long-polling to another transport does
+		// not have to wait for its buffer to expire.
+		buffer, isBuffer := existing.(*pollBuffer)
+		if !isBuffer {
+			return errors.NewCode(ErrClientAlreadyRegistered)
+		}
+
+		buffer.Stop()
 	}
 
 	m.clients[id] = client
+	m.filters[id] = filter
+	m.membership.addAll(memberships)
 
 	return nil
 }
@@ -101,67 +160,252 @@ func (m *managerImpl) OnDisconnect(id uuid.UUID) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
+	m.removeClient(id)
+}
+
+// removeClient expects the lock to be held by the caller.
+func (m *managerImpl) removeClient(id uuid.UUID) {
 	delete(m.clients, id)
+	delete(m.filters, id)
+	m.membership.removeUser(id)
 }
 
-func (m *managerImpl) Broadcast(msg persistence.Message) error {
-	users, err := m.userRepo.ListForRoom(context.Background(), msg.Room)
+func (m *managerImpl) Poll(
+	ctx context.Context, id uuid.UUID, cursor Cursor,
+) (PollResult, error) {
+	buffer, err := m.pollBufferFor(ctx, id)
 	if err != nil {
-		return errors.WrapCode(err, ErrBroadcastFailure)
-	}
-
-	ids := make([]uuid.UUID, 0, len(users))
-	for _, user := range users {
-		ids = append(ids, user.Id)
+		return PollResult{}, err
 	}
 
-	m.sendToMultiple(ids, msg)
-
-	return nil
+	return buffer.poll(ctx, cursor, m.poll.Timeout), nil
 }
 
-func (m *managerImpl) BroadcastExcept(id uuid.UUID, msg persistence.Message) error {
-	users, err := m.userRepo.ListForRoom(context.Background(), msg.Room)
+func (m *managerImpl) pollBufferFor(ctx context.Context, id uuid.UUID) (*pollBuffer, error) {
+	if buffer, ok, err := m.lookupPollBuffer(id); ok || err != nil {
+		return buffer, err
+	}
+
+	memberships, err := m.roomRepo.ListMemberships(ctx, []uuid.UUID{id})
 	if err != nil {
-		return errors.WrapCode(err, ErrBroadcastFailure)
+		return nil, errors.WrapCode(err, ErrMembershipFailure)
 	}
 
-	ids := make([]uuid.UUID, 0, len(users))
-	for _, user := range users {
-		if user.Id == id {
-			continue
-		}
+	m.lock.Lock()
+	defer m.lock.Unlock()
 
-		ids = append(ids, user.Id)
+	// Another request for the same user may have created the buffer.
+	if client, ok := m.clients[id]; ok {
+		return asPollBuffer(client)
 	}
 
-	m.sendToMultiple(ids, msg)
+	buffer := newPollBuffer(m.poll.BufferSize)
+	m.clients[id] = buffer
+	m.membership.addAll(memberships)
 
-	return nil
+	return buffer, nil
 }
 
-func (m *managerImpl) SendTo(id uuid.UUID, msg persistence.Message) {
+func (m *managerImpl) lookupPollBuffer(id uuid.UUID) (*pollBuffer, bool, error) {
 	m.lock.RLock()
 	defer m.lock.RUnlock()
 
 	client, ok := m.clients[id]
 	if !ok {
+		return nil, false, nil
+	}
+
+	buffer, err := asPollBuffer(client)
+	return buffer, true, err
+}
+
+func asPollBuffer(client Client) (*pollBuffer, error) {
+	buffer, ok := client.(*pollBuffer)
+	if !ok {
+		return nil, errors.NewCode(ErrClientAlreadyRegistered)
+	}
+
+	return buffer, nil
+}
+
+func (m *managerImpl) OnRegistration(user uuid.UUID, room uuid.UUID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// Users connecting later load their rooms from the database.
+	if _, ok := m.clients[user]; !ok {
 		return
 	}
 
-	client.Enqueue(msg)
+	m.membership.add(user, room)
+}
+
+func (m *managerImpl) OnUnregistration(user uuid.UUID, room uuid.UUID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.membership.remove(user, room)
+}
+
+func (m *managerImpl) OnRoomDeletion(room uuid.UUID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.membership.removeRoom(room)
 }
 
-func (m *managerImpl) sendToMultiple(ids []uuid.UUID, msg persistence.Message) {
+func (m *managerImpl) Broadcast(msg persistence.Message) error {
+	return m.BroadcastEvent(events.FromMessage(msg))
+}
+
+func (m *managerImpl) BroadcastEvent(event events.Event) error {
 	m.lock.RLock()
-	defer m.lock.RUnlock()
+	targets := m.targets(m.membership.members(event.Room), event)
+	m.lock.RUnlock()
+
+	m.enqueue(targets, event)
+
+	return nil
+}
 
-	for _, id := range ids {
+func (m *managerImpl) BroadcastExcept(id uuid.UUID, msg persistence.Message) error {
+	event := events.FromMessage(msg)
+
+	m.lock.RLock()
+	members := slices.DeleteFunc(m.membership.members(msg.Room), func(user uuid.UUID) bool {
+		return user == id
+	})
+	targets := m.targets(members, event)
+	m.lock.RUnlock()
+
+	m.enqueue(targets, event)
+
+	return nil
+}
+
+func (m *managerImpl) SendTo(id uuid.UUID, msg persistence.Message) {
+	event := events.FromMessage(msg)
+
+	m.lock.RLock()
+	targets := m.targets([]uuid.UUID{id}, event)
+	m.lock.RUnlock()
+
+	m.enqueue(targets, event)
+}
+
+// targets returns the clients of the users which accept the event. It
+// expects the lock to be held by the caller: the events are enqueued once
+// it is released, as the clients which can't keep up are disconnected.
+func (m *managerImpl) targets(users []uuid.UUID, event events.Event) map[uuid.UUID]Client {
+	out := make(map[uuid.UUID]Client, len(users))
+
+	for _, id := range users {
 		client, ok := m.clients[id]
 		if !ok {
 			continue
 		}
 
-		client.Enqueue(msg)
+		// The long-polling clients do not have a filter.
+		if filter, ok := m.filters[id]; ok && !filter.accepts(event) {
+			continue
+		}
+
+		out[id] = client
 	}
+
+	return out
+}
+
+// enqueue does not wait for the queues of the clients: a client whose
+// queue is full, because it can't keep up or was stopped, would block the
+// events of all the other ones. It is disconnected instead.
+func (m *managerImpl) enqueue(targets map[uuid.UUID]Client, event events.Event) {
+	for id, client := range targets {
+		if client.TryEnqueue(event) {
+			continue
+		}
+
+		if m.disconnect(id, client) {
+			// Stopping a running client sends the events of its queue,
+			// which can take a while on a slow connection.
+			go client.Stop()
+		}
+	}
+}
+
+// disconnect returns false when the user reconnected in the meantime: the
+// new client is kept.
+func (m *managerImpl) disconnect(id uuid.UUID, client Client) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.clients[id] != client {
+		return false
+	}
+
+	m.removeClient(id)
+	return true
+}
+
+func (m *managerImpl) reconcileUntilQuit() {
+	ticker := time.NewTicker(m.reconciliationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.quit:
+			return
+		case <-ticker.C:
+			m.evictIdlePollBuffers()
+			m.reconcile(context.Background())
+		}
+	}
+}
+
+// evictIdlePollBuffers drops the buffers of the users who did not poll
+// for longer than the retention. They are checked at each reconciliation
+// so a buffer can outlive the retention by up to one interval.
+func (m *managerImpl) evictIdlePollBuffers() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := time.Now()
+	for id, client := range m.clients {
+		buffer, ok := client.(*pollBuffer)
+		if !ok || !buffer.idle(now, m.poll.Retention) {
+			continue
+		}
+
+		buffer.Stop()
+		delete(m.clients, id)
+		delete(m.filters, id)
+		m.membership.removeUser(id)
+	}
+}
+
+// reconcile rebuilds the index from the database for the users connected
+// when it starts. Users connecting in the meantime already loaded their
+// rooms and keep them.
+func (m *managerImpl) reconcile(ctx context.Context) {
+	m.lock.RLock()
+	users := make([]uuid.UUID, 0, len(m.clients))
+	for id := range m.clients {
+		users = append(users, id)
+	}
+	m.lock.RUnlock()
+
+	if len(users) == 0 {
+		return
+	}
+
+	memberships, err := m.roomRepo.ListMemberships(ctx, users)
+	if err != nil {
+		// The index is kept as is until the next reconciliation.
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.membership = m.membership.reconcile(users, memberships, m.clients)
 }
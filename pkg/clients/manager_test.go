@@ -8,6 +8,8 @@ import (
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
 	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
 	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
 	"github.com/google/uuid"
@@ -19,10 +21,10 @@ func TestIT_Manager_WhenClientAlreadyRegistered_ExpectError(t *testing.T) {
 	defer dbConn.Close(context.Background())
 	id := uuid.New()
 
-	err := manager.OnConnect(id, nil)
+	err := manager.OnConnect(context.Background(), id, nil, Filter{})
 	assert.Nil(t, err, "Actual err: %v", err)
 
-	err = manager.OnConnect(id, nil)
+	err = manager.OnConnect(context.Background(), id, nil, Filter{})
 	assert.True(
 		t,
 		errors.IsErrorWithCode(err, ErrClientAlreadyRegistered),
@@ -37,7 +39,7 @@ func TestIT_Manager_WhenClosing_ExpectClientIsAlsoClosed(t *testing.T) {
 	id := uuid.New()
 	mock := &mockClient{}
 
-	err := manager.OnConnect(id, mock)
+	err := manager.OnConnect(context.Background(), id, mock, Filter{})
 	assert.Nil(t, err, "Actual err: %v", err)
 
 	asyncStartManagerAndAssertNoError(t, manager)
@@ -48,6 +50,42 @@ func TestIT_Manager_WhenClosing_ExpectClientIsAlsoClosed(t *testing.T) {
 	assert.Equal(t, 1, mock.stopCalled)
 }
 
+func TestIT_Manager_WhenClosing_ExpectShutdownEventSent(t *testing.T) {
+	manager, dbConn := newTestManager(t)
+	defer dbConn.Close(context.Background())
+	id := uuid.New()
+	mock := &mockClient{}
+
+	err := manager.OnConnect(context.Background(), id, mock, Filter{})
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	asyncStartManagerAndAssertNoError(t, manager)
+
+	err = manager.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Len(t, mock.enqueued, 1)
+	assert.Equal(t, events.Shutdown, mock.enqueued[0].Type)
+}
+
+func TestIT_Manager_WhenClosingAndClientQueueIsFull_ExpectClientClosed(t *testing.T) {
+	manager, dbConn := newTestManager(t)
+	defer dbConn.Close(context.Background())
+	id := uuid.New()
+	mock := &mockClient{full: true}
+
+	err := manager.OnConnect(context.Background(), id, mock, Filter{})
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	asyncStartManagerAndAssertNoError(t, manager)
+
+	err = manager.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Empty(t, mock.enqueued)
+	assert.Equal(t, 1, mock.stopCalled)
+}
+
 func TestIT_Manager_WhenUserInRoomAndBroadcast_ExpectMessageReceived(t *testing.T) {
 	manager, dbConn := newTestManager(t)
 	defer dbConn.Close(context.Background())
@@ -57,7 +95,7 @@ func TestIT_Manager_WhenUserInRoomAndBroadcast_ExpectMessageReceived(t *testing.
 	room := insertTestRoom(t, dbConn)
 	registerUserInRoom(t, dbConn, user1.Id, room.Id)
 
-	err := manager.OnConnect(user1.Id, mock)
+	err := manager.OnConnect(context.Background(), user1.Id, mock, Filter{})
 	assert.Nil(t, err, "Actual err: %v", err)
 
 	msg := persistence.Message{
@@ -70,10 +108,102 @@ func TestIT_Manager_WhenUserInRoomAndBroadcast_ExpectMessageReceived(t *testing.
 	manager.Broadcast(msg)
 
 	assert.Equal(t, 1, mock.enqueueCalled)
-	expected := []persistence.Message{msg}
+	expected := []events.Event{events.FromMessage(msg)}
 	assert.Equal(t, expected, mock.enqueued, 1)
 }
 
+func TestIT_Manager_WhenClientIsStoppedWithFullQueue_ExpectClientDisconnected(t *testing.T) {
+	manager, dbConn := newTestManager(t)
+	defer dbConn.Close(context.Background())
+	client := messages.NewProcessor(1, messages.Callbacks[events.Event]{})
+	err := client.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.True(t, client.TryEnqueue(events.FromShutdown()))
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	err = manager.OnConnect(context.Background(), user.Id, client, Filter{})
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	msg := persistence.Message{
+		Id:       uuid.New(),
+		ChatUser: uuid.New(),
+		Room:     room.Id,
+		Message:  "Hello",
+	}
+	broadcastDone := make(chan struct{})
+	go func() {
+		defer close(broadcastDone)
+		manager.Broadcast(msg)
+	}()
+
+	select {
+	case <-broadcastDone:
+	case <-time.After(time.Second):
+		assert.Fail(t, "Broadcast blocked by the client queue")
+	}
+
+	// The client is not registered anymore so the user can connect again.
+	err = manager.OnConnect(context.Background(), user.Id, &mockClient{}, Filter{})
+	assert.Nil(t, err, "Actual err: %v", err)
+}
+
+func TestUnit_Manager_Enqueue_WhenClientQueueIsFull_ExpectOnlyThisClientDisconnected(t *testing.T) {
+	manager := NewManager(time.Minute, PollPolicy{}, repositories.Repositories{}).(*managerImpl)
+	full := messages.NewProcessor(1, messages.Callbacks[events.Event]{})
+	err := full.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.True(t, full.TryEnqueue(events.FromShutdown()))
+	other := &mockClient{}
+
+	fullId, otherId := uuid.New(), uuid.New()
+	targets := map[uuid.UUID]Client{fullId: full, otherId: other}
+	manager.clients = map[uuid.UUID]Client{fullId: full, otherId: other}
+
+	manager.enqueue(targets, events.FromShutdown())
+
+	assert.Equal(t, map[uuid.UUID]Client{otherId: other}, manager.clients)
+	assert.Len(t, other.enqueued, 1)
+}
+
+func TestIT_Manager_WhenFilterExcludesRoom_ExpectMessageNotReceived(t *testing.T) {
+	manager, dbConn := newTestManager(t)
+	defer dbConn.Close(context.Background())
+	mock := &mockClient{}
+
+	user := insertTestUser(t, dbConn)
+	room1 := insertTestRoom(t, dbConn)
+	room2 := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room1.Id)
+	registerUserInRoom(t, dbConn, user.Id, room2.Id)
+
+	filter := Filter{Rooms: []uuid.UUID{room1.Id}}
+	err := manager.OnConnect(context.Background(), user.Id, mock, filter)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	msg1 := persistence.Message{
+		Id:        uuid.New(),
+		ChatUser:  uuid.New(),
+		Room:      room1.Id,
+		Message:   "Hello",
+		CreatedAt: time.Date(2025, 5, 5, 21, 44, 20, 0, time.UTC),
+	}
+	manager.Broadcast(msg1)
+	msg2 := persistence.Message{
+		Id:        uuid.New(),
+		ChatUser:  uuid.New(),
+		Room:      room2.Id,
+		Message:   "Hello",
+		CreatedAt: time.Date(2025, 5, 5, 21, 44, 20, 0, time.UTC),
+	}
+	manager.Broadcast(msg2)
+
+	expected := []events.Event{events.FromMessage(msg1)}
+	assert.Equal(t, expected, mock.enqueued)
+}
+
 func TestIT_Manager_WhenUserNotInRoomAndBroadcast_ExpectMessageNotReceived(t *testing.T) {
 	manager, dbConn := newTestManager(t)
 	defer dbConn.Close(context.Background())
@@ -82,7 +212,7 @@ func TestIT_Manager_WhenUserNotInRoomAndBroadcast_ExpectMessageNotReceived(t *te
 	user1 := insertTestUser(t, dbConn)
 	room := insertTestRoom(t, dbConn)
 
-	err := manager.OnConnect(user1.Id, mock)
+	err := manager.OnConnect(context.Background(), user1.Id, mock, Filter{})
 	assert.Nil(t, err, "Actual err: %v", err)
 
 	msg := persistence.Message{
@@ -106,7 +236,7 @@ func TestIT_Manager_WhenBroadcastAfterDisconnect_ExpectNoMessageReceived(t *test
 	room := insertTestRoom(t, dbConn)
 	registerUserInRoom(t, dbConn, user1.Id, room.Id)
 
-	err := manager.OnConnect(user1.Id, mock)
+	err := manager.OnConnect(context.Background(), user1.Id, mock, Filter{})
 	assert.Nil(t, err, "Actual err: %v", err)
 	manager.OnDisconnect(user1.Id)
 
@@ -134,9 +264,9 @@ func TestIT_Manager_WhenBroadcastExceptToClient_ExpectNoMessageReceived(t *testi
 	registerUserInRoom(t, dbConn, user1.Id, room.Id)
 	registerUserInRoom(t, dbConn, user2.Id, room.Id)
 
-	err := manager.OnConnect(user1.Id, mock1)
+	err := manager.OnConnect(context.Background(), user1.Id, mock1, Filter{})
 	assert.Nil(t, err, "Actual err: %v", err)
-	err = manager.OnConnect(user2.Id, mock2)
+	err = manager.OnConnect(context.Background(), user2.Id, mock2, Filter{})
 	assert.Nil(t, err, "Actual err: %v", err)
 
 	msg := persistence.Message{
@@ -149,7 +279,7 @@ func TestIT_Manager_WhenBroadcastExceptToClient_ExpectNoMessageReceived(t *testi
 	manager.BroadcastExcept(user2.Id, msg)
 
 	assert.Equal(t, 1, mock1.enqueueCalled)
-	expected := []persistence.Message{msg}
+	expected := []events.Event{events.FromMessage(msg)}
 	assert.Equal(t, expected, mock1.enqueued, 1)
 
 	assert.Equal(t, 0, mock2.enqueueCalled)
@@ -163,9 +293,9 @@ func TestIT_Manager_WhenSendingMessageToSpecificClient_ExpectMessageReceived(t *
 	clientId2 := uuid.New()
 	mock2 := &mockClient{}
 
-	err := manager.OnConnect(clientId1, mock1)
+	err := manager.OnConnect(context.Background(), clientId1, mock1, Filter{})
 	assert.Nil(t, err, "Actual err: %v", err)
-	err = manager.OnConnect(clientId2, mock2)
+	err = manager.OnConnect(context.Background(), clientId2, mock2, Filter{})
 	assert.Nil(t, err, "Actual err: %v", err)
 
 	msg := persistence.Message{
@@ -178,17 +308,224 @@ func TestIT_Manager_WhenSendingMessageToSpecificClient_ExpectMessageReceived(t *
 	manager.SendTo(clientId1, msg)
 
 	assert.Equal(t, 1, mock1.enqueueCalled)
-	expected := []persistence.Message{msg}
+	expected := []events.Event{events.FromMessage(msg)}
 	assert.Equal(t, expected, mock1.enqueued, 1)
 
 	assert.Equal(t, 0, mock2.enqueueCalled)
 }
 
+func TestIT_Manager_WhenUserRegisteredAfterConnecting_ExpectMessageReceived(t *testing.T) {
+	manager, dbConn := newTestManager(t)
+	defer dbConn.Close(context.Background())
+	mock := &mockClient{}
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+
+	err := manager.OnConnect(context.Background(), user.Id, mock, Filter{})
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	manager.OnRegistration(user.Id, room.Id)
+
+	msg := persistence.Message{Id: uuid.New(), Room: room.Id, Message: "Hello"}
+	manager.Broadcast(msg)
+
+	assert.Equal(t, 1, mock.enqueueCalled)
+}
+
+func TestIT_Manager_WhenUserUnregistered_ExpectMessageNotReceived(t *testing.T) {
+	manager, dbConn := newTestManager(t)
+	defer dbConn.Close(context.Background())
+	mock := &mockClient{}
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	err := manager.OnConnect(context.Background(), user.Id, mock, Filter{})
+	assert.Nil(t, err, "Actual err: %v", err)
+	manager.OnUnregistration(user.Id, room.Id)
+
+	msg := persistence.Message{Id: uuid.New(), Room: room.Id, Message: "Hello"}
+	manager.Broadcast(msg)
+
+	assert.Equal(t, 0, mock.enqueueCalled)
+}
+
+func TestIT_Manager_WhenRoomDeleted_ExpectMessageNotReceived(t *testing.T) {
+	manager, dbConn := newTestManager(t)
+	defer dbConn.Close(context.Background())
+	mock := &mockClient{}
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	err := manager.OnConnect(context.Background(), user.Id, mock, Filter{})
+	assert.Nil(t, err, "Actual err: %v", err)
+	manager.OnRoomDeletion(room.Id)
+
+	msg := persistence.Message{Id: uuid.New(), Room: room.Id, Message: "Hello"}
+	manager.Broadcast(msg)
+
+	assert.Equal(t, 0, mock.enqueueCalled)
+}
+
+func TestIT_Manager_WhenRegistrationMissed_ExpectReconciledWithDatabase(t *testing.T) {
+	dbConn := newTestDbConnection(t)
+	defer dbConn.Close(context.Background())
+	manager := NewManager(50*time.Millisecond, PollPolicy{}, repositories.New(dbConn))
+	mock := &mockClient{}
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+
+	err := manager.OnConnect(context.Background(), user.Id, mock, Filter{})
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	// No notification is sent for this registration.
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	wg := asyncStartManagerAndAssertNoError(t, manager)
+	time.Sleep(100 * time.Millisecond)
+
+	msg := persistence.Message{Id: uuid.New(), Room: room.Id, Message: "Hello"}
+	manager.Broadcast(msg)
+
+	err = manager.Stop()
+	wg.Wait()
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	// The message and the shutdown event.
+	assert.Equal(t, 2, mock.enqueueCalled)
+	assert.Equal(t, events.FromMessage(msg), mock.enqueued[0])
+}
+
+func TestIT_Manager_Poll_ExpectBroadcastEventsReturned(t *testing.T) {
+	dbConn := newTestDbConnection(t)
+	defer dbConn.Close(context.Background())
+	policy := PollPolicy{BufferSize: 10, Timeout: time.Second, Retention: time.Minute}
+	manager := NewManager(time.Minute, policy, repositories.New(dbConn))
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	msg := persistence.Message{Id: uuid.New(), Room: room.Id, Message: "Hello"}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		manager.Broadcast(msg)
+	}()
+
+	out, err := manager.Poll(context.Background(), user.Id, Cursor{})
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, int64(1), out.Cursor.Position)
+	assert.False(t, out.Resync)
+	assert.Equal(t, []events.Event{events.FromMessage(msg)}, out.Events)
+}
+
+func TestIT_Manager_Poll_WhenEventReceivedBetweenPolls_ExpectReturned(t *testing.T) {
+	dbConn := newTestDbConnection(t)
+	defer dbConn.Close(context.Background())
+	policy := PollPolicy{BufferSize: 10, Timeout: 50 * time.Millisecond, Retention: time.Minute}
+	manager := NewManager(time.Minute, policy, repositories.New(dbConn))
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	out, err := manager.Poll(context.Background(), user.Id, Cursor{})
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Empty(t, out.Events)
+
+	msg := persistence.Message{Id: uuid.New(), Room: room.Id, Message: "Hello"}
+	manager.Broadcast(msg)
+
+	out, err = manager.Poll(context.Background(), user.Id, out.Cursor)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, []events.Event{events.FromMessage(msg)}, out.Events)
+}
+
+func TestIT_Manager_Poll_WhenUserIsConnected_ExpectError(t *testing.T) {
+	manager, dbConn := newTestManager(t)
+	defer dbConn.Close(context.Background())
+	id := uuid.New()
+
+	err := manager.OnConnect(context.Background(), id, &mockClient{}, Filter{})
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	_, err = manager.Poll(context.Background(), id, Cursor{})
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrClientAlreadyRegistered),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_Manager_OnConnect_WhenUserIsPolling_ExpectBufferReplaced(t *testing.T) {
+	dbConn := newTestDbConnection(t)
+	defer dbConn.Close(context.Background())
+	policy := PollPolicy{BufferSize: 10, Timeout: time.Millisecond, Retention: time.Minute}
+	manager := NewManager(time.Minute, policy, repositories.New(dbConn))
+	mock := &mockClient{}
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	_, err := manager.Poll(context.Background(), user.Id, Cursor{})
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	err = manager.OnConnect(context.Background(), user.Id, mock, Filter{})
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	msg := persistence.Message{Id: uuid.New(), Room: room.Id, Message: "Hello"}
+	manager.Broadcast(msg)
+
+	assert.Equal(t, 1, mock.enqueueCalled)
+}
+
+func TestIT_Manager_WhenPollBufferIsIdle_ExpectEvicted(t *testing.T) {
+	dbConn := newTestDbConnection(t)
+	defer dbConn.Close(context.Background())
+	policy := PollPolicy{BufferSize: 10, Timeout: time.Millisecond, Retention: time.Millisecond}
+	manager := NewManager(50*time.Millisecond, policy, repositories.New(dbConn))
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	first, err := manager.Poll(context.Background(), user.Id, Cursor{})
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	msg := persistence.Message{Id: uuid.New(), Room: room.Id, Message: "Hello"}
+	manager.Broadcast(msg)
+
+	wg := asyncStartManagerAndAssertNoError(t, manager)
+	time.Sleep(100 * time.Millisecond)
+
+	// The event was dropped along with the buffer: the client is asked
+	// to resync.
+	out, err := manager.Poll(context.Background(), user.Id, first.Cursor)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Empty(t, out.Events)
+	assert.True(t, out.Resync)
+
+	err = manager.Stop()
+	wg.Wait()
+	assert.Nil(t, err, "Actual err: %v", err)
+}
+
 func newTestManager(t *testing.T) (Manager, db.Connection) {
 	dbConn := newTestDbConnection(t)
 	repos := repositories.New(dbConn)
 
-	manager := NewManager(repos)
+	manager := NewManager(time.Minute, PollPolicy{}, repos)
 
 	return manager, dbConn
 }
@@ -219,9 +556,10 @@ func asyncStartManagerAndAssertNoError(
 }
 
 type mockClient struct {
+	full          bool
 	stopCalled    int
 	enqueueCalled int
-	enqueued      []persistence.Message
+	enqueued      []events.Event
 }
 
 func (m *mockClient) Start() error {
@@ -233,7 +571,16 @@ func (m *mockClient) Stop() error {
 	return nil
 }
 
-func (m *mockClient) Enqueue(msg persistence.Message) {
+func (m *mockClient) Enqueue(event events.Event) {
 	m.enqueueCalled++
-	m.enqueued = append(m.enqueued, msg)
+	m.enqueued = append(m.enqueued, event)
+}
+
+func (m *mockClient) TryEnqueue(event events.Event) bool {
+	if m.full {
+		return false
+	}
+
+	m.Enqueue(event)
+	return true
 }
@@ -0,0 +1,97 @@
+package clients
+
+import (
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+// membership indexes the connected users by room. It is not safe for
+// concurrent use.
+type membership struct {
+	rooms map[uuid.UUID]map[uuid.UUID]struct{}
+}
+
+func newMembership() membership {
+	return membership{
+		rooms: make(map[uuid.UUID]map[uuid.UUID]struct{}),
+	}
+}
+
+func (m membership) add(user uuid.UUID, room uuid.UUID) {
+	members, ok := m.rooms[room]
+	if !ok {
+		members = make(map[uuid.UUID]struct{})
+		m.rooms[room] = members
+	}
+
+	members[user] = struct{}{}
+}
+
+func (m membership) addAll(memberships []persistence.Membership) {
+	for _, membership := range memberships {
+		m.add(membership.ChatUser, membership.Room)
+	}
+}
+
+func (m membership) remove(user uuid.UUID, room uuid.UUID) {
+	members, ok := m.rooms[room]
+	if !ok {
+		return
+	}
+
+	delete(members, user)
+	if len(members) == 0 {
+		delete(m.rooms, room)
+	}
+}
+
+func (m membership) removeUser(user uuid.UUID) {
+	for room := range m.rooms {
+		m.remove(user, room)
+	}
+}
+
+func (m membership) removeRoom(room uuid.UUID) {
+	delete(m.rooms, room)
+}
+
+func (m membership) members(room uuid.UUID) []uuid.UUID {
+	members := m.rooms[room]
+
+	out := make([]uuid.UUID, 0, len(members))
+	for user := range members {
+		out = append(out, user)
+	}
+
+	return out
+}
+
+// reconcile returns an index where the rooms of the users are replaced by
+// the memberships. Users who are not connected anymore are dropped.
+func (m membership) reconcile(
+	users []uuid.UUID,
+	memberships []persistence.Membership,
+	connected map[uuid.UUID]Client,
+) membership {
+	reconciled := make(map[uuid.UUID]struct{}, len(users))
+	for _, user := range users {
+		reconciled[user] = struct{}{}
+	}
+
+	out := newMembership()
+	for room, members := range m.rooms {
+		for user := range members {
+			if _, ok := reconciled[user]; !ok {
+				out.add(user, room)
+			}
+		}
+	}
+
+	for _, membership := range memberships {
+		if _, ok := connected[membership.ChatUser]; ok {
+			out.add(membership.ChatUser, membership.Room)
+		}
+	}
+
+	return out
+}
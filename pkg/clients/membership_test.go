@@ -0,0 +1,91 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_Membership_WhenUserAdded_ExpectMember(t *testing.T) {
+	m := newMembership()
+	user := uuid.New()
+	room := uuid.New()
+
+	m.add(user, room)
+
+	assert.Equal(t, []uuid.UUID{user}, m.members(room))
+}
+
+func TestUnit_Membership_WhenRoomIsUnknown_ExpectNoMember(t *testing.T) {
+	m := newMembership()
+
+	assert.Equal(t, []uuid.UUID{}, m.members(uuid.New()))
+}
+
+func TestUnit_Membership_WhenUserRemoved_ExpectOtherRoomsKept(t *testing.T) {
+	m := newMembership()
+	user := uuid.New()
+	room1 := uuid.New()
+	room2 := uuid.New()
+	m.add(user, room1)
+	m.add(user, room2)
+
+	m.remove(user, room1)
+
+	assert.Equal(t, []uuid.UUID{}, m.members(room1))
+	assert.Equal(t, []uuid.UUID{user}, m.members(room2))
+}
+
+func TestUnit_Membership_WhenUserRemovedFromAllRooms_ExpectNoMember(t *testing.T) {
+	m := newMembership()
+	user1 := uuid.New()
+	user2 := uuid.New()
+	room1 := uuid.New()
+	room2 := uuid.New()
+	m.add(user1, room1)
+	m.add(user1, room2)
+	m.add(user2, room2)
+
+	m.removeUser(user1)
+
+	assert.Equal(t, []uuid.UUID{}, m.members(room1))
+	assert.Equal(t, []uuid.UUID{user2}, m.members(room2))
+}
+
+func TestUnit_Membership_WhenRoomRemoved_ExpectNoMember(t *testing.T) {
+	m := newMembership()
+	user := uuid.New()
+	room := uuid.New()
+	m.add(user, room)
+
+	m.removeRoom(room)
+
+	assert.Equal(t, []uuid.UUID{}, m.members(room))
+}
+
+func TestUnit_Membership_Reconcile(t *testing.T) {
+	m := newMembership()
+	reconciled := uuid.New()
+	connectedLater := uuid.New()
+	disconnected := uuid.New()
+	room1 := uuid.New()
+	room2 := uuid.New()
+	m.add(reconciled, room1)
+	m.add(connectedLater, room1)
+
+	users := []uuid.UUID{reconciled, disconnected}
+	memberships := []persistence.Membership{
+		{Room: room2, ChatUser: reconciled},
+		{Room: room2, ChatUser: disconnected},
+	}
+	connected := map[uuid.UUID]Client{
+		reconciled:     nil,
+		connectedLater: nil,
+	}
+	actual := m.reconcile(users, memberships, connected)
+
+	assert.Equal(t, []uuid.UUID{connectedLater}, actual.members(room1))
+	assert.Equal(t, []uuid.UUID{reconciled}, actual.members(room2))
+}
@@ -0,0 +1,231 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/google/uuid"
+)
+
+// PollPolicy defines how the events of the long-polling clients are kept
+// between two requests. A request waits at most Timeout for new events
+// and the events of a user who did not poll for Retention are dropped.
+type PollPolicy struct {
+	BufferSize int
+	Timeout    time.Duration
+	Retention  time.Duration
+}
+
+// Cursor identifies the last event returned to a long-polling client. The
+// epoch identifies the buffer which produced it: positions are only
+// meaningful within a buffer.
+type Cursor struct {
+	Epoch    uuid.UUID
+	Position int64
+}
+
+// ParseCursor reads a cursor produced by Cursor.String. An empty string
+// is the cursor of a client which did not poll yet.
+func ParseCursor(maybeCursor string) (Cursor, error) {
+	if maybeCursor == "" {
+		return Cursor{}, nil
+	}
+
+	maybeEpoch, maybePosition, ok := strings.Cut(maybeCursor, ":")
+	if !ok {
+		return Cursor{}, errors.NewCode(ErrInvalidCursor)
+	}
+
+	epoch, err := uuid.Parse(maybeEpoch)
+	if err != nil {
+		return Cursor{}, errors.WrapCode(err, ErrInvalidCursor)
+	}
+	position, err := strconv.ParseInt(maybePosition, 10, 64)
+	if err != nil || position < 0 {
+		return Cursor{}, errors.NewCode(ErrInvalidCursor)
+	}
+
+	return Cursor{Epoch: epoch, Position: position}, nil
+}
+
+func (c Cursor) String() string {
+	return fmt.Sprintf("%s:%d", c.Epoch, c.Position)
+}
+
+type PollResult struct {
+	// Cursor should be sent back in the next request to only receive the
+	// events which were not returned yet.
+	Cursor Cursor
+	// Resync is set when events may have been missed since the cursor:
+	// it was produced by another buffer or the buffer dropped events
+	// before they were polled. The client should then fetch what it
+	// missed from the message history.
+	Resync bool
+	Events []events.Event
+}
+
+type bufferedEvent struct {
+	cursor int64
+	event  events.Event
+}
+
+// pollBuffer keeps the last events received for a user until they are
+// polled. It is registered in the manager like any other client.
+type pollBuffer struct {
+	lock   sync.Mutex
+	size   int
+	epoch  uuid.UUID
+	events []bufferedEvent
+	next   int64
+	closed bool
+	// wakeUp is closed and replaced each time an event is received.
+	wakeUp chan struct{}
+
+	waiting  int
+	lastPoll time.Time
+}
+
+func newPollBuffer(size int) *pollBuffer {
+	return &pollBuffer{
+		size:     size,
+		epoch:    uuid.New(),
+		next:     1,
+		wakeUp:   make(chan struct{}),
+		lastPoll: time.Now(),
+	}
+}
+
+func (b *pollBuffer) Start() error {
+	return nil
+}
+
+func (b *pollBuffer) Stop() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if !b.closed {
+		b.closed = true
+		close(b.wakeUp)
+	}
+
+	return nil
+}
+
+func (b *pollBuffer) Enqueue(event events.Event) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.events = append(b.events, bufferedEvent{cursor: b.next, event: event})
+	b.next++
+	if len(b.events) > b.size {
+		b.events = append([]bufferedEvent(nil), b.events[len(b.events)-b.size:]...)
+	}
+
+	close(b.wakeUp)
+	b.wakeUp = make(chan struct{})
+}
+
+// TryEnqueue never waits as the buffer drops its oldest events when full.
+func (b *pollBuffer) TryEnqueue(event events.Event) bool {
+	b.Enqueue(event)
+	return true
+}
+
+// poll returns the events received after the cursor. When there are none
+// it waits for new ones until the timeout expires.
+func (b *pollBuffer) poll(ctx context.Context, cursor Cursor, timeout time.Duration) PollResult {
+	b.startPolling()
+	defer b.stopPolling()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		out, wakeUp, done := b.since(cursor)
+		if done {
+			return out
+		}
+
+		select {
+		case <-ctx.Done():
+			return out
+		case <-timer.C:
+			return out
+		case <-wakeUp:
+		}
+	}
+}
+
+func (b *pollBuffer) since(cursor Cursor) (PollResult, <-chan struct{}, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	out := PollResult{
+		Cursor: Cursor{Epoch: b.epoch, Position: b.next - 1},
+	}
+
+	position := cursor.Position
+	if cursor != (Cursor{}) {
+		// A cursor from a previous buffer for the user, from another
+		// instance or which was not yet produced: all the events are
+		// returned but some may have been missed.
+		if cursor.Epoch != b.epoch || position >= b.next {
+			position = 0
+			out.Resync = true
+		}
+
+		// Events following the cursor were dropped when the buffer was full.
+		if position < b.oldest()-1 {
+			out.Resync = true
+		}
+	}
+
+	for _, buffered := range b.events {
+		if buffered.cursor > position {
+			out.Events = append(out.Events, buffered.event)
+		}
+	}
+
+	done := len(out.Events) > 0 || out.Resync || b.closed
+	return out, b.wakeUp, done
+}
+
+// oldest returns the position of the oldest event still in the buffer.
+func (b *pollBuffer) oldest() int64 {
+	if len(b.events) == 0 {
+		return b.next
+	}
+	return b.events[0].cursor
+}
+
+func (b *pollBuffer) startPolling() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.waiting++
+}
+
+func (b *pollBuffer) stopPolling() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.waiting--
+	b.lastPoll = time.Now()
+}
+
+func (b *pollBuffer) idle(now time.Time, retention time.Duration) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.waiting == 0 && now.Sub(b.lastPoll) > retention
+}
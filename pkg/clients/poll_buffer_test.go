@@ -0,0 +1,214 @@
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_ParseCursor(t *testing.T) {
+	expected := Cursor{Epoch: uuid.New(), Position: 12}
+
+	actual, err := ParseCursor(expected.String())
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestUnit_ParseCursor_WhenEmpty_ExpectZeroCursor(t *testing.T) {
+	actual, err := ParseCursor("")
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, Cursor{}, actual)
+}
+
+func TestUnit_ParseCursor_WhenInvalid_ExpectError(t *testing.T) {
+	for _, cursor := range []string{"12", "not-a-uuid:12", uuid.NewString() + ":-1", uuid.NewString() + ":a"} {
+		_, err := ParseCursor(cursor)
+
+		assert.True(
+			t,
+			errors.IsErrorWithCode(err, ErrInvalidCursor),
+			"Cursor: %s, actual err: %v",
+			cursor,
+			err,
+		)
+	}
+}
+
+func TestUnit_PollBuffer_ReturnsEventsAfterCursor(t *testing.T) {
+	buffer := newPollBuffer(10)
+	first := events.FromShutdown()
+	second := events.FromShutdown()
+	buffer.Enqueue(first)
+	buffer.Enqueue(second)
+
+	out := buffer.poll(context.Background(), cursorAt(buffer, 1), time.Second)
+
+	expected := PollResult{
+		Cursor: cursorAt(buffer, 2),
+		Events: []events.Event{second},
+	}
+	assert.Equal(t, expected, out)
+}
+
+func TestUnit_PollBuffer_WhenNoEvents_ExpectWaitsForTimeout(t *testing.T) {
+	buffer := newPollBuffer(10)
+
+	start := time.Now()
+	out := buffer.poll(context.Background(), Cursor{}, 50*time.Millisecond)
+
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, PollResult{Cursor: cursorAt(buffer, 0)}, out)
+}
+
+func TestUnit_PollBuffer_WhenEventReceivedWhileWaiting_ExpectReturned(t *testing.T) {
+	buffer := newPollBuffer(10)
+	event := events.FromShutdown()
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		buffer.Enqueue(event)
+	}()
+
+	start := time.Now()
+	out := buffer.poll(context.Background(), Cursor{}, time.Second)
+
+	assert.Less(t, time.Since(start), time.Second)
+	expected := PollResult{
+		Cursor: cursorAt(buffer, 1),
+		Events: []events.Event{event},
+	}
+	assert.Equal(t, expected, out)
+}
+
+func TestUnit_PollBuffer_WhenContextIsCancelled_ExpectReturns(t *testing.T) {
+	buffer := newPollBuffer(10)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	out := buffer.poll(ctx, Cursor{}, time.Second)
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, PollResult{Cursor: cursorAt(buffer, 0)}, out)
+}
+
+func TestUnit_PollBuffer_WhenFull_ExpectOldestEventsDropped(t *testing.T) {
+	buffer := newPollBuffer(2)
+	buffer.Enqueue(events.FromShutdown())
+	second := events.FromShutdown()
+	third := events.FromShutdown()
+	buffer.Enqueue(second)
+	buffer.Enqueue(third)
+
+	out := buffer.poll(context.Background(), Cursor{}, time.Second)
+
+	expected := PollResult{
+		Cursor: cursorAt(buffer, 3),
+		Events: []events.Event{second, third},
+	}
+	assert.Equal(t, expected, out)
+}
+
+func TestUnit_PollBuffer_WhenEventsDroppedAfterCursor_ExpectResync(t *testing.T) {
+	buffer := newPollBuffer(2)
+	buffer.Enqueue(events.FromShutdown())
+	second := events.FromShutdown()
+	third := events.FromShutdown()
+	buffer.Enqueue(second)
+	buffer.Enqueue(third)
+
+	out := buffer.poll(context.Background(), cursorAt(buffer, 0), time.Second)
+
+	expected := PollResult{
+		Cursor: cursorAt(buffer, 3),
+		Resync: true,
+		Events: []events.Event{second, third},
+	}
+	assert.Equal(t, expected, out)
+}
+
+func TestUnit_PollBuffer_WhenCursorIsFromAnotherBuffer_ExpectAllEventsReturnedWithResync(t *testing.T) {
+	buffer := newPollBuffer(10)
+	event := events.FromShutdown()
+	buffer.Enqueue(event)
+
+	out := buffer.poll(context.Background(), Cursor{Epoch: uuid.New(), Position: 1}, time.Second)
+
+	expected := PollResult{
+		Cursor: cursorAt(buffer, 1),
+		Resync: true,
+		Events: []events.Event{event},
+	}
+	assert.Equal(t, expected, out)
+}
+
+func TestUnit_PollBuffer_WhenCursorIsUnknown_ExpectAllEventsReturnedWithResync(t *testing.T) {
+	buffer := newPollBuffer(10)
+	event := events.FromShutdown()
+	buffer.Enqueue(event)
+
+	out := buffer.poll(context.Background(), cursorAt(buffer, 57), time.Second)
+
+	expected := PollResult{
+		Cursor: cursorAt(buffer, 1),
+		Resync: true,
+		Events: []events.Event{event},
+	}
+	assert.Equal(t, expected, out)
+}
+
+func TestUnit_PollBuffer_WhenResyncIsNeeded_ExpectReturnsWithoutWaiting(t *testing.T) {
+	buffer := newPollBuffer(10)
+
+	start := time.Now()
+	out := buffer.poll(context.Background(), Cursor{Epoch: uuid.New(), Position: 3}, time.Second)
+
+	assert.Less(t, time.Since(start), time.Second)
+	expected := PollResult{
+		Cursor: cursorAt(buffer, 0),
+		Resync: true,
+	}
+	assert.Equal(t, expected, out)
+}
+
+func TestUnit_PollBuffer_WhenStopped_ExpectWaitingPollReturns(t *testing.T) {
+	buffer := newPollBuffer(10)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		buffer.Stop()
+	}()
+
+	start := time.Now()
+	out := buffer.poll(context.Background(), Cursor{}, time.Second)
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, PollResult{Cursor: cursorAt(buffer, 0)}, out)
+}
+
+func TestUnit_PollBuffer_Idle(t *testing.T) {
+	buffer := newPollBuffer(10)
+
+	assert.False(t, buffer.idle(time.Now(), time.Minute))
+	assert.True(t, buffer.idle(time.Now().Add(2*time.Minute), time.Minute))
+}
+
+func TestUnit_PollBuffer_WhenPolling_ExpectNotIdle(t *testing.T) {
+	buffer := newPollBuffer(10)
+
+	go buffer.poll(context.Background(), Cursor{}, time.Second)
+	time.Sleep(50 * time.Millisecond)
+
+	assert.False(t, buffer.idle(time.Now().Add(2*time.Minute), time.Minute))
+}
+
+func cursorAt(buffer *pollBuffer, position int64) Cursor {
+	return Cursor{Epoch: buffer.epoch, Position: position}
+}
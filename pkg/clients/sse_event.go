@@ -2,13 +2,13 @@ package clients
 
 import (
 	"bytes"
-	"encoding/json"
+	"encoding/base64"
 	"fmt"
 	"net/http"
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
-	"github.com/Knoblauchpilze/chat-server/pkg/communication"
-	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/encoders"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
 )
 
 // https://echo.labstack.com/docs/cookbook/sse#event-structure-and-marshal-method
@@ -20,19 +20,28 @@ type sseEvent struct {
 	Comment []byte
 }
 
-func fromMessage(msg persistence.Message) (sseEvent, error) {
-	out := communication.ToMessageDtoResponse(msg)
-
-	data, err := json.Marshal(out)
+func fromEvent(event events.Event, encoder encoders.Encoder) (sseEvent, error) {
+	data, err := encoder.Marshal(event.Data)
 	if err != nil {
 		return sseEvent{}, err
 	}
 
+	// The stream only carries text.
+	if encoder.Binary {
+		data = []byte(base64.StdEncoding.EncodeToString(data))
+	}
+
 	e := sseEvent{
-		Id:   []byte(msg.Id.String()),
+		Id:   []byte(event.Id.String()),
 		Data: data,
 	}
 
+	// Messages are sent without an event type so that they are handled
+	// by the default `onmessage` handler of the clients.
+	if event.Type != events.Message {
+		e.Event = []byte(event.Type)
+	}
+
 	return e, nil
 }
 
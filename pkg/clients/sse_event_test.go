@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +11,8 @@ import (
 	"time"
 
 	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/encoders"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
 	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -82,7 +85,7 @@ data: line 2
 	)
 }
 
-func TestUnit_SseEvent_FromMessage(t *testing.T) {
+func TestUnit_SseEvent_FromEvent_Message(t *testing.T) {
 	msg := persistence.Message{
 		Id:        uuid.New(),
 		ChatUser:  uuid.New(),
@@ -91,7 +94,7 @@ func TestUnit_SseEvent_FromMessage(t *testing.T) {
 		CreatedAt: time.Date(2025, 5, 4, 17, 54, 40, 0, time.UTC),
 	}
 
-	e, err := fromMessage(msg)
+	e, err := fromEvent(events.FromMessage(msg), encoders.Json)
 	assert.Nil(t, err, "Actual err: %v", err)
 
 	response := communication.ToMessageDtoResponse(msg)
@@ -100,6 +103,44 @@ func TestUnit_SseEvent_FromMessage(t *testing.T) {
 	assert.Equal(t, expected, e.Data)
 }
 
+func TestUnit_SseEvent_FromEvent_Pin_SetsEventType(t *testing.T) {
+	unpin := communication.UnpinDtoResponse{
+		Room:       uuid.New(),
+		Message:    uuid.New(),
+		UnpinnedBy: uuid.New(),
+	}
+	event := events.FromUnpin(unpin)
+
+	e, err := fromEvent(event, encoders.Json)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	expected, err := json.Marshal(unpin)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, expected, e.Data)
+	assert.Equal(t, []byte(event.Id.String()), e.Id)
+	assert.Equal(t, []byte("unpin"), e.Event)
+}
+
+func TestUnit_SseEvent_FromEvent_WhenEncodingIsBinary_ExpectBase64Data(t *testing.T) {
+	msg := persistence.Message{
+		Id:        uuid.New(),
+		ChatUser:  uuid.New(),
+		Room:      uuid.New(),
+		Message:   "my-message",
+		CreatedAt: time.Date(2025, 5, 4, 17, 54, 40, 0, time.UTC),
+	}
+
+	e, err := fromEvent(events.FromMessage(msg), encoders.MsgPack)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	response := communication.ToMessageDtoResponse(msg)
+	expected, err := encoders.MsgPack.Marshal(response)
+	assert.Nil(t, err, "Actual err: %v", err)
+	actual, err := base64.StdEncoding.DecodeString(string(e.Data))
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, expected, actual)
+}
+
 func TestUnit_SseEvent_WriteMessage(t *testing.T) {
 	msg := persistence.Message{
 		Id:        uuid.MustParse("3cdfb4ea-d372-443e-92c0-6eea2f7cd2f0"),
@@ -109,7 +150,7 @@ func TestUnit_SseEvent_WriteMessage(t *testing.T) {
 		CreatedAt: time.Date(2025, 5, 4, 17, 56, 45, 0, time.UTC),
 	}
 
-	e, err := fromMessage(msg)
+	e, err := fromEvent(events.FromMessage(msg), encoders.Json)
 	assert.Nil(t, err, "Actual err: %v", err)
 
 	rec := httptest.NewRecorder()
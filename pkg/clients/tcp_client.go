@@ -0,0 +1,42 @@
+package clients
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+	"github.com/google/uuid"
+)
+
+// NewTcp sends the events to the client as JSON objects, one per line.
+// The writer should be safe for concurrent use if something else than
+// the client writes to it.
+func NewTcp(
+	messageQueueSize int,
+	_ uuid.UUID,
+	w io.Writer,
+) Client {
+	callbacks := messages.Callbacks[events.Event]{
+		Message: generateTcpMessageCallback(w),
+	}
+
+	return messages.NewProcessor(messageQueueSize, callbacks)
+}
+
+func generateTcpMessageCallback(w io.Writer) messages.MessageCallback[events.Event] {
+	return func(event events.Event) error {
+		payload, err := json.Marshal(toJsonEvent(event))
+		if err != nil {
+			return errors.WrapCode(err, ErrTcpWriteFailed)
+		}
+
+		_, err = w.Write(append(payload, '\n'))
+		if err != nil {
+			return errors.WrapCode(err, ErrTcpWriteFailed)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,40 @@
+package clients
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_TcpClient_SendsMessageAsJsonLine(t *testing.T) {
+	var out bytes.Buffer
+	client := NewTcp(1, uuid.New(), &out)
+
+	wg := asyncStartClientAndAssertNoError(t, client)
+
+	msg := persistence.Message{
+		Id:        uuid.MustParse("8f102c70-8eba-4094-bd4d-7f70d71b21f2"),
+		ChatUser:  uuid.MustParse("f2d9ce22-179d-431c-b63d-43d5a8ab5e18"),
+		Room:      uuid.MustParse("111838db-a871-47be-9149-c974fd356316"),
+		Message:   "Hello",
+		Sequence:  3,
+		CreatedAt: time.Date(2025, 5, 4, 20, 56, 16, 0, time.UTC),
+	}
+	client.Enqueue(events.FromMessage(msg))
+
+	// Wait for the message to be sent
+	time.Sleep(50 * time.Millisecond)
+
+	err := client.Stop()
+	wg.Wait()
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	expected := `{"id":"8f102c70-8eba-4094-bd4d-7f70d71b21f2","type":"message","data":{"id":"8f102c70-8eba-4094-bd4d-7f70d71b21f2","user":"f2d9ce22-179d-431c-b63d-43d5a8ab5e18","room":"111838db-a871-47be-9149-c974fd356316","message":"Hello","sequence":3,"created_at":"2025-05-04T20:56:16Z"}}
+`
+	assert.Equal(t, expected, out.String())
+}
@@ -0,0 +1,65 @@
+package clients
+
+import (
+	"context"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/encoders"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+)
+
+const webSocketWriteTimeout = 5 * time.Second
+
+// NewWebSocket sends the events to the client as text frames, or binary
+// frames for the binary encodings. The frames received from the client
+// are not handled by the client.
+func NewWebSocket(
+	messageQueueSize int,
+	_ uuid.UUID,
+	conn *websocket.Conn,
+	encoder encoders.Encoder,
+) Client {
+	callbacks := messages.Callbacks[events.Event]{
+		Message: NewWebSocketCallback(conn, encoder),
+	}
+
+	return messages.NewProcessor(messageQueueSize, callbacks)
+}
+
+// NewWebSocketCallback writes each event to the connection, for the
+// transports which serve the events through a callback.
+func NewWebSocketCallback(
+	conn *websocket.Conn, encoder encoders.Encoder,
+) messages.MessageCallback[events.Event] {
+	frameType := WebSocketFrameType(encoder)
+
+	return func(event events.Event) error {
+		payload, err := encoder.Marshal(toJsonEvent(event))
+		if err != nil {
+			return errors.WrapCode(err, ErrWebSocketFailed)
+		}
+
+		// Writing is safe while other frames are sent to the client, for
+		// example the replies to the frames it sent.
+		ctx, cancel := context.WithTimeout(context.Background(), webSocketWriteTimeout)
+		defer cancel()
+
+		err = conn.Write(ctx, frameType, payload)
+		if err != nil {
+			return errors.WrapCode(err, ErrWebSocketFailed)
+		}
+
+		return nil
+	}
+}
+
+func WebSocketFrameType(encoder encoders.Encoder) websocket.MessageType {
+	if encoder.Binary {
+		return websocket.MessageBinary
+	}
+	return websocket.MessageText
+}
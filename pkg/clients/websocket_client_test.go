@@ -0,0 +1,103 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/encoders"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/coder/websocket"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_WebSocketClient_SendsMessageAsTextFrame(t *testing.T) {
+	conns := make(chan *websocket.Conn, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		conn, err := websocket.Accept(rw, req, nil)
+		assert.Nil(t, err, "Actual err: %v", err)
+		conns <- conn
+		<-req.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	peer, _, err := websocket.Dial(ctx, url, nil)
+	assert.Nil(t, err, "Actual err: %v", err)
+	defer peer.CloseNow()
+
+	conn := <-conns
+	defer conn.CloseNow()
+
+	client := NewWebSocket(1, uuid.New(), conn, encoders.Json)
+	wg := asyncStartClientAndAssertNoError(t, client)
+
+	msg := persistence.Message{
+		Id:        uuid.MustParse("8f102c70-8eba-4094-bd4d-7f70d71b21f2"),
+		ChatUser:  uuid.MustParse("f2d9ce22-179d-431c-b63d-43d5a8ab5e18"),
+		Room:      uuid.MustParse("111838db-a871-47be-9149-c974fd356316"),
+		Message:   "Hello",
+		Sequence:  3,
+		CreatedAt: time.Date(2025, 5, 4, 20, 56, 16, 0, time.UTC),
+	}
+	client.Enqueue(events.FromMessage(msg))
+
+	kind, actual, err := peer.Read(ctx)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, websocket.MessageText, kind)
+	expected := `{"id":"8f102c70-8eba-4094-bd4d-7f70d71b21f2","type":"message","data":{"id":"8f102c70-8eba-4094-bd4d-7f70d71b21f2","user":"f2d9ce22-179d-431c-b63d-43d5a8ab5e18","room":"111838db-a871-47be-9149-c974fd356316","message":"Hello","sequence":3,"created_at":"2025-05-04T20:56:16Z"}}`
+	assert.Equal(t, expected, string(actual))
+
+	err = client.Stop()
+	wg.Wait()
+	assert.Nil(t, err, "Actual err: %v", err)
+}
+
+func TestUnit_WebSocketClient_WhenEncodingIsBinary_ExpectBinaryFrame(t *testing.T) {
+	conns := make(chan *websocket.Conn, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		conn, err := websocket.Accept(rw, req, nil)
+		assert.Nil(t, err, "Actual err: %v", err)
+		conns <- conn
+		<-req.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	peer, _, err := websocket.Dial(ctx, url, nil)
+	assert.Nil(t, err, "Actual err: %v", err)
+	defer peer.CloseNow()
+
+	conn := <-conns
+	defer conn.CloseNow()
+
+	client := NewWebSocket(1, uuid.New(), conn, encoders.Cbor)
+	wg := asyncStartClientAndAssertNoError(t, client)
+
+	event := events.FromShutdown()
+	client.Enqueue(event)
+
+	kind, actual, err := peer.Read(ctx)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, websocket.MessageBinary, kind)
+	expected, err := encoders.Cbor.Marshal(toJsonEvent(event))
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, expected, actual)
+
+	err = client.Stop()
+	wg.Wait()
+	assert.Nil(t, err, "Actual err: %v", err)
+}
@@ -0,0 +1,58 @@
+package communication
+
+import (
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type AttachmentDtoRequest struct {
+	User uuid.UUID
+	Room uuid.UUID
+	Name string
+}
+
+type AttachmentDtoResponse struct {
+	Id       uuid.UUID `json:"id"`
+	User     uuid.UUID `json:"user"`
+	Room     uuid.UUID `json:"room"`
+	Name     string    `json:"name"`
+	MimeType string    `json:"mime_type"`
+	Size     int64     `json:"size"`
+
+	Width        *int `json:"width,omitempty"`
+	Height       *int `json:"height,omitempty"`
+	HasThumbnail bool `json:"has_thumbnail,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func FromAttachmentDtoRequest(attachment AttachmentDtoRequest) persistence.Attachment {
+	t := time.Now().UTC()
+	return persistence.Attachment{
+		Id:       uuid.New(),
+		ChatUser: attachment.User,
+		Room:     attachment.Room,
+		Name:     attachment.Name,
+
+		CreatedAt: t,
+	}
+}
+
+func ToAttachmentDtoResponse(attachment persistence.Attachment) AttachmentDtoResponse {
+	return AttachmentDtoResponse{
+		Id:       attachment.Id,
+		User:     attachment.ChatUser,
+		Room:     attachment.Room,
+		Name:     attachment.Name,
+		MimeType: attachment.MimeType,
+		Size:     attachment.Size,
+
+		Width:        attachment.Width,
+		Height:       attachment.Height,
+		HasThumbnail: attachment.Thumbnail != nil,
+
+		CreatedAt: attachment.CreatedAt,
+	}
+}
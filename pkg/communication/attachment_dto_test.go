@@ -0,0 +1,131 @@
+package communication
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_FromAttachmentDtoRequest(t *testing.T) {
+	beforeConversion := time.Now()
+
+	dto := AttachmentDtoRequest{
+		User: uuid.New(),
+		Room: uuid.New(),
+		Name: "my-file.png",
+	}
+
+	actual := FromAttachmentDtoRequest(dto)
+
+	assert.Equal(t, dto.User, actual.ChatUser)
+	assert.Equal(t, dto.Room, actual.Room)
+	assert.Equal(t, dto.Name, actual.Name)
+	assert.Nil(t, actual.Message)
+	assert.True(t, actual.CreatedAt.After(beforeConversion))
+}
+
+func TestUnit_AttachmentDtoResponse_MarshalsToCamelCase(t *testing.T) {
+	dto := AttachmentDtoResponse{
+		Id:        uuid.MustParse("0b3c4d6e-1e51-4a44-9a1e-3f0f0a4b5c6d"),
+		User:      uuid.MustParse("e1c24e7d-b7b2-4118-85ac-d724b8e889dd"),
+		Room:      uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+		Name:      "my-file.png",
+		MimeType:  "image/png",
+		Size:      1234,
+		CreatedAt: someTime,
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"id": "0b3c4d6e-1e51-4a44-9a1e-3f0f0a4b5c6d",
+		"user": "e1c24e7d-b7b2-4118-85ac-d724b8e889dd",
+		"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+		"name": "my-file.png",
+		"mime_type": "image/png",
+		"size": 1234,
+		"created_at": "2024-11-12T19:09:36Z"
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
+
+func TestUnit_ToAttachmentDtoResponse(t *testing.T) {
+	entity := persistence.Attachment{
+		Id:       uuid.New(),
+		ChatUser: uuid.New(),
+		Room:     uuid.New(),
+		Name:     "my-file.png",
+		MimeType: "image/png",
+		Size:     1234,
+
+		CreatedAt: someTime,
+	}
+
+	actual := ToAttachmentDtoResponse(entity)
+
+	assert.Equal(t, entity.Id, actual.Id)
+	assert.Equal(t, entity.ChatUser, actual.User)
+	assert.Equal(t, entity.Room, actual.Room)
+	assert.Equal(t, entity.Name, actual.Name)
+	assert.Equal(t, entity.MimeType, actual.MimeType)
+	assert.Equal(t, entity.Size, actual.Size)
+	assert.Equal(t, someTime, actual.CreatedAt)
+}
+
+func TestUnit_ToAttachmentDtoResponse_WithThumbnail(t *testing.T) {
+	width, height, thumbnail := 640, 480, "my-thumbnail"
+	entity := persistence.Attachment{
+		Id:        uuid.New(),
+		Name:      "my-file.png",
+		MimeType:  "image/png",
+		Width:     &width,
+		Height:    &height,
+		Thumbnail: &thumbnail,
+	}
+
+	actual := ToAttachmentDtoResponse(entity)
+
+	assert.Equal(t, &width, actual.Width)
+	assert.Equal(t, &height, actual.Height)
+	assert.True(t, actual.HasThumbnail)
+}
+
+func TestUnit_AttachmentDtoResponse_WithThumbnail_MarshalsDimensions(t *testing.T) {
+	width, height := 640, 480
+	dto := AttachmentDtoResponse{
+		Id:           uuid.MustParse("0b3c4d6e-1e51-4a44-9a1e-3f0f0a4b5c6d"),
+		User:         uuid.MustParse("e1c24e7d-b7b2-4118-85ac-d724b8e889dd"),
+		Room:         uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+		Name:         "my-file.png",
+		MimeType:     "image/png",
+		Size:         1234,
+		Width:        &width,
+		Height:       &height,
+		HasThumbnail: true,
+		CreatedAt:    someTime,
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"id": "0b3c4d6e-1e51-4a44-9a1e-3f0f0a4b5c6d",
+		"user": "e1c24e7d-b7b2-4118-85ac-d724b8e889dd",
+		"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+		"name": "my-file.png",
+		"mime_type": "image/png",
+		"size": 1234,
+		"width": 640,
+		"height": 480,
+		"has_thumbnail": true,
+		"created_at": "2024-11-12T19:09:36Z"
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
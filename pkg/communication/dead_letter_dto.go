@@ -0,0 +1,36 @@
+package communication
+
+import (
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type DeadLetterDtoResponse struct {
+	Id          uuid.UUID   `json:"id"`
+	User        uuid.UUID   `json:"user"`
+	Room        uuid.UUID   `json:"room"`
+	Message     string      `json:"message"`
+	Attachments []uuid.UUID `json:"attachments,omitempty"`
+	ExpiresAt   *time.Time  `json:"expires_at,omitempty"`
+	Reason      string      `json:"reason"`
+	Attempts    int         `json:"attempts"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ToDeadLetterDtoResponse(letter persistence.DeadLetter) DeadLetterDtoResponse {
+	return DeadLetterDtoResponse{
+		Id:          letter.Id,
+		User:        letter.ChatUser,
+		Room:        letter.Room,
+		Message:     letter.Message,
+		Attachments: letter.Attachments,
+		ExpiresAt:   letter.ExpiresAt,
+		Reason:      letter.Reason,
+		Attempts:    letter.Attempts,
+
+		CreatedAt: letter.CreatedAt,
+	}
+}
@@ -0,0 +1,66 @@
+package communication
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_DeadLetterDtoResponse_MarshalsToCamelCase(t *testing.T) {
+	dto := DeadLetterDtoResponse{
+		Id:          uuid.MustParse("a590b448-d3cd-4dbc-a9e3-8d642b1a5814"),
+		User:        uuid.MustParse("e1c24e7d-b7b2-4118-85ac-d724b8e889dd"),
+		Room:        uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+		Message:     "my-message",
+		Attachments: []uuid.UUID{uuid.MustParse("0a4b6f5b-2a9f-4e57-8d8b-4d1f0cb1e0a1")},
+		Reason:      "some reason",
+		Attempts:    3,
+		CreatedAt:   someTime,
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"id": "a590b448-d3cd-4dbc-a9e3-8d642b1a5814",
+		"user": "e1c24e7d-b7b2-4118-85ac-d724b8e889dd",
+		"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+		"message": "my-message",
+		"attachments": ["0a4b6f5b-2a9f-4e57-8d8b-4d1f0cb1e0a1"],
+		"reason": "some reason",
+		"attempts": 3,
+		"created_at": "2024-11-12T19:09:36Z"
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
+
+func TestUnit_ToDeadLetterDtoResponse(t *testing.T) {
+	entity := persistence.DeadLetter{
+		Id:          uuid.New(),
+		ChatUser:    uuid.New(),
+		Room:        uuid.New(),
+		Message:     "my-message",
+		Attachments: []uuid.UUID{uuid.New()},
+		Reason:      "some reason",
+		Attempts:    2,
+		CreatedAt:   someTime,
+	}
+
+	actual := ToDeadLetterDtoResponse(entity)
+
+	expected := DeadLetterDtoResponse{
+		Id:          entity.Id,
+		User:        entity.ChatUser,
+		Room:        entity.Room,
+		Message:     entity.Message,
+		Attachments: entity.Attachments,
+		Reason:      entity.Reason,
+		Attempts:    entity.Attempts,
+		CreatedAt:   someTime,
+	}
+	assert.Equal(t, expected, actual)
+}
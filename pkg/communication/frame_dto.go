@@ -0,0 +1,45 @@
+package communication
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// FrameType describes the frames exchanged with the clients using a
+// bidirectional transport such as WebSocket or the TCP gateway.
+type FrameType string
+
+const (
+	MessageFrame FrameType = "message"
+	TypingFrame  FrameType = "typing"
+	ReadFrame    FrameType = "read"
+	ReplyFrame   FrameType = "reply"
+	// The following frames are only used by the TCP gateway: the other
+	// transports identify the user when the connection is opened.
+	AuthFrame FrameType = "auth"
+	JoinFrame FrameType = "join"
+)
+
+type FrameDtoRequest struct {
+	// Ref is chosen by the client and sent back in the reply to the frame.
+	Ref  string          `json:"ref,omitempty"`
+	Type FrameType       `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+type ReplyDtoResponse struct {
+	Ref  string    `json:"ref,omitempty"`
+	Type FrameType `json:"type"`
+	// Status uses the same value as the HTTP endpoint for the same action.
+	Status int `json:"status"`
+	Data   any `json:"data,omitempty"`
+}
+
+type AuthDtoRequest struct {
+	User uuid.UUID `json:"user"`
+}
+
+type JoinDtoRequest struct {
+	Room uuid.UUID `json:"room"`
+}
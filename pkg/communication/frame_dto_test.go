@@ -0,0 +1,46 @@
+package communication
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_FrameDtoRequest_UnmarshalsFromCamelCase(t *testing.T) {
+	in := `
+	{
+		"ref": "1",
+		"type": "typing",
+		"data": {"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"}
+	}`
+
+	var actual FrameDtoRequest
+	err := json.Unmarshal([]byte(in), &actual)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "1", actual.Ref)
+	assert.Equal(t, TypingFrame, actual.Type)
+	assert.JSONEq(t, `{"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"}`, string(actual.Data))
+}
+
+func TestUnit_ReplyDtoResponse_MarshalsToCamelCase(t *testing.T) {
+	dto := ReplyDtoResponse{
+		Ref:    "1",
+		Type:   ReplyFrame,
+		Status: 400,
+		Data:   "Invalid empty message",
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"ref": "1",
+		"type": "reply",
+		"status": 400,
+		"data": "Invalid empty message"
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
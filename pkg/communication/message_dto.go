@@ -8,39 +8,75 @@ import (
 )
 
 type MessageDtoRequest struct {
-	User    uuid.UUID `json:"user"`
-	Room    uuid.UUID `json:"room"`
-	Message string    `json:"message"`
+	User        uuid.UUID   `json:"user"`
+	Room        uuid.UUID   `json:"room"`
+	Message     string      `json:"message"`
+	Attachments []uuid.UUID `json:"attachments,omitempty"`
+	SendAt      *time.Time  `json:"send_at,omitempty"`
+	// Ttl is the lifetime of the message in seconds
+	Ttl            *int   `json:"ttl,omitempty"`
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
 }
 
 type MessageDtoResponse struct {
-	Id      uuid.UUID `json:"id"`
-	User    uuid.UUID `json:"user"`
-	Room    uuid.UUID `json:"room"`
-	Message string    `json:"message"`
+	Id          uuid.UUID               `json:"id"`
+	User        uuid.UUID               `json:"user"`
+	Room        uuid.UUID               `json:"room"`
+	Message     string                  `json:"message"`
+	Attachments []AttachmentDtoResponse `json:"attachments,omitempty"`
+	Sequence    int64                   `json:"sequence"`
+
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
 
-	CreatedAt time.Time `json:"created_at"`
+type ExpiredMessageDtoResponse struct {
+	Room    uuid.UUID `json:"room"`
+	Message uuid.UUID `json:"message"`
 }
 
 func FromMessageDtoRequest(message MessageDtoRequest) persistence.Message {
 	t := time.Now().UTC()
-	return persistence.Message{
+	out := persistence.Message{
 		Id:       uuid.New(),
 		ChatUser: message.User,
 		Room:     message.Room,
 		Message:  message.Message,
 
 		CreatedAt: t,
+
+		IdempotencyKey: message.IdempotencyKey,
 	}
+
+	if message.Ttl != nil {
+		expiresAt := t.Add(time.Duration(*message.Ttl) * time.Second)
+		out.ExpiresAt = &expiresAt
+	}
+
+	return out
 }
 
 func ToMessageDtoResponse(message persistence.Message) MessageDtoResponse {
-	return MessageDtoResponse{
+	out := MessageDtoResponse{
 		Id:      message.Id,
 		User:    message.ChatUser,
 		Room:    message.Room,
 		Message: message.Message,
 
+		Sequence: message.Sequence,
+
 		CreatedAt: message.CreatedAt,
+		ExpiresAt: message.ExpiresAt,
+
+		IdempotencyKey: message.IdempotencyKey,
 	}
+
+	for _, attachment := range message.Attachments {
+		dto := ToAttachmentDtoResponse(attachment)
+		out.Attachments = append(out.Attachments, dto)
+	}
+
+	return out
 }
@@ -52,6 +52,7 @@ func TestUnit_MessageDtoResponse_MarshalsToCamelCase(t *testing.T) {
 		User:      uuid.MustParse("e1c24e7d-b7b2-4118-85ac-d724b8e889dd"),
 		Room:      uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
 		Message:   "my-message",
+		Sequence:  12,
 		CreatedAt: someTime,
 	}
 
@@ -64,6 +65,7 @@ func TestUnit_MessageDtoResponse_MarshalsToCamelCase(t *testing.T) {
 		"user": "e1c24e7d-b7b2-4118-85ac-d724b8e889dd",
 		"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
 		"message": "my-message",
+		"sequence": 12,
 		"created_at": "2024-11-12T19:09:36Z"
 	}`
 	assert.JSONEq(t, expectedJson, string(out))
@@ -75,6 +77,7 @@ func TestUnit_ToMessageDtoResponse(t *testing.T) {
 		ChatUser: uuid.New(),
 		Room:     uuid.New(),
 		Message:  "my-message",
+		Sequence: 12,
 
 		CreatedAt: someTime,
 	}
@@ -85,5 +88,222 @@ func TestUnit_ToMessageDtoResponse(t *testing.T) {
 	assert.Equal(t, entity.ChatUser, actual.User)
 	assert.Equal(t, entity.Room, actual.Room)
 	assert.Equal(t, entity.Message, actual.Message)
+	assert.Equal(t, int64(12), actual.Sequence)
 	assert.Equal(t, someTime, actual.CreatedAt)
 }
+
+func TestUnit_MessageDtoRequest_WithAttachments_MarshalsToCamelCase(t *testing.T) {
+	dto := MessageDtoRequest{
+		User:    uuid.MustParse("e1c24e7d-b7b2-4118-85ac-d724b8e889dd"),
+		Room:    uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+		Message: "my-message",
+		Attachments: []uuid.UUID{
+			uuid.MustParse("0b3c4d6e-1e51-4a44-9a1e-3f0f0a4b5c6d"),
+		},
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"user": "e1c24e7d-b7b2-4118-85ac-d724b8e889dd",
+		"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+		"message": "my-message",
+		"attachments": ["0b3c4d6e-1e51-4a44-9a1e-3f0f0a4b5c6d"]
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
+
+func TestUnit_ToMessageDtoResponse_WithAttachments(t *testing.T) {
+	attachment := persistence.Attachment{
+		Id:       uuid.New(),
+		ChatUser: uuid.New(),
+		Room:     uuid.New(),
+		Name:     "my-file.png",
+		MimeType: "image/png",
+		Size:     1234,
+
+		CreatedAt: someTime,
+	}
+	entity := persistence.Message{
+		Id:          uuid.New(),
+		ChatUser:    attachment.ChatUser,
+		Room:        attachment.Room,
+		Message:     "my-message",
+		Attachments: []persistence.Attachment{attachment},
+
+		CreatedAt: someTime,
+	}
+
+	actual := ToMessageDtoResponse(entity)
+
+	expected := []AttachmentDtoResponse{ToAttachmentDtoResponse(attachment)}
+	assert.Equal(t, expected, actual.Attachments)
+}
+
+func TestUnit_MessageDtoRequest_WithTtl_MarshalsToCamelCase(t *testing.T) {
+	ttl := 60
+	dto := MessageDtoRequest{
+		User:    uuid.MustParse("e1c24e7d-b7b2-4118-85ac-d724b8e889dd"),
+		Room:    uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+		Message: "my-message",
+		Ttl:     &ttl,
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"user": "e1c24e7d-b7b2-4118-85ac-d724b8e889dd",
+		"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+		"message": "my-message",
+		"ttl": 60
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
+
+func TestUnit_FromMessageDtoRequest_WithTtl_ExpectExpirationSet(t *testing.T) {
+	ttl := 60
+	dto := MessageDtoRequest{
+		User:    uuid.New(),
+		Room:    uuid.New(),
+		Message: "my-message",
+		Ttl:     &ttl,
+	}
+
+	actual := FromMessageDtoRequest(dto)
+
+	assert.NotNil(t, actual.ExpiresAt)
+	assert.Equal(t, actual.CreatedAt.Add(time.Minute), *actual.ExpiresAt)
+}
+
+func TestUnit_FromMessageDtoRequest_WithoutTtl_ExpectNoExpiration(t *testing.T) {
+	dto := MessageDtoRequest{
+		User:    uuid.New(),
+		Room:    uuid.New(),
+		Message: "my-message",
+	}
+
+	actual := FromMessageDtoRequest(dto)
+
+	assert.Nil(t, actual.ExpiresAt)
+}
+
+func TestUnit_MessageDtoResponse_WithExpiration_MarshalsToCamelCase(t *testing.T) {
+	expiresAt := someTime.Add(time.Hour)
+	dto := MessageDtoResponse{
+		Id:        uuid.MustParse("a590b448-d3cd-4dbc-a9e3-8d642b1a5814"),
+		User:      uuid.MustParse("e1c24e7d-b7b2-4118-85ac-d724b8e889dd"),
+		Room:      uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+		Message:   "my-message",
+		CreatedAt: someTime,
+		ExpiresAt: &expiresAt,
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"id": "a590b448-d3cd-4dbc-a9e3-8d642b1a5814",
+		"user": "e1c24e7d-b7b2-4118-85ac-d724b8e889dd",
+		"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+		"message": "my-message",
+		"sequence": 0,
+		"created_at": "2024-11-12T19:09:36Z",
+		"expires_at": "2024-11-12T20:09:36Z"
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
+
+func TestUnit_ToMessageDtoResponse_WithExpiration(t *testing.T) {
+	expiresAt := someTime.Add(time.Hour)
+	entity := persistence.Message{
+		Id:       uuid.New(),
+		ChatUser: uuid.New(),
+		Room:     uuid.New(),
+		Message:  "my-message",
+
+		CreatedAt: someTime,
+		ExpiresAt: &expiresAt,
+	}
+
+	actual := ToMessageDtoResponse(entity)
+
+	assert.Equal(t, &expiresAt, actual.ExpiresAt)
+}
+
+func TestUnit_ExpiredMessageDtoResponse_MarshalsToCamelCase(t *testing.T) {
+	dto := ExpiredMessageDtoResponse{
+		Room:    uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+		Message: uuid.MustParse("a590b448-d3cd-4dbc-a9e3-8d642b1a5814"),
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+		"message": "a590b448-d3cd-4dbc-a9e3-8d642b1a5814"
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
+
+func TestUnit_FromMessageDtoRequest_WithIdempotencyKey_ExpectKeyForwarded(t *testing.T) {
+	dto := MessageDtoRequest{
+		User:           uuid.New(),
+		Room:           uuid.New(),
+		Message:        "my-message",
+		IdempotencyKey: "my-key",
+	}
+
+	actual := FromMessageDtoRequest(dto)
+
+	assert.Equal(t, "my-key", actual.IdempotencyKey)
+}
+
+func TestUnit_MessageDtoResponse_WithIdempotencyKey_MarshalsToCamelCase(t *testing.T) {
+	dto := MessageDtoResponse{
+		Id:             uuid.MustParse("a590b448-d3cd-4dbc-a9e3-8d642b1a5814"),
+		User:           uuid.MustParse("e1c24e7d-b7b2-4118-85ac-d724b8e889dd"),
+		Room:           uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+		Message:        "my-message",
+		CreatedAt:      someTime,
+		IdempotencyKey: "my-key",
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"id": "a590b448-d3cd-4dbc-a9e3-8d642b1a5814",
+		"user": "e1c24e7d-b7b2-4118-85ac-d724b8e889dd",
+		"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+		"message": "my-message",
+		"sequence": 0,
+		"created_at": "2024-11-12T19:09:36Z",
+		"idempotency_key": "my-key"
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
+
+func TestUnit_ToMessageDtoResponse_WithIdempotencyKey(t *testing.T) {
+	entity := persistence.Message{
+		Id:       uuid.New(),
+		ChatUser: uuid.New(),
+		Room:     uuid.New(),
+		Message:  "my-message",
+
+		CreatedAt: someTime,
+
+		IdempotencyKey: "my-key",
+	}
+
+	actual := ToMessageDtoResponse(entity)
+
+	assert.Equal(t, "my-key", actual.IdempotencyKey)
+}
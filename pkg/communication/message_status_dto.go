@@ -0,0 +1,30 @@
+package communication
+
+import (
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type MessageStatusDtoResponse struct {
+	Id     uuid.UUID `json:"id"`
+	Room   uuid.UUID `json:"room"`
+	Status string    `json:"status"`
+	Reason *string   `json:"reason,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func ToMessageStatusDtoResponse(status persistence.MessageStatus) MessageStatusDtoResponse {
+	return MessageStatusDtoResponse{
+		Id:     status.Message,
+		Room:   status.Room,
+		Status: status.Status,
+		Reason: status.Reason,
+
+		CreatedAt: status.CreatedAt,
+		UpdatedAt: status.UpdatedAt,
+	}
+}
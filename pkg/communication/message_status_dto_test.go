@@ -0,0 +1,83 @@
+package communication
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_MessageStatusDtoResponse_MarshalsToCamelCase(t *testing.T) {
+	dto := MessageStatusDtoResponse{
+		Id:        uuid.MustParse("a590b448-d3cd-4dbc-a9e3-8d642b1a5814"),
+		Room:      uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+		Status:    "queued",
+		CreatedAt: someTime,
+		UpdatedAt: someTime,
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"id": "a590b448-d3cd-4dbc-a9e3-8d642b1a5814",
+		"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+		"status": "queued",
+		"created_at": "2024-11-12T19:09:36Z",
+		"updated_at": "2024-11-12T19:09:36Z"
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
+
+func TestUnit_MessageStatusDtoResponse_WithReason_MarshalsToCamelCase(t *testing.T) {
+	reason := "some reason"
+	dto := MessageStatusDtoResponse{
+		Id:        uuid.MustParse("a590b448-d3cd-4dbc-a9e3-8d642b1a5814"),
+		Room:      uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+		Status:    "failed",
+		Reason:    &reason,
+		CreatedAt: someTime,
+		UpdatedAt: someTime,
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"id": "a590b448-d3cd-4dbc-a9e3-8d642b1a5814",
+		"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+		"status": "failed",
+		"reason": "some reason",
+		"created_at": "2024-11-12T19:09:36Z",
+		"updated_at": "2024-11-12T19:09:36Z"
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
+
+func TestUnit_ToMessageStatusDtoResponse(t *testing.T) {
+	reason := "some reason"
+	entity := persistence.MessageStatus{
+		Message:   uuid.New(),
+		Room:      uuid.New(),
+		Status:    persistence.MessageFailed,
+		Reason:    &reason,
+		CreatedAt: someTime,
+		UpdatedAt: someTime,
+	}
+
+	actual := ToMessageStatusDtoResponse(entity)
+
+	expected := MessageStatusDtoResponse{
+		Id:        entity.Message,
+		Room:      entity.Room,
+		Status:    persistence.MessageFailed,
+		Reason:    &reason,
+		CreatedAt: someTime,
+		UpdatedAt: someTime,
+	}
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,32 @@
+package communication
+
+import (
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type PinDtoResponse struct {
+	Room     uuid.UUID          `json:"room"`
+	Message  MessageDtoResponse `json:"message"`
+	PinnedBy uuid.UUID          `json:"pinned_by"`
+
+	PinnedAt time.Time `json:"pinned_at"`
+}
+
+type UnpinDtoResponse struct {
+	Room       uuid.UUID `json:"room"`
+	Message    uuid.UUID `json:"message"`
+	UnpinnedBy uuid.UUID `json:"unpinned_by"`
+}
+
+func ToPinDtoResponse(pin persistence.Pin, message persistence.Message) PinDtoResponse {
+	return PinDtoResponse{
+		Room:     pin.Room,
+		Message:  ToMessageDtoResponse(message),
+		PinnedBy: pin.PinnedBy,
+
+		PinnedAt: pin.CreatedAt,
+	}
+}
@@ -0,0 +1,86 @@
+package communication
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_ToPinDtoResponse(t *testing.T) {
+	message := persistence.Message{
+		Id:        uuid.New(),
+		ChatUser:  uuid.New(),
+		Room:      uuid.New(),
+		Message:   "my-message",
+		CreatedAt: someTime,
+	}
+	pin := persistence.Pin{
+		Room:      message.Room,
+		Message:   message.Id,
+		PinnedBy:  uuid.New(),
+		CreatedAt: someTime,
+	}
+
+	actual := ToPinDtoResponse(pin, message)
+
+	assert.Equal(t, pin.Room, actual.Room)
+	assert.Equal(t, ToMessageDtoResponse(message), actual.Message)
+	assert.Equal(t, pin.PinnedBy, actual.PinnedBy)
+	assert.Equal(t, someTime, actual.PinnedAt)
+}
+
+func TestUnit_PinDtoResponse_MarshalsToCamelCase(t *testing.T) {
+	dto := PinDtoResponse{
+		Room: uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+		Message: MessageDtoResponse{
+			Id:        uuid.MustParse("a590b448-d3cd-4dbc-a9e3-8d642b1a5814"),
+			User:      uuid.MustParse("e1c24e7d-b7b2-4118-85ac-d724b8e889dd"),
+			Room:      uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+			Message:   "my-message",
+			CreatedAt: someTime,
+		},
+		PinnedBy: uuid.MustParse("0b3c4d6e-1e51-4a44-9a1e-3f0f0a4b5c6d"),
+		PinnedAt: someTime,
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+		"message": {
+			"id": "a590b448-d3cd-4dbc-a9e3-8d642b1a5814",
+			"user": "e1c24e7d-b7b2-4118-85ac-d724b8e889dd",
+			"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+			"message": "my-message",
+			"sequence": 0,
+			"created_at": "2024-11-12T19:09:36Z"
+		},
+		"pinned_by": "0b3c4d6e-1e51-4a44-9a1e-3f0f0a4b5c6d",
+		"pinned_at": "2024-11-12T19:09:36Z"
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
+
+func TestUnit_UnpinDtoResponse_MarshalsToCamelCase(t *testing.T) {
+	dto := UnpinDtoResponse{
+		Room:       uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+		Message:    uuid.MustParse("a590b448-d3cd-4dbc-a9e3-8d642b1a5814"),
+		UnpinnedBy: uuid.MustParse("0b3c4d6e-1e51-4a44-9a1e-3f0f0a4b5c6d"),
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+		"message": "a590b448-d3cd-4dbc-a9e3-8d642b1a5814",
+		"unpinned_by": "0b3c4d6e-1e51-4a44-9a1e-3f0f0a4b5c6d"
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
@@ -0,0 +1,20 @@
+package communication
+
+import (
+	"github.com/google/uuid"
+)
+
+type EventDtoResponse struct {
+	Id   uuid.UUID `json:"id"`
+	Type string    `json:"type"`
+	Data any       `json:"data"`
+}
+
+type PollDtoResponse struct {
+	// Cursor should be sent in the `since` parameter of the next request.
+	Cursor string `json:"cursor"`
+	// Resync indicates that events may have been missed since the cursor
+	// of the request.
+	Resync bool               `json:"resync"`
+	Events []EventDtoResponse `json:"events"`
+}
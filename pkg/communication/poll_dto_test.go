@@ -0,0 +1,46 @@
+package communication
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_PollDtoResponse_MarshalsToCamelCase(t *testing.T) {
+	dto := PollDtoResponse{
+		Cursor: "2d5d5a8e-5c8e-4b8a-9d3e-3f1d4f0f6a11:12",
+		Resync: true,
+		Events: []EventDtoResponse{
+			{
+				Id:   uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+				Type: "typing",
+				Data: TypingDtoResponse{
+					Room: uuid.MustParse("e1c24e7d-b7b2-4118-85ac-d724b8e889dd"),
+					User: uuid.MustParse("6ac3b8c0-0c5b-4b5e-9b1c-4bde2e0c3a1f"),
+				},
+			},
+		},
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"cursor": "2d5d5a8e-5c8e-4b8a-9d3e-3f1d4f0f6a11:12",
+		"resync": true,
+		"events": [
+			{
+				"id": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+				"type": "typing",
+				"data": {
+					"room": "e1c24e7d-b7b2-4118-85ac-d724b8e889dd",
+					"user": "6ac3b8c0-0c5b-4b5e-9b1c-4bde2e0c3a1f"
+				}
+			}
+		]
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
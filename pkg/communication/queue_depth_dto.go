@@ -0,0 +1,18 @@
+package communication
+
+type QueueDepthDtoResponse struct {
+	Shard int `json:"shard"`
+	Depth int `json:"depth"`
+}
+
+func ToQueueDepthDtoResponses(depths []int) []QueueDepthDtoResponse {
+	out := make([]QueueDepthDtoResponse, 0, len(depths))
+	for shard, depth := range depths {
+		out = append(out, QueueDepthDtoResponse{
+			Shard: shard,
+			Depth: depth,
+		})
+	}
+
+	return out
+}
@@ -0,0 +1,35 @@
+package communication
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_QueueDepthDtoResponse_MarshalsToCamelCase(t *testing.T) {
+	dto := QueueDepthDtoResponse{
+		Shard: 1,
+		Depth: 12,
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"shard": 1,
+		"depth": 12
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
+
+func TestUnit_ToQueueDepthDtoResponses(t *testing.T) {
+	actual := ToQueueDepthDtoResponses([]int{3, 0})
+
+	expected := []QueueDepthDtoResponse{
+		{Shard: 0, Depth: 3},
+		{Shard: 1, Depth: 0},
+	}
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,25 @@
+package communication
+
+import (
+	"github.com/google/uuid"
+)
+
+type ReadMarkerDtoRequest struct {
+	User    uuid.UUID `json:"user"`
+	Room    uuid.UUID `json:"room"`
+	Message uuid.UUID `json:"message"`
+}
+
+type ReadMarkerDtoResponse struct {
+	Room    uuid.UUID `json:"room"`
+	User    uuid.UUID `json:"user"`
+	Message uuid.UUID `json:"message"`
+}
+
+func ToReadMarkerDtoResponse(marker ReadMarkerDtoRequest) ReadMarkerDtoResponse {
+	return ReadMarkerDtoResponse{
+		Room:    marker.Room,
+		User:    marker.User,
+		Message: marker.Message,
+	}
+}
@@ -0,0 +1,42 @@
+package communication
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_ToReadMarkerDtoResponse(t *testing.T) {
+	dto := ReadMarkerDtoRequest{
+		User:    uuid.New(),
+		Room:    uuid.New(),
+		Message: uuid.New(),
+	}
+
+	actual := ToReadMarkerDtoResponse(dto)
+
+	assert.Equal(t, dto.User, actual.User)
+	assert.Equal(t, dto.Room, actual.Room)
+	assert.Equal(t, dto.Message, actual.Message)
+}
+
+func TestUnit_ReadMarkerDtoResponse_MarshalsToCamelCase(t *testing.T) {
+	dto := ReadMarkerDtoResponse{
+		Room:    uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+		User:    uuid.MustParse("e1c24e7d-b7b2-4118-85ac-d724b8e889dd"),
+		Message: uuid.MustParse("a590b448-d3cd-4dbc-a9e3-8d642b1a5814"),
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+		"user": "e1c24e7d-b7b2-4118-85ac-d724b8e889dd",
+		"message": "a590b448-d3cd-4dbc-a9e3-8d642b1a5814"
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
@@ -8,12 +8,14 @@ import (
 )
 
 type RoomDtoRequest struct {
-	Name string `json:"name" form:"name"`
+	Name       string `json:"name" form:"name"`
+	MessageTtl *int   `json:"message_ttl,omitempty" form:"message_ttl"`
 }
 
 type RoomDtoResponse struct {
-	Id   uuid.UUID `json:"id"`
-	Name string    `json:"name"`
+	Id         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	MessageTtl *int      `json:"message_ttl,omitempty"`
 
 	CreatedAt time.Time `json:"created_at"`
 }
@@ -22,11 +24,20 @@ type RoomRegistrationDtoRequest struct {
 	User uuid.UUID `json:"chat_user"`
 }
 
+type RoomMessageTtlDtoRequest struct {
+	MessageTtl *int `json:"message_ttl"`
+}
+
+type RoomModeratorDtoRequest struct {
+	Moderator bool `json:"moderator"`
+}
+
 func FromRoomDtoRequest(room RoomDtoRequest) persistence.Room {
 	t := time.Now().UTC()
 	return persistence.Room{
-		Id:   uuid.New(),
-		Name: room.Name,
+		Id:         uuid.New(),
+		Name:       room.Name,
+		MessageTtl: room.MessageTtl,
 
 		CreatedAt: t,
 		UpdatedAt: t,
@@ -35,8 +46,9 @@ func FromRoomDtoRequest(room RoomDtoRequest) persistence.Room {
 
 func ToRoomDtoResponse(room persistence.Room) RoomDtoResponse {
 	return RoomDtoResponse{
-		Id:   room.Id,
-		Name: room.Name,
+		Id:         room.Id,
+		Name:       room.Name,
+		MessageTtl: room.MessageTtl,
 
 		CreatedAt: room.CreatedAt,
 	}
@@ -75,6 +75,64 @@ func TestUnit_ToRoomDtoResponse(t *testing.T) {
 	assert.Equal(t, someTime, actual.CreatedAt)
 }
 
+func TestUnit_RoomDtoRequest_WithMessageTtl_MarshalsToCamelCase(t *testing.T) {
+	ttl := 3600
+	dto := RoomDtoRequest{
+		Name:       "my-room",
+		MessageTtl: &ttl,
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"name": "my-room",
+		"message_ttl": 3600
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
+
+func TestUnit_FromRoomDtoRequest_WithMessageTtl(t *testing.T) {
+	ttl := 3600
+	dto := RoomDtoRequest{
+		Name:       "my-room",
+		MessageTtl: &ttl,
+	}
+
+	actual := FromRoomDtoRequest(dto)
+
+	assert.Equal(t, &ttl, actual.MessageTtl)
+}
+
+func TestUnit_ToRoomDtoResponse_WithMessageTtl(t *testing.T) {
+	ttl := 3600
+	entity := persistence.Room{
+		Id:         uuid.New(),
+		Name:       "my-room",
+		MessageTtl: &ttl,
+
+		CreatedAt: someTime,
+	}
+
+	actual := ToRoomDtoResponse(entity)
+
+	assert.Equal(t, &ttl, actual.MessageTtl)
+}
+
+func TestUnit_RoomMessageTtlDtoRequest_MarshalsToCamelCase(t *testing.T) {
+	dto := RoomMessageTtlDtoRequest{}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"message_ttl": null
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
+
 func TestUnit_RoomRegistrationDtoRequest_MarshalsToCamelCase(t *testing.T) {
 	dto := RoomRegistrationDtoRequest{
 		User: uuid.MustParse("ebebaf8a-704c-4a83-a6e6-95f6f194c4eb"),
@@ -0,0 +1,70 @@
+package communication
+
+import (
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type ScheduledMessageDtoRequest struct {
+	User    uuid.UUID  `json:"user"`
+	Message *string    `json:"message,omitempty"`
+	SendAt  *time.Time `json:"send_at,omitempty"`
+}
+
+type ScheduledMessageDtoResponse struct {
+	Id      uuid.UUID `json:"id"`
+	User    uuid.UUID `json:"user"`
+	Room    uuid.UUID `json:"room"`
+	Message string    `json:"message"`
+	SendAt  time.Time `json:"send_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func FromScheduledMessageDtoRequest(message MessageDtoRequest) persistence.ScheduledMessage {
+	t := time.Now().UTC()
+	out := persistence.ScheduledMessage{
+		Id:       uuid.New(),
+		ChatUser: message.User,
+		Room:     message.Room,
+		Message:  message.Message,
+
+		CreatedAt: t,
+		UpdatedAt: t,
+	}
+
+	if message.SendAt != nil {
+		out.SendAt = message.SendAt.UTC()
+	}
+
+	return out
+}
+
+func ToScheduledMessageDtoResponse(message persistence.ScheduledMessage) ScheduledMessageDtoResponse {
+	return ScheduledMessageDtoResponse{
+		Id:      message.Id,
+		User:    message.ChatUser,
+		Room:    message.Room,
+		Message: message.Message,
+		SendAt:  message.SendAt,
+
+		CreatedAt: message.CreatedAt,
+		UpdatedAt: message.UpdatedAt,
+	}
+}
+
+// FromScheduledMessage converts a scheduled message which is due to a regular
+// message. The identifier is kept so that clients can correlate both.
+func FromScheduledMessage(message persistence.ScheduledMessage) persistence.Message {
+	return persistence.Message{
+		Id:       message.Id,
+		ChatUser: message.ChatUser,
+		Room:     message.Room,
+		Message:  message.Message,
+
+		CreatedAt: time.Now().UTC(),
+	}
+}
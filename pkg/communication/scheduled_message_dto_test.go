@@ -0,0 +1,124 @@
+package communication
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_ScheduledMessageDtoRequest_MarshalsToCamelCase(t *testing.T) {
+	message := "my-message"
+	dto := ScheduledMessageDtoRequest{
+		User:    uuid.MustParse("e1c24e7d-b7b2-4118-85ac-d724b8e889dd"),
+		Message: &message,
+		SendAt:  &someTime,
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"user": "e1c24e7d-b7b2-4118-85ac-d724b8e889dd",
+		"message": "my-message",
+		"send_at": "2024-11-12T19:09:36Z"
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
+
+func TestUnit_FromScheduledMessageDtoRequest(t *testing.T) {
+	beforeConversion := time.Now()
+	sendAt := time.Date(2100, 1, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	dto := MessageDtoRequest{
+		User:    uuid.New(),
+		Room:    uuid.New(),
+		Message: "my-message",
+		SendAt:  &sendAt,
+	}
+
+	actual := FromScheduledMessageDtoRequest(dto)
+
+	assert.Equal(t, dto.User, actual.ChatUser)
+	assert.Equal(t, dto.Room, actual.Room)
+	assert.Equal(t, dto.Message, actual.Message)
+	assert.Equal(t, sendAt.UTC(), actual.SendAt)
+	assert.True(t, actual.CreatedAt.After(beforeConversion))
+}
+
+func TestUnit_ScheduledMessageDtoResponse_MarshalsToCamelCase(t *testing.T) {
+	dto := ScheduledMessageDtoResponse{
+		Id:        uuid.MustParse("a590b448-d3cd-4dbc-a9e3-8d642b1a5814"),
+		User:      uuid.MustParse("e1c24e7d-b7b2-4118-85ac-d724b8e889dd"),
+		Room:      uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+		Message:   "my-message",
+		SendAt:    someTime,
+		CreatedAt: someTime,
+		UpdatedAt: someTime,
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"id": "a590b448-d3cd-4dbc-a9e3-8d642b1a5814",
+		"user": "e1c24e7d-b7b2-4118-85ac-d724b8e889dd",
+		"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+		"message": "my-message",
+		"send_at": "2024-11-12T19:09:36Z",
+		"created_at": "2024-11-12T19:09:36Z",
+		"updated_at": "2024-11-12T19:09:36Z"
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
+
+func TestUnit_ToScheduledMessageDtoResponse(t *testing.T) {
+	entity := persistence.ScheduledMessage{
+		Id:       uuid.New(),
+		ChatUser: uuid.New(),
+		Room:     uuid.New(),
+		Message:  "my-message",
+		SendAt:   someTime.Add(time.Hour),
+
+		CreatedAt: someTime,
+		UpdatedAt: someTime.Add(time.Minute),
+	}
+
+	actual := ToScheduledMessageDtoResponse(entity)
+
+	assert.Equal(t, entity.Id, actual.Id)
+	assert.Equal(t, entity.ChatUser, actual.User)
+	assert.Equal(t, entity.Room, actual.Room)
+	assert.Equal(t, entity.Message, actual.Message)
+	assert.Equal(t, entity.SendAt, actual.SendAt)
+	assert.Equal(t, entity.CreatedAt, actual.CreatedAt)
+	assert.Equal(t, entity.UpdatedAt, actual.UpdatedAt)
+}
+
+func TestUnit_FromScheduledMessage(t *testing.T) {
+	beforeConversion := time.Now()
+
+	entity := persistence.ScheduledMessage{
+		Id:       uuid.New(),
+		ChatUser: uuid.New(),
+		Room:     uuid.New(),
+		Message:  "my-message",
+		SendAt:   someTime,
+
+		CreatedAt: someTime,
+		UpdatedAt: someTime,
+	}
+
+	actual := FromScheduledMessage(entity)
+
+	assert.Equal(t, entity.Id, actual.Id)
+	assert.Equal(t, entity.ChatUser, actual.ChatUser)
+	assert.Equal(t, entity.Room, actual.Room)
+	assert.Equal(t, entity.Message, actual.Message)
+	assert.True(t, actual.CreatedAt.After(beforeConversion))
+}
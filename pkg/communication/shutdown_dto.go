@@ -0,0 +1,5 @@
+package communication
+
+type ShutdownDtoResponse struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,51 @@
+package communication
+
+import (
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+)
+
+type SnapshotDtoResponse struct {
+	Rooms []RoomSnapshotDtoResponse `json:"rooms"`
+}
+
+type RoomSnapshotDtoResponse struct {
+	Room        RoomDtoResponse     `json:"room"`
+	Members     []UserDtoResponse   `json:"members"`
+	LastMessage *MessageDtoResponse `json:"last_message,omitempty"`
+	UnreadCount int64               `json:"unread_count"`
+	// Sequence is the one of the last message of the room when the
+	// snapshot was taken: the messages up to it are accounted for.
+	Sequence int64 `json:"sequence"`
+}
+
+func ToSnapshotDtoResponse(rooms []persistence.RoomSnapshot) SnapshotDtoResponse {
+	out := SnapshotDtoResponse{
+		Rooms: make([]RoomSnapshotDtoResponse, 0, len(rooms)),
+	}
+
+	for _, room := range rooms {
+		out.Rooms = append(out.Rooms, ToRoomSnapshotDtoResponse(room))
+	}
+
+	return out
+}
+
+func ToRoomSnapshotDtoResponse(room persistence.RoomSnapshot) RoomSnapshotDtoResponse {
+	out := RoomSnapshotDtoResponse{
+		Room:        ToRoomDtoResponse(room.Room),
+		Members:     make([]UserDtoResponse, 0, len(room.Members)),
+		UnreadCount: room.UnreadCount,
+		Sequence:    room.LastSequence,
+	}
+
+	for _, member := range room.Members {
+		out.Members = append(out.Members, ToUserDtoResponse(member))
+	}
+
+	if room.LastMessage != nil {
+		msg := ToMessageDtoResponse(*room.LastMessage)
+		out.LastMessage = &msg
+	}
+
+	return out
+}
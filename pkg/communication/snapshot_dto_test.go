@@ -0,0 +1,114 @@
+package communication
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_ToSnapshotDtoResponse(t *testing.T) {
+	room := persistence.Room{
+		Id:        uuid.New(),
+		Name:      "my-room",
+		CreatedAt: time.Date(2025, 5, 4, 20, 56, 16, 0, time.UTC),
+	}
+	user := persistence.User{
+		Id:      uuid.New(),
+		Name:    "my-user",
+		ApiUser: uuid.New(),
+	}
+	msg := persistence.Message{
+		Id:       uuid.New(),
+		ChatUser: user.Id,
+		Room:     room.Id,
+		Message:  "my-message",
+		Sequence: 4,
+	}
+	snapshot := persistence.RoomSnapshot{
+		Room:         room,
+		Members:      []persistence.User{user},
+		LastMessage:  &msg,
+		LastSequence: 5,
+		UnreadCount:  2,
+	}
+
+	actual := ToSnapshotDtoResponse([]persistence.RoomSnapshot{snapshot})
+
+	expectedMessage := ToMessageDtoResponse(msg)
+	expected := SnapshotDtoResponse{
+		Rooms: []RoomSnapshotDtoResponse{
+			{
+				Room:        ToRoomDtoResponse(room),
+				Members:     []UserDtoResponse{ToUserDtoResponse(user)},
+				LastMessage: &expectedMessage,
+				UnreadCount: 2,
+				Sequence:    5,
+			},
+		},
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestUnit_SnapshotDtoResponse_MarshalsToCamelCase(t *testing.T) {
+	dto := SnapshotDtoResponse{
+		Rooms: []RoomSnapshotDtoResponse{
+			{
+				Room: RoomDtoResponse{
+					Id:        uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+					Name:      "my-room",
+					CreatedAt: time.Date(2025, 5, 4, 20, 56, 16, 0, time.UTC),
+				},
+				Members: []UserDtoResponse{
+					{
+						Id:        uuid.MustParse("e1c24e7d-b7b2-4118-85ac-d724b8e889dd"),
+						Name:      "my-user",
+						ApiUser:   uuid.MustParse("a590b448-d3cd-4dbc-a9e3-8d642b1a5814"),
+						CreatedAt: time.Date(2025, 5, 4, 20, 56, 16, 0, time.UTC),
+					},
+				},
+				UnreadCount: 3,
+				Sequence:    7,
+			},
+		},
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"rooms": [
+			{
+				"room": {
+					"id": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+					"name": "my-room",
+					"created_at": "2025-05-04T20:56:16Z"
+				},
+				"members": [
+					{
+						"id": "e1c24e7d-b7b2-4118-85ac-d724b8e889dd",
+						"name": "my-user",
+						"api_user": "a590b448-d3cd-4dbc-a9e3-8d642b1a5814",
+						"created_at": "2025-05-04T20:56:16Z"
+					}
+				],
+				"unread_count": 3,
+				"sequence": 7
+			}
+		]
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
+
+func TestUnit_ToSnapshotDtoResponse_WhenNoRooms_ExpectEmptySlice(t *testing.T) {
+	actual := ToSnapshotDtoResponse(nil)
+
+	out, err := json.Marshal(actual)
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"rooms": []}`, string(out))
+}
@@ -0,0 +1,22 @@
+package communication
+
+import (
+	"github.com/google/uuid"
+)
+
+type TypingDtoRequest struct {
+	User uuid.UUID `json:"user"`
+	Room uuid.UUID `json:"room"`
+}
+
+type TypingDtoResponse struct {
+	Room uuid.UUID `json:"room"`
+	User uuid.UUID `json:"user"`
+}
+
+func ToTypingDtoResponse(typing TypingDtoRequest) TypingDtoResponse {
+	return TypingDtoResponse{
+		Room: typing.Room,
+		User: typing.User,
+	}
+}
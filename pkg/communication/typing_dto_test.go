@@ -0,0 +1,38 @@
+package communication
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_ToTypingDtoResponse(t *testing.T) {
+	dto := TypingDtoRequest{
+		User: uuid.New(),
+		Room: uuid.New(),
+	}
+
+	actual := ToTypingDtoResponse(dto)
+
+	assert.Equal(t, dto.User, actual.User)
+	assert.Equal(t, dto.Room, actual.Room)
+}
+
+func TestUnit_TypingDtoResponse_MarshalsToCamelCase(t *testing.T) {
+	dto := TypingDtoResponse{
+		Room: uuid.MustParse("aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9"),
+		User: uuid.MustParse("e1c24e7d-b7b2-4118-85ac-d724b8e889dd"),
+	}
+
+	out, err := json.Marshal(dto)
+
+	assert.Nil(t, err)
+	expectedJson := `
+	{
+		"room": "aaa3fc52-3ad8-4679-ae92-6c1bddb8d7f9",
+		"user": "e1c24e7d-b7b2-4118-85ac-d724b8e889dd"
+	}`
+	assert.JSONEq(t, expectedJson, string(out))
+}
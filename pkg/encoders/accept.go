@@ -0,0 +1,57 @@
+package encoders
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FromAccept returns the preferred encoder among the media types of an
+// `Accept` header. The default encoding is used when the header is empty
+// or does not list any supported media type.
+func FromAccept(header string) Encoder {
+	best, bestQuality := registered[0], 0.0
+
+	for _, part := range strings.Split(header, ",") {
+		mediaType, quality := ParseQualityValue(part)
+
+		encoder, ok := fromMediaType(mediaType)
+		if !ok && isWildcard(mediaType) {
+			encoder, ok = registered[0], true
+		}
+
+		// Ties are resolved by the order of the header.
+		if ok && quality > bestQuality {
+			best, bestQuality = encoder, quality
+		}
+	}
+
+	return best
+}
+
+// ParseQualityValue parses an element of a header weighted with quality
+// values, like `Accept` or `Accept-Encoding`. It returns the lower cased
+// value and its quality, which is 1 by default and 0 when it is invalid.
+func ParseQualityValue(part string) (string, float64) {
+	params := strings.Split(part, ";")
+	value := strings.ToLower(strings.TrimSpace(params[0]))
+
+	quality := 1.0
+	for _, param := range params[1:] {
+		key, q, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if strings.ToLower(key) != "q" {
+			continue
+		}
+
+		parsed, err := strconv.ParseFloat(q, 64)
+		if err != nil {
+			return value, 0
+		}
+		quality = parsed
+	}
+
+	return value, quality
+}
+
+func isWildcard(mediaType string) bool {
+	return mediaType == "*/*" || mediaType == "application/*"
+}
@@ -0,0 +1,122 @@
+package encoders
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_FromAccept(t *testing.T) {
+	type testCase struct {
+		header   string
+		expected Encoder
+	}
+
+	testCases := map[string]testCase{
+		"empty": {
+			header:   "",
+			expected: Json,
+		},
+		"json": {
+			header:   "application/json",
+			expected: Json,
+		},
+		"msgpack": {
+			header:   "application/msgpack",
+			expected: MsgPack,
+		},
+		"msgpackAlias": {
+			header:   "application/x-msgpack",
+			expected: MsgPack,
+		},
+		"cbor": {
+			header:   "application/cbor",
+			expected: Cbor,
+		},
+		"caseInsensitive": {
+			header:   "Application/CBOR",
+			expected: Cbor,
+		},
+		"wildcard": {
+			header:   "*/*",
+			expected: Json,
+		},
+		"unsupported": {
+			header:   "text/html",
+			expected: Json,
+		},
+		"firstSupported": {
+			header:   "text/html, application/cbor, application/msgpack",
+			expected: Cbor,
+		},
+		"quality": {
+			header:   "application/json;q=0.5, application/msgpack",
+			expected: MsgPack,
+		},
+		"wildcardWithLowerQuality": {
+			header:   "*/*;q=0.1, application/cbor;q=0.9",
+			expected: Cbor,
+		},
+		"notAcceptable": {
+			header:   "application/msgpack;q=0",
+			expected: Json,
+		},
+		"invalidQuality": {
+			header:   "application/msgpack;q=high, application/cbor;q=0.2",
+			expected: Cbor,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := FromAccept(testCase.header)
+
+			assert.Equal(t, testCase.expected.Name, actual.Name)
+		})
+	}
+}
+
+func TestUnit_ParseQualityValue(t *testing.T) {
+	type testCase struct {
+		part            string
+		expectedValue   string
+		expectedQuality float64
+	}
+
+	testCases := map[string]testCase{
+		"noQuality": {
+			part:            " gzip ",
+			expectedValue:   "gzip",
+			expectedQuality: 1,
+		},
+		"quality": {
+			part:            "application/msgpack; q=0.5",
+			expectedValue:   "application/msgpack",
+			expectedQuality: 0.5,
+		},
+		"otherParams": {
+			part:            "text/html;level=1;Q=0.2",
+			expectedValue:   "text/html",
+			expectedQuality: 0.2,
+		},
+		"caseInsensitive": {
+			part:            "Application/CBOR",
+			expectedValue:   "application/cbor",
+			expectedQuality: 1,
+		},
+		"invalidQuality": {
+			part:            "deflate;q=high",
+			expectedValue:   "deflate",
+			expectedQuality: 0,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			value, quality := ParseQualityValue(testCase.part)
+
+			assert.Equal(t, testCase.expectedValue, value)
+			assert.Equal(t, testCase.expectedQuality, quality)
+		})
+	}
+}
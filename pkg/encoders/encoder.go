@@ -0,0 +1,156 @@
+package encoders
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Encoder produces the same documents as the JSON encoding in another
+// format. The values are always marshalled to JSON first so that the
+// field names and the representation of ids and dates do not depend on
+// the encoding.
+type Encoder struct {
+	Name        string
+	ContentType string
+	// Binary encodings need to be wrapped, for example in base64, by the
+	// transports which only carry text.
+	Binary bool
+
+	encode func(value any) ([]byte, error)
+}
+
+var Json = Encoder{
+	Name:        "json",
+	ContentType: "application/json",
+}
+
+var MsgPack = Encoder{
+	Name:        "msgpack",
+	ContentType: "application/msgpack",
+	Binary:      true,
+	encode:      encodeMsgPack,
+}
+
+var Cbor = Encoder{
+	Name:        "cbor",
+	ContentType: "application/cbor",
+	Binary:      true,
+	encode:      encodeCbor,
+}
+
+// registered lists the supported encodings. The first one is used when
+// the client does not express a preference.
+var registered = []Encoder{Json, MsgPack, Cbor}
+
+// aliases are other media types used by clients for the encodings.
+var aliases = map[string]string{
+	"application/x-msgpack":   MsgPack.ContentType,
+	"application/vnd.msgpack": MsgPack.ContentType,
+}
+
+var cborEncMode, _ = cbor.CoreDetEncOptions().EncMode()
+
+// FromName returns the encoder with the name. An empty name selects the
+// default encoding.
+func FromName(name string) (Encoder, error) {
+	if name == "" {
+		return registered[0], nil
+	}
+
+	for _, encoder := range registered {
+		if encoder.Name == name {
+			return encoder, nil
+		}
+	}
+
+	return Encoder{}, errors.NewCode(ErrUnsupportedEncoding)
+}
+
+func fromMediaType(mediaType string) (Encoder, bool) {
+	if alias, ok := aliases[mediaType]; ok {
+		mediaType = alias
+	}
+
+	for _, encoder := range registered {
+		if encoder.ContentType == mediaType {
+			return encoder, true
+		}
+	}
+
+	return Encoder{}, false
+}
+
+func (e Encoder) Marshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.WrapCode(err, ErrEncodingFailed)
+	}
+
+	return e.FromJson(data)
+}
+
+// FromJson converts a JSON document to the encoding.
+func (e Encoder) FromJson(data []byte) ([]byte, error) {
+	if e.encode == nil {
+		return data, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Numbers would otherwise all be encoded as floats.
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, errors.WrapCode(err, ErrEncodingFailed)
+	}
+
+	out, err := e.encode(toNativeNumbers(value))
+	if err != nil {
+		return nil, errors.WrapCode(err, ErrEncodingFailed)
+	}
+
+	return out, nil
+}
+
+func encodeMsgPack(value any) ([]byte, error) {
+	var out bytes.Buffer
+
+	encoder := msgpack.NewEncoder(&out)
+	encoder.SetSortMapKeys(true)
+	encoder.UseCompactInts(true)
+
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+func encodeCbor(value any) ([]byte, error) {
+	return cborEncMode.Marshal(value)
+}
+
+func toNativeNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, item := range v {
+			v[key] = toNativeNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = toNativeNumbers(item)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,140 @@
+package encoders
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type sampleDto struct {
+	Id        uuid.UUID `json:"id"`
+	Count     int       `json:"count"`
+	Ratio     float64   `json:"ratio"`
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"created_at"`
+	Ignored   string    `json:"-"`
+}
+
+var sample = sampleDto{
+	Id:        uuid.MustParse("f2d9ce22-179d-431c-b63d-43d5a8ab5e18"),
+	Count:     12,
+	Ratio:     0.5,
+	Tags:      []string{"a", "b"},
+	CreatedAt: time.Date(2025, 5, 4, 20, 56, 16, 0, time.UTC),
+	Ignored:   "ignored",
+}
+
+func TestUnit_FromName(t *testing.T) {
+	type testCase struct {
+		name     string
+		expected Encoder
+	}
+
+	testCases := map[string]testCase{
+		"default": {
+			name:     "",
+			expected: Json,
+		},
+		"json": {
+			name:     "json",
+			expected: Json,
+		},
+		"msgpack": {
+			name:     "msgpack",
+			expected: MsgPack,
+		},
+		"cbor": {
+			name:     "cbor",
+			expected: Cbor,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := FromName(testCase.name)
+
+			assert.Nil(t, err, "Actual err: %v", err)
+			assert.Equal(t, testCase.expected.Name, actual.Name)
+		})
+	}
+}
+
+func TestUnit_FromName_WhenNameIsUnknown_ExpectError(t *testing.T) {
+	_, err := FromName("xml")
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrUnsupportedEncoding),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestUnit_Encoder_Json_ExpectJsonDocument(t *testing.T) {
+	out, err := Json.Marshal(sample)
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	expected := `{"id":"f2d9ce22-179d-431c-b63d-43d5a8ab5e18","count":12,"ratio":0.5,"tags":["a","b"],"created_at":"2025-05-04T20:56:16Z"}`
+	assert.Equal(t, expected, string(out))
+}
+
+func TestUnit_Encoder_MsgPack_ExpectSameDocumentAsJson(t *testing.T) {
+	out, err := MsgPack.Marshal(sample)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	var actual map[string]any
+	err = msgpack.Unmarshal(out, &actual)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	expected := map[string]any{
+		"id":         "f2d9ce22-179d-431c-b63d-43d5a8ab5e18",
+		"count":      int8(12),
+		"ratio":      0.5,
+		"tags":       []any{"a", "b"},
+		"created_at": "2025-05-04T20:56:16Z",
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestUnit_Encoder_Cbor_ExpectSameDocumentAsJson(t *testing.T) {
+	out, err := Cbor.Marshal(sample)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	var actual map[string]any
+	err = cbor.Unmarshal(out, &actual)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	expected := map[string]any{
+		"id":         "f2d9ce22-179d-431c-b63d-43d5a8ab5e18",
+		"count":      uint64(12),
+		"ratio":      0.5,
+		"tags":       []any{"a", "b"},
+		"created_at": "2025-05-04T20:56:16Z",
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestUnit_Encoder_FromJson_WhenDataIsNotJson_ExpectError(t *testing.T) {
+	_, err := MsgPack.FromJson([]byte("not-json"))
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrEncodingFailed),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestUnit_Encoder_MsgPack_ExpectSmallerThanJson(t *testing.T) {
+	jsonOut, err := Json.Marshal(sample)
+	assert.Nil(t, err, "Actual err: %v", err)
+	msgPackOut, err := MsgPack.Marshal(sample)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Less(t, len(msgPackOut), len(jsonOut))
+}
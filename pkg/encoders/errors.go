@@ -0,0 +1,8 @@
+package encoders
+
+import "github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+
+const (
+	ErrUnsupportedEncoding errors.ErrorCode = 1100
+	ErrEncodingFailed      errors.ErrorCode = 1101
+)
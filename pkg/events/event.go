@@ -0,0 +1,147 @@
+package events
+
+import (
+	"encoding/json"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type Type string
+
+const (
+	Message  Type = "message"
+	Pin      Type = "pin"
+	Unpin    Type = "unpin"
+	Expire   Type = "expire"
+	Typing   Type = "typing"
+	Read     Type = "read"
+	Snapshot Type = "snapshot"
+	Shutdown Type = "shutdown"
+)
+
+// IsSubscribable returns whether clients can select the type when they
+// subscribe. The shutdown event is always sent.
+func IsSubscribable(t Type) bool {
+	switch t {
+	case Message, Pin, Unpin, Expire, Typing, Read:
+		return true
+	default:
+		return false
+	}
+}
+
+type Event struct {
+	Id   uuid.UUID
+	Type Type
+	Room uuid.UUID
+	// Data is serialized as is and sent to the clients
+	Data any
+}
+
+// DecodeData converts the data of an event serialized as JSON back to the
+// type it has when built with the functions of this package. The data of
+// unknown types is kept serialized.
+func DecodeData(t Type, data json.RawMessage) (any, error) {
+	switch t {
+	case Message:
+		return decode[communication.MessageDtoResponse](data)
+	case Pin:
+		return decode[communication.PinDtoResponse](data)
+	case Unpin:
+		return decode[communication.UnpinDtoResponse](data)
+	case Expire:
+		return decode[communication.ExpiredMessageDtoResponse](data)
+	case Typing:
+		return decode[communication.TypingDtoResponse](data)
+	case Read:
+		return decode[communication.ReadMarkerDtoResponse](data)
+	case Snapshot:
+		return decode[communication.SnapshotDtoResponse](data)
+	case Shutdown:
+		return decode[communication.ShutdownDtoResponse](data)
+	default:
+		return data, nil
+	}
+}
+
+func decode[T any](data json.RawMessage) (any, error) {
+	var out T
+	err := json.Unmarshal(data, &out)
+	return out, err
+}
+
+func FromMessage(msg persistence.Message) Event {
+	return Event{
+		Id:   msg.Id,
+		Type: Message,
+		Room: msg.Room,
+		Data: communication.ToMessageDtoResponse(msg),
+	}
+}
+
+func FromPin(pin communication.PinDtoResponse) Event {
+	return Event{
+		Id:   uuid.New(),
+		Type: Pin,
+		Room: pin.Room,
+		Data: pin,
+	}
+}
+
+func FromUnpin(unpin communication.UnpinDtoResponse) Event {
+	return Event{
+		Id:   uuid.New(),
+		Type: Unpin,
+		Room: unpin.Room,
+		Data: unpin,
+	}
+}
+
+func FromExpiration(expired communication.ExpiredMessageDtoResponse) Event {
+	return Event{
+		Id:   uuid.New(),
+		Type: Expire,
+		Room: expired.Room,
+		Data: expired,
+	}
+}
+
+func FromTyping(typing communication.TypingDtoResponse) Event {
+	return Event{
+		Id:   uuid.New(),
+		Type: Typing,
+		Room: typing.Room,
+		Data: typing,
+	}
+}
+
+func FromReadMarker(marker communication.ReadMarkerDtoResponse) Event {
+	return Event{
+		Id:   uuid.New(),
+		Type: Read,
+		Room: marker.Room,
+		Data: marker,
+	}
+}
+
+// FromSnapshot is sent first to the clients asking for it. It does not
+// belong to a room.
+func FromSnapshot(snapshot communication.SnapshotDtoResponse) Event {
+	return Event{
+		Id:   uuid.New(),
+		Type: Snapshot,
+		Data: snapshot,
+	}
+}
+
+func FromShutdown() Event {
+	return Event{
+		Id:   uuid.New(),
+		Type: Shutdown,
+		Data: communication.ShutdownDtoResponse{
+			Reason: "server shutting down",
+		},
+	}
+}
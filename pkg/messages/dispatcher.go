@@ -1,6 +1,7 @@
 package messages
 
 import (
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
 	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
 	"github.com/google/uuid"
 )
@@ -9,4 +10,11 @@ type Dispatcher interface {
 	Broadcast(msg persistence.Message) error
 	BroadcastExcept(id uuid.UUID, msg persistence.Message) error
 	SendTo(id uuid.UUID, msg persistence.Message)
+	BroadcastEvent(event events.Event) error
+
+	// The changes in the membership of the rooms are forwarded to the
+	// dispatcher so that it knows who should receive the messages.
+	OnRegistration(user uuid.UUID, room uuid.UUID)
+	OnUnregistration(user uuid.UUID, room uuid.UUID)
+	OnRoomDeletion(room uuid.UUID)
 }
@@ -21,6 +21,11 @@ var dbTestConfig = postgresql.NewConfigForLocalhost(
 	"manager_password",
 )
 
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts: 2,
+	Backoff:     time.Millisecond,
+}
+
 func newTestDbConnection(t *testing.T) db.Connection {
 	conn, err := db.New(context.Background(), dbTestConfig)
 	assert.Nil(t, err, "Actual err: %v", err)
@@ -114,18 +119,50 @@ func assertMessageExists(t *testing.T, conn db.Connection, id uuid.UUID) {
 	assert.Equal(t, id, value)
 }
 
+func insertTestAttachment(
+	t *testing.T, conn db.Connection, user uuid.UUID, room uuid.UUID,
+) persistence.Attachment {
+	repo := repositories.NewAttachmentRepository(conn)
+
+	attachment := persistence.Attachment{
+		Id:       uuid.New(),
+		ChatUser: user,
+		Room:     room,
+		Name:     fmt.Sprintf("my-file-%s", uuid.NewString()),
+		MimeType: "text/plain; charset=utf-8",
+		Size:     12,
+	}
+	out, err := repo.Create(context.Background(), attachment)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return out
+}
+
+func assertAttachmentLinkedToMessage(
+	t *testing.T, conn db.Connection, attachment uuid.UUID, msg uuid.UUID,
+) {
+	value, err := db.QueryOne[uuid.UUID](
+		context.Background(),
+		conn,
+		"SELECT message FROM attachment WHERE id = $1",
+		attachment,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, msg, value)
+}
+
 func dummyMessageCallback(_ persistence.Message) error {
 	return nil
 }
 
 func asyncStartProcessorAndAssertNoError(
-	t *testing.T, processor Processor,
+	t *testing.T, processor Processor[persistence.Message],
 ) *sync.WaitGroup {
 	return asyncStartProcessorAndAssertError(t, processor, nil)
 }
 
 func asyncStartProcessorAndAssertError(
-	t *testing.T, processor Processor, expectedErr error,
+	t *testing.T, processor Processor[persistence.Message], expectedErr error,
 ) *sync.WaitGroup {
 	var wg sync.WaitGroup
 
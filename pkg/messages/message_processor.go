@@ -3,41 +3,218 @@ package messages
 import (
 	"context"
 
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/pgx"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
 	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
 	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
 )
 
 func NewMessageProcessor(
 	messageQueueSize int,
+	retry RetryPolicy,
 	dispatcher Dispatcher,
 	repos repositories.Repositories,
-) Processor {
-	callbacks := Callbacks{
-		Message: generateMessageCallback(dispatcher, repos.Message),
+) Processor[persistence.Message] {
+	callbacks := Callbacks[persistence.Message]{
+		Message: generateMessageCallback(retry, dispatcher, repos),
 	}
 
 	return NewProcessor(messageQueueSize, callbacks)
 }
 
+// NewShardedMessageProcessor spreads the messages on several workers.
+// Messages are routed by room so that their order within a room is kept
+// while different rooms are processed in parallel. Each worker persists
+// the messages in batches according to the policy.
+func NewShardedMessageProcessor(
+	workers int,
+	messageQueueSize int,
+	batch BatchPolicy,
+	retry RetryPolicy,
+	dispatcher Dispatcher,
+	repos repositories.Repositories,
+) ShardedProcessor[persistence.Message] {
+	callbacks := Callbacks[persistence.Message]{
+		Message: generateMessageCallback(retry, dispatcher, repos),
+		Batch:   generateBatchCallback(retry, dispatcher, repos),
+	}
+
+	return NewShardedProcessor(workers, messageQueueSize, batch, roomOf, callbacks)
+}
+
+func roomOf(msg persistence.Message) uuid.UUID {
+	return msg.Room
+}
+
 func generateMessageCallback(
+	retry RetryPolicy,
 	dispatcher Dispatcher,
-	messageRepo repositories.MessageRepository,
-) MessageCallback {
+	repos repositories.Repositories,
+) MessageCallback[persistence.Message] {
+	// At this point we can't return an error to the client anyway: the
+	// failures are recorded so that they can be inspected and the loop
+	// goes on with the next messages.
 	return func(msg persistence.Message) error {
-		_, err := messageRepo.Create(context.Background(), msg)
-		// TODO: Returning an error here means the processing of messages
-		// will stop. Probably we should not do that and just go on
-		// At this point we can't return an error to the client anyway
-		if err != nil {
+		var created persistence.Message
+		attempts, err := retry.run(func() error {
+			var err error
+			created, err = repos.Message.Create(context.Background(), msg)
 			return err
+		})
+		// The server might have stopped after persisting the message but
+		// before marking it as delivered: it was already handled.
+		if errors.IsErrorWithCode(err, pgx.UniqueConstraintViolation) {
+			markDelivered(repos, msg)
+			return nil
 		}
-
-		// TODO: Also here, we probably don't want to return the error
-		err = dispatcher.Broadcast(msg)
 		if err != nil {
+			// The message is kept in the outbox when it can't be stored
+			// anywhere: the relay will dispatch it again later.
+			if recordDeadLetter(repos, msg, attempts, err) == nil {
+				markDelivered(repos, msg)
+			}
+			return nil
+		}
+
+		// The expiration might come from the default of the room and the
+		// sequence is assigned by the database.
+		msg.ExpiresAt = created.ExpiresAt
+		msg.Sequence = created.Sequence
+		publishMessage(retry, dispatcher, repos, msg)
+		markDelivered(repos, msg)
+
+		return nil
+	}
+}
+
+func generateBatchCallback(
+	retry RetryPolicy,
+	dispatcher Dispatcher,
+	repos repositories.Repositories,
+) BatchCallback[persistence.Message] {
+	processOne := generateMessageCallback(retry, dispatcher, repos)
+
+	return func(msgs []persistence.Message) error {
+		if len(msgs) == 1 {
+			return processOne(msgs[0])
+		}
+
+		var created []persistence.Message
+		_, err := retry.run(func() error {
+			var err error
+			created, err = repos.Message.CreateBatch(context.Background(), msgs)
 			return err
+		})
+		if err != nil {
+			// Nothing was persisted: handling the messages one at a time
+			// isolates the ones which can't be persisted.
+			for _, msg := range msgs {
+				processOne(msg)
+			}
+			return nil
+		}
+
+		for id, msg := range msgs {
+			msg.ExpiresAt = created[id].ExpiresAt
+			msg.Sequence = created[id].Sequence
+			publishMessage(retry, dispatcher, repos, msg)
+			markDelivered(repos, msg)
 		}
 
 		return nil
 	}
 }
+
+// publishMessage completes the processing of a persisted message: its
+// attachments are linked to it, its status is updated and it is sent
+// to the members of the room.
+func publishMessage(
+	retry RetryPolicy,
+	dispatcher Dispatcher,
+	repos repositories.Repositories,
+	msg persistence.Message,
+) {
+	if len(msg.Attachments) > 0 {
+		ids := make([]uuid.UUID, 0, len(msg.Attachments))
+		for _, attachment := range msg.Attachments {
+			ids = append(ids, attachment.Id)
+		}
+
+		// The message is persisted: failing to link the attachments
+		// is reported in the status but there's nothing to replay.
+		_, err := retry.run(func() error {
+			return repos.Attachment.AttachToMessage(context.Background(), msg.Id, ids)
+		})
+		if err != nil {
+			recordMessageStatus(repos, msg, persistence.MessageFailed, err)
+			return
+		}
+	}
+
+	recordMessageStatus(repos, msg, persistence.MessagePersisted, nil)
+
+	// Clients can still fetch the message from the history of the
+	// room if it can't be broadcast.
+	retry.run(func() error {
+		return dispatcher.Broadcast(msg)
+	})
+}
+
+func recordDeadLetter(
+	repos repositories.Repositories,
+	msg persistence.Message,
+	attempts int,
+	cause error,
+) error {
+	letter := persistence.DeadLetter{
+		Id:        msg.Id,
+		ChatUser:  msg.ChatUser,
+		Room:      msg.Room,
+		Message:   msg.Message,
+		ExpiresAt: msg.ExpiresAt,
+		Reason:    cause.Error(),
+		Attempts:  attempts,
+	}
+	for _, attachment := range msg.Attachments {
+		letter.Attachments = append(letter.Attachments, attachment.Id)
+	}
+
+	_, err := repos.DeadLetter.Create(context.Background(), letter)
+	// The letter might have been recorded by a previous dispatch of the
+	// message which failed to be marked as delivered.
+	if err != nil && !errors.IsErrorWithCode(err, pgx.UniqueConstraintViolation) {
+		return err
+	}
+
+	recordMessageStatus(repos, msg, persistence.MessageFailed, cause)
+
+	return nil
+}
+
+func recordMessageStatus(
+	repos repositories.Repositories,
+	msg persistence.Message,
+	status string,
+	cause error,
+) {
+	out := persistence.MessageStatus{
+		Message: msg.Id,
+		Room:    msg.Room,
+		Status:  status,
+	}
+	if cause != nil {
+		reason := cause.Error()
+		out.Reason = &reason
+	}
+
+	// Voluntarily ignore errors: the status is informative and should not
+	// prevent the message from being delivered.
+	repos.MessageStatus.Update(context.Background(), out)
+}
+
+func markDelivered(repos repositories.Repositories, msg persistence.Message) {
+	// Voluntarily ignore errors: the message would be dispatched again
+	// after a restart and detected as a duplicate.
+	repos.Outbox.MarkDelivered(context.Background(), msg.Id)
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/pgx"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
 	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
 	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
 	"github.com/google/uuid"
@@ -64,55 +67,464 @@ func TestIT_MessageProcessor_EnqueueMessage_ExpectSentToDispatcher(t *testing.T)
 	assert.Equal(t, msg, mock.receivedMsg)
 }
 
-func TestIT_MessageProcessor_WhenMessageFailsToBeWritten_ExpectProcessingStops(t *testing.T) {
+func TestIT_MessageProcessor_EnqueueMessageWithAttachment_ExpectAttachmentLinked(t *testing.T) {
+	processor, dbConn, _ := newTestMessageProcessor(t)
+	defer dbConn.Close(context.Background())
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+	attachment := insertTestAttachment(t, dbConn, user.Id, room.Id)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	msg := persistence.Message{
+		Id:          uuid.New(),
+		ChatUser:    user.Id,
+		Room:        room.Id,
+		Attachments: []persistence.Attachment{attachment},
+	}
+	processor.Enqueue(msg)
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assertAttachmentLinkedToMessage(t, dbConn, attachment.Id, msg.Id)
+}
+
+func TestIT_MessageProcessor_EnqueueMessage_ExpectStatusPersisted(t *testing.T) {
+	processor, dbConn, _ := newTestMessageProcessor(t)
+	defer dbConn.Close(context.Background())
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+	registerUserInRoom(t, dbConn, user.Id, room.Id)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	msg := persistence.Message{
+		Id:       uuid.New(),
+		ChatUser: user.Id,
+		Room:     room.Id,
+		Message:  fmt.Sprintf("hello %s", room.Name),
+	}
+	processor.Enqueue(msg)
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assertMessageStatus(t, dbConn, msg.Id, persistence.MessagePersisted)
+}
+
+func TestIT_MessageProcessor_WhenMessageFailsToBeWritten_ExpectFailureRecorded(t *testing.T) {
+	processor, dbConn, _ := newTestMessageProcessor(t)
+	defer dbConn.Close(context.Background())
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	// The user is not registered in the room
+	msg := persistence.Message{
+		Id:       uuid.New(),
+		ChatUser: user.Id,
+		Room:     room.Id,
+		Message:  fmt.Sprintf("hello %s", room.Name),
+	}
+	processor.Enqueue(msg)
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assertMessageStatus(t, dbConn, msg.Id, persistence.MessageFailed)
+}
+
+func TestIT_MessageProcessor_WhenMessageFailsToBeWritten_ExpectDeadLetterRecorded(t *testing.T) {
+	processor, dbConn, _ := newTestMessageProcessor(t)
+	defer dbConn.Close(context.Background())
+
+	user := insertTestUser(t, dbConn)
+	room := insertTestRoom(t, dbConn)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	// The user is not registered in the room
+	msg := persistence.Message{
+		Id:       uuid.New(),
+		ChatUser: user.Id,
+		Room:     room.Id,
+		Message:  fmt.Sprintf("hello %s", room.Name),
+	}
+	processor.Enqueue(msg)
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	// Foreign key violations are permanent: no retry expected
+	letter, err := db.QueryOne[persistence.DeadLetter](
+		context.Background(),
+		dbConn,
+		`SELECT
+			id, chat_user, room, message, attachments, expires_at, reason, attempts, created_at
+		FROM dead_letter WHERE id = $1`,
+		msg.Id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, msg.Message, letter.Message)
+	assert.Equal(t, 1, letter.Attempts)
+}
+
+func TestIT_MessageProcessor_WhenMessageFailsTransiently_ExpectRetried(t *testing.T) {
+	testErr := fmt.Errorf("some error")
+	mock := newMockMessageRepository(false, testErr)
+	deadLetterMock := &mockDeadLetterRepository{}
+	repos := repositories.Repositories{
+		DeadLetter:    deadLetterMock,
+		Message:       mock,
+		MessageStatus: &mockMessageStatusRepository{},
+		Outbox:        &mockOutboxRepository{},
+	}
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	processor := NewMessageProcessor(1, policy, &mockDispatcher{}, repos)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	msg := persistence.Message{Id: uuid.New()}
+	processor.Enqueue(msg)
+	time.Sleep(50 * time.Millisecond)
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Equal(t, int32(3), mock.calls.Load())
+	assert.Len(t, deadLetterMock.created, 1)
+	letter := deadLetterMock.created[0]
+	assert.Equal(t, msg.Id, letter.Id)
+	assert.Equal(t, 3, letter.Attempts)
+	assert.Equal(t, testErr.Error(), letter.Reason)
+}
+
+func TestIT_MessageProcessor_WhenBroadcastFails_ExpectProcessingContinues(t *testing.T) {
+	mock := newMockMessageRepository(false, nil)
+	statusMock := &mockMessageStatusRepository{}
+	repos := repositories.Repositories{
+		Message:       mock,
+		MessageStatus: statusMock,
+		Outbox:        &mockOutboxRepository{},
+	}
+	dispatcher := &mockDispatcher{err: fmt.Errorf("some error")}
+	processor := NewMessageProcessor(1, RetryPolicy{MaxAttempts: 1}, dispatcher, repos)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	for range 3 {
+		processor.Enqueue(persistence.Message{Id: uuid.New()})
+	}
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.GreaterOrEqual(t, len(statusMock.updated), 2)
+}
+
+func TestIT_MessageProcessor_WhenMessageFailsToBeWritten_ExpectProcessingContinues(t *testing.T) {
 	testErr := fmt.Errorf("some error")
 	mock := newMockMessageRepository(false, testErr)
+	statusMock := &mockMessageStatusRepository{}
+	repos := repositories.Repositories{
+		DeadLetter:    &mockDeadLetterRepository{},
+		Message:       mock,
+		MessageStatus: statusMock,
+		Outbox:        &mockOutboxRepository{},
+	}
+	processor := NewMessageProcessor(1, RetryPolicy{MaxAttempts: 1}, &mockDispatcher{}, repos)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	// The queue holds a single message: enqueuing the third one means
+	// that the second one was picked up after the first one failed.
+	for range 3 {
+		processor.Enqueue(persistence.Message{Id: uuid.New()})
+	}
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.GreaterOrEqual(t, len(statusMock.updated), 2)
+	for _, status := range statusMock.updated {
+		assert.Equal(t, persistence.MessageFailed, status.Status)
+		assert.Equal(t, testErr.Error(), *status.Reason)
+	}
+}
+
+func TestIT_MessageProcessor_EnqueueMessage_ExpectMarkedAsDelivered(t *testing.T) {
+	mock := newMockMessageRepository(false, nil)
+	outboxMock := &mockOutboxRepository{}
 	repos := repositories.Repositories{
-		Message: mock,
+		Message:       mock,
+		MessageStatus: &mockMessageStatusRepository{},
+		Outbox:        outboxMock,
 	}
-	processor := NewMessageProcessor(1, &mockDispatcher{}, repos)
+	processor := NewMessageProcessor(1, testRetryPolicy, &mockDispatcher{}, repos)
 
-	wg := asyncStartProcessorAndAssertError(t, processor, testErr)
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
 
-	msg := persistence.Message{}
+	msg := persistence.Message{Id: uuid.New()}
 	processor.Enqueue(msg)
+	time.Sleep(50 * time.Millisecond)
 
 	err := processor.Stop()
 	assert.Nil(t, err, "Actual err: %v", err)
 	wg.Wait()
+
+	assert.Equal(t, []uuid.UUID{msg.Id}, outboxMock.delivered)
 }
 
-func newTestMessageProcessor(t *testing.T) (Processor, db.Connection, *mockDispatcher) {
+func TestIT_MessageProcessor_WhenDeadLetterFails_ExpectNotMarkedAsDelivered(t *testing.T) {
+	mock := newMockMessageRepository(false, fmt.Errorf("some error"))
+	statusMock := &mockMessageStatusRepository{}
+	outboxMock := &mockOutboxRepository{}
+	repos := repositories.Repositories{
+		DeadLetter:    &mockDeadLetterRepository{err: fmt.Errorf("some error")},
+		Message:       mock,
+		MessageStatus: statusMock,
+		Outbox:        outboxMock,
+	}
+	processor := NewMessageProcessor(1, RetryPolicy{MaxAttempts: 1}, &mockDispatcher{}, repos)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	processor.Enqueue(persistence.Message{Id: uuid.New()})
+	time.Sleep(50 * time.Millisecond)
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Empty(t, outboxMock.delivered)
+	assert.Empty(t, statusMock.updated)
+}
+
+func TestIT_MessageProcessor_WhenMessageWasAlreadyPersisted_ExpectNoDeadLetter(t *testing.T) {
+	mock := newMockMessageRepository(false, errors.NewCode(pgx.UniqueConstraintViolation))
+	deadLetterMock := &mockDeadLetterRepository{}
+	outboxMock := &mockOutboxRepository{}
+	dispatcher := &mockDispatcher{}
+	repos := repositories.Repositories{
+		DeadLetter:    deadLetterMock,
+		Message:       mock,
+		MessageStatus: &mockMessageStatusRepository{},
+		Outbox:        outboxMock,
+	}
+	processor := NewMessageProcessor(1, testRetryPolicy, dispatcher, repos)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	msg := persistence.Message{Id: uuid.New()}
+	processor.Enqueue(msg)
+	time.Sleep(50 * time.Millisecond)
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), mock.calls.Load())
+	assert.Empty(t, deadLetterMock.created)
+	assert.Equal(t, []uuid.UUID{msg.Id}, outboxMock.delivered)
+	assert.Equal(t, uuid.Nil, dispatcher.receivedMsg.Id)
+}
+
+func TestIT_MessageProcessor_Batch_ExpectWrittenToDatabase(t *testing.T) {
 	conn := newTestDbConnection(t)
-	mock := &mockDispatcher{}
+	defer conn.Close(context.Background())
+	dispatcher := &mockDispatcher{}
+	processor := newTestBatchMessageProcessor(newTestRepositories(conn), dispatcher)
+
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	var expected []uuid.UUID
+	for id := range 2 {
+		msg := persistence.Message{
+			Id:       uuid.New(),
+			ChatUser: user.Id,
+			Room:     room.Id,
+			Message:  fmt.Sprintf("hello %d", id),
+		}
+		expected = append(expected, msg.Id)
+		processor.Enqueue(msg)
+	}
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	for _, id := range expected {
+		assertMessageExists(t, conn, id)
+		assertMessageStatus(t, conn, id, persistence.MessagePersisted)
+	}
+	assert.Equal(t, expected, dispatcher.received)
+}
+
+func TestIT_MessageProcessor_Batch_WhenOneMessageCannotBeWritten_ExpectOthersWritten(t *testing.T) {
+	conn := newTestDbConnection(t)
+	defer conn.Close(context.Background())
+	dispatcher := &mockDispatcher{}
+	processor := newTestBatchMessageProcessor(newTestRepositories(conn), dispatcher)
+
+	user := insertTestUser(t, conn)
+	other := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	valid := persistence.Message{
+		Id:       uuid.New(),
+		ChatUser: user.Id,
+		Room:     room.Id,
+		Message:  "hello",
+	}
+	invalid := persistence.Message{
+		Id:       uuid.New(),
+		ChatUser: other.Id,
+		Room:     room.Id,
+		Message:  "not in room",
+	}
+	processor.Enqueue(invalid)
+	processor.Enqueue(valid)
 
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assertMessageExists(t, conn, valid.Id)
+	assertMessageStatus(t, conn, valid.Id, persistence.MessagePersisted)
+	assertDeadLetterExists(t, conn, invalid.Id)
+	assert.Equal(t, []uuid.UUID{valid.Id}, dispatcher.received)
+}
+
+func TestIT_MessageProcessor_Batch_ExpectOneInsertAndMessagesBroadcastInOrder(t *testing.T) {
+	mock := newMockMessageRepository(false, nil)
+	outboxMock := &mockOutboxRepository{}
+	dispatcher := &mockDispatcher{}
+	repos := repositories.Repositories{
+		DeadLetter:    &mockDeadLetterRepository{},
+		Message:       mock,
+		MessageStatus: &mockMessageStatusRepository{},
+		Outbox:        outboxMock,
+	}
+	processor := newTestBatchMessageProcessor(repos, dispatcher)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	expected := []uuid.UUID{uuid.New(), uuid.New()}
+	for _, id := range expected {
+		processor.Enqueue(persistence.Message{Id: id})
+	}
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), mock.batchCalls.Load())
+	assert.Equal(t, int32(0), mock.calls.Load())
+	assert.Equal(t, expected, dispatcher.received)
+	assert.Equal(t, expected, outboxMock.delivered)
+}
+
+func TestIT_MessageProcessor_Batch_WhenBatchFails_ExpectMessagesWrittenOneByOne(t *testing.T) {
+	mock := newMockMessageRepository(false, nil)
+	mock.batchErr = errors.NewCode(pgx.ForeignKeyValidation)
+	dispatcher := &mockDispatcher{}
 	repos := repositories.Repositories{
-		User:    repositories.NewUserRepository(conn),
-		Room:    repositories.NewRoomRepository(conn),
-		Message: repositories.NewMessageRepository(conn),
+		DeadLetter:    &mockDeadLetterRepository{},
+		Message:       mock,
+		MessageStatus: &mockMessageStatusRepository{},
+		Outbox:        &mockOutboxRepository{},
+	}
+	processor := newTestBatchMessageProcessor(repos, dispatcher)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	expected := []uuid.UUID{uuid.New(), uuid.New()}
+	for _, id := range expected {
+		processor.Enqueue(persistence.Message{Id: id})
+	}
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), mock.batchCalls.Load())
+	assert.Equal(t, int32(2), mock.calls.Load())
+	assert.Equal(t, expected, dispatcher.received)
+}
+
+func newTestMessageProcessor(t *testing.T) (Processor[persistence.Message], db.Connection, *mockDispatcher) {
+	conn := newTestDbConnection(t)
+	mock := &mockDispatcher{}
+	repos := newTestRepositories(conn)
+
+	return NewMessageProcessor(1, testRetryPolicy, mock, repos), conn, mock
+}
+
+func newTestRepositories(conn db.Connection) repositories.Repositories {
+	return repositories.Repositories{
+		Attachment:    repositories.NewAttachmentRepository(conn),
+		User:          repositories.NewUserRepository(conn),
+		Room:          repositories.NewRoomRepository(conn),
+		Message:       repositories.NewMessageRepository(conn),
+		MessageStatus: repositories.NewMessageStatusRepository(conn),
+		DeadLetter:    repositories.NewDeadLetterRepository(conn),
+		Outbox:        repositories.NewOutboxRepository(conn),
 	}
+}
 
-	return NewMessageProcessor(1, mock, repos), conn, mock
+// newTestBatchMessageProcessor only processes a batch once it holds two
+// messages or when it is stopped.
+func newTestBatchMessageProcessor(
+	repos repositories.Repositories, dispatcher Dispatcher,
+) Processor[persistence.Message] {
+	batch := BatchPolicy{Size: 2, Delay: time.Hour}
+	return NewShardedMessageProcessor(1, 2, batch, testRetryPolicy, dispatcher, repos)
 }
 
 type mockDispatcher struct {
 	Dispatcher
 
 	receivedMsg persistence.Message
+	received    []uuid.UUID
+	err         error
 }
 
 func (m *mockDispatcher) Broadcast(msg persistence.Message) error {
 	m.receivedMsg = msg
-	return nil
+	m.received = append(m.received, msg.Id)
+	return m.err
 }
 
 type mockMessageRepository struct {
 	repositories.MessageRepository
 
-	block   atomic.Bool
-	unblock chan struct{}
+	block      atomic.Bool
+	unblock    chan struct{}
+	calls      atomic.Int32
+	batchCalls atomic.Int32
 
-	err error
+	err      error
+	batchErr error
 }
 
 func newMockMessageRepository(block bool, err error) *mockMessageRepository {
@@ -126,9 +538,83 @@ func newMockMessageRepository(block bool, err error) *mockMessageRepository {
 }
 
 func (m *mockMessageRepository) Create(ctx context.Context, msg persistence.Message) (persistence.Message, error) {
+	m.calls.Add(1)
 	if m.block.Load() {
 		<-m.unblock
 	}
 
 	return persistence.Message{}, m.err
 }
+
+func (m *mockMessageRepository) CreateBatch(
+	ctx context.Context, msgs []persistence.Message,
+) ([]persistence.Message, error) {
+	m.batchCalls.Add(1)
+	if m.batchErr != nil {
+		return nil, m.batchErr
+	}
+
+	return msgs, nil
+}
+
+type mockMessageStatusRepository struct {
+	repositories.MessageStatusRepository
+
+	created []persistence.MessageStatus
+	updated []persistence.MessageStatus
+}
+
+func (m *mockMessageStatusRepository) Create(
+	ctx context.Context, tx db.Transaction, status persistence.MessageStatus,
+) (persistence.MessageStatus, error) {
+	m.created = append(m.created, status)
+	return status, nil
+}
+
+func (m *mockMessageStatusRepository) Update(
+	ctx context.Context, status persistence.MessageStatus,
+) (persistence.MessageStatus, error) {
+	m.updated = append(m.updated, status)
+	return status, nil
+}
+
+type mockDeadLetterRepository struct {
+	repositories.DeadLetterRepository
+
+	err     error
+	created []persistence.DeadLetter
+}
+
+func (m *mockDeadLetterRepository) Create(
+	ctx context.Context, letter persistence.DeadLetter,
+) (persistence.DeadLetter, error) {
+	if m.err != nil {
+		return persistence.DeadLetter{}, m.err
+	}
+	m.created = append(m.created, letter)
+	return letter, nil
+}
+
+func assertDeadLetterExists(t *testing.T, conn db.Connection, id uuid.UUID) {
+	value, err := db.QueryOne[uuid.UUID](
+		context.Background(),
+		conn,
+		"SELECT id FROM dead_letter WHERE id = $1",
+		id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, id, value)
+}
+
+func assertMessageStatus(
+	t *testing.T, conn db.Connection, id uuid.UUID, expected string,
+) {
+	value, err := db.QueryOne[string](
+		context.Background(),
+		conn,
+		"SELECT status FROM message_status WHERE message = $1",
+		id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, expected, value)
+}
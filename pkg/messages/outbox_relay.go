@@ -0,0 +1,157 @@
+package messages
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
+)
+
+// deliveredOutboxRetention defines how long entries are kept in the
+// outbox once they have been handled by the processor.
+const deliveredOutboxRetention = time.Hour
+
+// maxDispatchedOutboxEntries bounds the number of entries read from the
+// outbox at each tick. The next ones are dispatched once the first ones
+// are delivered.
+const maxDispatchedOutboxEntries = 500
+
+// inFlightLease defines how long an entry handed to the processor is not
+// dispatched again. Once it elapsed, the entry is dispatched again: this
+// is safe as the processor detects messages which were already handled.
+const inFlightLease = time.Minute
+
+// notifyCoalescingDelay defines how long the relay waits after being
+// notified so that messages posted in a burst are dispatched at once.
+const notifyCoalescingDelay = 5 * time.Millisecond
+
+type OutboxRelay interface {
+	Start() error
+	Stop() error
+	// Notify asks the relay to dispatch the pending entries without
+	// waiting for the next tick.
+	Notify()
+}
+
+type outboxRelayImpl struct {
+	*periodicImpl
+
+	outboxRepo     repositories.OutboxRepository
+	attachmentRepo repositories.AttachmentRepository
+	processor      Processor[persistence.Message]
+
+	// inFlight holds the entries handed to the processor which are not
+	// yet marked as delivered along with the end of their lease. It is
+	// only accessed by the polling loop.
+	inFlight map[uuid.UUID]time.Time
+	notified atomic.Bool
+}
+
+func NewOutboxRelay(
+	pollInterval time.Duration,
+	repos repositories.Repositories,
+	processor Processor[persistence.Message],
+) OutboxRelay {
+	r := &outboxRelayImpl{
+		outboxRepo:     repos.Outbox,
+		attachmentRepo: repos.Attachment,
+		processor:      processor,
+		inFlight:       make(map[uuid.UUID]time.Time),
+	}
+
+	r.periodicImpl = newPeriodic(pollInterval, r.dispatchPending)
+
+	// The entries left over by a previous run of the server are picked
+	// up as soon as the relay starts.
+	r.trigger()
+
+	return r
+}
+
+func (r *outboxRelayImpl) Notify() {
+	if !r.notified.CompareAndSwap(false, true) {
+		return
+	}
+
+	time.AfterFunc(notifyCoalescingDelay, func() {
+		r.notified.Store(false)
+		r.trigger()
+	})
+}
+
+func (r *outboxRelayImpl) dispatchPending(now time.Time) {
+	ctx := context.Background()
+
+	pending, err := r.outboxRepo.ListPending(ctx, maxDispatchedOutboxEntries)
+	if err != nil {
+		return
+	}
+
+	inFlight := make(map[uuid.UUID]time.Time, len(pending))
+	blockedRooms := make(map[uuid.UUID]bool)
+
+	for _, entry := range pending {
+		// The entry might not have been marked as delivered because of a
+		// failure: it is dispatched again once its lease expired.
+		if leaseEnd, ok := r.inFlight[entry.Id]; ok && now.Before(leaseEnd) {
+			inFlight[entry.Id] = leaseEnd
+			continue
+		}
+
+		// Dispatching the next messages of the room would break their
+		// order: they will be retried at the next tick.
+		if blockedRooms[entry.Room] {
+			continue
+		}
+
+		msg, err := r.toMessage(ctx, entry)
+		if err != nil {
+			blockedRooms[entry.Room] = true
+			continue
+		}
+
+		r.processor.Enqueue(msg)
+		inFlight[entry.Id] = now.Add(inFlightLease)
+	}
+
+	r.inFlight = inFlight
+
+	// Voluntarily ignore errors: the entries will be removed at the
+	// next tick.
+	r.outboxRepo.DeleteDelivered(ctx, now.Add(-deliveredOutboxRetention))
+}
+
+func (r *outboxRelayImpl) toMessage(
+	ctx context.Context, entry persistence.OutboxEntry,
+) (persistence.Message, error) {
+	msg := persistence.Message{
+		Id:       entry.Id,
+		ChatUser: entry.ChatUser,
+		Room:     entry.Room,
+		Message:  entry.Message,
+
+		CreatedAt: entry.CreatedAt,
+		ExpiresAt: entry.ExpiresAt,
+
+		IdempotencyKey: entry.IdempotencyKey,
+	}
+
+	for _, id := range entry.Attachments {
+		attachment, err := r.attachmentRepo.Get(ctx, id)
+		// The attachment might have been removed in the meantime
+		if errors.IsErrorWithCode(err, db.NoMatchingRows) {
+			continue
+		} else if err != nil {
+			return persistence.Message{}, err
+		}
+
+		msg.Attachments = append(msg.Attachments, attachment)
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,301 @@
+package messages
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_OutboxRelay_StartStop(t *testing.T) {
+	relay, _, _, _ := newTestOutboxRelay(10 * time.Millisecond)
+
+	wg := asyncStartOutboxRelay(relay)
+	time.Sleep(50 * time.Millisecond)
+
+	err := relay.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+}
+
+func TestUnit_OutboxRelay_WhenEntriesArePending_ExpectEnqueuedInOrder(t *testing.T) {
+	relay, outboxRepo, _, processor := newTestOutboxRelay(time.Hour)
+
+	expiresAt := time.Now().Add(time.Hour)
+	first := newTestOutboxEntry(uuid.New())
+	first.ExpiresAt = &expiresAt
+	first.IdempotencyKey = "my-key"
+	second := newTestOutboxEntry(first.Room)
+	outboxRepo.pending = []persistence.OutboxEntry{first, second}
+
+	relay.dispatchPending(time.Now())
+
+	assert.Len(t, processor.enqueued, 2)
+	actual := processor.enqueued[0]
+	assert.Equal(t, first.Id, actual.Id)
+	assert.Equal(t, first.ChatUser, actual.ChatUser)
+	assert.Equal(t, first.Room, actual.Room)
+	assert.Equal(t, first.Message, actual.Message)
+	assert.Equal(t, first.CreatedAt, actual.CreatedAt)
+	assert.Equal(t, first.ExpiresAt, actual.ExpiresAt)
+	assert.Equal(t, first.IdempotencyKey, actual.IdempotencyKey)
+	assert.Equal(t, second.Id, processor.enqueued[1].Id)
+}
+
+func TestUnit_OutboxRelay_WhenEntryIsInFlight_ExpectNotEnqueuedTwice(t *testing.T) {
+	relay, outboxRepo, _, processor := newTestOutboxRelay(time.Hour)
+
+	entry := newTestOutboxEntry(uuid.New())
+	outboxRepo.pending = []persistence.OutboxEntry{entry}
+
+	relay.dispatchPending(time.Now())
+	relay.dispatchPending(time.Now())
+
+	assert.Len(t, processor.enqueued, 1)
+}
+
+func TestUnit_OutboxRelay_WhenLeaseExpired_ExpectEnqueuedAgain(t *testing.T) {
+	relay, outboxRepo, _, processor := newTestOutboxRelay(time.Hour)
+
+	entry := newTestOutboxEntry(uuid.New())
+	outboxRepo.pending = []persistence.OutboxEntry{entry}
+
+	now := time.Now()
+	relay.dispatchPending(now)
+	relay.dispatchPending(now.Add(inFlightLease))
+
+	assert.Len(t, processor.enqueued, 2)
+	assert.Equal(t, entry.Id, processor.enqueued[1].Id)
+}
+
+func TestUnit_OutboxRelay_ExpectPendingEntriesListedInBatches(t *testing.T) {
+	relay, outboxRepo, _, _ := newTestOutboxRelay(time.Hour)
+
+	relay.dispatchPending(time.Now())
+
+	assert.Equal(t, maxDispatchedOutboxEntries, outboxRepo.limit)
+}
+
+func TestUnit_OutboxRelay_WhenListingFails_ExpectNothingEnqueued(t *testing.T) {
+	relay, outboxRepo, _, processor := newTestOutboxRelay(time.Hour)
+
+	outboxRepo.pending = []persistence.OutboxEntry{newTestOutboxEntry(uuid.New())}
+	outboxRepo.err = errors.New("some error")
+
+	relay.dispatchPending(time.Now())
+
+	assert.Empty(t, processor.enqueued)
+}
+
+func TestUnit_OutboxRelay_WhenAttachmentWasRemoved_ExpectMessageEnqueuedWithoutIt(t *testing.T) {
+	relay, outboxRepo, attachmentRepo, processor := newTestOutboxRelay(time.Hour)
+
+	entry := newTestOutboxEntry(uuid.New())
+	entry.Attachments = []uuid.UUID{uuid.New()}
+	outboxRepo.pending = []persistence.OutboxEntry{entry}
+	attachmentRepo.err = errors.NewCode(db.NoMatchingRows)
+
+	relay.dispatchPending(time.Now())
+
+	assert.Len(t, processor.enqueued, 1)
+	assert.Empty(t, processor.enqueued[0].Attachments)
+}
+
+func TestUnit_OutboxRelay_WhenAttachmentCannotBeFetched_ExpectRoomBlocked(t *testing.T) {
+	relay, outboxRepo, attachmentRepo, processor := newTestOutboxRelay(time.Hour)
+
+	blocked := newTestOutboxEntry(uuid.New())
+	blocked.Attachments = []uuid.UUID{uuid.New()}
+	sameRoom := newTestOutboxEntry(blocked.Room)
+	otherRoom := newTestOutboxEntry(uuid.New())
+	outboxRepo.pending = []persistence.OutboxEntry{blocked, sameRoom, otherRoom}
+	attachmentRepo.err = errors.New("some error")
+
+	relay.dispatchPending(time.Now())
+
+	assert.Len(t, processor.enqueued, 1)
+	assert.Equal(t, otherRoom.Id, processor.enqueued[0].Id)
+}
+
+func TestUnit_OutboxRelay_WhenStarted_ExpectPendingEntriesRecovered(t *testing.T) {
+	relay, outboxRepo, _, processor := newTestOutboxRelay(time.Hour)
+
+	entry := newTestOutboxEntry(uuid.New())
+	outboxRepo.pending = []persistence.OutboxEntry{entry}
+
+	wg := asyncStartOutboxRelay(relay)
+	time.Sleep(50 * time.Millisecond)
+
+	err := relay.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Len(t, processor.enqueued, 1)
+	assert.Equal(t, entry.Id, processor.enqueued[0].Id)
+}
+
+func TestUnit_OutboxRelay_WhenNotified_ExpectPendingEntriesDispatched(t *testing.T) {
+	relay, outboxRepo, _, processor := newTestOutboxRelay(time.Hour)
+
+	wg := asyncStartOutboxRelay(relay)
+	time.Sleep(50 * time.Millisecond)
+
+	entry := newTestOutboxEntry(uuid.New())
+	outboxRepo.setPending([]persistence.OutboxEntry{entry})
+	relay.Notify()
+	time.Sleep(50 * time.Millisecond)
+
+	err := relay.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Len(t, processor.enqueued, 1)
+	assert.Equal(t, entry.Id, processor.enqueued[0].Id)
+}
+
+func TestUnit_OutboxRelay_WhenNotifiedRepeatedly_ExpectDispatchedOnce(t *testing.T) {
+	relay, outboxRepo, _, _ := newTestOutboxRelay(time.Hour)
+
+	wg := asyncStartOutboxRelay(relay)
+	time.Sleep(50 * time.Millisecond)
+
+	before := outboxRepo.listCount()
+	for range 10 {
+		relay.Notify()
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	err := relay.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Equal(t, before+1, outboxRepo.listCount())
+}
+
+type mockOutboxRepository struct {
+	repositories.OutboxRepository
+
+	lock      sync.Mutex
+	pending   []persistence.OutboxEntry
+	limit     int
+	listed    int
+	err       error
+	created   []persistence.OutboxEntry
+	delivered []uuid.UUID
+}
+
+func (m *mockOutboxRepository) Create(
+	ctx context.Context, tx db.Transaction, entry persistence.OutboxEntry,
+) (persistence.OutboxEntry, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.created = append(m.created, entry)
+	return entry, nil
+}
+
+func (m *mockOutboxRepository) ListPending(
+	ctx context.Context, limit int,
+) ([]persistence.OutboxEntry, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.limit = limit
+	m.listed++
+	return m.pending, m.err
+}
+
+func (m *mockOutboxRepository) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.delivered = append(m.delivered, id)
+	return nil
+}
+
+func (m *mockOutboxRepository) DeleteDelivered(ctx context.Context, until time.Time) error {
+	return nil
+}
+
+func (m *mockOutboxRepository) listCount() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.listed
+}
+
+func (m *mockOutboxRepository) setPending(pending []persistence.OutboxEntry) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.pending = pending
+}
+
+type mockAttachmentRepository struct {
+	repositories.AttachmentRepository
+
+	err error
+}
+
+func (m *mockAttachmentRepository) Get(
+	ctx context.Context, id uuid.UUID,
+) (persistence.Attachment, error) {
+	return persistence.Attachment{Id: id}, m.err
+}
+
+type mockProcessor struct {
+	Processor[persistence.Message]
+
+	enqueued []persistence.Message
+}
+
+func (m *mockProcessor) Enqueue(msg persistence.Message) {
+	m.enqueued = append(m.enqueued, msg)
+}
+
+func newTestOutboxRelay(interval time.Duration) (
+	*outboxRelayImpl,
+	*mockOutboxRepository,
+	*mockAttachmentRepository,
+	*mockProcessor,
+) {
+	outboxRepo := &mockOutboxRepository{}
+	attachmentRepo := &mockAttachmentRepository{}
+	processor := &mockProcessor{}
+
+	repos := repositories.Repositories{
+		Attachment: attachmentRepo,
+		Outbox:     outboxRepo,
+	}
+
+	relay := NewOutboxRelay(interval, repos, processor)
+	return relay.(*outboxRelayImpl), outboxRepo, attachmentRepo, processor
+}
+
+func newTestOutboxEntry(room uuid.UUID) persistence.OutboxEntry {
+	return persistence.OutboxEntry{
+		Id:        uuid.New(),
+		ChatUser:  uuid.New(),
+		Room:      room,
+		Message:   "hello",
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func asyncStartOutboxRelay(relay OutboxRelay) *sync.WaitGroup {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		relay.Start()
+	}()
+
+	return &wg
+}
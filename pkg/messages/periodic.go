@@ -0,0 +1,80 @@
+package messages
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+type tickCallback func(now time.Time)
+
+// periodicImpl calls the callback at a fixed interval until it is
+// stopped. It is meant to be embedded by runnables polling the db.
+type periodicImpl struct {
+	interval time.Duration
+	callback tickCallback
+
+	running atomic.Bool
+	stopped atomic.Bool
+	wake    chan struct{}
+	quit    chan struct{}
+	done    chan struct{}
+}
+
+func newPeriodic(interval time.Duration, callback tickCallback) *periodicImpl {
+	return &periodicImpl{
+		interval: interval,
+		callback: callback,
+
+		wake: make(chan struct{}, 1),
+		quit: make(chan struct{}, 1),
+		done: make(chan struct{}, 1),
+	}
+}
+
+func (p *periodicImpl) Start() error {
+	if !p.running.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	defer func() {
+		p.done <- struct{}{}
+	}()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.quit:
+			return nil
+		case <-p.wake:
+			p.callback(time.Now())
+		case now := <-ticker.C:
+			p.callback(now)
+		}
+	}
+}
+
+// trigger runs the callback without waiting for the next tick. Calls
+// made while a run is already pending are merged.
+func (p *periodicImpl) trigger() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *periodicImpl) Stop() error {
+	if !p.stopped.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	close(p.quit)
+	// A component stopped before it started returns as soon as it starts:
+	// there is nothing to wait for.
+	if p.running.Load() {
+		<-p.done
+	}
+
+	return nil
+}
@@ -2,25 +2,44 @@ package messages
 
 import (
 	"sync/atomic"
+	"time"
 
 	"github.com/Knoblauchpilze/backend-toolkit/pkg/process"
-	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
 )
 
-type processorImpl struct {
-	queue     chan persistence.Message
-	callbacks Callbacks
+// BatchPolicy defines how many messages are accumulated before being
+// handed to the batch callback. A batch is processed as soon as it is
+// full or when the delay since its first message expired.
+type BatchPolicy struct {
+	Size  int
+	Delay time.Duration
+}
+
+type processorImpl[T any] struct {
+	queue     chan T
+	batch     BatchPolicy
+	callbacks Callbacks[T]
 
 	running atomic.Bool
+	stopped atomic.Bool
 	quit    chan struct{}
 	done    chan struct{}
 }
 
-func NewProcessor(
-	messageQueueSize int, callbacks Callbacks,
-) Processor {
-	return &processorImpl{
-		queue:     make(chan persistence.Message, messageQueueSize),
+func NewProcessor[T any](
+	messageQueueSize int, callbacks Callbacks[T],
+) Processor[T] {
+	return NewBatchProcessor(messageQueueSize, BatchPolicy{Size: 1}, callbacks)
+}
+
+func NewBatchProcessor[T any](
+	messageQueueSize int, batch BatchPolicy, callbacks Callbacks[T],
+) Processor[T] {
+	batch.Size = max(batch.Size, 1)
+
+	return &processorImpl[T]{
+		queue:     make(chan T, messageQueueSize),
+		batch:     batch,
 		callbacks: callbacks,
 
 		quit: make(chan struct{}, 1),
@@ -28,7 +47,7 @@ func NewProcessor(
 	}
 }
 
-func (p *processorImpl) Start() error {
+func (p *processorImpl[T]) Start() error {
 	if !p.running.CompareAndSwap(false, true) {
 		return nil
 	}
@@ -36,22 +55,35 @@ func (p *processorImpl) Start() error {
 	return p.activeLoop()
 }
 
-func (p *processorImpl) Stop() error {
-	if !p.running.CompareAndSwap(true, false) {
+func (p *processorImpl[T]) Stop() error {
+	if !p.stopped.CompareAndSwap(false, true) {
 		return nil
 	}
 
-	p.quit <- struct{}{}
-	<-p.done
+	close(p.quit)
+	// A component stopped before it started returns as soon as it starts:
+	// there is nothing to wait for.
+	if p.running.Load() {
+		<-p.done
+	}
 
 	return nil
 }
 
-func (p *processorImpl) Enqueue(msg persistence.Message) {
+func (p *processorImpl[T]) Enqueue(msg T) {
 	p.queue <- msg
 }
 
-func (p *processorImpl) activeLoop() error {
+func (p *processorImpl[T]) TryEnqueue(msg T) bool {
+	select {
+	case p.queue <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *processorImpl[T]) activeLoop() error {
 	running := true
 
 	var err error
@@ -67,12 +99,39 @@ func (p *processorImpl) activeLoop() error {
 		}
 	}
 
+	var batch []T
+	var timer *time.Timer
+	var expired <-chan time.Time
+
+	flush := func() error {
+		if timer != nil {
+			timer.Stop()
+			timer, expired = nil, nil
+		}
+
+		msgs := batch
+		batch = nil
+		return p.processBatch(msgs)
+	}
+
 	for running {
 		select {
 		case <-p.quit:
 			running = false
+			err = flush()
+			if err == nil {
+				err = p.drain()
+			}
 		case msg := <-p.queue:
-			err = p.processMessage(msg)
+			batch = append(batch, msg)
+			if len(batch) >= p.batch.Size {
+				err = flush()
+			} else if timer == nil {
+				timer = time.NewTimer(p.batch.Delay)
+				expired = timer.C
+			}
+		case <-expired:
+			err = flush()
 		}
 
 		if err != nil {
@@ -90,7 +149,53 @@ func (p *processorImpl) activeLoop() error {
 	return err
 }
 
-func (p *processorImpl) processMessage(msg persistence.Message) error {
+// drain processes the messages still in the queue when the processor
+// is asked to stop so that they are not lost.
+func (p *processorImpl[T]) drain() error {
+	var batch []T
+
+	for {
+		select {
+		case msg := <-p.queue:
+			batch = append(batch, msg)
+			if len(batch) < p.batch.Size {
+				continue
+			}
+
+			if err := p.processBatch(batch); err != nil {
+				return err
+			}
+			batch = nil
+		default:
+			return p.processBatch(batch)
+		}
+	}
+}
+
+func (p *processorImpl[T]) processBatch(msgs []T) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	// The batch callback takes precedence over the message one.
+	if p.callbacks.Batch != nil {
+		return process.SafeRunSync(
+			func() error {
+				return p.callbacks.Batch(msgs)
+			},
+		)
+	}
+
+	for _, msg := range msgs {
+		if err := p.processMessage(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *processorImpl[T]) processMessage(msg T) error {
 	return process.SafeRunSync(
 		func() error {
 			// Note: this is technically unsafe as we don't verify that the
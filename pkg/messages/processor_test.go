@@ -46,6 +46,25 @@ func TestUnit_Processor_WhenStartCallbackFails_ExpectErrorIsReturned(t *testing.
 	wg.Wait()
 }
 
+func TestUnit_Processor_WhenStoppedBeforeStart_ExpectStartReturns(t *testing.T) {
+	processor := newTestProcessorWithCallbacks(nil, dummyMessageCallback, nil)
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- processor.Start()
+	}()
+
+	select {
+	case err = <-done:
+		assert.Nil(t, err, "Actual err: %v", err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "Processor started after being stopped")
+	}
+}
+
 func TestUnit_Processor_EnqueueMessage_ExpectMessageCallbackCalled(t *testing.T) {
 	var receivedMsg persistence.Message
 	var called int
@@ -146,6 +165,49 @@ func TestUnit_Processor_WhenMessageFailsToBeProcessed_ExpectProcessingStops(t *t
 	wg.Wait()
 }
 
+func TestUnit_Processor_WhenStopped_ExpectQueuedMessagesProcessed(t *testing.T) {
+	var called atomic.Int32
+	unblock := make(chan struct{}, 1)
+
+	blockingMsgCb := func(msg persistence.Message) error {
+		if called.Add(1) == 1 {
+			<-unblock
+		}
+
+		return nil
+	}
+	processor := newTestProcessorWithCallbacks(nil, blockingMsgCb, nil)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	// The first message is stuck in the callback and the second one
+	// stays in the queue.
+	processor.Enqueue(persistence.Message{})
+	processor.Enqueue(persistence.Message{})
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- processor.Stop()
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	unblock <- struct{}{}
+
+	err := <-stopped
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), called.Load())
+}
+
+func TestUnit_Processor_TryEnqueue_WhenQueueIsFull_ExpectFalse(t *testing.T) {
+	processor := newTestProcessorWithCallbacks(nil, dummyMessageCallback, nil)
+
+	// The processor is not started so the queue is never consumed.
+	assert.True(t, processor.TryEnqueue(persistence.Message{}))
+	assert.False(t, processor.TryEnqueue(persistence.Message{}))
+}
+
 func TestUnit_Processor_WhenStopped_ExpectFinishCallbackCalled(t *testing.T) {
 	var called int
 	finishCb := func() error {
@@ -190,12 +252,102 @@ func TestUnit_Processor_WhenFinishCallbackFails_ExpectErrorIsReturned(t *testing
 	wg.Wait()
 }
 
+func TestUnit_BatchProcessor_WhenBatchIsFull_ExpectBatchCallbackCalled(t *testing.T) {
+	batches := make(chan []persistence.Message, 1)
+	processor := newTestBatchProcessor(2, time.Hour, batches)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	first := persistence.Message{Id: uuid.New()}
+	second := persistence.Message{Id: uuid.New()}
+	processor.Enqueue(first)
+	processor.Enqueue(second)
+
+	select {
+	case batch := <-batches:
+		assert.Equal(t, []persistence.Message{first, second}, batch)
+	case <-time.After(time.Second):
+		t.Errorf("Batch was not processed")
+	}
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+}
+
+func TestUnit_BatchProcessor_WhenDelayExpires_ExpectPartialBatchProcessed(t *testing.T) {
+	batches := make(chan []persistence.Message, 1)
+	processor := newTestBatchProcessor(10, 20*time.Millisecond, batches)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	msg := persistence.Message{Id: uuid.New()}
+	processor.Enqueue(msg)
+
+	select {
+	case batch := <-batches:
+		assert.Equal(t, []persistence.Message{msg}, batch)
+	case <-time.After(time.Second):
+		t.Errorf("Batch was not processed")
+	}
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+}
+
+func TestUnit_BatchProcessor_WhenStopped_ExpectPendingBatchProcessed(t *testing.T) {
+	batches := make(chan []persistence.Message, 1)
+	processor := newTestBatchProcessor(10, time.Hour, batches)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	first := persistence.Message{Id: uuid.New()}
+	second := persistence.Message{Id: uuid.New()}
+	processor.Enqueue(first)
+	processor.Enqueue(second)
+	time.Sleep(50 * time.Millisecond)
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Equal(t, []persistence.Message{first, second}, <-batches)
+}
+
+func TestUnit_BatchProcessor_WhenBatchCallbackFails_ExpectErrorIsReturned(t *testing.T) {
+	testErr := fmt.Errorf("some error")
+	cb := Callbacks[persistence.Message]{
+		Batch: func(msgs []persistence.Message) error {
+			return testErr
+		},
+	}
+	processor := NewBatchProcessor(1, BatchPolicy{Size: 1}, cb)
+
+	wg := asyncStartProcessorAndAssertError(t, processor, testErr)
+
+	processor.Enqueue(persistence.Message{})
+	wg.Wait()
+}
+
+func newTestBatchProcessor(
+	size int, delay time.Duration, batches chan []persistence.Message,
+) Processor[persistence.Message] {
+	cb := Callbacks[persistence.Message]{
+		Batch: func(msgs []persistence.Message) error {
+			batches <- msgs
+			return nil
+		},
+	}
+	return NewBatchProcessor(size, BatchPolicy{Size: size, Delay: delay}, cb)
+}
+
 func newTestProcessorWithCallbacks(
 	startCallback StartCallback,
-	msgCallback MessageCallback,
+	msgCallback MessageCallback[persistence.Message],
 	finishCallback FinishCallback,
-) Processor {
-	cb := Callbacks{
+) Processor[persistence.Message] {
+	cb := Callbacks[persistence.Message]{
 		Start:   startCallback,
 		Message: msgCallback,
 		Finish:  finishCallback,
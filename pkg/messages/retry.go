@@ -0,0 +1,69 @@
+package messages
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/pgx"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// RetryPolicy defines how many times an action is attempted before
+// giving up. The backoff is doubled after each failed attempt.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// run executes the action until it succeeds, fails with a permanent
+// error or the maximum number of attempts is reached. It returns the
+// number of attempts along with the last error.
+func (p RetryPolicy) run(action func() error) (int, error) {
+	backoff := p.Backoff
+	attempts := 0
+
+	for {
+		attempts++
+
+		err := action()
+		if err == nil || !isTransientError(err) || attempts >= p.MaxAttempts {
+			return attempts, err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// integrityConstraintViolation is the class of the errors reported by
+// the database when the data is not valid, see:
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const integrityConstraintViolation = "23"
+
+// isTransientError returns false for errors caused by the data itself
+// (constraint violations, missing rows, ...): retrying would produce the
+// same result. Other database errors, including the ones raised when the
+// connection is lost, are worth retrying.
+func isTransientError(err error) bool {
+	permanent := errors.IsErrorWithCode(err, pgx.ForeignKeyValidation) ||
+		errors.IsErrorWithCode(err, pgx.UniqueConstraintViolation) ||
+		errors.IsErrorWithCode(err, db.NoMatchingRows) ||
+		isConstraintViolation(err)
+
+	return !permanent
+}
+
+func isConstraintViolation(err error) bool {
+	if !errors.IsErrorWithCode(err, pgx.GenericSqlError) {
+		return false
+	}
+
+	pgErr, ok := errors.Unwrap(err).(*pgconn.PgError)
+	if !ok {
+		return false
+	}
+
+	return strings.HasPrefix(pgErr.Code, integrityConstraintViolation)
+}
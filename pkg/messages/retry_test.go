@@ -0,0 +1,151 @@
+package messages
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/pgx"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_RetryPolicy_WhenActionSucceeds_ExpectSingleAttempt(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+
+	calls := 0
+	attempts, err := policy.run(func() error {
+		calls++
+		return nil
+	})
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, 1, calls)
+}
+
+func TestUnit_RetryPolicy_WhenErrorIsTransient_ExpectRetried(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+
+	calls := 0
+	attempts, err := policy.run(func() error {
+		calls++
+		if calls < 2 {
+			return fmt.Errorf("some error")
+		}
+		return nil
+	})
+
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestUnit_RetryPolicy_WhenErrorIsTransient_ExpectMaxAttemptsRespected(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	testErr := fmt.Errorf("some error")
+
+	calls := 0
+	attempts, err := policy.run(func() error {
+		calls++
+		return testErr
+	})
+
+	assert.Equal(t, testErr, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 3, calls)
+}
+
+func TestUnit_RetryPolicy_WhenErrorIsPermanent_ExpectNotRetried(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	testErr := errors.NewCode(pgx.ForeignKeyValidation)
+
+	calls := 0
+	attempts, err := policy.run(func() error {
+		calls++
+		return testErr
+	})
+
+	assert.Equal(t, testErr, err)
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, 1, calls)
+}
+
+func TestUnit_IsTransientError(t *testing.T) {
+	type testCase struct {
+		err      error
+		expected bool
+	}
+
+	testCases := map[string]testCase{
+		"unknown": {
+			err:      fmt.Errorf("some error"),
+			expected: true,
+		},
+		"connectionLost": {
+			err:      errors.NewCode(pgx.GenericSqlError),
+			expected: true,
+		},
+		"deadlock": {
+			err:      pgx.AnalyzeAndWrapPgError(&pgconn.PgError{Code: "40P01"}),
+			expected: true,
+		},
+		"adminShutdown": {
+			err:      pgx.AnalyzeAndWrapPgError(&pgconn.PgError{Code: "57P01"}),
+			expected: true,
+		},
+		"foreignKeyViolation": {
+			err:      pgx.AnalyzeAndWrapPgError(&pgconn.PgError{Code: "23503"}),
+			expected: false,
+		},
+		"uniqueViolation": {
+			err:      pgx.AnalyzeAndWrapPgError(&pgconn.PgError{Code: "23505"}),
+			expected: false,
+		},
+		"checkViolation": {
+			err:      pgx.AnalyzeAndWrapPgError(&pgconn.PgError{Code: "23514"}),
+			expected: false,
+		},
+		"noMatchingRows": {
+			err:      errors.NewCode(db.NoMatchingRows),
+			expected: false,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual := isTransientError(testCase.err)
+
+			assert.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestUnit_RetryPolicy_WhenDatabaseIsUnavailable_ExpectRetried(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	testErr := pgx.AnalyzeAndWrapPgError(&pgconn.PgError{Code: "57P01"})
+
+	calls := 0
+	attempts, err := policy.run(func() error {
+		calls++
+		return testErr
+	})
+
+	assert.Equal(t, testErr, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 3, calls)
+}
+
+func TestUnit_RetryPolicy_WhenNoAttemptsConfigured_ExpectSingleAttempt(t *testing.T) {
+	policy := RetryPolicy{}
+
+	calls := 0
+	attempts, _ := policy.run(func() error {
+		calls++
+		return fmt.Errorf("some error")
+	})
+
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,121 @@
+package messages
+
+import (
+	"context"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+)
+
+type Scheduler interface {
+	Start() error
+	Stop() error
+}
+
+type schedulerImpl struct {
+	*periodicImpl
+
+	conn          db.Connection
+	scheduledRepo repositories.ScheduledMessageRepository
+	roomRepo      repositories.RoomRepository
+	statusRepo    repositories.MessageStatusRepository
+	outboxRepo    repositories.OutboxRepository
+	relay         OutboxRelay
+}
+
+// NewScheduler moves the messages which are due to the outbox: they are
+// then handled by the relay like the messages posted by the clients.
+func NewScheduler(
+	pollInterval time.Duration,
+	conn db.Connection,
+	repos repositories.Repositories,
+	relay OutboxRelay,
+) Scheduler {
+	s := &schedulerImpl{
+		conn:          conn,
+		scheduledRepo: repos.ScheduledMessage,
+		roomRepo:      repos.Room,
+		statusRepo:    repos.MessageStatus,
+		outboxRepo:    repos.Outbox,
+		relay:         relay,
+	}
+
+	// Failing to move the due messages leaves them in the table: they
+	// will be picked up again at the next tick.
+	s.periodicImpl = newPeriodic(pollInterval, s.enqueueDueMessages)
+
+	return s
+}
+
+func (s *schedulerImpl) enqueueDueMessages(now time.Time) {
+	moved, err := s.moveDueMessagesToOutbox(context.Background(), now)
+	if err != nil || moved == 0 {
+		return
+	}
+
+	s.relay.Notify()
+}
+
+// moveDueMessagesToOutbox removes the due messages and writes them to the
+// outbox in the same transaction so that they are not lost if the server
+// stops in between.
+func (s *schedulerImpl) moveDueMessagesToOutbox(
+	ctx context.Context, now time.Time,
+) (int, error) {
+	tx, err := s.conn.BeginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Close(ctx)
+
+	due, err := s.scheduledRepo.DeleteDue(ctx, tx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, scheduled := range due {
+		// The author might have left the room since the message was
+		// scheduled: in this case the message is dropped.
+		registered, err := s.roomRepo.UserInRoom(ctx, scheduled.ChatUser, scheduled.Room)
+		if err != nil {
+			return 0, err
+		}
+		if !registered {
+			continue
+		}
+
+		msg := communication.FromScheduledMessage(scheduled)
+		if err := s.writeToOutbox(ctx, tx, msg); err != nil {
+			return 0, err
+		}
+		moved++
+	}
+
+	return moved, nil
+}
+
+func (s *schedulerImpl) writeToOutbox(
+	ctx context.Context, tx db.Transaction, msg persistence.Message,
+) error {
+	status := persistence.MessageStatus{
+		Message: msg.Id,
+		Room:    msg.Room,
+		Status:  persistence.MessageQueued,
+	}
+	if _, err := s.statusRepo.Create(ctx, tx, status); err != nil {
+		return err
+	}
+
+	entry := persistence.OutboxEntry{
+		Id:       msg.Id,
+		ChatUser: msg.ChatUser,
+		Room:     msg.Room,
+		Message:  msg.Message,
+	}
+	_, err := s.outboxRepo.Create(ctx, tx, entry)
+	return err
+}
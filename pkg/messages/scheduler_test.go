@@ -0,0 +1,250 @@
+package messages
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_Scheduler_StartStop(t *testing.T) {
+	scheduler, _, _, _ := newTestScheduler()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := scheduler.Start()
+		assert.Nil(t, err, "Actual err: %v", err)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	err := scheduler.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+}
+
+func TestUnit_Scheduler_WhenMessagesAreDue_ExpectEnqueued(t *testing.T) {
+	scheduler, scheduledRepo, roomRepo, outbox := newTestScheduler()
+
+	scheduled := persistence.ScheduledMessage{
+		Id:       uuid.New(),
+		ChatUser: uuid.New(),
+		Room:     uuid.New(),
+		Message:  "hello",
+		SendAt:   time.Now(),
+	}
+	scheduledRepo.due = []persistence.ScheduledMessage{scheduled}
+	roomRepo.registered = true
+
+	now := time.Now()
+	scheduler.enqueueDueMessages(now)
+
+	assert.Equal(t, now, scheduledRepo.until)
+	assert.Equal(t, 1, len(outbox.repo.created))
+	actual := outbox.repo.created[0]
+	assert.Equal(t, scheduled.Id, actual.Id)
+	assert.Equal(t, scheduled.ChatUser, actual.ChatUser)
+	assert.Equal(t, scheduled.Room, actual.Room)
+	assert.Equal(t, scheduled.Message, actual.Message)
+	assert.Equal(t, 1, len(outbox.status.created))
+	assert.Equal(t, persistence.MessageQueued, outbox.status.created[0].Status)
+	assert.Equal(t, 1, outbox.relay.notified)
+}
+
+func TestUnit_Scheduler_WhenUserLeftTheRoom_ExpectMessageDropped(t *testing.T) {
+	scheduler, scheduledRepo, roomRepo, outbox := newTestScheduler()
+
+	scheduledRepo.due = []persistence.ScheduledMessage{
+		{
+			Id:       uuid.New(),
+			ChatUser: uuid.New(),
+			Room:     uuid.New(),
+			Message:  "hello",
+		},
+	}
+	roomRepo.registered = false
+
+	scheduler.enqueueDueMessages(time.Now())
+
+	assert.Empty(t, outbox.repo.created)
+	assert.Equal(t, 0, outbox.relay.notified)
+}
+
+func TestUnit_Scheduler_WhenFetchingDueMessagesFails_ExpectNothingEnqueued(t *testing.T) {
+	scheduler, scheduledRepo, roomRepo, outbox := newTestScheduler()
+
+	scheduledRepo.err = errors.New("some error")
+	roomRepo.registered = true
+
+	scheduler.enqueueDueMessages(time.Now())
+
+	assert.Empty(t, outbox.repo.created)
+	assert.Equal(t, 0, outbox.relay.notified)
+}
+
+func TestUnit_Scheduler_WhenTicking_ExpectDueMessagesEnqueued(t *testing.T) {
+	scheduler, scheduledRepo, roomRepo, outbox := newTestScheduler()
+
+	scheduledRepo.due = []persistence.ScheduledMessage{
+		{
+			Id:       uuid.New(),
+			ChatUser: uuid.New(),
+			Room:     uuid.New(),
+			Message:  "hello",
+		},
+	}
+	roomRepo.registered = true
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		scheduler.Start()
+	}()
+
+	time.Sleep(5 * scheduler.interval)
+
+	err := scheduler.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Equal(t, 1, len(outbox.repo.created))
+}
+
+func TestIT_Scheduler_WhenMessageIsDue_ExpectMovedToOutbox(t *testing.T) {
+	conn := newTestDbConnection(t)
+	defer conn.Close(context.Background())
+	repos := repositories.New(conn)
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+
+	scheduled := persistence.ScheduledMessage{
+		Id:       uuid.New(),
+		ChatUser: user.Id,
+		Room:     room.Id,
+		Message:  "hello",
+		SendAt:   time.Now().Add(-time.Minute),
+	}
+	_, err := repos.ScheduledMessage.Create(context.Background(), scheduled)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	relay := &mockRelay{}
+	scheduler := NewScheduler(time.Hour, conn, repos, relay).(*schedulerImpl)
+	scheduler.enqueueDueMessages(time.Now())
+
+	entry, err := repos.Outbox.Get(context.Background(), scheduled.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, scheduled.Message, entry.Message)
+	status, err := repos.MessageStatus.Get(context.Background(), scheduled.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, persistence.MessageQueued, status.Status)
+	_, err = repos.ScheduledMessage.Get(context.Background(), scheduled.Id)
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+	assert.Equal(t, 1, relay.notified)
+}
+
+type mockScheduledMessageRepository struct {
+	repositories.ScheduledMessageRepository
+
+	lock  sync.Mutex
+	due   []persistence.ScheduledMessage
+	err   error
+	until time.Time
+}
+
+type mockRoomRepository struct {
+	repositories.RoomRepository
+
+	registered bool
+}
+
+type mockConnection struct {
+	db.Connection
+}
+
+type mockTransaction struct {
+	db.Transaction
+}
+
+type mockRelay struct {
+	OutboxRelay
+
+	notified int
+}
+
+// mockOutbox gathers what is written to the outbox.
+type mockOutbox struct {
+	repo   *mockOutboxRepository
+	status *mockMessageStatusRepository
+	relay  *mockRelay
+}
+
+func newTestScheduler() (
+	*schedulerImpl,
+	*mockScheduledMessageRepository,
+	*mockRoomRepository,
+	*mockOutbox,
+) {
+	scheduledRepo := &mockScheduledMessageRepository{}
+	roomRepo := &mockRoomRepository{}
+	outbox := &mockOutbox{
+		repo:   &mockOutboxRepository{},
+		status: &mockMessageStatusRepository{},
+		relay:  &mockRelay{},
+	}
+
+	repos := repositories.Repositories{
+		MessageStatus:    outbox.status,
+		Outbox:           outbox.repo,
+		ScheduledMessage: scheduledRepo,
+		Room:             roomRepo,
+	}
+
+	scheduler := NewScheduler(10*time.Millisecond, &mockConnection{}, repos, outbox.relay)
+	return scheduler.(*schedulerImpl), scheduledRepo, roomRepo, outbox
+}
+
+func (m *mockConnection) BeginTx(ctx context.Context) (db.Transaction, error) {
+	return &mockTransaction{}, nil
+}
+
+func (m *mockTransaction) Close(ctx context.Context) {}
+
+func (m *mockRelay) Notify() {
+	m.notified++
+}
+
+// DeleteDue mimics the behavior of the database: messages are only
+// returned once.
+func (m *mockScheduledMessageRepository) DeleteDue(
+	ctx context.Context, tx db.Transaction, until time.Time,
+) ([]persistence.ScheduledMessage, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.until = until
+	out := m.due
+	m.due = nil
+	return out, m.err
+}
+
+func (m *mockRoomRepository) UserInRoom(
+	ctx context.Context, user uuid.UUID, room uuid.UUID,
+) (bool, error) {
+	return m.registered, nil
+}
@@ -0,0 +1,101 @@
+package messages
+
+import (
+	"hash/fnv"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+)
+
+// KeyFunc extracts the key used to route a message to a shard. Messages
+// with the same key are always processed by the same shard, in order.
+type KeyFunc[T any] func(msg T) uuid.UUID
+
+type QueueMonitor interface {
+	// QueueDepths returns the number of messages waiting in the queue of
+	// each shard.
+	QueueDepths() []int
+}
+
+type ShardedProcessor[T any] interface {
+	Processor[T]
+	QueueMonitor
+}
+
+type shardedProcessorImpl[T any] struct {
+	shards []*processorImpl[T]
+	key    KeyFunc[T]
+}
+
+func NewShardedProcessor[T any](
+	workers int,
+	messageQueueSize int,
+	batch BatchPolicy,
+	key KeyFunc[T],
+	callbacks Callbacks[T],
+) ShardedProcessor[T] {
+	workers = max(workers, 1)
+
+	p := &shardedProcessorImpl[T]{
+		shards: make([]*processorImpl[T], 0, workers),
+		key:    key,
+	}
+	for range workers {
+		shard := NewBatchProcessor(messageQueueSize, batch, callbacks).(*processorImpl[T])
+		p.shards = append(p.shards, shard)
+	}
+
+	return p
+}
+
+func (p *shardedProcessorImpl[T]) Start() error {
+	var group errgroup.Group
+	for _, shard := range p.shards {
+		group.Go(func() error {
+			err := shard.Start()
+			if err != nil {
+				// The processor is not usable without all its shards.
+				p.Stop()
+			}
+			return err
+		})
+	}
+
+	return group.Wait()
+}
+
+func (p *shardedProcessorImpl[T]) Stop() error {
+	// Shards are drained in parallel to fit in the shutdown timeout.
+	var group errgroup.Group
+	for _, shard := range p.shards {
+		group.Go(shard.Stop)
+	}
+
+	return group.Wait()
+}
+
+func (p *shardedProcessorImpl[T]) Enqueue(msg T) {
+	p.shardFor(msg).Enqueue(msg)
+}
+
+func (p *shardedProcessorImpl[T]) TryEnqueue(msg T) bool {
+	return p.shardFor(msg).TryEnqueue(msg)
+}
+
+func (p *shardedProcessorImpl[T]) QueueDepths() []int {
+	out := make([]int, 0, len(p.shards))
+	for _, shard := range p.shards {
+		out = append(out, len(shard.queue))
+	}
+
+	return out
+}
+
+func (p *shardedProcessorImpl[T]) shardFor(msg T) *processorImpl[T] {
+	key := p.key(msg)
+
+	hash := fnv.New32a()
+	hash.Write(key[:])
+
+	return p.shards[hash.Sum32()%uint32(len(p.shards))]
+}
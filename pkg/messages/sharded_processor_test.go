@@ -0,0 +1,166 @@
+package messages
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_ShardedProcessor_WhenWorkersIsNotPositive_ExpectOneShard(t *testing.T) {
+	processor := newTestShardedProcessor(0, 1, dummyMessageCallback)
+
+	assert.Equal(t, []int{0}, processor.QueueDepths())
+}
+
+func TestUnit_ShardedProcessor_WhenMessagesForSameRoom_ExpectProcessedInOrder(t *testing.T) {
+	var lock sync.Mutex
+	var received []uuid.UUID
+	msgCb := func(msg persistence.Message) error {
+		lock.Lock()
+		defer lock.Unlock()
+		received = append(received, msg.Id)
+		return nil
+	}
+	processor := newTestShardedProcessor(4, 1, msgCb)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	room := uuid.New()
+	var expected []uuid.UUID
+	for range 20 {
+		msg := persistence.Message{Id: uuid.New(), Room: room}
+		expected = append(expected, msg.Id)
+		processor.Enqueue(msg)
+	}
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Equal(t, expected, received)
+}
+
+func TestUnit_ShardedProcessor_WhenShardIsBlocked_ExpectOtherRoomsProcessed(t *testing.T) {
+	blockedRoom, otherRoom := newTestRoomsOnDifferentShards(t, 2)
+	unblock := make(chan struct{})
+	processed := make(chan uuid.UUID, 1)
+	msgCb := func(msg persistence.Message) error {
+		if msg.Room == blockedRoom {
+			<-unblock
+			return nil
+		}
+
+		processed <- msg.Room
+		return nil
+	}
+	processor := newTestShardedProcessor(2, 1, msgCb)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	processor.Enqueue(persistence.Message{Room: blockedRoom})
+	processor.Enqueue(persistence.Message{Room: otherRoom})
+
+	select {
+	case room := <-processed:
+		assert.Equal(t, otherRoom, room)
+	case <-time.After(time.Second):
+		t.Errorf("Message for %s was not processed", otherRoom)
+	}
+
+	close(unblock)
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+}
+
+func TestUnit_ShardedProcessor_QueueDepths_ExpectMessagesWaitingInEachShard(t *testing.T) {
+	blockedRoom, _ := newTestRoomsOnDifferentShards(t, 2)
+	unblock := make(chan struct{})
+	msgCb := func(msg persistence.Message) error {
+		<-unblock
+		return nil
+	}
+	processor := newTestShardedProcessor(2, 3, msgCb)
+
+	wg := asyncStartProcessorAndAssertNoError(t, processor)
+
+	// The first message is stuck in the callback and the other ones stay
+	// in the queue.
+	for range 3 {
+		processor.Enqueue(persistence.Message{Room: blockedRoom})
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	shard := indexOfShard(processor, blockedRoom)
+	expected := []int{0, 0}
+	expected[shard] = 2
+	assert.Equal(t, expected, processor.QueueDepths())
+
+	close(unblock)
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+
+	assert.Equal(t, []int{0, 0}, processor.QueueDepths())
+}
+
+func TestUnit_ShardedProcessor_WhenStartCallbackFails_ExpectErrorIsReturned(t *testing.T) {
+	testErr := fmt.Errorf("some error")
+	cb := Callbacks[persistence.Message]{
+		Start: func() error {
+			return testErr
+		},
+		Message: dummyMessageCallback,
+	}
+	processor := NewShardedProcessor(2, 1, BatchPolicy{}, roomOf, cb)
+
+	wg := asyncStartProcessorAndAssertError(t, processor, testErr)
+
+	err := processor.Stop()
+	assert.Nil(t, err, "Actual err: %v", err)
+	wg.Wait()
+}
+
+func newTestShardedProcessor(
+	workers int,
+	messageQueueSize int,
+	msgCallback MessageCallback[persistence.Message],
+) ShardedProcessor[persistence.Message] {
+	cb := Callbacks[persistence.Message]{
+		Message: msgCallback,
+	}
+	return NewShardedProcessor(workers, messageQueueSize, BatchPolicy{}, roomOf, cb)
+}
+
+func newTestRoomsOnDifferentShards(t *testing.T, workers int) (uuid.UUID, uuid.UUID) {
+	processor := newTestShardedProcessor(workers, 1, dummyMessageCallback)
+
+	first := uuid.New()
+	for range 100 {
+		second := uuid.New()
+		if indexOfShard(processor, first) != indexOfShard(processor, second) {
+			return first, second
+		}
+	}
+
+	t.Fatalf("Failed to find rooms on different shards")
+	return first, first
+}
+
+func indexOfShard(processor ShardedProcessor[persistence.Message], room uuid.UUID) int {
+	impl := processor.(*shardedProcessorImpl[persistence.Message])
+	shard := impl.shardFor(persistence.Message{Room: room})
+
+	for id, candidate := range impl.shards {
+		if candidate == shard {
+			return id
+		}
+	}
+
+	return -1
+}
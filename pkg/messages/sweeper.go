@@ -0,0 +1,139 @@
+package messages
+
+import (
+	"context"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/Knoblauchpilze/chat-server/pkg/storage"
+)
+
+type Sweeper interface {
+	Start() error
+	Stop() error
+}
+
+type sweeperImpl struct {
+	*periodicImpl
+
+	conn           db.Connection
+	pinRepo        repositories.PinRepository
+	attachmentRepo repositories.AttachmentRepository
+	messageRepo    repositories.MessageRepository
+	store          storage.BlobStore
+	dispatcher     Dispatcher
+
+	attachmentRetention time.Duration
+}
+
+// NewExpirationSweeper deletes the expired messages along with their pins
+// and attachments. The attachments which were not sent in a message
+// within the retention are also deleted.
+func NewExpirationSweeper(
+	pollInterval time.Duration,
+	attachmentRetention time.Duration,
+	conn db.Connection,
+	repos repositories.Repositories,
+	store storage.BlobStore,
+	dispatcher Dispatcher,
+) Sweeper {
+	s := &sweeperImpl{
+		conn:           conn,
+		pinRepo:        repos.Pin,
+		attachmentRepo: repos.Attachment,
+		messageRepo:    repos.Message,
+		store:          store,
+		dispatcher:     dispatcher,
+
+		attachmentRetention: attachmentRetention,
+	}
+
+	// Failing to delete the expired messages leaves them in the table:
+	// they are already hidden from the clients and will be removed at
+	// the next tick.
+	s.periodicImpl = newPeriodic(pollInterval, s.sweep)
+
+	return s
+}
+
+func (s *sweeperImpl) sweep(now time.Time) {
+	s.deleteExpiredMessages(now)
+	s.deleteUnlinkedAttachments(now)
+}
+
+func (s *sweeperImpl) deleteExpiredMessages(now time.Time) {
+	ctx := context.Background()
+
+	expired, attachments, err := s.deleteExpiredMessagesInTransaction(ctx, now)
+	if err != nil {
+		return
+	}
+
+	// The transaction is closed at this point: the blobs are only removed
+	// once the attachments referencing them are gone from the database.
+	s.deleteBlobs(ctx, attachments)
+
+	for _, msg := range expired {
+		expiration := communication.ExpiredMessageDtoResponse{
+			Room:    msg.Room,
+			Message: msg.Id,
+		}
+		s.dispatcher.BroadcastEvent(events.FromExpiration(expiration))
+	}
+}
+
+func (s *sweeperImpl) deleteExpiredMessagesInTransaction(
+	ctx context.Context, now time.Time,
+) ([]persistence.Message, []persistence.Attachment, error) {
+	tx, err := s.conn.BeginTx(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Close(ctx)
+
+	err = s.pinRepo.DeleteForExpiredMessages(ctx, tx, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachments, err := s.attachmentRepo.DeleteForExpiredMessages(ctx, tx, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	expired, err := s.messageRepo.DeleteExpired(ctx, tx, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return expired, attachments, nil
+}
+
+func (s *sweeperImpl) deleteUnlinkedAttachments(now time.Time) {
+	ctx := context.Background()
+
+	attachments, err := s.attachmentRepo.DeleteUnlinked(ctx, now.Add(-s.attachmentRetention))
+	if err != nil {
+		return
+	}
+
+	s.deleteBlobs(ctx, attachments)
+}
+
+// deleteBlobs removes the content of the attachments from the storage once
+// they are removed from the database. Voluntarily ignore errors: a blob
+// which is not referenced anymore can't be downloaded.
+func (s *sweeperImpl) deleteBlobs(
+	ctx context.Context, attachments []persistence.Attachment,
+) {
+	for _, attachment := range attachments {
+		s.store.Delete(ctx, attachment.Id.String())
+		if attachment.Thumbnail != nil {
+			s.store.Delete(ctx, *attachment.Thumbnail)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package messages
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/communication"
+	"github.com/Knoblauchpilze/chat-server/pkg/events"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/Knoblauchpilze/chat-server/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_ExpirationSweeper_WhenMessageExpired_ExpectDeleted(t *testing.T) {
+	sweeper, conn, _ := newTestExpirationSweeper(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessageWithExpiration(t, conn, user.Id, room.Id, time.Now().Add(time.Hour))
+	expired := insertTestMessageWithExpiration(t, conn, user.Id, room.Id, time.Now().Add(-time.Minute))
+
+	sweeper.deleteExpiredMessages(time.Now())
+
+	assertMessageExists(t, conn, msg.Id)
+	assertMessageDoesNotExist(t, conn, expired.Id)
+}
+
+func TestIT_ExpirationSweeper_WhenMessageExpired_ExpectEventBroadcast(t *testing.T) {
+	sweeper, conn, dispatcher := newTestExpirationSweeper(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	expired := insertTestMessageWithExpiration(t, conn, user.Id, room.Id, time.Now().Add(-time.Minute))
+
+	sweeper.deleteExpiredMessages(time.Now())
+
+	expected := communication.ExpiredMessageDtoResponse{
+		Room:    room.Id,
+		Message: expired.Id,
+	}
+	var found bool
+	for _, event := range dispatcher.events {
+		if event.Type == events.Expire && event.Data == expected {
+			found = true
+		}
+	}
+	assert.True(t, found, "Actual events: %v", dispatcher.events)
+}
+
+func TestIT_ExpirationSweeper_WhenExpiredMessageHasAttachment_ExpectDeletedWithBlob(t *testing.T) {
+	sweeper, conn, _ := newTestExpirationSweeper(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	expired := insertTestMessageWithExpiration(t, conn, user.Id, room.Id, time.Now().Add(-time.Minute))
+	attachment := insertTestAttachment(t, conn, user.Id, room.Id)
+	err := repositories.NewAttachmentRepository(conn).AttachToMessage(
+		context.Background(), expired.Id, []uuid.UUID{attachment.Id},
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	_, err = sweeper.store.Put(
+		context.Background(), attachment.Id.String(), strings.NewReader("content"),
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	sweeper.deleteExpiredMessages(time.Now())
+
+	assertMessageDoesNotExist(t, conn, expired.Id)
+	_, err = sweeper.store.Get(context.Background(), attachment.Id.String())
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, storage.ErrNoSuchBlob),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_ExpirationSweeper_WhenAttachmentIsUnlinked_ExpectDeletedWithBlob(t *testing.T) {
+	sweeper, conn, _ := newTestExpirationSweeper(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	unlinked := insertTestAttachment(t, conn, user.Id, room.Id)
+	recent := insertTestAttachment(t, conn, user.Id, room.Id)
+	for _, attachment := range []persistence.Attachment{unlinked, recent} {
+		_, err := sweeper.store.Put(
+			context.Background(), attachment.Id.String(), strings.NewReader("content"),
+		)
+		assert.Nil(t, err, "Actual err: %v", err)
+	}
+
+	now := time.Now()
+	_, err := conn.Exec(
+		context.Background(),
+		"UPDATE attachment SET created_at = $2 WHERE id = $1",
+		unlinked.Id,
+		now.Add(-2*sweeper.attachmentRetention),
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	sweeper.deleteUnlinkedAttachments(now)
+
+	_, err = sweeper.store.Get(context.Background(), unlinked.Id.String())
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, storage.ErrNoSuchBlob),
+		"Actual err: %v",
+		err,
+	)
+	blob, err := sweeper.store.Get(context.Background(), recent.Id.String())
+	assert.Nil(t, err, "Actual err: %v", err)
+	blob.Close()
+}
+
+func newTestExpirationSweeper(
+	t *testing.T,
+) (*sweeperImpl, db.Connection, *mockEventDispatcher) {
+	conn := newTestDbConnection(t)
+	dispatcher := &mockEventDispatcher{}
+
+	store, err := storage.NewFilesystemStore(t.TempDir())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	sweeper := NewExpirationSweeper(
+		time.Second, time.Hour, conn, repositories.New(conn), store, dispatcher,
+	)
+	return sweeper.(*sweeperImpl), conn, dispatcher
+}
+
+func insertTestMessageWithExpiration(
+	t *testing.T,
+	conn db.Connection,
+	user uuid.UUID,
+	room uuid.UUID,
+	expiresAt time.Time,
+) persistence.Message {
+	repo := repositories.NewMessageRepository(conn)
+
+	msg := persistence.Message{
+		Id:        uuid.New(),
+		ChatUser:  user,
+		Room:      room,
+		Message:   "my-message-" + uuid.NewString(),
+		ExpiresAt: &expiresAt,
+	}
+	out, err := repo.Create(context.Background(), msg)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return out
+}
+
+func assertMessageDoesNotExist(t *testing.T, conn db.Connection, id uuid.UUID) {
+	value, err := db.QueryOne[int](
+		context.Background(),
+		conn,
+		"SELECT COUNT(id) FROM message WHERE id = $1",
+		id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Zero(t, value)
+}
+
+type mockEventDispatcher struct {
+	Dispatcher
+
+	events []events.Event
+}
+
+func (m *mockEventDispatcher) BroadcastEvent(event events.Event) error {
+	m.events = append(m.events, event)
+	return nil
+}
@@ -1,20 +1,23 @@
 package messages
 
-import "github.com/Knoblauchpilze/chat-server/pkg/persistence"
-
-type Processor interface {
+type Processor[T any] interface {
 	Start() error
 	Stop() error
 
-	Enqueue(msg persistence.Message)
+	Enqueue(msg T)
+	// TryEnqueue does not wait for room in the queue: it returns false
+	// when the message could not be enqueued.
+	TryEnqueue(msg T) bool
 }
 
 type StartCallback func() error
-type MessageCallback func(msg persistence.Message) error
+type MessageCallback[T any] func(msg T) error
+type BatchCallback[T any] func(msgs []T) error
 type FinishCallback func() error
 
-type Callbacks struct {
+type Callbacks[T any] struct {
 	Start   StartCallback
-	Message MessageCallback
+	Message MessageCallback[T]
+	Batch   BatchCallback[T]
 	Finish  FinishCallback
 }
@@ -0,0 +1,23 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Attachment struct {
+	Id       uuid.UUID
+	ChatUser uuid.UUID
+	Room     uuid.UUID
+	Message  *uuid.UUID
+	Name     string
+	MimeType string
+	Size     int64
+
+	Width     *int
+	Height    *int
+	Thumbnail *string
+
+	CreatedAt time.Time
+}
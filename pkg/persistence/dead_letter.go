@@ -0,0 +1,21 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeadLetter is a message which could not be persisted. The id is
+// the one of the message.
+type DeadLetter struct {
+	Id          uuid.UUID
+	ChatUser    uuid.UUID
+	Room        uuid.UUID
+	Message     string
+	Attachments []uuid.UUID
+	ExpiresAt   *time.Time
+	Reason      string
+	Attempts    int
+	CreatedAt   time.Time
+}
@@ -0,0 +1,14 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IdempotencyKey struct {
+	ChatUser  uuid.UUID
+	Key       string
+	Message   uuid.UUID
+	CreatedAt time.Time
+}
@@ -0,0 +1,15 @@
+package persistence
+
+import (
+	"github.com/google/uuid"
+)
+
+type Membership struct {
+	Room     uuid.UUID
+	ChatUser uuid.UUID
+}
+
+type MemberCount struct {
+	Room  uuid.UUID
+	Count int
+}
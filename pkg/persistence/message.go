@@ -7,9 +7,18 @@ import (
 )
 
 type Message struct {
-	Id        uuid.UUID
-	ChatUser  uuid.UUID
-	Room      uuid.UUID
-	Message   string
+	Id       uuid.UUID
+	ChatUser uuid.UUID
+	Room     uuid.UUID
+	Message  string
+	// Sequence numbers the messages of a room without gap, starting at 1.
+	// It is assigned by the database when the message is persisted.
+	Sequence  int64
 	CreatedAt time.Time
+	ExpiresAt *time.Time
+
+	Attachments []Attachment `db:"-"`
+	// IdempotencyKey is only forwarded to the clients when the message
+	// is broadcast, it is not persisted with the message.
+	IdempotencyKey string `db:"-"`
 }
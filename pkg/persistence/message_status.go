@@ -0,0 +1,22 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MessageQueued    = "queued"
+	MessagePersisted = "persisted"
+	MessageFailed    = "failed"
+)
+
+type MessageStatus struct {
+	Message   uuid.UUID
+	Room      uuid.UUID
+	Status    string
+	Reason    *string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
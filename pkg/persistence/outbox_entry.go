@@ -0,0 +1,23 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEntry is a message accepted by the server but not yet handled
+// by the processor. The id is the one of the message and the sequence
+// defines the order in which entries are dispatched.
+type OutboxEntry struct {
+	Id             uuid.UUID
+	Sequence       int64
+	ChatUser       uuid.UUID
+	Room           uuid.UUID
+	Message        string
+	Attachments    []uuid.UUID
+	ExpiresAt      *time.Time
+	IdempotencyKey string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
@@ -0,0 +1,20 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Pin struct {
+	Room     uuid.UUID
+	Message  uuid.UUID
+	PinnedBy uuid.UUID
+
+	CreatedAt time.Time
+}
+
+type PinnedMessage struct {
+	Pin     Pin
+	Message Message
+}
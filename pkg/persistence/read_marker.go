@@ -0,0 +1,18 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReadMarker struct {
+	Room     uuid.UUID
+	ChatUser uuid.UUID
+	Message  uuid.UUID
+	// Sequence is the one of the message, which allows to count the
+	// messages posted after it.
+	Sequence int64
+
+	UpdatedAt time.Time
+}
@@ -7,8 +7,11 @@ import (
 )
 
 type Room struct {
-	Id        uuid.UUID
-	Name      string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id   uuid.UUID
+	Name string
+	// MessageTtl is the default lifetime in seconds of the messages
+	// posted in the room. Messages do not expire when it is not set.
+	MessageTtl *int
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
 }
@@ -0,0 +1,17 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ScheduledMessage struct {
+	Id        uuid.UUID
+	ChatUser  uuid.UUID
+	Room      uuid.UUID
+	Message   string
+	SendAt    time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
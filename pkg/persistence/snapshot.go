@@ -0,0 +1,14 @@
+package persistence
+
+// RoomSnapshot is the state of a room as seen by one of its members.
+type RoomSnapshot struct {
+	Room    Room
+	Members []User
+	// LastMessage is nil when there is no message in the room.
+	LastMessage *Message
+	// LastSequence is the sequence of the last message posted in the
+	// room, even if it expired since then.
+	LastSequence int64
+	// UnreadCount only counts the messages of the other members.
+	UnreadCount int64
+}
@@ -0,0 +1,272 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment persistence.Attachment) (persistence.Attachment, error)
+	Get(ctx context.Context, id uuid.UUID) (persistence.Attachment, error)
+	ListForRoom(ctx context.Context, room uuid.UUID) ([]persistence.Attachment, error)
+	AttachToMessage(ctx context.Context, message uuid.UUID, ids []uuid.UUID) error
+	DeleteForRoom(ctx context.Context, tx db.Transaction, room uuid.UUID) error
+	DeleteForExpiredMessages(ctx context.Context, tx db.Transaction, until time.Time) ([]persistence.Attachment, error)
+	// DeleteUnlinked removes the attachments created before until which are
+	// not part of a message, nor of a message waiting to be persisted.
+	DeleteUnlinked(ctx context.Context, until time.Time) ([]persistence.Attachment, error)
+	UpdateAttachmentsOwner(ctx context.Context, tx db.Transaction, oldUser uuid.UUID, newUser string) error
+	UpdateThumbnail(ctx context.Context, id uuid.UUID, width int, height int, thumbnail string) error
+}
+
+type attachmentRepositoryImpl struct {
+	conn db.Connection
+}
+
+func NewAttachmentRepository(conn db.Connection) AttachmentRepository {
+	return &attachmentRepositoryImpl{
+		conn: conn,
+	}
+}
+
+const createAttachmentSqlTemplate = `
+INSERT INTO attachment (id, chat_user, room, name, mime_type, size)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING created_at`
+
+func (r *attachmentRepositoryImpl) Create(
+	ctx context.Context, attachment persistence.Attachment,
+) (persistence.Attachment, error) {
+	createdAt, err := db.QueryOne[time.Time](
+		ctx,
+		r.conn,
+		createAttachmentSqlTemplate,
+		attachment.Id,
+		attachment.ChatUser,
+		attachment.Room,
+		attachment.Name,
+		attachment.MimeType,
+		attachment.Size,
+	)
+
+	attachment.CreatedAt = createdAt.UTC()
+
+	return attachment, err
+}
+
+const getAttachmentSqlTemplate = `
+SELECT
+	id,
+	chat_user,
+	room,
+	message,
+	name,
+	mime_type,
+	size,
+	width,
+	height,
+	thumbnail,
+	created_at
+FROM
+	attachment
+WHERE
+	id = $1`
+
+func (r *attachmentRepositoryImpl) Get(
+	ctx context.Context, id uuid.UUID,
+) (persistence.Attachment, error) {
+	attachment, err := db.QueryOne[persistence.Attachment](
+		ctx, r.conn, getAttachmentSqlTemplate, id,
+	)
+
+	if err == nil {
+		attachment.CreatedAt = attachment.CreatedAt.UTC()
+	}
+
+	return attachment, err
+}
+
+const listAttachmentByRoomSqlTemplate = `
+SELECT
+	id,
+	chat_user,
+	room,
+	message,
+	name,
+	mime_type,
+	size,
+	width,
+	height,
+	thumbnail,
+	created_at
+FROM
+	attachment
+WHERE
+	room = $1
+	AND message IS NOT NULL
+ORDER BY
+	created_at`
+
+func (r *attachmentRepositoryImpl) ListForRoom(
+	ctx context.Context, room uuid.UUID,
+) ([]persistence.Attachment, error) {
+	attachments, err := db.QueryAll[persistence.Attachment](
+		ctx, r.conn, listAttachmentByRoomSqlTemplate, room,
+	)
+
+	if err == nil {
+		for id, attachment := range attachments {
+			attachments[id].CreatedAt = attachment.CreatedAt.UTC()
+		}
+	}
+
+	return attachments, err
+}
+
+const attachToMessageSqlTemplate = `
+UPDATE attachment SET
+	message = $1
+WHERE
+	id = ANY($2)
+	AND message IS NULL`
+
+func (r *attachmentRepositoryImpl) AttachToMessage(
+	ctx context.Context, message uuid.UUID, ids []uuid.UUID,
+) error {
+	_, err := r.conn.Exec(ctx, attachToMessageSqlTemplate, message, ids)
+	return err
+}
+
+const deleteAttachmentByRoomSqlTemplate = `
+DELETE FROM
+	attachment
+WHERE
+	room = $1`
+
+func (r *attachmentRepositoryImpl) DeleteForRoom(
+	ctx context.Context, tx db.Transaction, room uuid.UUID,
+) error {
+	_, err := tx.Exec(ctx, deleteAttachmentByRoomSqlTemplate, room)
+	return err
+}
+
+const deleteAttachmentForExpiredMessagesSqlTemplate = `
+DELETE FROM
+	attachment
+WHERE
+	message IN (
+		SELECT id FROM message WHERE expires_at <= $1
+	)
+RETURNING
+	id,
+	chat_user,
+	room,
+	message,
+	name,
+	mime_type,
+	size,
+	width,
+	height,
+	thumbnail,
+	created_at`
+
+func (r *attachmentRepositoryImpl) DeleteForExpiredMessages(
+	ctx context.Context, tx db.Transaction, until time.Time,
+) ([]persistence.Attachment, error) {
+	attachments, err := db.QueryAllTx[persistence.Attachment](
+		ctx, tx, deleteAttachmentForExpiredMessagesSqlTemplate, until,
+	)
+
+	if err == nil {
+		for id, attachment := range attachments {
+			attachments[id].CreatedAt = attachment.CreatedAt.UTC()
+		}
+	}
+
+	return attachments, err
+}
+
+const deleteUnlinkedAttachmentsSqlTemplate = `
+DELETE FROM
+	attachment
+WHERE
+	message IS NULL
+	AND created_at < $1
+	AND NOT EXISTS (
+		SELECT 1 FROM outbox WHERE delivered_at IS NULL AND attachment.id = ANY(outbox.attachments)
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM dead_letter WHERE attachment.id = ANY(dead_letter.attachments)
+	)
+RETURNING
+	id,
+	chat_user,
+	room,
+	message,
+	name,
+	mime_type,
+	size,
+	width,
+	height,
+	thumbnail,
+	created_at`
+
+func (r *attachmentRepositoryImpl) DeleteUnlinked(
+	ctx context.Context, until time.Time,
+) ([]persistence.Attachment, error) {
+	attachments, err := db.QueryAll[persistence.Attachment](
+		ctx, r.conn, deleteUnlinkedAttachmentsSqlTemplate, until,
+	)
+
+	if err == nil {
+		for id, attachment := range attachments {
+			attachments[id].CreatedAt = attachment.CreatedAt.UTC()
+		}
+	}
+
+	return attachments, err
+}
+
+const updateAttachmentsOwnerSqlTemplate = `
+WITH new_user AS (
+	SELECT id FROM chat_user WHERE name = $2
+)
+UPDATE attachment SET
+	chat_user = new_user.id
+FROM
+	new_user
+WHERE
+	chat_user = $1`
+
+func (r *attachmentRepositoryImpl) UpdateAttachmentsOwner(
+	ctx context.Context, tx db.Transaction, oldUser uuid.UUID, newUser string,
+) error {
+	_, err := tx.Exec(ctx, updateAttachmentsOwnerSqlTemplate, oldUser, newUser)
+	return err
+}
+
+const updateThumbnailSqlTemplate = `
+UPDATE attachment SET
+	width = $2,
+	height = $3,
+	thumbnail = $4
+WHERE
+	id = $1`
+
+func (r *attachmentRepositoryImpl) UpdateThumbnail(
+	ctx context.Context, id uuid.UUID, width int, height int, thumbnail string,
+) error {
+	updated, err := r.conn.Exec(
+		ctx, updateThumbnailSqlTemplate, id, width, height, thumbnail,
+	)
+
+	if err == nil && updated == 0 {
+		return errors.NewCode(db.NoMatchingRows)
+	}
+	return err
+}
@@ -0,0 +1,377 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/pgx"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	eassert "github.com/Knoblauchpilze/easy-assert/assert"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_AttachmentRepository_Create(t *testing.T) {
+	repo, conn := newTestAttachmentRepository(t)
+	defer conn.Close(context.Background())
+	beforeInsertion := time.Now()
+
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+
+	attachment := persistence.Attachment{
+		Id:       uuid.New(),
+		ChatUser: user.Id,
+		Room:     room.Id,
+		Name:     "my-file.png",
+		MimeType: "image/png",
+		Size:     1234,
+	}
+
+	actual, err := repo.Create(context.Background(), attachment)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.True(t, eassert.EqualsIgnoringFields(actual, attachment, "CreatedAt"))
+	assert.True(t, actual.CreatedAt.After(beforeInsertion))
+	assertAttachmentExists(t, conn, attachment.Id)
+}
+
+func TestIT_AttachmentRepository_Create_WhenRoomDoesNotExist_ExpectFailure(t *testing.T) {
+	repo, conn := newTestAttachmentRepository(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+
+	attachment := persistence.Attachment{
+		Id:       uuid.New(),
+		ChatUser: user.Id,
+		Room:     uuid.New(),
+		Name:     "my-file.png",
+		MimeType: "image/png",
+		Size:     1234,
+	}
+
+	_, err := repo.Create(context.Background(), attachment)
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, pgx.ForeignKeyValidation),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_AttachmentRepository_Get(t *testing.T) {
+	repo, conn := newTestAttachmentRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	attachment := insertTestAttachment(t, conn, user.Id, room.Id)
+
+	actual, err := repo.Get(context.Background(), attachment.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, attachment, actual)
+}
+
+func TestIT_AttachmentRepository_Get_WhenNotFound_ExpectFailure(t *testing.T) {
+	repo, conn := newTestAttachmentRepository(t)
+	defer conn.Close(context.Background())
+
+	_, err := repo.Get(context.Background(), uuid.New())
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_AttachmentRepository_AttachToMessage(t *testing.T) {
+	repo, conn := newTestAttachmentRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	attachment := insertTestAttachment(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+
+	err := repo.AttachToMessage(context.Background(), msg.Id, []uuid.UUID{attachment.Id})
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual, err := repo.Get(context.Background(), attachment.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, &msg.Id, actual.Message)
+}
+
+func TestIT_AttachmentRepository_AttachToMessage_WhenAlreadyAttached_ExpectNotChanged(t *testing.T) {
+	repo, conn := newTestAttachmentRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	attachment := insertTestAttachment(t, conn, user.Id, room.Id)
+	msg1 := insertTestMessage(t, conn, user.Id, room.Id)
+	msg2 := insertTestMessage(t, conn, user.Id, room.Id)
+
+	err := repo.AttachToMessage(context.Background(), msg1.Id, []uuid.UUID{attachment.Id})
+	assert.Nil(t, err, "Actual err: %v", err)
+	err = repo.AttachToMessage(context.Background(), msg2.Id, []uuid.UUID{attachment.Id})
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual, err := repo.Get(context.Background(), attachment.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, &msg1.Id, actual.Message)
+}
+
+func TestIT_AttachmentRepository_ListForRoom_OnlyReturnsAttachedFiles(t *testing.T) {
+	repo, conn := newTestAttachmentRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	attached := insertTestAttachment(t, conn, user.Id, room.Id)
+	insertTestAttachment(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+
+	err := repo.AttachToMessage(context.Background(), msg.Id, []uuid.UUID{attached.Id})
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual, err := repo.ListForRoom(context.Background(), room.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	attached.Message = &msg.Id
+	expected := []persistence.Attachment{attached}
+	assert.Equal(t, expected, actual)
+}
+
+func TestIT_AttachmentRepository_DeleteForRoom(t *testing.T) {
+	repo, conn := newTestAttachmentRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	attachment := insertTestAttachment(t, conn, user.Id, room.Id)
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	err = repo.DeleteForRoom(context.Background(), tx, room.Id)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assertAttachmentDoesNotExist(t, conn, attachment.Id)
+}
+
+func TestIT_AttachmentRepository_DeleteForExpiredMessages(t *testing.T) {
+	repo, conn := newTestAttachmentRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+	expired := insertTestExpiredMessage(t, conn, user.Id, room.Id)
+	attachment := insertTestAttachment(t, conn, user.Id, room.Id)
+	expiredAttachment := insertTestAttachment(t, conn, user.Id, room.Id)
+	err := repo.AttachToMessage(context.Background(), msg.Id, []uuid.UUID{attachment.Id})
+	assert.Nil(t, err, "Actual err: %v", err)
+	err = repo.AttachToMessage(context.Background(), expired.Id, []uuid.UUID{expiredAttachment.Id})
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	actual, err := repo.DeleteForExpiredMessages(context.Background(), tx, time.Now())
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	ids := make([]uuid.UUID, 0, len(actual))
+	for _, deleted := range actual {
+		ids = append(ids, deleted.Id)
+	}
+	assert.Contains(t, ids, expiredAttachment.Id)
+	assert.NotContains(t, ids, attachment.Id)
+	assertAttachmentExists(t, conn, attachment.Id)
+	assertAttachmentDoesNotExist(t, conn, expiredAttachment.Id)
+}
+
+func TestIT_AttachmentRepository_DeleteUnlinked(t *testing.T) {
+	repo, conn := newTestAttachmentRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+	attached := insertTestAttachment(t, conn, user.Id, room.Id)
+	unlinked := insertTestAttachment(t, conn, user.Id, room.Id)
+	recent := insertTestAttachment(t, conn, user.Id, room.Id)
+	err := repo.AttachToMessage(context.Background(), msg.Id, []uuid.UUID{attached.Id})
+	assert.Nil(t, err, "Actual err: %v", err)
+	now := time.Now()
+	ageTestAttachment(t, conn, attached.Id, now.Add(-2*time.Hour))
+	ageTestAttachment(t, conn, unlinked.Id, now.Add(-2*time.Hour))
+
+	actual, err := repo.DeleteUnlinked(context.Background(), now.Add(-time.Hour))
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	ids := make([]uuid.UUID, 0, len(actual))
+	for _, attachment := range actual {
+		ids = append(ids, attachment.Id)
+	}
+	assert.Contains(t, ids, unlinked.Id)
+	assertAttachmentExists(t, conn, attached.Id)
+	assertAttachmentDoesNotExist(t, conn, unlinked.Id)
+	assertAttachmentExists(t, conn, recent.Id)
+}
+
+func TestIT_AttachmentRepository_DeleteUnlinked_WhenMessageIsPending_ExpectKept(t *testing.T) {
+	repo, conn := newTestAttachmentRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	attachment := insertTestAttachment(t, conn, user.Id, room.Id)
+	now := time.Now()
+	ageTestAttachment(t, conn, attachment.Id, now.Add(-2*time.Hour))
+
+	_, err := conn.Exec(
+		context.Background(),
+		`INSERT INTO
+			outbox (id, chat_user, room, message, attachments)
+			VALUES ($1, $2, $3, $4, $5)`,
+		uuid.New(),
+		user.Id,
+		room.Id,
+		"my-message",
+		[]uuid.UUID{attachment.Id},
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	_, err = repo.DeleteUnlinked(context.Background(), now.Add(-time.Hour))
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assertAttachmentExists(t, conn, attachment.Id)
+}
+
+func TestIT_AttachmentRepository_UpdateAttachmentsOwner(t *testing.T) {
+	repo, conn := newTestAttachmentRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	userOld := insertTestUser(t, conn)
+	userNew := insertTestUser(t, conn)
+	attachment := insertTestAttachment(t, conn, userOld.Id, room.Id)
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	err = repo.UpdateAttachmentsOwner(context.Background(), tx, userOld.Id, userNew.Name)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual, err := repo.Get(context.Background(), attachment.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, userNew.Id, actual.ChatUser)
+}
+
+func TestIT_AttachmentRepository_UpdateThumbnail(t *testing.T) {
+	repo, conn := newTestAttachmentRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	attachment := insertTestAttachment(t, conn, user.Id, room.Id)
+
+	err := repo.UpdateThumbnail(context.Background(), attachment.Id, 640, 480, "my-thumbnail")
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual, err := repo.Get(context.Background(), attachment.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	width, height, thumbnail := 640, 480, "my-thumbnail"
+	assert.Equal(t, &width, actual.Width)
+	assert.Equal(t, &height, actual.Height)
+	assert.Equal(t, &thumbnail, actual.Thumbnail)
+}
+
+func TestIT_AttachmentRepository_UpdateThumbnail_WhenNotFound_ExpectFailure(t *testing.T) {
+	repo, conn := newTestAttachmentRepository(t)
+	defer conn.Close(context.Background())
+
+	err := repo.UpdateThumbnail(context.Background(), uuid.New(), 640, 480, "my-thumbnail")
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func newTestAttachmentRepository(t *testing.T) (AttachmentRepository, db.Connection) {
+	conn := newTestConnection(t)
+	return NewAttachmentRepository(conn), conn
+}
+
+func assertAttachmentExists(t *testing.T, conn db.Connection, id uuid.UUID) {
+	value, err := db.QueryOne[uuid.UUID](
+		context.Background(),
+		conn,
+		"SELECT id FROM attachment WHERE id = $1",
+		id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, id, value)
+}
+
+func assertAttachmentDoesNotExist(t *testing.T, conn db.Connection, id uuid.UUID) {
+	value, err := db.QueryOne[int](
+		context.Background(),
+		conn,
+		"SELECT COUNT(id) FROM attachment WHERE id = $1",
+		id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Zero(t, value)
+}
+
+func insertTestAttachment(
+	t *testing.T,
+	conn db.Connection,
+	user uuid.UUID,
+	room uuid.UUID,
+) persistence.Attachment {
+	attachment := persistence.Attachment{
+		Id:       uuid.New(),
+		ChatUser: user,
+		Room:     room,
+		Name:     "my-file-" + uuid.NewString(),
+		MimeType: "text/plain; charset=utf-8",
+		Size:     12,
+	}
+
+	createdAt, err := db.QueryOne[time.Time](
+		context.Background(),
+		conn,
+		`INSERT INTO
+			attachment (id, chat_user, room, name, mime_type, size)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING created_at`,
+		attachment.Id,
+		attachment.ChatUser,
+		attachment.Room,
+		attachment.Name,
+		attachment.MimeType,
+		attachment.Size,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	attachment.CreatedAt = createdAt.UTC()
+
+	return attachment
+}
+
+func ageTestAttachment(
+	t *testing.T, conn db.Connection, id uuid.UUID, createdAt time.Time,
+) {
+	_, err := conn.Exec(
+		context.Background(),
+		"UPDATE attachment SET created_at = $2 WHERE id = $1",
+		id,
+		createdAt,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+}
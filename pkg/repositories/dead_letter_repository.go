@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type DeadLetterRepository interface {
+	Create(ctx context.Context, letter persistence.DeadLetter) (persistence.DeadLetter, error)
+	Get(ctx context.Context, id uuid.UUID) (persistence.DeadLetter, error)
+	List(ctx context.Context) ([]persistence.DeadLetter, error)
+	// Delete removes the letter and returns it: this allows to act on
+	// the letter only once when several callers try to remove it.
+	Delete(ctx context.Context, tx db.Transaction, id uuid.UUID) (persistence.DeadLetter, error)
+	DeleteForRoom(ctx context.Context, tx db.Transaction, room uuid.UUID) error
+}
+
+type deadLetterRepositoryImpl struct {
+	conn db.Connection
+}
+
+func NewDeadLetterRepository(conn db.Connection) DeadLetterRepository {
+	return &deadLetterRepositoryImpl{
+		conn: conn,
+	}
+}
+
+const createDeadLetterSqlTemplate = `
+INSERT INTO dead_letter (id, chat_user, room, message, attachments, expires_at, reason, attempts)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING created_at`
+
+func (r *deadLetterRepositoryImpl) Create(
+	ctx context.Context, letter persistence.DeadLetter,
+) (persistence.DeadLetter, error) {
+	if letter.Attachments == nil {
+		letter.Attachments = []uuid.UUID{}
+	}
+
+	createdAt, err := db.QueryOne[time.Time](
+		ctx,
+		r.conn,
+		createDeadLetterSqlTemplate,
+		letter.Id,
+		letter.ChatUser,
+		letter.Room,
+		letter.Message,
+		letter.Attachments,
+		letter.ExpiresAt,
+		letter.Reason,
+		letter.Attempts,
+	)
+
+	letter.CreatedAt = createdAt.UTC()
+
+	return letter, err
+}
+
+const getDeadLetterSqlTemplate = `
+SELECT
+	id,
+	chat_user,
+	room,
+	message,
+	attachments,
+	expires_at,
+	reason,
+	attempts,
+	created_at
+FROM
+	dead_letter
+WHERE
+	id = $1`
+
+func (r *deadLetterRepositoryImpl) Get(
+	ctx context.Context, id uuid.UUID,
+) (persistence.DeadLetter, error) {
+	letter, err := db.QueryOne[persistence.DeadLetter](
+		ctx, r.conn, getDeadLetterSqlTemplate, id,
+	)
+
+	if err == nil {
+		letter = toUtcDeadLetter(letter)
+	}
+
+	return letter, err
+}
+
+const listDeadLetterSqlTemplate = `
+SELECT
+	id,
+	chat_user,
+	room,
+	message,
+	attachments,
+	expires_at,
+	reason,
+	attempts,
+	created_at
+FROM
+	dead_letter
+ORDER BY
+	created_at`
+
+func (r *deadLetterRepositoryImpl) List(
+	ctx context.Context,
+) ([]persistence.DeadLetter, error) {
+	letters, err := db.QueryAll[persistence.DeadLetter](
+		ctx, r.conn, listDeadLetterSqlTemplate,
+	)
+
+	if err == nil {
+		for id, letter := range letters {
+			letters[id] = toUtcDeadLetter(letter)
+		}
+	}
+
+	return letters, err
+}
+
+const deleteDeadLetterSqlTemplate = `
+DELETE FROM
+	dead_letter
+WHERE
+	id = $1
+RETURNING
+	id,
+	chat_user,
+	room,
+	message,
+	attachments,
+	expires_at,
+	reason,
+	attempts,
+	created_at`
+
+func (r *deadLetterRepositoryImpl) Delete(
+	ctx context.Context, tx db.Transaction, id uuid.UUID,
+) (persistence.DeadLetter, error) {
+	letter, err := db.QueryOneTx[persistence.DeadLetter](
+		ctx, tx, deleteDeadLetterSqlTemplate, id,
+	)
+
+	if err == nil {
+		letter = toUtcDeadLetter(letter)
+	}
+
+	return letter, err
+}
+
+const deleteDeadLetterByRoomSqlTemplate = `
+DELETE FROM
+	dead_letter
+WHERE
+	room = $1`
+
+func (r *deadLetterRepositoryImpl) DeleteForRoom(
+	ctx context.Context, tx db.Transaction, room uuid.UUID,
+) error {
+	_, err := tx.Exec(ctx, deleteDeadLetterByRoomSqlTemplate, room)
+	return err
+}
+
+func toUtcDeadLetter(letter persistence.DeadLetter) persistence.DeadLetter {
+	letter.ExpiresAt = toUtcTime(letter.ExpiresAt)
+	letter.CreatedAt = letter.CreatedAt.UTC()
+	return letter
+}
@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	eassert "github.com/Knoblauchpilze/easy-assert/assert"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_DeadLetterRepository_Create(t *testing.T) {
+	repo, conn := newTestDeadLetterRepository(t)
+	defer conn.Close(context.Background())
+	beforeInsertion := time.Now()
+
+	expiresAt := time.Date(2100, 1, 1, 12, 0, 0, 0, time.UTC)
+	letter := persistence.DeadLetter{
+		Id:          uuid.New(),
+		ChatUser:    uuid.New(),
+		Room:        uuid.New(),
+		Message:     "Hello world",
+		Attachments: []uuid.UUID{uuid.New()},
+		ExpiresAt:   &expiresAt,
+		Reason:      "some reason",
+		Attempts:    3,
+	}
+
+	actual, err := repo.Create(context.Background(), letter)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.True(t, eassert.EqualsIgnoringFields(actual, letter, "CreatedAt"))
+	assert.True(t, actual.CreatedAt.After(beforeInsertion))
+	assertDeadLetterExists(t, conn, letter.Id)
+}
+
+func TestIT_DeadLetterRepository_Create_WithoutAttachments(t *testing.T) {
+	repo, conn := newTestDeadLetterRepository(t)
+	defer conn.Close(context.Background())
+
+	letter := persistence.DeadLetter{
+		Id:       uuid.New(),
+		ChatUser: uuid.New(),
+		Room:     uuid.New(),
+		Message:  "Hello world",
+		Reason:   "some reason",
+		Attempts: 1,
+	}
+
+	_, err := repo.Create(context.Background(), letter)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual, err := repo.Get(context.Background(), letter.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Empty(t, actual.Attachments)
+}
+
+func TestIT_DeadLetterRepository_Get(t *testing.T) {
+	repo, conn := newTestDeadLetterRepository(t)
+	defer conn.Close(context.Background())
+	letter := insertTestDeadLetter(t, conn, uuid.New())
+
+	actual, err := repo.Get(context.Background(), letter.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, letter, actual)
+}
+
+func TestIT_DeadLetterRepository_Get_WhenNotFound_ExpectFailure(t *testing.T) {
+	repo, conn := newTestDeadLetterRepository(t)
+	defer conn.Close(context.Background())
+
+	_, err := repo.Get(context.Background(), uuid.New())
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_DeadLetterRepository_List(t *testing.T) {
+	repo, conn := newTestDeadLetterRepository(t)
+	defer conn.Close(context.Background())
+	letter1 := insertTestDeadLetter(t, conn, uuid.New())
+	letter2 := insertTestDeadLetter(t, conn, uuid.New())
+
+	actual, err := repo.List(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Contains(t, actual, letter1)
+	assert.Contains(t, actual, letter2)
+}
+
+func TestIT_DeadLetterRepository_Delete(t *testing.T) {
+	repo, conn := newTestDeadLetterRepository(t)
+	defer conn.Close(context.Background())
+	letter := insertTestDeadLetter(t, conn, uuid.New())
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	actual, err := repo.Delete(context.Background(), tx, letter.Id)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, letter, actual)
+	assertDeadLetterDoesNotExist(t, conn, letter.Id)
+}
+
+func TestIT_DeadLetterRepository_Delete_WhenNotFound_ExpectFailure(t *testing.T) {
+	repo, conn := newTestDeadLetterRepository(t)
+	defer conn.Close(context.Background())
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	_, err = repo.Delete(context.Background(), tx, uuid.New())
+	tx.Close(context.Background())
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_DeadLetterRepository_DeleteForRoom(t *testing.T) {
+	repo, conn := newTestDeadLetterRepository(t)
+	defer conn.Close(context.Background())
+	room := uuid.New()
+	letter := insertTestDeadLetter(t, conn, room)
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	err = repo.DeleteForRoom(context.Background(), tx, room)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assertDeadLetterDoesNotExist(t, conn, letter.Id)
+}
+
+func newTestDeadLetterRepository(
+	t *testing.T,
+) (DeadLetterRepository, db.Connection) {
+	conn := newTestConnection(t)
+	return NewDeadLetterRepository(conn), conn
+}
+
+func insertTestDeadLetter(
+	t *testing.T, conn db.Connection, room uuid.UUID,
+) persistence.DeadLetter {
+	letter := persistence.DeadLetter{
+		Id:          uuid.New(),
+		ChatUser:    uuid.New(),
+		Room:        room,
+		Message:     "Dead " + uuid.NewString(),
+		Attachments: []uuid.UUID{},
+		Reason:      "some reason",
+		Attempts:    1,
+	}
+
+	createdAt, err := db.QueryOne[time.Time](
+		context.Background(),
+		conn,
+		`INSERT INTO
+			dead_letter (id, chat_user, room, message, reason, attempts)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING created_at`,
+		letter.Id,
+		letter.ChatUser,
+		letter.Room,
+		letter.Message,
+		letter.Reason,
+		letter.Attempts,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	letter.CreatedAt = createdAt.UTC()
+
+	return letter
+}
+
+func assertDeadLetterExists(t *testing.T, conn db.Connection, id uuid.UUID) {
+	value, err := db.QueryOne[int](
+		context.Background(),
+		conn,
+		"SELECT COUNT(*) FROM dead_letter WHERE id = $1",
+		id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, 1, value)
+}
+
+func assertDeadLetterDoesNotExist(t *testing.T, conn db.Connection, id uuid.UUID) {
+	value, err := db.QueryOne[int](
+		context.Background(),
+		conn,
+		"SELECT COUNT(*) FROM dead_letter WHERE id = $1",
+		id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Zero(t, value)
+}
@@ -7,4 +7,5 @@ const (
 	ErrUserNotRegisteredInRoom     errors.ErrorCode = 601
 	ErrNoSuchUser                  errors.ErrorCode = 602
 	ErrUserAlreadyRegisteredInRoom errors.ErrorCode = 603
+	ErrMessageAlreadyPinned        errors.ErrorCode = 604
 )
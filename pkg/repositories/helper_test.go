@@ -34,6 +34,18 @@ func registerUserInRoom(
 	assert.Nil(t, err, "Actual err: %v", err)
 }
 
+func setUserAsModerator(
+	t *testing.T, conn db.Connection, user uuid.UUID, room uuid.UUID,
+) {
+	_, err := conn.Exec(
+		context.Background(),
+		`UPDATE room_user SET moderator = true WHERE room = $1 AND chat_user = $2`,
+		room,
+		user,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+}
+
 func assertUserRegisteredInRoom(
 	t *testing.T, conn db.Connection, user uuid.UUID, room uuid.UUID,
 ) {
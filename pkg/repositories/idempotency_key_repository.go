@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type IdempotencyKeyRepository interface {
+	// Claim registers the key for the user unless it is already used by
+	// a key created after the input time. The returned value is the key
+	// as stored in the database: if its message differs from the input
+	// one, the key was already claimed. The claim is released if the
+	// transaction is rolled back.
+	Claim(ctx context.Context, tx db.Transaction, key persistence.IdempotencyKey, notBefore time.Time) (persistence.IdempotencyKey, error)
+	Get(ctx context.Context, user uuid.UUID, key string) (persistence.IdempotencyKey, error)
+	DeleteForUser(ctx context.Context, tx db.Transaction, user uuid.UUID) error
+}
+
+type idempotencyKeyRepositoryImpl struct {
+	conn db.Connection
+}
+
+func NewIdempotencyKeyRepository(conn db.Connection) IdempotencyKeyRepository {
+	return &idempotencyKeyRepositoryImpl{
+		conn: conn,
+	}
+}
+
+const claimIdempotencyKeySqlTemplate = `
+INSERT INTO idempotency_key (chat_user, key, message)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (chat_user, key) DO UPDATE SET
+		message = excluded.message,
+		created_at = CURRENT_TIMESTAMP
+	WHERE
+		idempotency_key.created_at < $4
+	RETURNING
+		chat_user,
+		key,
+		message,
+		created_at`
+
+func (r *idempotencyKeyRepositoryImpl) Claim(
+	ctx context.Context,
+	tx db.Transaction,
+	key persistence.IdempotencyKey,
+	notBefore time.Time,
+) (persistence.IdempotencyKey, error) {
+	out, err := db.QueryOneTx[persistence.IdempotencyKey](
+		ctx,
+		tx,
+		claimIdempotencyKeySqlTemplate,
+		key.ChatUser,
+		key.Key,
+		key.Message,
+		notBefore,
+	)
+
+	// No row is returned when the key is already claimed.
+	if errors.IsErrorWithCode(err, db.NoMatchingRows) {
+		out, err = db.QueryOneTx[persistence.IdempotencyKey](
+			ctx, tx, getIdempotencyKeySqlTemplate, key.ChatUser, key.Key,
+		)
+	}
+
+	out.CreatedAt = out.CreatedAt.UTC()
+
+	return out, err
+}
+
+const getIdempotencyKeySqlTemplate = `
+SELECT
+	chat_user,
+	key,
+	message,
+	created_at
+FROM
+	idempotency_key
+WHERE
+	chat_user = $1
+	AND key = $2`
+
+func (r *idempotencyKeyRepositoryImpl) Get(
+	ctx context.Context, user uuid.UUID, key string,
+) (persistence.IdempotencyKey, error) {
+	out, err := db.QueryOne[persistence.IdempotencyKey](
+		ctx, r.conn, getIdempotencyKeySqlTemplate, user, key,
+	)
+
+	out.CreatedAt = out.CreatedAt.UTC()
+
+	return out, err
+}
+
+const deleteIdempotencyKeyByUserSqlTemplate = `
+DELETE FROM
+	idempotency_key
+WHERE
+	chat_user = $1`
+
+func (r *idempotencyKeyRepositoryImpl) DeleteForUser(
+	ctx context.Context, tx db.Transaction, user uuid.UUID,
+) error {
+	_, err := tx.Exec(ctx, deleteIdempotencyKeyByUserSqlTemplate, user)
+	return err
+}
@@ -0,0 +1,232 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/pgx"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_IdempotencyKeyRepository_Claim(t *testing.T) {
+	repo, conn := newTestIdempotencyKeyRepository(t)
+	defer conn.Close(context.Background())
+	beforeInsertion := time.Now()
+	user := insertTestUser(t, conn)
+
+	key := persistence.IdempotencyKey{
+		ChatUser: user.Id,
+		Key:      uuid.NewString(),
+		Message:  uuid.New(),
+	}
+
+	actual, err := claimTestIdempotencyKey(t, repo, conn, key, time.Now().Add(-time.Hour))
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, key.ChatUser, actual.ChatUser)
+	assert.Equal(t, key.Key, actual.Key)
+	assert.Equal(t, key.Message, actual.Message)
+	assert.True(t, actual.CreatedAt.After(beforeInsertion))
+}
+
+func TestIT_IdempotencyKeyRepository_Claim_WhenUserDoesNotExist_ExpectFailure(t *testing.T) {
+	repo, conn := newTestIdempotencyKeyRepository(t)
+	defer conn.Close(context.Background())
+
+	key := persistence.IdempotencyKey{
+		ChatUser: uuid.New(),
+		Key:      uuid.NewString(),
+		Message:  uuid.New(),
+	}
+
+	_, err := claimTestIdempotencyKey(t, repo, conn, key, time.Now().Add(-time.Hour))
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, pgx.ForeignKeyValidation),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_IdempotencyKeyRepository_Claim_WhenAlreadyClaimed_ExpectExistingKey(t *testing.T) {
+	repo, conn := newTestIdempotencyKeyRepository(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	existing := insertTestIdempotencyKey(t, conn, user.Id)
+
+	key := persistence.IdempotencyKey{
+		ChatUser: user.Id,
+		Key:      existing.Key,
+		Message:  uuid.New(),
+	}
+
+	actual, err := claimTestIdempotencyKey(t, repo, conn, key, time.Now().Add(-time.Hour))
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, existing, actual)
+}
+
+func TestIT_IdempotencyKeyRepository_Claim_WhenAlreadyClaimedByAnotherUser_ExpectSuccess(t *testing.T) {
+	repo, conn := newTestIdempotencyKeyRepository(t)
+	defer conn.Close(context.Background())
+	user1 := insertTestUser(t, conn)
+	user2 := insertTestUser(t, conn)
+	existing := insertTestIdempotencyKey(t, conn, user1.Id)
+
+	key := persistence.IdempotencyKey{
+		ChatUser: user2.Id,
+		Key:      existing.Key,
+		Message:  uuid.New(),
+	}
+
+	actual, err := claimTestIdempotencyKey(t, repo, conn, key, time.Now().Add(-time.Hour))
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, key.Message, actual.Message)
+}
+
+func TestIT_IdempotencyKeyRepository_Claim_WhenClaimedBeforeWindow_ExpectReclaimed(t *testing.T) {
+	repo, conn := newTestIdempotencyKeyRepository(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	existing := insertTestIdempotencyKey(t, conn, user.Id)
+
+	key := persistence.IdempotencyKey{
+		ChatUser: user.Id,
+		Key:      existing.Key,
+		Message:  uuid.New(),
+	}
+
+	actual, err := claimTestIdempotencyKey(t, repo, conn, key, time.Now().Add(time.Minute))
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, key.Message, actual.Message)
+	assert.True(t, actual.CreatedAt.After(existing.CreatedAt))
+}
+
+func TestIT_IdempotencyKeyRepository_Get(t *testing.T) {
+	repo, conn := newTestIdempotencyKeyRepository(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	key := insertTestIdempotencyKey(t, conn, user.Id)
+
+	actual, err := repo.Get(context.Background(), user.Id, key.Key)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, key, actual)
+}
+
+func TestIT_IdempotencyKeyRepository_Get_WhenNotFound_ExpectFailure(t *testing.T) {
+	repo, conn := newTestIdempotencyKeyRepository(t)
+	defer conn.Close(context.Background())
+
+	_, err := repo.Get(context.Background(), uuid.New(), uuid.NewString())
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_IdempotencyKeyRepository_DeleteForUser(t *testing.T) {
+	repo, conn := newTestIdempotencyKeyRepository(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	key := insertTestIdempotencyKey(t, conn, user.Id)
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	err = repo.DeleteForUser(context.Background(), tx, user.Id)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	_, err = repo.Get(context.Background(), user.Id, key.Key)
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_IdempotencyKeyRepository_Claim_WhenTransactionFails_ExpectKeyReleased(t *testing.T) {
+	repo, conn := newTestIdempotencyKeyRepository(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+
+	key := persistence.IdempotencyKey{
+		ChatUser: user.Id,
+		Key:      uuid.NewString(),
+		Message:  uuid.New(),
+	}
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	_, err = repo.Claim(context.Background(), tx, key, time.Now().Add(-time.Hour))
+	assert.Nil(t, err, "Actual err: %v", err)
+	_, err = tx.Exec(context.Background(), "SELECT 1/0")
+	assert.NotNil(t, err)
+	tx.Close(context.Background())
+
+	_, err = repo.Get(context.Background(), user.Id, key.Key)
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func newTestIdempotencyKeyRepository(
+	t *testing.T,
+) (IdempotencyKeyRepository, db.Connection) {
+	conn := newTestConnection(t)
+	return NewIdempotencyKeyRepository(conn), conn
+}
+
+func claimTestIdempotencyKey(
+	t *testing.T,
+	repo IdempotencyKeyRepository,
+	conn db.Connection,
+	key persistence.IdempotencyKey,
+	notBefore time.Time,
+) (persistence.IdempotencyKey, error) {
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	defer tx.Close(context.Background())
+
+	return repo.Claim(context.Background(), tx, key, notBefore)
+}
+
+func insertTestIdempotencyKey(
+	t *testing.T, conn db.Connection, user uuid.UUID,
+) persistence.IdempotencyKey {
+	key := persistence.IdempotencyKey{
+		ChatUser: user,
+		Key:      uuid.NewString(),
+		Message:  uuid.New(),
+	}
+
+	createdAt, err := db.QueryOne[time.Time](
+		context.Background(),
+		conn,
+		`INSERT INTO
+			idempotency_key (chat_user, key, message)
+			VALUES ($1, $2, $3)
+			RETURNING created_at`,
+		key.ChatUser,
+		key.Key,
+		key.Message,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	key.CreatedAt = createdAt.UTC()
+
+	return key
+}
@@ -13,8 +13,11 @@ import (
 
 type MessageRepository interface {
 	Create(ctx context.Context, msg persistence.Message) (persistence.Message, error)
-	ListForRoom(ctx context.Context, room uuid.UUID) ([]persistence.Message, error)
+	CreateBatch(ctx context.Context, msgs []persistence.Message) ([]persistence.Message, error)
+	Get(ctx context.Context, id uuid.UUID) (persistence.Message, error)
+	ListForRoom(ctx context.Context, room uuid.UUID, sequences SequenceRange) ([]persistence.Message, error)
 	DeleteForRoom(ctx context.Context, tx db.Transaction, room uuid.UUID) error
+	DeleteExpired(ctx context.Context, tx db.Transaction, until time.Time) ([]persistence.Message, error)
 	UpdateMessagesOwner(ctx context.Context, tx db.Transaction, oldUser uuid.UUID, newUser string) error
 	UpdateMessagesOwnerForRoom(ctx context.Context, tx db.Transaction, room uuid.UUID, oldUser uuid.UUID, newUser string) error
 }
@@ -29,17 +32,34 @@ func NewMessageRepository(conn db.Connection) MessageRepository {
 	}
 }
 
+// When the message does not define an expiration time, the default
+// lifetime of the room (if any) is used.
 const createMessageSqlTemplate = `
-INSERT INTO message (id, chat_user, room, message)
-	VALUES ($1, $2, $3, $4)
-	RETURNING created_at`
+INSERT INTO message (id, chat_user, room, message, expires_at)
+	VALUES (
+		$1,
+		$2,
+		$3,
+		$4,
+		COALESCE(
+			$5,
+			CURRENT_TIMESTAMP + (SELECT message_ttl FROM room WHERE id = $3) * INTERVAL '1 second'
+		)
+	)
+	RETURNING created_at, expires_at, sequence`
+
+type createdAtExpiresAtSequence struct {
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+	Sequence  int64
+}
 
 const userNotInRoomForeignKey = "message_chat_user_room_fkey"
 
 func (r *messageRepositoryImpl) Create(
 	ctx context.Context, msg persistence.Message,
 ) (persistence.Message, error) {
-	createdAt, err := db.QueryOne[time.Time](
+	times, err := db.QueryOne[createdAtExpiresAtSequence](
 		ctx,
 		r.conn,
 		createMessageSqlTemplate,
@@ -47,9 +67,12 @@ func (r *messageRepositoryImpl) Create(
 		msg.ChatUser,
 		msg.Room,
 		msg.Message,
+		msg.ExpiresAt,
 	)
 
-	msg.CreatedAt = createdAt.UTC()
+	msg.CreatedAt = times.CreatedAt.UTC()
+	msg.ExpiresAt = toUtcTime(times.ExpiresAt)
+	msg.Sequence = times.Sequence
 
 	if errors.IsErrorWithCode(err, pgx.ForeignKeyValidation) {
 		foreignKey, ok := extractForeignKeyViolation(err)
@@ -62,32 +85,173 @@ func (r *messageRepositoryImpl) Create(
 	return msg, err
 }
 
+// The messages are inserted in a single statement: either all of them
+// are persisted or none is.
+const createMessageBatchSqlTemplate = `
+INSERT INTO message (id, chat_user, room, message, expires_at)
+	SELECT
+		m.id,
+		m.chat_user,
+		m.room,
+		m.message,
+		COALESCE(
+			m.expires_at,
+			CURRENT_TIMESTAMP + r.message_ttl * INTERVAL '1 second'
+		)
+	FROM
+		UNNEST($1::UUID[], $2::UUID[], $3::UUID[], $4::TEXT[], $5::TIMESTAMP WITH TIME ZONE[])
+			WITH ORDINALITY AS m(id, chat_user, room, message, expires_at, position)
+		LEFT JOIN room AS r ON r.id = m.room
+	ORDER BY
+		m.position`
+
+const getMessageBatchTimesSqlTemplate = `
+SELECT
+	id,
+	created_at,
+	expires_at,
+	sequence
+FROM
+	message
+WHERE
+	id = ANY($1)`
+
+type idCreatedAtExpiresAtSequence struct {
+	Id        uuid.UUID
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+	Sequence  int64
+}
+
+func (r *messageRepositoryImpl) CreateBatch(
+	ctx context.Context, msgs []persistence.Message,
+) ([]persistence.Message, error) {
+	ids := make([]uuid.UUID, 0, len(msgs))
+	users := make([]uuid.UUID, 0, len(msgs))
+	rooms := make([]uuid.UUID, 0, len(msgs))
+	texts := make([]string, 0, len(msgs))
+	expirations := make([]*time.Time, 0, len(msgs))
+	for _, msg := range msgs {
+		ids = append(ids, msg.Id)
+		users = append(users, msg.ChatUser)
+		rooms = append(rooms, msg.Room)
+		texts = append(texts, msg.Message)
+		expirations = append(expirations, msg.ExpiresAt)
+	}
+
+	tx, err := r.conn.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close(ctx)
+
+	_, err = tx.Exec(
+		ctx,
+		createMessageBatchSqlTemplate,
+		ids,
+		users,
+		rooms,
+		texts,
+		expirations,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := db.QueryAllTx[idCreatedAtExpiresAtSequence](
+		ctx, tx, getMessageBatchTimesSqlTemplate, ids,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	times := make(map[uuid.UUID]idCreatedAtExpiresAtSequence, len(created))
+	for _, row := range created {
+		times[row.Id] = row
+	}
+
+	out := make([]persistence.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		msg.CreatedAt = times[msg.Id].CreatedAt.UTC()
+		msg.ExpiresAt = toUtcTime(times[msg.Id].ExpiresAt)
+		msg.Sequence = times[msg.Id].Sequence
+		out = append(out, msg)
+	}
+
+	return out, nil
+}
+
+const getMessageSqlTemplate = `
+SELECT
+	id,
+	chat_user,
+	room,
+	message,
+	sequence,
+	created_at,
+	expires_at
+FROM
+	message
+WHERE
+	id = $1
+	AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)`
+
+func (r *messageRepositoryImpl) Get(
+	ctx context.Context, id uuid.UUID,
+) (persistence.Message, error) {
+	message, err := db.QueryOne[persistence.Message](ctx, r.conn, getMessageSqlTemplate, id)
+
+	if err == nil {
+		message.CreatedAt = message.CreatedAt.UTC()
+		message.ExpiresAt = toUtcTime(message.ExpiresAt)
+	}
+
+	return message, err
+}
+
 const listMessageByRoomSqlTemplate = `
 SELECT
 	m.id,
 	m.chat_user,
 	m.room,
 	m.message,
-	m.created_at
+	m.sequence,
+	m.created_at,
+	m.expires_at
 FROM
 	message AS m
 	LEFT JOIN room AS r ON m.room = r.id
 WHERE
-	m.room = $1`
+	m.room = $1
+	AND (m.expires_at IS NULL OR m.expires_at > CURRENT_TIMESTAMP)
+	AND ($2 = 0 OR m.sequence >= $2)
+	AND ($3 = 0 OR m.sequence <= $3)
+ORDER BY
+	m.sequence`
+
+// SequenceRange restricts a list of messages to the ones with a sequence
+// between the bounds, inclusive. A bound set to 0 is not applied.
+type SequenceRange struct {
+	From int64
+	To   int64
+}
 
 func (r *messageRepositoryImpl) ListForRoom(
-	ctx context.Context, room uuid.UUID,
+	ctx context.Context, room uuid.UUID, sequences SequenceRange,
 ) ([]persistence.Message, error) {
 	messages, err := db.QueryAll[persistence.Message](
 		ctx,
 		r.conn,
 		listMessageByRoomSqlTemplate,
 		room,
+		sequences.From,
+		sequences.To,
 	)
 
 	if err == nil {
 		for id, message := range messages {
 			messages[id].CreatedAt = message.CreatedAt.UTC()
+			messages[id].ExpiresAt = toUtcTime(message.ExpiresAt)
 		}
 	}
 
@@ -107,6 +271,37 @@ func (r *messageRepositoryImpl) DeleteForRoom(
 	return err
 }
 
+const deleteExpiredMessageSqlTemplate = `
+DELETE FROM
+	message
+WHERE
+	expires_at <= $1
+RETURNING
+	id,
+	chat_user,
+	room,
+	message,
+	sequence,
+	created_at,
+	expires_at`
+
+func (r *messageRepositoryImpl) DeleteExpired(
+	ctx context.Context, tx db.Transaction, until time.Time,
+) ([]persistence.Message, error) {
+	messages, err := db.QueryAllTx[persistence.Message](
+		ctx, tx, deleteExpiredMessageSqlTemplate, until,
+	)
+
+	if err == nil {
+		for id, message := range messages {
+			messages[id].CreatedAt = message.CreatedAt.UTC()
+			messages[id].ExpiresAt = toUtcTime(message.ExpiresAt)
+		}
+	}
+
+	return messages, err
+}
+
 // https://stackoverflow.com/questions/7869592/how-to-do-an-update-join-in-postgresql
 const updateMessagesOwnerSqlTemplate = `
 WITH new_user AS (
@@ -34,11 +34,77 @@ func TestIT_MessageRepository_Create(t *testing.T) {
 	actual, err := repo.Create(context.Background(), msg)
 	assert.Nil(t, err, "Actual err: %v", err)
 
-	assert.True(t, eassert.EqualsIgnoringFields(actual, msg, "CreatedAt"))
+	assert.True(t, eassert.EqualsIgnoringFields(actual, msg, "CreatedAt", "Sequence"))
 	assert.True(t, actual.CreatedAt.After(beforeInsertion))
+	assert.Equal(t, int64(1), actual.Sequence)
 	assertMessageExists(t, conn, msg.Id)
 }
 
+func TestIT_MessageRepository_Create_WithExpiration(t *testing.T) {
+	repo, conn := newTestMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Microsecond)
+	msg := persistence.Message{
+		Id:        uuid.New(),
+		ChatUser:  user.Id,
+		Room:      room.Id,
+		Message:   "hello world!",
+		ExpiresAt: &expiresAt,
+	}
+
+	actual, err := repo.Create(context.Background(), msg)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, &expiresAt, actual.ExpiresAt)
+}
+
+func TestIT_MessageRepository_Create_WhenRoomDefinesTtl_ExpectMessageExpires(t *testing.T) {
+	repo, conn := newTestMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoomWithTtl(t, conn, 3600)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+
+	msg := persistence.Message{
+		Id:       uuid.New(),
+		ChatUser: user.Id,
+		Room:     room.Id,
+		Message:  "hello world!",
+	}
+
+	actual, err := repo.Create(context.Background(), msg)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.NotNil(t, actual.ExpiresAt)
+	assert.Equal(t, actual.CreatedAt.Add(time.Hour), *actual.ExpiresAt)
+}
+
+func TestIT_MessageRepository_Create_WhenMessageDefinesExpiration_ExpectRoomTtlIgnored(t *testing.T) {
+	repo, conn := newTestMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoomWithTtl(t, conn, 3600)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+
+	expiresAt := time.Now().Add(time.Minute).UTC().Truncate(time.Microsecond)
+	msg := persistence.Message{
+		Id:        uuid.New(),
+		ChatUser:  user.Id,
+		Room:      room.Id,
+		Message:   "hello world!",
+		ExpiresAt: &expiresAt,
+	}
+
+	actual, err := repo.Create(context.Background(), msg)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, &expiresAt, actual.ExpiresAt)
+}
+
 func TestIT_MessageRepository_Create_WhenUserNotRegisteredInRoom_ExpectFailure(t *testing.T) {
 	repo, conn := newTestMessageRepository(t)
 	defer conn.Close(context.Background())
@@ -110,6 +176,138 @@ func TestIT_MessageRepository_Create_WhenRoomDoesNotExist_ExpectFailure(t *testi
 	assertUserDoesNotExist(t, conn, msg.Id)
 }
 
+func TestIT_MessageRepository_CreateBatch(t *testing.T) {
+	repo, conn := newTestMessageRepository(t)
+	defer conn.Close(context.Background())
+	beforeInsertion := time.Now()
+
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Microsecond)
+	msgs := []persistence.Message{
+		{
+			Id:       uuid.New(),
+			ChatUser: user.Id,
+			Room:     room.Id,
+			Message:  "hello world!",
+		},
+		{
+			Id:        uuid.New(),
+			ChatUser:  user.Id,
+			Room:      room.Id,
+			Message:   "hello again!",
+			ExpiresAt: &expiresAt,
+		},
+	}
+
+	actual, err := repo.CreateBatch(context.Background(), msgs)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Len(t, actual, 2)
+	for id, msg := range msgs {
+		assert.True(t, eassert.EqualsIgnoringFields(actual[id], msg, "CreatedAt", "Sequence"))
+		assert.True(t, actual[id].CreatedAt.After(beforeInsertion))
+		assert.Equal(t, int64(id+1), actual[id].Sequence)
+		assertMessageExists(t, conn, msg.Id)
+	}
+}
+
+func TestIT_MessageRepository_CreateBatch_WhenRoomDefinesTtl_ExpectMessagesExpire(t *testing.T) {
+	repo, conn := newTestMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoomWithTtl(t, conn, 3600)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+
+	msgs := []persistence.Message{
+		{
+			Id:       uuid.New(),
+			ChatUser: user.Id,
+			Room:     room.Id,
+			Message:  "hello world!",
+		},
+		{
+			Id:       uuid.New(),
+			ChatUser: user.Id,
+			Room:     room.Id,
+			Message:  "hello again!",
+		},
+	}
+
+	actual, err := repo.CreateBatch(context.Background(), msgs)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	for _, msg := range actual {
+		assert.NotNil(t, msg.ExpiresAt)
+		assert.Equal(t, msg.CreatedAt.Add(time.Hour), *msg.ExpiresAt)
+	}
+}
+
+func TestIT_MessageRepository_CreateBatch_WhenOneMessageFails_ExpectNoneCreated(t *testing.T) {
+	repo, conn := newTestMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	other := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+
+	msgs := []persistence.Message{
+		{
+			Id:       uuid.New(),
+			ChatUser: user.Id,
+			Room:     room.Id,
+			Message:  "hello world!",
+		},
+		{
+			Id:       uuid.New(),
+			ChatUser: other.Id,
+			Room:     room.Id,
+			Message:  "not in room",
+		},
+	}
+
+	_, err := repo.CreateBatch(context.Background(), msgs)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, pgx.ForeignKeyValidation),
+		"Actual err: %v",
+		err,
+	)
+	for _, msg := range msgs {
+		assertMessageDoesNotExist(t, conn, msg.Id)
+	}
+}
+
+func TestIT_MessageRepository_Get(t *testing.T) {
+	repo, conn := newTestMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+
+	actual, err := repo.Get(context.Background(), msg.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, msg, actual)
+}
+
+func TestIT_MessageRepository_Get_WhenNotFound_ExpectFailure(t *testing.T) {
+	repo, conn := newTestMessageRepository(t)
+	defer conn.Close(context.Background())
+
+	_, err := repo.Get(context.Background(), uuid.New())
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
 func TestIT_MessageRepository_ListForRoom(t *testing.T) {
 	repo, conn := newTestMessageRepository(t)
 	defer conn.Close(context.Background())
@@ -123,13 +321,138 @@ func TestIT_MessageRepository_ListForRoom(t *testing.T) {
 	msg1 := insertTestMessage(t, conn, user1.Id, room1.Id)
 	insertTestMessage(t, conn, user2.Id, room2.Id)
 
-	actual, err := repo.ListForRoom(context.Background(), room1.Id)
+	actual, err := repo.ListForRoom(context.Background(), room1.Id, SequenceRange{})
 	assert.Nil(t, err, "Actual err: %v", err)
 
 	expected := []persistence.Message{msg1}
 	assert.ElementsMatch(t, expected, actual)
 }
 
+func TestIT_MessageRepository_ListForRoom_DoesNotReturnExpiredMessages(t *testing.T) {
+	repo, conn := newTestMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+	insertTestExpiredMessage(t, conn, user.Id, room.Id)
+
+	actual, err := repo.ListForRoom(context.Background(), room.Id, SequenceRange{})
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, []persistence.Message{msg}, actual)
+}
+
+func TestIT_MessageRepository_ListForRoom_WithSequenceRange(t *testing.T) {
+	repo, conn := newTestMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	insertTestMessage(t, conn, user.Id, room.Id)
+	msg2 := insertTestMessage(t, conn, user.Id, room.Id)
+	msg3 := insertTestMessage(t, conn, user.Id, room.Id)
+	insertTestMessage(t, conn, user.Id, room.Id)
+
+	sequences := SequenceRange{From: msg2.Sequence, To: msg3.Sequence}
+	actual, err := repo.ListForRoom(context.Background(), room.Id, sequences)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, []persistence.Message{msg2, msg3}, actual)
+}
+
+func TestIT_MessageRepository_ListForRoom_WithOpenSequenceRange(t *testing.T) {
+	repo, conn := newTestMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	insertTestMessage(t, conn, user.Id, room.Id)
+	msg2 := insertTestMessage(t, conn, user.Id, room.Id)
+	msg3 := insertTestMessage(t, conn, user.Id, room.Id)
+
+	sequences := SequenceRange{From: msg2.Sequence}
+	actual, err := repo.ListForRoom(context.Background(), room.Id, sequences)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, []persistence.Message{msg2, msg3}, actual)
+}
+
+func TestIT_MessageRepository_Create_AssignsSequencePerRoom(t *testing.T) {
+	_, conn := newTestMessageRepository(t)
+	defer conn.Close(context.Background())
+	room1 := insertTestRoom(t, conn)
+	room2 := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room1.Id)
+	registerUserInRoom(t, conn, user.Id, room2.Id)
+
+	msg1 := insertTestMessage(t, conn, user.Id, room1.Id)
+	msg2 := insertTestMessage(t, conn, user.Id, room2.Id)
+	msg3 := insertTestMessage(t, conn, user.Id, room1.Id)
+
+	assert.Equal(t, int64(1), msg1.Sequence)
+	assert.Equal(t, int64(1), msg2.Sequence)
+	assert.Equal(t, int64(2), msg3.Sequence)
+}
+
+func TestIT_MessageRepository_Create_WhenInsertionFails_ExpectNoGapInSequence(t *testing.T) {
+	repo, conn := newTestMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg1 := insertTestMessage(t, conn, user.Id, room.Id)
+
+	duplicate := msg1
+	duplicate.Sequence = 0
+	_, err := repo.Create(context.Background(), duplicate)
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, pgx.UniqueConstraintViolation),
+		"Actual err: %v",
+		err,
+	)
+
+	msg2 := insertTestMessage(t, conn, user.Id, room.Id)
+	assert.Equal(t, msg1.Sequence+1, msg2.Sequence)
+}
+
+func TestIT_MessageRepository_Get_WhenExpired_ExpectFailure(t *testing.T) {
+	repo, conn := newTestMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestExpiredMessage(t, conn, user.Id, room.Id)
+
+	_, err := repo.Get(context.Background(), msg.Id)
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_MessageRepository_DeleteExpired(t *testing.T) {
+	repo, conn, tx := newTestMessageRepositoryAndTransaction(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+	expired := insertTestExpiredMessage(t, conn, user.Id, room.Id)
+
+	actual, err := repo.DeleteExpired(context.Background(), tx, time.Now())
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Contains(t, actual, expired)
+	assertMessageExists(t, conn, msg.Id)
+	assertMessageDoesNotExist(t, conn, expired.Id)
+}
+
 func TestIT_MessageRepository_DeleteForRoom(t *testing.T) {
 	repo, conn, tx := newTestMessageRepositoryAndTransaction(t)
 	defer conn.Close(context.Background())
@@ -358,7 +681,7 @@ func TestIT_MessageRepository_ListForRoom_WhenNoMessageAvailable_ReturnsEmptySli
 	defer conn.Close(context.Background())
 	room := insertTestRoom(t, conn)
 
-	actual, err := repo.ListForRoom(context.Background(), room.Id)
+	actual, err := repo.ListForRoom(context.Background(), room.Id, SequenceRange{})
 	assert.Nil(t, err, "Actual err: %v", err)
 
 	assert.Equal(t, []persistence.Message{}, actual)
@@ -411,6 +734,11 @@ func assertMessageOwner(t *testing.T, conn db.Connection, msg uuid.UUID, user uu
 	assert.Equal(t, user, value)
 }
 
+type createdAtSequence struct {
+	CreatedAt time.Time
+	Sequence  int64
+}
+
 func insertTestMessage(
 	t *testing.T,
 	conn db.Connection,
@@ -424,21 +752,58 @@ func insertTestMessage(
 		Message:  "my-message-" + uuid.NewString(),
 	}
 
-	createdAt, err := db.QueryOne[time.Time](
+	created, err := db.QueryOne[createdAtSequence](
 		context.Background(),
 		conn,
 		`INSERT INTO
 			message (id, chat_user, room, message)
 			VALUES ($1, $2, $3, $4)
-			RETURNING created_at`,
+			RETURNING created_at, sequence`,
+		msg.Id,
+		msg.ChatUser,
+		msg.Room,
+		msg.Message,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	msg.CreatedAt = created.CreatedAt.UTC()
+	msg.Sequence = created.Sequence
+
+	return msg
+}
+
+func insertTestExpiredMessage(
+	t *testing.T,
+	conn db.Connection,
+	user uuid.UUID,
+	room uuid.UUID,
+) persistence.Message {
+	expiresAt := time.Now().Add(-time.Minute).UTC().Truncate(time.Microsecond)
+	msg := persistence.Message{
+		Id:        uuid.New(),
+		ChatUser:  user,
+		Room:      room,
+		Message:   "my-message-" + uuid.NewString(),
+		ExpiresAt: &expiresAt,
+	}
+
+	created, err := db.QueryOne[createdAtSequence](
+		context.Background(),
+		conn,
+		`INSERT INTO
+			message (id, chat_user, room, message, expires_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING created_at, sequence`,
 		msg.Id,
 		msg.ChatUser,
 		msg.Room,
 		msg.Message,
+		msg.ExpiresAt,
 	)
 	assert.Nil(t, err, "Actual err: %v", err)
 
-	msg.CreatedAt = createdAt.UTC()
+	msg.CreatedAt = created.CreatedAt.UTC()
+	msg.Sequence = created.Sequence
 
 	return msg
 }
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type MessageStatusRepository interface {
+	Create(ctx context.Context, tx db.Transaction, status persistence.MessageStatus) (persistence.MessageStatus, error)
+	Get(ctx context.Context, message uuid.UUID) (persistence.MessageStatus, error)
+	// Update creates the status if it does not exist yet: this is the
+	// case for messages which did not go through the queue first (e.g.
+	// scheduled messages).
+	Update(ctx context.Context, status persistence.MessageStatus) (persistence.MessageStatus, error)
+	// Requeue marks the message as queued again as part of the transaction.
+	Requeue(ctx context.Context, tx db.Transaction, message uuid.UUID, room uuid.UUID) (persistence.MessageStatus, error)
+	DeleteForRoom(ctx context.Context, tx db.Transaction, room uuid.UUID) error
+}
+
+type messageStatusRepositoryImpl struct {
+	conn db.Connection
+}
+
+func NewMessageStatusRepository(conn db.Connection) MessageStatusRepository {
+	return &messageStatusRepositoryImpl{
+		conn: conn,
+	}
+}
+
+const createMessageStatusSqlTemplate = `
+INSERT INTO message_status (message, room, status, reason)
+	VALUES ($1, $2, $3, $4)
+	RETURNING created_at, updated_at`
+
+func (r *messageStatusRepositoryImpl) Create(
+	ctx context.Context, tx db.Transaction, status persistence.MessageStatus,
+) (persistence.MessageStatus, error) {
+	times, err := db.QueryOneTx[createdAtUpdatedAt](
+		ctx,
+		tx,
+		createMessageStatusSqlTemplate,
+		status.Message,
+		status.Room,
+		status.Status,
+		status.Reason,
+	)
+
+	status.CreatedAt = times.CreatedAt.UTC()
+	status.UpdatedAt = times.UpdatedAt.UTC()
+
+	return status, err
+}
+
+const getMessageStatusSqlTemplate = `
+SELECT
+	message,
+	room,
+	status,
+	reason,
+	created_at,
+	updated_at
+FROM
+	message_status
+WHERE
+	message = $1`
+
+func (r *messageStatusRepositoryImpl) Get(
+	ctx context.Context, message uuid.UUID,
+) (persistence.MessageStatus, error) {
+	status, err := db.QueryOne[persistence.MessageStatus](
+		ctx, r.conn, getMessageStatusSqlTemplate, message,
+	)
+
+	status.CreatedAt = status.CreatedAt.UTC()
+	status.UpdatedAt = status.UpdatedAt.UTC()
+
+	return status, err
+}
+
+const updateMessageStatusSqlTemplate = `
+INSERT INTO message_status (message, room, status, reason)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (message) DO UPDATE SET
+		status = excluded.status,
+		reason = excluded.reason
+	RETURNING created_at, updated_at`
+
+func (r *messageStatusRepositoryImpl) Update(
+	ctx context.Context, status persistence.MessageStatus,
+) (persistence.MessageStatus, error) {
+	times, err := db.QueryOne[createdAtUpdatedAt](
+		ctx,
+		r.conn,
+		updateMessageStatusSqlTemplate,
+		status.Message,
+		status.Room,
+		status.Status,
+		status.Reason,
+	)
+
+	status.CreatedAt = times.CreatedAt.UTC()
+	status.UpdatedAt = times.UpdatedAt.UTC()
+
+	return status, err
+}
+
+func (r *messageStatusRepositoryImpl) Requeue(
+	ctx context.Context, tx db.Transaction, message uuid.UUID, room uuid.UUID,
+) (persistence.MessageStatus, error) {
+	status := persistence.MessageStatus{
+		Message: message,
+		Room:    room,
+		Status:  persistence.MessageQueued,
+	}
+
+	times, err := db.QueryOneTx[createdAtUpdatedAt](
+		ctx,
+		tx,
+		updateMessageStatusSqlTemplate,
+		status.Message,
+		status.Room,
+		status.Status,
+		status.Reason,
+	)
+
+	status.CreatedAt = times.CreatedAt.UTC()
+	status.UpdatedAt = times.UpdatedAt.UTC()
+
+	return status, err
+}
+
+const deleteMessageStatusByRoomSqlTemplate = `
+DELETE FROM
+	message_status
+WHERE
+	room = $1`
+
+func (r *messageStatusRepositoryImpl) DeleteForRoom(
+	ctx context.Context, tx db.Transaction, room uuid.UUID,
+) error {
+	_, err := tx.Exec(ctx, deleteMessageStatusByRoomSqlTemplate, room)
+	return err
+}
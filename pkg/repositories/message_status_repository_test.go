@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	eassert "github.com/Knoblauchpilze/easy-assert/assert"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_MessageStatusRepository_Create(t *testing.T) {
+	repo, conn := newTestMessageStatusRepository(t)
+	defer conn.Close(context.Background())
+	beforeInsertion := time.Now()
+
+	status := persistence.MessageStatus{
+		Message: uuid.New(),
+		Room:    uuid.New(),
+		Status:  persistence.MessageQueued,
+	}
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	actual, err := repo.Create(context.Background(), tx, status)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.True(t, eassert.EqualsIgnoringFields(actual, status, "CreatedAt", "UpdatedAt"))
+	assert.True(t, actual.CreatedAt.After(beforeInsertion))
+	assert.Equal(t, actual.CreatedAt, actual.UpdatedAt)
+}
+
+func TestIT_MessageStatusRepository_Create_WhenStatusIsUnknown_ExpectFailure(t *testing.T) {
+	repo, conn := newTestMessageStatusRepository(t)
+	defer conn.Close(context.Background())
+
+	status := persistence.MessageStatus{
+		Message: uuid.New(),
+		Room:    uuid.New(),
+		Status:  "not-a-status",
+	}
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	_, err = repo.Create(context.Background(), tx, status)
+	tx.Close(context.Background())
+	assert.NotNil(t, err)
+}
+
+func TestIT_MessageStatusRepository_Get(t *testing.T) {
+	repo, conn := newTestMessageStatusRepository(t)
+	defer conn.Close(context.Background())
+	status := insertTestMessageStatus(t, conn, uuid.New())
+
+	actual, err := repo.Get(context.Background(), status.Message)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, status, actual)
+}
+
+func TestIT_MessageStatusRepository_Get_WhenNotFound_ExpectFailure(t *testing.T) {
+	repo, conn := newTestMessageStatusRepository(t)
+	defer conn.Close(context.Background())
+
+	_, err := repo.Get(context.Background(), uuid.New())
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_MessageStatusRepository_Update(t *testing.T) {
+	repo, conn := newTestMessageStatusRepository(t)
+	defer conn.Close(context.Background())
+	status := insertTestMessageStatus(t, conn, uuid.New())
+
+	reason := "some reason"
+	status.Status = persistence.MessageFailed
+	status.Reason = &reason
+
+	actual, err := repo.Update(context.Background(), status)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.True(t, eassert.EqualsIgnoringFields(actual, status, "UpdatedAt"))
+	assert.True(t, actual.UpdatedAt.After(status.UpdatedAt))
+
+	stored, err := repo.Get(context.Background(), status.Message)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, actual, stored)
+}
+
+func TestIT_MessageStatusRepository_Update_WhenStatusDoesNotExist_ExpectCreated(t *testing.T) {
+	repo, conn := newTestMessageStatusRepository(t)
+	defer conn.Close(context.Background())
+
+	status := persistence.MessageStatus{
+		Message: uuid.New(),
+		Room:    uuid.New(),
+		Status:  persistence.MessagePersisted,
+	}
+
+	actual, err := repo.Update(context.Background(), status)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	stored, err := repo.Get(context.Background(), status.Message)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, actual, stored)
+}
+
+func TestIT_MessageStatusRepository_Requeue(t *testing.T) {
+	repo, conn := newTestMessageStatusRepository(t)
+	defer conn.Close(context.Background())
+	status := insertTestMessageStatus(t, conn, uuid.New())
+
+	reason := "some reason"
+	status.Status = persistence.MessageFailed
+	status.Reason = &reason
+	_, err := repo.Update(context.Background(), status)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	actual, err := repo.Requeue(context.Background(), tx, status.Message, status.Room)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, persistence.MessageQueued, actual.Status)
+	assert.Nil(t, actual.Reason)
+
+	stored, err := repo.Get(context.Background(), status.Message)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, actual, stored)
+}
+
+func TestIT_MessageStatusRepository_DeleteForRoom(t *testing.T) {
+	repo, conn := newTestMessageStatusRepository(t)
+	defer conn.Close(context.Background())
+	room := uuid.New()
+	status := insertTestMessageStatus(t, conn, room)
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	err = repo.DeleteForRoom(context.Background(), tx, room)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	_, err = repo.Get(context.Background(), status.Message)
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func newTestMessageStatusRepository(
+	t *testing.T,
+) (MessageStatusRepository, db.Connection) {
+	conn := newTestConnection(t)
+	return NewMessageStatusRepository(conn), conn
+}
+
+func insertTestMessageStatus(
+	t *testing.T, conn db.Connection, room uuid.UUID,
+) persistence.MessageStatus {
+	status := persistence.MessageStatus{
+		Message: uuid.New(),
+		Room:    room,
+		Status:  persistence.MessageQueued,
+	}
+
+	times, err := db.QueryOne[createdAtUpdatedAt](
+		context.Background(),
+		conn,
+		`INSERT INTO
+			message_status (message, room, status)
+			VALUES ($1, $2, $3)
+			RETURNING created_at, updated_at`,
+		status.Message,
+		status.Room,
+		status.Status,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	status.CreatedAt = times.CreatedAt.UTC()
+	status.UpdatedAt = times.UpdatedAt.UTC()
+
+	return status
+}
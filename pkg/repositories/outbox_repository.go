@@ -0,0 +1,230 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type OutboxRepository interface {
+	Create(ctx context.Context, tx db.Transaction, entry persistence.OutboxEntry) (persistence.OutboxEntry, error)
+	// Requeue creates the entry or makes it pending again if it was
+	// already delivered: it is then dispatched after the entries which
+	// are currently pending.
+	Requeue(ctx context.Context, tx db.Transaction, entry persistence.OutboxEntry) (persistence.OutboxEntry, error)
+	Get(ctx context.Context, id uuid.UUID) (persistence.OutboxEntry, error)
+	// ListPending returns at most limit entries in the order in which
+	// they were written to the outbox.
+	ListPending(ctx context.Context, limit int) ([]persistence.OutboxEntry, error)
+	MarkDelivered(ctx context.Context, id uuid.UUID) error
+	DeleteDelivered(ctx context.Context, until time.Time) error
+	DeleteForRoom(ctx context.Context, tx db.Transaction, room uuid.UUID) error
+}
+
+type outboxRepositoryImpl struct {
+	conn db.Connection
+}
+
+func NewOutboxRepository(conn db.Connection) OutboxRepository {
+	return &outboxRepositoryImpl{
+		conn: conn,
+	}
+}
+
+const createOutboxEntrySqlTemplate = `
+INSERT INTO outbox (id, chat_user, room, message, attachments, expires_at, idempotency_key)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING sequence, created_at`
+
+type sequenceCreatedAt struct {
+	Sequence  int64
+	CreatedAt time.Time
+}
+
+func (r *outboxRepositoryImpl) Create(
+	ctx context.Context, tx db.Transaction, entry persistence.OutboxEntry,
+) (persistence.OutboxEntry, error) {
+	if entry.Attachments == nil {
+		entry.Attachments = []uuid.UUID{}
+	}
+
+	out, err := db.QueryOneTx[sequenceCreatedAt](
+		ctx,
+		tx,
+		createOutboxEntrySqlTemplate,
+		entry.Id,
+		entry.ChatUser,
+		entry.Room,
+		entry.Message,
+		entry.Attachments,
+		entry.ExpiresAt,
+		entry.IdempotencyKey,
+	)
+
+	entry.Sequence = out.Sequence
+	entry.CreatedAt = out.CreatedAt.UTC()
+
+	return entry, err
+}
+
+const requeueOutboxEntrySqlTemplate = `
+INSERT INTO outbox (id, chat_user, room, message, attachments, expires_at, idempotency_key)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (id) DO UPDATE SET
+		sequence = excluded.sequence,
+		chat_user = excluded.chat_user,
+		room = excluded.room,
+		message = excluded.message,
+		attachments = excluded.attachments,
+		expires_at = excluded.expires_at,
+		idempotency_key = excluded.idempotency_key,
+		created_at = CURRENT_TIMESTAMP,
+		delivered_at = NULL
+	RETURNING sequence, created_at`
+
+func (r *outboxRepositoryImpl) Requeue(
+	ctx context.Context, tx db.Transaction, entry persistence.OutboxEntry,
+) (persistence.OutboxEntry, error) {
+	if entry.Attachments == nil {
+		entry.Attachments = []uuid.UUID{}
+	}
+
+	out, err := db.QueryOneTx[sequenceCreatedAt](
+		ctx,
+		tx,
+		requeueOutboxEntrySqlTemplate,
+		entry.Id,
+		entry.ChatUser,
+		entry.Room,
+		entry.Message,
+		entry.Attachments,
+		entry.ExpiresAt,
+		entry.IdempotencyKey,
+	)
+
+	entry.Sequence = out.Sequence
+	entry.CreatedAt = out.CreatedAt.UTC()
+	entry.DeliveredAt = nil
+
+	return entry, err
+}
+
+const getOutboxEntrySqlTemplate = `
+SELECT
+	id,
+	sequence,
+	chat_user,
+	room,
+	message,
+	attachments,
+	expires_at,
+	idempotency_key,
+	created_at,
+	delivered_at
+FROM
+	outbox
+WHERE
+	id = $1`
+
+func (r *outboxRepositoryImpl) Get(
+	ctx context.Context, id uuid.UUID,
+) (persistence.OutboxEntry, error) {
+	entry, err := db.QueryOne[persistence.OutboxEntry](
+		ctx, r.conn, getOutboxEntrySqlTemplate, id,
+	)
+
+	if err == nil {
+		entry = toUtcOutboxEntry(entry)
+	}
+
+	return entry, err
+}
+
+const listPendingOutboxEntriesSqlTemplate = `
+SELECT
+	id,
+	sequence,
+	chat_user,
+	room,
+	message,
+	attachments,
+	expires_at,
+	idempotency_key,
+	created_at,
+	delivered_at
+FROM
+	outbox
+WHERE
+	delivered_at IS NULL
+ORDER BY
+	sequence
+LIMIT
+	$1`
+
+func (r *outboxRepositoryImpl) ListPending(
+	ctx context.Context, limit int,
+) ([]persistence.OutboxEntry, error) {
+	entries, err := db.QueryAll[persistence.OutboxEntry](
+		ctx, r.conn, listPendingOutboxEntriesSqlTemplate, limit,
+	)
+
+	if err == nil {
+		for id, entry := range entries {
+			entries[id] = toUtcOutboxEntry(entry)
+		}
+	}
+
+	return entries, err
+}
+
+const markOutboxEntryDeliveredSqlTemplate = `
+UPDATE
+	outbox
+SET
+	delivered_at = CURRENT_TIMESTAMP
+WHERE
+	id = $1
+	AND delivered_at IS NULL`
+
+func (r *outboxRepositoryImpl) MarkDelivered(
+	ctx context.Context, id uuid.UUID,
+) error {
+	_, err := r.conn.Exec(ctx, markOutboxEntryDeliveredSqlTemplate, id)
+	return err
+}
+
+const deleteDeliveredOutboxEntriesSqlTemplate = `
+DELETE FROM
+	outbox
+WHERE
+	delivered_at < $1`
+
+func (r *outboxRepositoryImpl) DeleteDelivered(
+	ctx context.Context, until time.Time,
+) error {
+	_, err := r.conn.Exec(ctx, deleteDeliveredOutboxEntriesSqlTemplate, until)
+	return err
+}
+
+const deleteOutboxEntryByRoomSqlTemplate = `
+DELETE FROM
+	outbox
+WHERE
+	room = $1`
+
+func (r *outboxRepositoryImpl) DeleteForRoom(
+	ctx context.Context, tx db.Transaction, room uuid.UUID,
+) error {
+	_, err := tx.Exec(ctx, deleteOutboxEntryByRoomSqlTemplate, room)
+	return err
+}
+
+func toUtcOutboxEntry(entry persistence.OutboxEntry) persistence.OutboxEntry {
+	entry.ExpiresAt = toUtcTime(entry.ExpiresAt)
+	entry.CreatedAt = entry.CreatedAt.UTC()
+	entry.DeliveredAt = toUtcTime(entry.DeliveredAt)
+	return entry
+}
@@ -0,0 +1,240 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	eassert "github.com/Knoblauchpilze/easy-assert/assert"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_OutboxRepository_Create(t *testing.T) {
+	repo, conn := newTestOutboxRepository(t)
+	defer conn.Close(context.Background())
+	beforeInsertion := time.Now()
+
+	expiresAt := time.Date(2100, 1, 1, 12, 0, 0, 0, time.UTC)
+	entry := persistence.OutboxEntry{
+		Id:             uuid.New(),
+		ChatUser:       uuid.New(),
+		Room:           uuid.New(),
+		Message:        "Hello world",
+		Attachments:    []uuid.UUID{uuid.New()},
+		ExpiresAt:      &expiresAt,
+		IdempotencyKey: "my-key",
+	}
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	actual, err := repo.Create(context.Background(), tx, entry)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.True(t, eassert.EqualsIgnoringFields(actual, entry, "Sequence", "CreatedAt"))
+	assert.True(t, actual.CreatedAt.After(beforeInsertion))
+	assert.Nil(t, actual.DeliveredAt)
+
+	stored, err := repo.Get(context.Background(), entry.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, actual, stored)
+}
+
+func TestIT_OutboxRepository_Create_ExpectIncreasingSequence(t *testing.T) {
+	_, conn := newTestOutboxRepository(t)
+	defer conn.Close(context.Background())
+
+	first := insertTestOutboxEntry(t, conn, uuid.New())
+	second := insertTestOutboxEntry(t, conn, uuid.New())
+
+	assert.Less(t, first.Sequence, second.Sequence)
+}
+
+func TestIT_OutboxRepository_Requeue_WhenDelivered_ExpectPendingAgain(t *testing.T) {
+	repo, conn := newTestOutboxRepository(t)
+	defer conn.Close(context.Background())
+	entry := insertTestOutboxEntry(t, conn, uuid.New())
+	other := insertTestOutboxEntry(t, conn, entry.Room)
+
+	err := repo.MarkDelivered(context.Background(), entry.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	actual, err := repo.Requeue(context.Background(), tx, entry)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Greater(t, actual.Sequence, other.Sequence)
+	stored, err := repo.Get(context.Background(), entry.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Nil(t, stored.DeliveredAt)
+	assert.Equal(t, actual.Sequence, stored.Sequence)
+}
+
+func TestIT_OutboxRepository_Get_WhenNotFound_ExpectFailure(t *testing.T) {
+	repo, conn := newTestOutboxRepository(t)
+	defer conn.Close(context.Background())
+
+	_, err := repo.Get(context.Background(), uuid.New())
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_OutboxRepository_ListPending(t *testing.T) {
+	repo, conn := newTestOutboxRepository(t)
+	defer conn.Close(context.Background())
+	first := insertTestOutboxEntry(t, conn, uuid.New())
+	second := insertTestOutboxEntry(t, conn, uuid.New())
+
+	actual, err := repo.ListPending(context.Background(), 1000)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	firstIndex := indexOfOutboxEntry(actual, first.Id)
+	secondIndex := indexOfOutboxEntry(actual, second.Id)
+	assert.NotEqual(t, -1, firstIndex)
+	assert.Less(t, firstIndex, secondIndex)
+}
+
+func TestIT_OutboxRepository_ListPending_ExpectLimitApplied(t *testing.T) {
+	repo, conn := newTestOutboxRepository(t)
+	defer conn.Close(context.Background())
+	insertTestOutboxEntry(t, conn, uuid.New())
+	insertTestOutboxEntry(t, conn, uuid.New())
+
+	actual, err := repo.ListPending(context.Background(), 1)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Len(t, actual, 1)
+}
+
+func TestIT_OutboxRepository_ListPending_WhenDelivered_ExpectNotReturned(t *testing.T) {
+	repo, conn := newTestOutboxRepository(t)
+	defer conn.Close(context.Background())
+	entry := insertTestOutboxEntry(t, conn, uuid.New())
+
+	err := repo.MarkDelivered(context.Background(), entry.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual, err := repo.ListPending(context.Background(), 1000)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, -1, indexOfOutboxEntry(actual, entry.Id))
+}
+
+func TestIT_OutboxRepository_MarkDelivered(t *testing.T) {
+	repo, conn := newTestOutboxRepository(t)
+	defer conn.Close(context.Background())
+	entry := insertTestOutboxEntry(t, conn, uuid.New())
+
+	err := repo.MarkDelivered(context.Background(), entry.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual, err := repo.Get(context.Background(), entry.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.NotNil(t, actual.DeliveredAt)
+}
+
+func TestIT_OutboxRepository_MarkDelivered_WhenNotFound_ExpectSuccess(t *testing.T) {
+	repo, conn := newTestOutboxRepository(t)
+	defer conn.Close(context.Background())
+
+	err := repo.MarkDelivered(context.Background(), uuid.New())
+	assert.Nil(t, err, "Actual err: %v", err)
+}
+
+func TestIT_OutboxRepository_DeleteDelivered(t *testing.T) {
+	repo, conn := newTestOutboxRepository(t)
+	defer conn.Close(context.Background())
+	delivered := insertTestOutboxEntry(t, conn, uuid.New())
+	pending := insertTestOutboxEntry(t, conn, uuid.New())
+
+	err := repo.MarkDelivered(context.Background(), delivered.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	err = repo.DeleteDelivered(context.Background(), time.Now().Add(time.Minute))
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	_, err = repo.Get(context.Background(), delivered.Id)
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+	_, err = repo.Get(context.Background(), pending.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+}
+
+func TestIT_OutboxRepository_DeleteForRoom(t *testing.T) {
+	repo, conn := newTestOutboxRepository(t)
+	defer conn.Close(context.Background())
+	room := uuid.New()
+	entry := insertTestOutboxEntry(t, conn, room)
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	err = repo.DeleteForRoom(context.Background(), tx, room)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	_, err = repo.Get(context.Background(), entry.Id)
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func newTestOutboxRepository(t *testing.T) (OutboxRepository, db.Connection) {
+	conn := newTestConnection(t)
+	return NewOutboxRepository(conn), conn
+}
+
+func insertTestOutboxEntry(
+	t *testing.T, conn db.Connection, room uuid.UUID,
+) persistence.OutboxEntry {
+	entry := persistence.OutboxEntry{
+		Id:          uuid.New(),
+		ChatUser:    uuid.New(),
+		Room:        room,
+		Message:     "my-message-" + uuid.NewString(),
+		Attachments: []uuid.UUID{},
+	}
+
+	out, err := db.QueryOne[sequenceCreatedAt](
+		context.Background(),
+		conn,
+		`INSERT INTO
+			outbox (id, chat_user, room, message)
+			VALUES ($1, $2, $3, $4)
+			RETURNING sequence, created_at`,
+		entry.Id,
+		entry.ChatUser,
+		entry.Room,
+		entry.Message,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	entry.Sequence = out.Sequence
+	entry.CreatedAt = out.CreatedAt.UTC()
+
+	return entry
+}
+
+func indexOfOutboxEntry(entries []persistence.OutboxEntry, id uuid.UUID) int {
+	for index, entry := range entries {
+		if entry.Id == id {
+			return index
+		}
+	}
+	return -1
+}
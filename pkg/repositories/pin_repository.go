@@ -0,0 +1,204 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/pgx"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type PinRepository interface {
+	Create(ctx context.Context, tx db.Transaction, pin persistence.Pin) (persistence.Pin, error)
+	CountForRoom(ctx context.Context, tx db.Transaction, room uuid.UUID) (int, error)
+	ListForRoom(ctx context.Context, room uuid.UUID) ([]persistence.PinnedMessage, error)
+	Delete(ctx context.Context, room uuid.UUID, message uuid.UUID) error
+	DeleteForRoom(ctx context.Context, tx db.Transaction, room uuid.UUID) error
+	DeleteForExpiredMessages(ctx context.Context, tx db.Transaction, until time.Time) error
+	UpdatePinsOwner(ctx context.Context, tx db.Transaction, oldUser uuid.UUID, newUser string) error
+}
+
+type pinRepositoryImpl struct {
+	conn db.Connection
+}
+
+func NewPinRepository(conn db.Connection) PinRepository {
+	return &pinRepositoryImpl{
+		conn: conn,
+	}
+}
+
+const createPinSqlTemplate = `
+INSERT INTO pinned_message (room, message, pinned_by)
+	VALUES ($1, $2, $3)
+	RETURNING created_at`
+
+func (r *pinRepositoryImpl) Create(
+	ctx context.Context, tx db.Transaction, pin persistence.Pin,
+) (persistence.Pin, error) {
+	createdAt, err := db.QueryOneTx[time.Time](
+		ctx,
+		tx,
+		createPinSqlTemplate,
+		pin.Room,
+		pin.Message,
+		pin.PinnedBy,
+	)
+
+	pin.CreatedAt = createdAt.UTC()
+
+	if errors.IsErrorWithCode(err, pgx.UniqueConstraintViolation) {
+		return pin, errors.WrapCode(err, ErrMessageAlreadyPinned)
+	}
+
+	return pin, err
+}
+
+const countPinByRoomSqlTemplate = `
+SELECT
+	COUNT(*)
+FROM
+	pinned_message
+WHERE
+	room = $1`
+
+func (r *pinRepositoryImpl) CountForRoom(
+	ctx context.Context, tx db.Transaction, room uuid.UUID,
+) (int, error) {
+	return db.QueryOneTx[int](ctx, tx, countPinByRoomSqlTemplate, room)
+}
+
+type pinnedMessage struct {
+	Room     uuid.UUID
+	Message  uuid.UUID
+	PinnedBy uuid.UUID
+	PinnedAt time.Time
+
+	ChatUser  uuid.UUID
+	Content   string
+	Sequence  int64
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+}
+
+const listPinByRoomSqlTemplate = `
+SELECT
+	p.room,
+	p.message,
+	p.pinned_by,
+	p.created_at AS pinned_at,
+	m.chat_user,
+	m.message AS content,
+	m.sequence,
+	m.created_at,
+	m.expires_at
+FROM
+	pinned_message AS p
+	INNER JOIN message AS m ON p.message = m.id
+WHERE
+	p.room = $1
+	AND (m.expires_at IS NULL OR m.expires_at > CURRENT_TIMESTAMP)
+ORDER BY
+	p.created_at`
+
+func (r *pinRepositoryImpl) ListForRoom(
+	ctx context.Context, room uuid.UUID,
+) ([]persistence.PinnedMessage, error) {
+	rows, err := db.QueryAll[pinnedMessage](
+		ctx, r.conn, listPinByRoomSqlTemplate, room,
+	)
+	if err != nil {
+		return []persistence.PinnedMessage{}, err
+	}
+
+	out := make([]persistence.PinnedMessage, 0, len(rows))
+	for _, row := range rows {
+		pinned := persistence.PinnedMessage{
+			Pin: persistence.Pin{
+				Room:      row.Room,
+				Message:   row.Message,
+				PinnedBy:  row.PinnedBy,
+				CreatedAt: row.PinnedAt.UTC(),
+			},
+			Message: persistence.Message{
+				Id:        row.Message,
+				ChatUser:  row.ChatUser,
+				Room:      row.Room,
+				Message:   row.Content,
+				Sequence:  row.Sequence,
+				CreatedAt: row.CreatedAt.UTC(),
+				ExpiresAt: toUtcTime(row.ExpiresAt),
+			},
+		}
+		out = append(out, pinned)
+	}
+
+	return out, nil
+}
+
+const deletePinSqlTemplate = `
+DELETE FROM
+	pinned_message
+WHERE
+	room = $1
+	AND message = $2`
+
+func (r *pinRepositoryImpl) Delete(
+	ctx context.Context, room uuid.UUID, message uuid.UUID,
+) error {
+	deleted, err := r.conn.Exec(ctx, deletePinSqlTemplate, room, message)
+
+	if err == nil && deleted == 0 {
+		return errors.NewCode(db.NoMatchingRows)
+	}
+	return err
+}
+
+const deletePinByRoomSqlTemplate = `
+DELETE FROM
+	pinned_message
+WHERE
+	room = $1`
+
+func (r *pinRepositoryImpl) DeleteForRoom(
+	ctx context.Context, tx db.Transaction, room uuid.UUID,
+) error {
+	_, err := tx.Exec(ctx, deletePinByRoomSqlTemplate, room)
+	return err
+}
+
+const deletePinForExpiredMessagesSqlTemplate = `
+DELETE FROM
+	pinned_message
+WHERE
+	message IN (
+		SELECT id FROM message WHERE expires_at <= $1
+	)`
+
+func (r *pinRepositoryImpl) DeleteForExpiredMessages(
+	ctx context.Context, tx db.Transaction, until time.Time,
+) error {
+	_, err := tx.Exec(ctx, deletePinForExpiredMessagesSqlTemplate, until)
+	return err
+}
+
+const updatePinsOwnerSqlTemplate = `
+WITH new_user AS (
+	SELECT id FROM chat_user WHERE name = $2
+)
+UPDATE pinned_message SET
+	pinned_by = new_user.id
+FROM
+	new_user
+WHERE
+	pinned_by = $1`
+
+func (r *pinRepositoryImpl) UpdatePinsOwner(
+	ctx context.Context, tx db.Transaction, oldUser uuid.UUID, newUser string,
+) error {
+	_, err := tx.Exec(ctx, updatePinsOwnerSqlTemplate, oldUser, newUser)
+	return err
+}
@@ -0,0 +1,283 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	eassert "github.com/Knoblauchpilze/easy-assert/assert"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_PinRepository_Create(t *testing.T) {
+	repo, conn, tx := newTestPinRepositoryAndTransaction(t)
+	defer conn.Close(context.Background())
+	beforeInsertion := time.Now()
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+
+	pin := persistence.Pin{
+		Room:     room.Id,
+		Message:  msg.Id,
+		PinnedBy: user.Id,
+	}
+
+	actual, err := repo.Create(context.Background(), tx, pin)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.True(t, eassert.EqualsIgnoringFields(actual, pin, "CreatedAt"))
+	assert.True(t, actual.CreatedAt.After(beforeInsertion))
+	assertMessagePinned(t, conn, msg.Id)
+}
+
+func TestIT_PinRepository_Create_WhenAlreadyPinned_ExpectFailure(t *testing.T) {
+	repo, conn, tx := newTestPinRepositoryAndTransaction(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+	pin := insertTestPin(t, conn, user.Id, room.Id, msg.Id)
+
+	_, err := repo.Create(context.Background(), tx, pin)
+	tx.Close(context.Background())
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrMessageAlreadyPinned),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_PinRepository_ListForRoom_ReturnsPinsInPinOrder(t *testing.T) {
+	repo, conn := newTestPinRepository(t)
+	defer conn.Close(context.Background())
+	room1 := insertTestRoom(t, conn)
+	room2 := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room1.Id)
+	registerUserInRoom(t, conn, user.Id, room2.Id)
+	msg1 := insertTestMessage(t, conn, user.Id, room1.Id)
+	msg2 := insertTestMessage(t, conn, user.Id, room1.Id)
+	msg3 := insertTestMessage(t, conn, user.Id, room2.Id)
+	pin2 := insertTestPin(t, conn, user.Id, room1.Id, msg2.Id)
+	pin1 := insertTestPin(t, conn, user.Id, room1.Id, msg1.Id)
+	insertTestPin(t, conn, user.Id, room2.Id, msg3.Id)
+
+	actual, err := repo.ListForRoom(context.Background(), room1.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	expected := []persistence.PinnedMessage{
+		{Pin: pin2, Message: msg2},
+		{Pin: pin1, Message: msg1},
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestIT_PinRepository_ListForRoom_WhenMessageExpired_ExpectSkipped(t *testing.T) {
+	repo, conn := newTestPinRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+	expired := insertTestMessage(t, conn, user.Id, room.Id)
+	pin := insertTestPin(t, conn, user.Id, room.Id, msg.Id)
+	insertTestPin(t, conn, user.Id, room.Id, expired.Id)
+	_, err := conn.Exec(
+		context.Background(),
+		"UPDATE message SET expires_at = $2 WHERE id = $1",
+		expired.Id,
+		time.Now().Add(-time.Minute),
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual, err := repo.ListForRoom(context.Background(), room.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	expected := []persistence.PinnedMessage{{Pin: pin, Message: msg}}
+	assert.Equal(t, expected, actual)
+}
+
+func TestIT_PinRepository_CountForRoom(t *testing.T) {
+	repo, conn, tx := newTestPinRepositoryAndTransaction(t)
+	defer conn.Close(context.Background())
+	room1 := insertTestRoom(t, conn)
+	room2 := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room1.Id)
+	registerUserInRoom(t, conn, user.Id, room2.Id)
+	msg1 := insertTestMessage(t, conn, user.Id, room1.Id)
+	msg2 := insertTestMessage(t, conn, user.Id, room1.Id)
+	msg3 := insertTestMessage(t, conn, user.Id, room2.Id)
+	insertTestPin(t, conn, user.Id, room1.Id, msg1.Id)
+	insertTestPin(t, conn, user.Id, room1.Id, msg2.Id)
+	insertTestPin(t, conn, user.Id, room2.Id, msg3.Id)
+
+	actual, err := repo.CountForRoom(context.Background(), tx, room1.Id)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, 2, actual)
+}
+
+func TestIT_PinRepository_Delete(t *testing.T) {
+	repo, conn := newTestPinRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+	insertTestPin(t, conn, user.Id, room.Id, msg.Id)
+
+	err := repo.Delete(context.Background(), room.Id, msg.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assertMessageNotPinned(t, conn, msg.Id)
+}
+
+func TestIT_PinRepository_Delete_WhenNotPinned_ExpectFailure(t *testing.T) {
+	repo, conn := newTestPinRepository(t)
+	defer conn.Close(context.Background())
+
+	err := repo.Delete(context.Background(), uuid.New(), uuid.New())
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_PinRepository_DeleteForRoom(t *testing.T) {
+	repo, conn := newTestPinRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+	insertTestPin(t, conn, user.Id, room.Id, msg.Id)
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	err = repo.DeleteForRoom(context.Background(), tx, room.Id)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assertMessageNotPinned(t, conn, msg.Id)
+}
+
+func TestIT_PinRepository_DeleteForExpiredMessages(t *testing.T) {
+	repo, conn := newTestPinRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+	expired := insertTestExpiredMessage(t, conn, user.Id, room.Id)
+	insertTestPin(t, conn, user.Id, room.Id, msg.Id)
+	insertTestPin(t, conn, user.Id, room.Id, expired.Id)
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	err = repo.DeleteForExpiredMessages(context.Background(), tx, time.Now())
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assertMessagePinned(t, conn, msg.Id)
+	assertMessageNotPinned(t, conn, expired.Id)
+}
+
+func TestIT_PinRepository_UpdatePinsOwner(t *testing.T) {
+	repo, conn := newTestPinRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	userOld := insertTestUser(t, conn)
+	userNew := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, userOld.Id, room.Id)
+	msg := insertTestMessage(t, conn, userOld.Id, room.Id)
+	insertTestPin(t, conn, userOld.Id, room.Id, msg.Id)
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	err = repo.UpdatePinsOwner(context.Background(), tx, userOld.Id, userNew.Name)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual, err := repo.ListForRoom(context.Background(), room.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, 1, len(actual))
+	assert.Equal(t, userNew.Id, actual[0].Pin.PinnedBy)
+}
+
+func newTestPinRepository(t *testing.T) (PinRepository, db.Connection) {
+	conn := newTestConnection(t)
+	return NewPinRepository(conn), conn
+}
+
+func assertMessagePinned(t *testing.T, conn db.Connection, msg uuid.UUID) {
+	value, err := db.QueryOne[int](
+		context.Background(),
+		conn,
+		"SELECT COUNT(*) FROM pinned_message WHERE message = $1",
+		msg,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, 1, value)
+}
+
+func assertMessageNotPinned(t *testing.T, conn db.Connection, msg uuid.UUID) {
+	value, err := db.QueryOne[int](
+		context.Background(),
+		conn,
+		"SELECT COUNT(*) FROM pinned_message WHERE message = $1",
+		msg,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Zero(t, value)
+}
+
+func newTestPinRepositoryAndTransaction(t *testing.T) (PinRepository, db.Connection, db.Transaction) {
+	conn := newTestConnection(t)
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	return NewPinRepository(conn), conn, tx
+}
+
+func insertTestPin(
+	t *testing.T,
+	conn db.Connection,
+	user uuid.UUID,
+	room uuid.UUID,
+	msg uuid.UUID,
+) persistence.Pin {
+	pin := persistence.Pin{
+		Room:     room,
+		Message:  msg,
+		PinnedBy: user,
+	}
+
+	createdAt, err := db.QueryOne[time.Time](
+		context.Background(),
+		conn,
+		`INSERT INTO
+			pinned_message (room, message, pinned_by)
+			VALUES ($1, $2, $3)
+			RETURNING created_at`,
+		pin.Room,
+		pin.Message,
+		pin.PinnedBy,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	pin.CreatedAt = createdAt.UTC()
+
+	return pin
+}
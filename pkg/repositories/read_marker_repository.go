@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type ReadMarkerRepository interface {
+	// Upsert only moves the marker forward: a marker for a message older
+	// than the current one is ignored.
+	Upsert(ctx context.Context, marker persistence.ReadMarker) error
+	Get(ctx context.Context, user uuid.UUID, room uuid.UUID) (persistence.ReadMarker, error)
+}
+
+type readMarkerRepositoryImpl struct {
+	conn db.Connection
+}
+
+func NewReadMarkerRepository(conn db.Connection) ReadMarkerRepository {
+	return &readMarkerRepositoryImpl{
+		conn: conn,
+	}
+}
+
+const upsertReadMarkerSqlTemplate = `
+INSERT INTO read_marker (room, chat_user, message, sequence)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (room, chat_user) DO UPDATE SET
+		message = EXCLUDED.message,
+		sequence = EXCLUDED.sequence,
+		updated_at = CURRENT_TIMESTAMP
+	WHERE
+		read_marker.sequence < EXCLUDED.sequence`
+
+func (r *readMarkerRepositoryImpl) Upsert(
+	ctx context.Context, marker persistence.ReadMarker,
+) error {
+	_, err := r.conn.Exec(
+		ctx,
+		upsertReadMarkerSqlTemplate,
+		marker.Room,
+		marker.ChatUser,
+		marker.Message,
+		marker.Sequence,
+	)
+	return err
+}
+
+const getReadMarkerSqlTemplate = `
+SELECT
+	room,
+	chat_user,
+	message,
+	sequence,
+	updated_at
+FROM
+	read_marker
+WHERE
+	chat_user = $1
+	AND room = $2`
+
+func (r *readMarkerRepositoryImpl) Get(
+	ctx context.Context, user uuid.UUID, room uuid.UUID,
+) (persistence.ReadMarker, error) {
+	marker, err := db.QueryOne[persistence.ReadMarker](
+		ctx, r.conn, getReadMarkerSqlTemplate, user, room,
+	)
+
+	if err == nil {
+		marker.UpdatedAt = marker.UpdatedAt.UTC()
+	}
+
+	return marker, err
+}
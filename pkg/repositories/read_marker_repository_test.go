@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_ReadMarkerRepository_Upsert(t *testing.T) {
+	repo, conn := newTestReadMarkerRepository(t)
+	defer conn.Close(context.Background())
+	beforeInsertion := time.Now()
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg := insertTestMessage(t, conn, user.Id, room.Id)
+
+	marker := persistence.ReadMarker{
+		Room:     room.Id,
+		ChatUser: user.Id,
+		Message:  msg.Id,
+		Sequence: msg.Sequence,
+	}
+
+	err := repo.Upsert(context.Background(), marker)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual, err := repo.Get(context.Background(), user.Id, room.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, msg.Id, actual.Message)
+	assert.Equal(t, msg.Sequence, actual.Sequence)
+	assert.True(t, actual.UpdatedAt.After(beforeInsertion))
+}
+
+func TestIT_ReadMarkerRepository_Upsert_WhenMessageIsNewer_ExpectMarkerMoved(t *testing.T) {
+	repo, conn := newTestReadMarkerRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg1 := insertTestMessage(t, conn, user.Id, room.Id)
+	msg2 := insertTestMessage(t, conn, user.Id, room.Id)
+	insertTestReadMarker(t, conn, user.Id, msg1)
+
+	marker := persistence.ReadMarker{
+		Room:     room.Id,
+		ChatUser: user.Id,
+		Message:  msg2.Id,
+		Sequence: msg2.Sequence,
+	}
+
+	err := repo.Upsert(context.Background(), marker)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual, err := repo.Get(context.Background(), user.Id, room.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, msg2.Id, actual.Message)
+	assert.Equal(t, msg2.Sequence, actual.Sequence)
+}
+
+func TestIT_ReadMarkerRepository_Upsert_WhenMessageIsOlder_ExpectMarkerKept(t *testing.T) {
+	repo, conn := newTestReadMarkerRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	msg1 := insertTestMessage(t, conn, user.Id, room.Id)
+	msg2 := insertTestMessage(t, conn, user.Id, room.Id)
+	insertTestReadMarker(t, conn, user.Id, msg2)
+
+	marker := persistence.ReadMarker{
+		Room:     room.Id,
+		ChatUser: user.Id,
+		Message:  msg1.Id,
+		Sequence: msg1.Sequence,
+	}
+
+	err := repo.Upsert(context.Background(), marker)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual, err := repo.Get(context.Background(), user.Id, room.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, msg2.Id, actual.Message)
+}
+
+func TestIT_ReadMarkerRepository_Get_WhenNoMarker_ExpectNoMatchingRows(t *testing.T) {
+	repo, conn := newTestReadMarkerRepository(t)
+	defer conn.Close(context.Background())
+
+	_, err := repo.Get(context.Background(), uuid.New(), uuid.New())
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func newTestReadMarkerRepository(t *testing.T) (ReadMarkerRepository, db.Connection) {
+	conn := newTestConnection(t)
+	return NewReadMarkerRepository(conn), conn
+}
+
+func insertTestReadMarker(
+	t *testing.T, conn db.Connection, user uuid.UUID, msg persistence.Message,
+) {
+	_, err := conn.Exec(
+		context.Background(),
+		`INSERT INTO
+			read_marker (room, chat_user, message, sequence)
+			VALUES ($1, $2, $3, $4)`,
+		msg.Room,
+		user,
+		msg.Id,
+		msg.Sequence,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+}
@@ -14,6 +14,8 @@ type RegistrationRepository interface {
 	RegisterByNameInRoom(ctx context.Context, tx db.Transaction, user string, room uuid.UUID) error
 	DeleteForRoom(ctx context.Context, tx db.Transaction, room uuid.UUID) error
 	DeleteFromRoom(ctx context.Context, tx db.Transaction, room uuid.UUID, user uuid.UUID) error
+	SetModerator(ctx context.Context, tx db.Transaction, room uuid.UUID, user uuid.UUID, moderator bool) error
+	ListModerators(ctx context.Context, tx db.Transaction, room uuid.UUID) ([]uuid.UUID, error)
 }
 
 type registrationRepositoryImpl struct{}
@@ -104,3 +106,36 @@ func (r *registrationRepositoryImpl) DeleteFromRoom(
 	_, err := tx.Exec(ctx, deleteFromRoomSqlTemplate, room, user)
 	return err
 }
+
+const setModeratorSqlTemplate = `
+UPDATE room_user SET
+	moderator = $3
+WHERE
+	room = $1
+	AND chat_user = $2`
+
+func (r *registrationRepositoryImpl) SetModerator(
+	ctx context.Context, tx db.Transaction, room uuid.UUID, user uuid.UUID, moderator bool,
+) error {
+	updated, err := tx.Exec(ctx, setModeratorSqlTemplate, room, user, moderator)
+
+	if err == nil && updated == 0 {
+		return errors.NewCode(ErrUserNotRegisteredInRoom)
+	}
+	return err
+}
+
+const listModeratorsSqlTemplate = `
+SELECT
+	chat_user
+FROM
+	room_user
+WHERE
+	room = $1
+	AND moderator = true`
+
+func (r *registrationRepositoryImpl) ListModerators(
+	ctx context.Context, tx db.Transaction, room uuid.UUID,
+) ([]uuid.UUID, error) {
+	return db.QueryAllTx[uuid.UUID](ctx, tx, listModeratorsSqlTemplate, room)
+}
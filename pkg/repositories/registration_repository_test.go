@@ -284,6 +284,61 @@ func TestIT_RegistrationRepository_DeleteFromRoom_DoesNotDeleteOtherUsers(t *tes
 	assertUserRegisteredInRoom(t, conn, user2.Id, room.Id)
 }
 
+func TestIT_RegistrationRepository_SetModerator(t *testing.T) {
+	repo, conn, tx := newTestRegistrationRepositoryAndTransaction(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+
+	err := repo.SetModerator(context.Background(), tx, room.Id, user.Id, true)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	value, err := db.QueryOne[bool](
+		context.Background(),
+		conn,
+		"SELECT moderator FROM room_user WHERE chat_user = $1 AND room = $2",
+		user.Id,
+		room.Id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.True(t, value)
+}
+
+func TestIT_RegistrationRepository_SetModerator_WhenUserNotRegistered_ExpectFailure(t *testing.T) {
+	repo, conn, tx := newTestRegistrationRepositoryAndTransaction(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+
+	err := repo.SetModerator(context.Background(), tx, room.Id, user.Id, true)
+	tx.Close(context.Background())
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrUserNotRegisteredInRoom),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_RegistrationRepository_ListModerators(t *testing.T) {
+	repo, conn, tx := newTestRegistrationRepositoryAndTransaction(t)
+	defer conn.Close(context.Background())
+	moderator := insertTestUser(t, conn)
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, moderator.Id, room.Id)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	setUserAsModerator(t, conn, moderator.Id, room.Id)
+
+	actual, err := repo.ListModerators(context.Background(), tx, room.Id)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, []uuid.UUID{moderator.Id}, actual)
+}
+
 func newTestRegistrationRepositoryAndTransaction(t *testing.T) (RegistrationRepository, db.Connection, db.Transaction) {
 	conn := newTestConnection(t)
 	tx, err := conn.BeginTx(context.Background())
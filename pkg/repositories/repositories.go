@@ -3,17 +3,35 @@ package repositories
 import "github.com/Knoblauchpilze/backend-toolkit/pkg/db"
 
 type Repositories struct {
-	Message      MessageRepository
-	Registration RegistrationRepository
-	Room         RoomRepository
-	User         UserRepository
+	Attachment       AttachmentRepository
+	DeadLetter       DeadLetterRepository
+	IdempotencyKey   IdempotencyKeyRepository
+	Message          MessageRepository
+	MessageStatus    MessageStatusRepository
+	Outbox           OutboxRepository
+	Pin              PinRepository
+	ReadMarker       ReadMarkerRepository
+	Registration     RegistrationRepository
+	Room             RoomRepository
+	ScheduledMessage ScheduledMessageRepository
+	Snapshot         SnapshotRepository
+	User             UserRepository
 }
 
 func New(conn db.Connection) Repositories {
 	return Repositories{
-		Message:      NewMessageRepository(conn),
-		Registration: NewRegistrationRepository(),
-		Room:         NewRoomRepository(conn),
-		User:         NewUserRepository(conn),
+		Attachment:       NewAttachmentRepository(conn),
+		DeadLetter:       NewDeadLetterRepository(conn),
+		IdempotencyKey:   NewIdempotencyKeyRepository(conn),
+		Message:          NewMessageRepository(conn),
+		MessageStatus:    NewMessageStatusRepository(conn),
+		Outbox:           NewOutboxRepository(conn),
+		Pin:              NewPinRepository(conn),
+		ReadMarker:       NewReadMarkerRepository(conn),
+		Registration:     NewRegistrationRepository(),
+		Room:             NewRoomRepository(conn),
+		ScheduledMessage: NewScheduledMessageRepository(conn),
+		Snapshot:         NewSnapshotRepository(),
+		User:             NewUserRepository(conn),
 	}
 }
@@ -13,10 +13,16 @@ import (
 type RoomRepository interface {
 	Create(ctx context.Context, tx db.Transaction, room persistence.Room) (persistence.Room, error)
 	Get(ctx context.Context, id uuid.UUID) (persistence.Room, error)
+	GetByName(ctx context.Context, name string) (persistence.Room, error)
 	List(ctx context.Context) ([]persistence.Room, error)
 	UserInRoom(ctx context.Context, user uuid.UUID, room uuid.UUID) (bool, error)
+	UserIsModerator(ctx context.Context, user uuid.UUID, room uuid.UUID) (bool, error)
 	ListForUser(ctx context.Context, user uuid.UUID) ([]persistence.Room, error)
+	ListMemberships(ctx context.Context, users []uuid.UUID) ([]persistence.Membership, error)
+	CountMembers(ctx context.Context) ([]persistence.MemberCount, error)
+	UpdateMessageTtl(ctx context.Context, id uuid.UUID, ttl *int) (persistence.Room, error)
 	Delete(ctx context.Context, tx db.Transaction, id uuid.UUID) error
+	Lock(ctx context.Context, tx db.Transaction, id uuid.UUID) error
 }
 
 type roomRepositoryImpl struct {
@@ -30,8 +36,8 @@ func NewRoomRepository(conn db.Connection) RoomRepository {
 }
 
 const createRoomSqlTemplate = `
-INSERT INTO room (id, name)
-	VALUES ($1, $2)
+INSERT INTO room (id, name, message_ttl)
+	VALUES ($1, $2, $3)
 	RETURNING created_at, updated_at`
 
 func (r *roomRepositoryImpl) Create(
@@ -43,6 +49,7 @@ func (r *roomRepositoryImpl) Create(
 		createRoomSqlTemplate,
 		room.Id,
 		room.Name,
+		room.MessageTtl,
 	)
 
 	// https://www.reddit.com/r/golang/comments/1gbvowf/dealing_with_timezone_issues_when_running_unit/
@@ -56,6 +63,7 @@ const getRoomSqlTemplate = `
 SELECT
 	id,
 	name,
+	message_ttl,
 	created_at,
 	updated_at
 FROM
@@ -76,10 +84,38 @@ func (r *roomRepositoryImpl) Get(
 	return room, err
 }
 
+const getRoomByNameSqlTemplate = `
+SELECT
+	id,
+	name,
+	message_ttl,
+	created_at,
+	updated_at
+FROM
+	room
+WHERE
+	name = $1`
+
+func (r *roomRepositoryImpl) GetByName(
+	ctx context.Context, name string,
+) (persistence.Room, error) {
+	room, err := db.QueryOne[persistence.Room](
+		ctx, r.conn, getRoomByNameSqlTemplate, name,
+	)
+
+	if err == nil {
+		room.CreatedAt = room.CreatedAt.UTC()
+		room.UpdatedAt = room.UpdatedAt.UTC()
+	}
+
+	return room, err
+}
+
 const listRoomSqlTemplate = `
 SELECT
 	id,
 	name,
+	message_ttl,
 	created_at,
 	updated_at
 FROM
@@ -115,10 +151,28 @@ func (r *roomRepositoryImpl) UserInRoom(
 	return count > 0, err
 }
 
+const userIsModeratorSqlTemplate = `
+SELECT
+	COUNT(*)
+FROM
+	room_user
+WHERE
+	chat_user = $1
+	AND room = $2
+	AND moderator = true`
+
+func (r *roomRepositoryImpl) UserIsModerator(
+	ctx context.Context, user uuid.UUID, room uuid.UUID,
+) (bool, error) {
+	count, err := db.QueryOne[int](ctx, r.conn, userIsModeratorSqlTemplate, user, room)
+	return count > 0, err
+}
+
 const listForUserSqlTemplate = `
 SELECT
 	r.id,
 	r.name,
+	r.message_ttl,
 	r.created_at,
 	r.updated_at
 FROM
@@ -142,6 +196,63 @@ func (r *roomRepositoryImpl) ListForUser(
 	return rooms, err
 }
 
+const listMembershipsSqlTemplate = `
+SELECT
+	room,
+	chat_user
+FROM
+	room_user
+WHERE
+	chat_user = ANY($1)`
+
+func (r *roomRepositoryImpl) ListMemberships(
+	ctx context.Context, users []uuid.UUID,
+) ([]persistence.Membership, error) {
+	return db.QueryAll[persistence.Membership](ctx, r.conn, listMembershipsSqlTemplate, users)
+}
+
+const countMembersSqlTemplate = `
+SELECT
+	room,
+	COUNT(*) AS count
+FROM
+	room_user
+GROUP BY
+	room`
+
+func (r *roomRepositoryImpl) CountMembers(
+	ctx context.Context,
+) ([]persistence.MemberCount, error) {
+	return db.QueryAll[persistence.MemberCount](ctx, r.conn, countMembersSqlTemplate)
+}
+
+const updateRoomMessageTtlSqlTemplate = `
+UPDATE room SET
+	message_ttl = $2
+WHERE
+	id = $1
+RETURNING
+	id,
+	name,
+	message_ttl,
+	created_at,
+	updated_at`
+
+func (r *roomRepositoryImpl) UpdateMessageTtl(
+	ctx context.Context, id uuid.UUID, ttl *int,
+) (persistence.Room, error) {
+	room, err := db.QueryOne[persistence.Room](
+		ctx, r.conn, updateRoomMessageTtlSqlTemplate, id, ttl,
+	)
+
+	if err == nil {
+		room.CreatedAt = room.CreatedAt.UTC()
+		room.UpdatedAt = room.UpdatedAt.UTC()
+	}
+
+	return room, err
+}
+
 const noSuchUserForeignKey = "room_user_chat_user_fkey"
 const noSuchRoomForeignKey = "room_user_room_fkey"
 
@@ -178,3 +289,19 @@ func (r *roomRepositoryImpl) Delete(
 	_, err := tx.Exec(ctx, deleteRoomSqlTemplate, id)
 	return err
 }
+
+const lockRoomSqlTemplate = `
+SELECT
+	id
+FROM
+	room
+WHERE
+	id = $1
+FOR UPDATE`
+
+func (r *roomRepositoryImpl) Lock(
+	ctx context.Context, tx db.Transaction, id uuid.UUID,
+) error {
+	_, err := db.QueryOneTx[uuid.UUID](ctx, tx, lockRoomSqlTemplate, id)
+	return err
+}
@@ -35,6 +35,26 @@ func TestIT_RoomRepository_Create(t *testing.T) {
 	assertRoomExists(t, conn, room.Id)
 }
 
+func TestIT_RoomRepository_Create_WithMessageTtl(t *testing.T) {
+	repo, conn, tx := newTestRoomRepositoryAndTransaction(t)
+	defer conn.Close(context.Background())
+
+	ttl := 3600
+	room := persistence.Room{
+		Id:         uuid.New(),
+		Name:       "my-room-" + uuid.New().String(),
+		MessageTtl: &ttl,
+	}
+
+	_, err := repo.Create(context.Background(), tx, room)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual, err := repo.Get(context.Background(), room.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, &ttl, actual.MessageTtl)
+}
+
 func TestIT_RoomRepository_Create_WhenDuplicateName_ExpectFailure(t *testing.T) {
 	repo, conn, tx := newTestRoomRepositoryAndTransaction(t)
 	defer conn.Close(context.Background())
@@ -83,6 +103,33 @@ func TestIT_RoomRepository_Get_WhenNotFound_ExpectFailure(t *testing.T) {
 	)
 }
 
+func TestIT_RoomRepository_GetByName(t *testing.T) {
+	repo, conn := newTestRoomRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	insertTestRoom(t, conn)
+
+	actual, err := repo.GetByName(context.Background(), room.Name)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, room, actual)
+}
+
+func TestIT_RoomRepository_GetByName_WhenNotFound_ExpectFailure(t *testing.T) {
+	repo, conn := newTestRoomRepository(t)
+	defer conn.Close(context.Background())
+
+	// Non-existent name
+	name := "my-non-existent-room"
+	_, err := repo.GetByName(context.Background(), name)
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
 func TestIT_RoomRepository_List(t *testing.T) {
 	repo, conn := newTestRoomRepository(t)
 	defer conn.Close(context.Background())
@@ -94,6 +141,25 @@ func TestIT_RoomRepository_List(t *testing.T) {
 	assert.Contains(t, rooms, room)
 }
 
+func TestIT_RoomRepository_CountMembers(t *testing.T) {
+	repo, conn := newTestRoomRepository(t)
+	defer conn.Close(context.Background())
+	user1 := insertTestUser(t, conn)
+	user2 := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user1.Id, room.Id)
+	registerUserInRoom(t, conn, user2.Id, room.Id)
+
+	counts, err := repo.CountMembers(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	expected := persistence.MemberCount{
+		Room:  room.Id,
+		Count: 2,
+	}
+	assert.Contains(t, counts, expected)
+}
+
 func TestIT_RoomRepository_UserInRoom(t *testing.T) {
 	repo, conn := newTestRoomRepository(t)
 	defer conn.Close(context.Background())
@@ -117,6 +183,31 @@ func TestIT_RoomRepository_UserInRoom_WhenNotRegistered_ExpectFalse(t *testing.T
 	assert.False(t, actual)
 }
 
+func TestIT_RoomRepository_UserIsModerator(t *testing.T) {
+	repo, conn := newTestRoomRepository(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+	setUserAsModerator(t, conn, user.Id, room.Id)
+
+	actual, err := repo.UserIsModerator(context.Background(), user.Id, room.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.True(t, actual)
+}
+
+func TestIT_RoomRepository_UserIsModerator_WhenRegularMember_ExpectFalse(t *testing.T) {
+	repo, conn := newTestRoomRepository(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+	room := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+
+	actual, err := repo.UserIsModerator(context.Background(), user.Id, room.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.False(t, actual)
+}
+
 func TestIT_RoomRepository_ListForUser(t *testing.T) {
 	repo, conn := newTestRoomRepository(t)
 	defer conn.Close(context.Background())
@@ -143,6 +234,70 @@ func TestIT_RoomRepository_ListForUser_WhenNoRoomRegistered_ReturnsEmptySlice(t
 	assert.Equal(t, []persistence.Room{}, actual)
 }
 
+func TestIT_RoomRepository_ListMemberships(t *testing.T) {
+	repo, conn := newTestRoomRepository(t)
+	defer conn.Close(context.Background())
+	user1 := insertTestUser(t, conn)
+	user2 := insertTestUser(t, conn)
+	user3 := insertTestUser(t, conn)
+	room1 := insertTestRoom(t, conn)
+	room2 := insertTestRoom(t, conn)
+	registerUserInRoom(t, conn, user1.Id, room1.Id)
+	registerUserInRoom(t, conn, user1.Id, room2.Id)
+	registerUserInRoom(t, conn, user2.Id, room2.Id)
+	registerUserInRoom(t, conn, user3.Id, room1.Id)
+
+	users := []uuid.UUID{user1.Id, user2.Id}
+	actual, err := repo.ListMemberships(context.Background(), users)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	expected := []persistence.Membership{
+		{Room: room1.Id, ChatUser: user1.Id},
+		{Room: room2.Id, ChatUser: user1.Id},
+		{Room: room2.Id, ChatUser: user2.Id},
+	}
+	assert.ElementsMatch(t, expected, actual)
+}
+
+func TestIT_RoomRepository_UpdateMessageTtl(t *testing.T) {
+	repo, conn := newTestRoomRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+
+	ttl := 60
+	actual, err := repo.UpdateMessageTtl(context.Background(), room.Id, &ttl)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.True(t, eassert.EqualsIgnoringFields(actual, room, "MessageTtl", "UpdatedAt"))
+	assert.Equal(t, &ttl, actual.MessageTtl)
+	assert.True(t, actual.UpdatedAt.After(room.UpdatedAt))
+}
+
+func TestIT_RoomRepository_UpdateMessageTtl_WhenCleared_ExpectNoTtl(t *testing.T) {
+	repo, conn := newTestRoomRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoomWithTtl(t, conn, 60)
+
+	actual, err := repo.UpdateMessageTtl(context.Background(), room.Id, nil)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Nil(t, actual.MessageTtl)
+}
+
+func TestIT_RoomRepository_UpdateMessageTtl_WhenNotFound_ExpectFailure(t *testing.T) {
+	repo, conn := newTestRoomRepository(t)
+	defer conn.Close(context.Background())
+
+	ttl := 60
+	_, err := repo.UpdateMessageTtl(context.Background(), uuid.New(), &ttl)
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
 func TestIT_RoomRepository_Delete(t *testing.T) {
 	repo, conn, tx := newTestRoomRepositoryAndTransaction(t)
 	defer conn.Close(context.Background())
@@ -171,6 +326,33 @@ func TestIT_RoomRepository_Delete_WhenNotFound_ExpectSuccess(t *testing.T) {
 	assertRoomExists(t, conn, room.Id)
 }
 
+func TestIT_RoomRepository_Lock(t *testing.T) {
+	repo, conn, tx := newTestRoomRepositoryAndTransaction(t)
+	defer conn.Close(context.Background())
+
+	room := insertTestRoom(t, conn)
+
+	err := repo.Lock(context.Background(), tx, room.Id)
+	tx.Close(context.Background())
+
+	assert.Nil(t, err, "Actual err: %v", err)
+}
+
+func TestIT_RoomRepository_Lock_WhenNotFound_ExpectFailure(t *testing.T) {
+	repo, conn, tx := newTestRoomRepositoryAndTransaction(t)
+	defer conn.Close(context.Background())
+
+	err := repo.Lock(context.Background(), tx, uuid.New())
+	tx.Close(context.Background())
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
 func newTestRoomRepository(t *testing.T) (RoomRepository, db.Connection) {
 	conn := newTestConnection(t)
 	return NewRoomRepository(conn), conn
@@ -228,3 +410,29 @@ func insertTestRoom(t *testing.T, conn db.Connection) persistence.Room {
 
 	return room
 }
+
+func insertTestRoomWithTtl(t *testing.T, conn db.Connection, ttl int) persistence.Room {
+	room := persistence.Room{
+		Id:         uuid.New(),
+		Name:       "my-room-" + uuid.New().String(),
+		MessageTtl: &ttl,
+	}
+
+	times, err := db.QueryOne[createdAtUpdatedAt](
+		context.Background(),
+		conn,
+		`INSERT INTO
+			room (id, name, message_ttl)
+			VALUES ($1, $2, $3)
+			RETURNING created_at, updated_at`,
+		room.Id,
+		room.Name,
+		room.MessageTtl,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	room.CreatedAt = times.CreatedAt.UTC()
+	room.UpdatedAt = times.UpdatedAt.UTC()
+
+	return room
+}
@@ -0,0 +1,230 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type ScheduledMessageRepository interface {
+	Create(ctx context.Context, msg persistence.ScheduledMessage) (persistence.ScheduledMessage, error)
+	Get(ctx context.Context, id uuid.UUID) (persistence.ScheduledMessage, error)
+	ListForRoom(ctx context.Context, room uuid.UUID) ([]persistence.ScheduledMessage, error)
+	Update(ctx context.Context, msg persistence.ScheduledMessage) (persistence.ScheduledMessage, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// DeleteDue removes and returns the messages which should be sent
+	// before the input time. As the messages are removed, concurrent
+	// calls will never return the same message twice.
+	DeleteDue(ctx context.Context, tx db.Transaction, until time.Time) ([]persistence.ScheduledMessage, error)
+	DeleteForRoom(ctx context.Context, tx db.Transaction, room uuid.UUID) error
+	DeleteForUser(ctx context.Context, tx db.Transaction, user uuid.UUID) error
+}
+
+type scheduledMessageRepositoryImpl struct {
+	conn db.Connection
+}
+
+func NewScheduledMessageRepository(conn db.Connection) ScheduledMessageRepository {
+	return &scheduledMessageRepositoryImpl{
+		conn: conn,
+	}
+}
+
+const createScheduledMessageSqlTemplate = `
+INSERT INTO scheduled_message (id, chat_user, room, message, send_at)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING created_at, updated_at`
+
+func (r *scheduledMessageRepositoryImpl) Create(
+	ctx context.Context, msg persistence.ScheduledMessage,
+) (persistence.ScheduledMessage, error) {
+	times, err := db.QueryOne[createdAtUpdatedAt](
+		ctx,
+		r.conn,
+		createScheduledMessageSqlTemplate,
+		msg.Id,
+		msg.ChatUser,
+		msg.Room,
+		msg.Message,
+		msg.SendAt,
+	)
+
+	msg.SendAt = msg.SendAt.UTC()
+	msg.CreatedAt = times.CreatedAt.UTC()
+	msg.UpdatedAt = times.UpdatedAt.UTC()
+
+	return msg, err
+}
+
+const getScheduledMessageSqlTemplate = `
+SELECT
+	id,
+	chat_user,
+	room,
+	message,
+	send_at,
+	created_at,
+	updated_at
+FROM
+	scheduled_message
+WHERE
+	id = $1`
+
+func (r *scheduledMessageRepositoryImpl) Get(
+	ctx context.Context, id uuid.UUID,
+) (persistence.ScheduledMessage, error) {
+	msg, err := db.QueryOne[persistence.ScheduledMessage](
+		ctx, r.conn, getScheduledMessageSqlTemplate, id,
+	)
+
+	if err == nil {
+		msg = toUtcScheduledMessage(msg)
+	}
+
+	return msg, err
+}
+
+const listScheduledMessageByRoomSqlTemplate = `
+SELECT
+	id,
+	chat_user,
+	room,
+	message,
+	send_at,
+	created_at,
+	updated_at
+FROM
+	scheduled_message
+WHERE
+	room = $1
+ORDER BY
+	send_at`
+
+func (r *scheduledMessageRepositoryImpl) ListForRoom(
+	ctx context.Context, room uuid.UUID,
+) ([]persistence.ScheduledMessage, error) {
+	messages, err := db.QueryAll[persistence.ScheduledMessage](
+		ctx, r.conn, listScheduledMessageByRoomSqlTemplate, room,
+	)
+
+	if err == nil {
+		for id, msg := range messages {
+			messages[id] = toUtcScheduledMessage(msg)
+		}
+	}
+
+	return messages, err
+}
+
+const updateScheduledMessageSqlTemplate = `
+UPDATE scheduled_message SET
+	message = $2,
+	send_at = $3
+WHERE
+	id = $1
+RETURNING
+	created_at,
+	updated_at`
+
+func (r *scheduledMessageRepositoryImpl) Update(
+	ctx context.Context, msg persistence.ScheduledMessage,
+) (persistence.ScheduledMessage, error) {
+	times, err := db.QueryOne[createdAtUpdatedAt](
+		ctx,
+		r.conn,
+		updateScheduledMessageSqlTemplate,
+		msg.Id,
+		msg.Message,
+		msg.SendAt,
+	)
+
+	msg.SendAt = msg.SendAt.UTC()
+	msg.CreatedAt = times.CreatedAt.UTC()
+	msg.UpdatedAt = times.UpdatedAt.UTC()
+
+	return msg, err
+}
+
+const deleteScheduledMessageSqlTemplate = `
+DELETE FROM
+	scheduled_message
+WHERE
+	id = $1`
+
+func (r *scheduledMessageRepositoryImpl) Delete(
+	ctx context.Context, id uuid.UUID,
+) error {
+	deleted, err := r.conn.Exec(ctx, deleteScheduledMessageSqlTemplate, id)
+
+	if err == nil && deleted == 0 {
+		return errors.NewCode(db.NoMatchingRows)
+	}
+	return err
+}
+
+const deleteDueScheduledMessageSqlTemplate = `
+DELETE FROM
+	scheduled_message
+WHERE
+	send_at <= $1
+RETURNING
+	id,
+	chat_user,
+	room,
+	message,
+	send_at,
+	created_at,
+	updated_at`
+
+func (r *scheduledMessageRepositoryImpl) DeleteDue(
+	ctx context.Context, tx db.Transaction, until time.Time,
+) ([]persistence.ScheduledMessage, error) {
+	messages, err := db.QueryAllTx[persistence.ScheduledMessage](
+		ctx, tx, deleteDueScheduledMessageSqlTemplate, until,
+	)
+
+	if err == nil {
+		for id, msg := range messages {
+			messages[id] = toUtcScheduledMessage(msg)
+		}
+	}
+
+	return messages, err
+}
+
+const deleteScheduledMessageByRoomSqlTemplate = `
+DELETE FROM
+	scheduled_message
+WHERE
+	room = $1`
+
+func (r *scheduledMessageRepositoryImpl) DeleteForRoom(
+	ctx context.Context, tx db.Transaction, room uuid.UUID,
+) error {
+	_, err := tx.Exec(ctx, deleteScheduledMessageByRoomSqlTemplate, room)
+	return err
+}
+
+const deleteScheduledMessageByUserSqlTemplate = `
+DELETE FROM
+	scheduled_message
+WHERE
+	chat_user = $1`
+
+func (r *scheduledMessageRepositoryImpl) DeleteForUser(
+	ctx context.Context, tx db.Transaction, user uuid.UUID,
+) error {
+	_, err := tx.Exec(ctx, deleteScheduledMessageByUserSqlTemplate, user)
+	return err
+}
+
+func toUtcScheduledMessage(msg persistence.ScheduledMessage) persistence.ScheduledMessage {
+	msg.SendAt = msg.SendAt.UTC()
+	msg.CreatedAt = msg.CreatedAt.UTC()
+	msg.UpdatedAt = msg.UpdatedAt.UTC()
+	return msg
+}
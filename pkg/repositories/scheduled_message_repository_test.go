@@ -0,0 +1,285 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db/pgx"
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	eassert "github.com/Knoblauchpilze/easy-assert/assert"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_ScheduledMessageRepository_Create(t *testing.T) {
+	repo, conn := newTestScheduledMessageRepository(t)
+	defer conn.Close(context.Background())
+	beforeInsertion := time.Now()
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+
+	msg := persistence.ScheduledMessage{
+		Id:       uuid.New(),
+		ChatUser: user.Id,
+		Room:     room.Id,
+		Message:  "Hello world",
+		SendAt:   time.Date(2100, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	actual, err := repo.Create(context.Background(), msg)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.True(t, eassert.EqualsIgnoringFields(actual, msg, "CreatedAt", "UpdatedAt"))
+	assert.True(t, actual.CreatedAt.After(beforeInsertion))
+	assert.Equal(t, actual.CreatedAt, actual.UpdatedAt)
+	assertScheduledMessageExists(t, conn, msg.Id)
+}
+
+func TestIT_ScheduledMessageRepository_Create_WhenRoomDoesNotExist_ExpectFailure(t *testing.T) {
+	repo, conn := newTestScheduledMessageRepository(t)
+	defer conn.Close(context.Background())
+	user := insertTestUser(t, conn)
+
+	msg := persistence.ScheduledMessage{
+		Id:       uuid.New(),
+		ChatUser: user.Id,
+		Room:     uuid.New(),
+		Message:  "Hello world",
+		SendAt:   time.Now().Add(time.Hour),
+	}
+
+	_, err := repo.Create(context.Background(), msg)
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, pgx.ForeignKeyValidation),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_ScheduledMessageRepository_Get(t *testing.T) {
+	repo, conn := newTestScheduledMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	msg := insertTestScheduledMessage(t, conn, user.Id, room.Id, time.Now().Add(time.Hour))
+
+	actual, err := repo.Get(context.Background(), msg.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, msg, actual)
+}
+
+func TestIT_ScheduledMessageRepository_Get_WhenNotFound_ExpectFailure(t *testing.T) {
+	repo, conn := newTestScheduledMessageRepository(t)
+	defer conn.Close(context.Background())
+
+	_, err := repo.Get(context.Background(), uuid.New())
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_ScheduledMessageRepository_ListForRoom_ReturnsMessagesBySendTime(t *testing.T) {
+	repo, conn := newTestScheduledMessageRepository(t)
+	defer conn.Close(context.Background())
+	room1 := insertTestRoom(t, conn)
+	room2 := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	later := insertTestScheduledMessage(t, conn, user.Id, room1.Id, time.Now().Add(2*time.Hour))
+	sooner := insertTestScheduledMessage(t, conn, user.Id, room1.Id, time.Now().Add(time.Hour))
+	insertTestScheduledMessage(t, conn, user.Id, room2.Id, time.Now().Add(time.Hour))
+
+	actual, err := repo.ListForRoom(context.Background(), room1.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	expected := []persistence.ScheduledMessage{sooner, later}
+	assert.Equal(t, expected, actual)
+}
+
+func TestIT_ScheduledMessageRepository_Update(t *testing.T) {
+	repo, conn := newTestScheduledMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	msg := insertTestScheduledMessage(t, conn, user.Id, room.Id, time.Now().Add(time.Hour))
+
+	updated := msg
+	updated.Message = "Updated message"
+	updated.SendAt = time.Date(2100, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	actual, err := repo.Update(context.Background(), updated)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.True(t, eassert.EqualsIgnoringFields(actual, updated, "UpdatedAt"))
+	assert.True(t, actual.UpdatedAt.After(msg.UpdatedAt))
+}
+
+func TestIT_ScheduledMessageRepository_Update_WhenNotFound_ExpectFailure(t *testing.T) {
+	repo, conn := newTestScheduledMessageRepository(t)
+	defer conn.Close(context.Background())
+
+	msg := persistence.ScheduledMessage{
+		Id:      uuid.New(),
+		Message: "Hello world",
+		SendAt:  time.Now().Add(time.Hour),
+	}
+
+	_, err := repo.Update(context.Background(), msg)
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_ScheduledMessageRepository_Delete(t *testing.T) {
+	repo, conn := newTestScheduledMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	msg := insertTestScheduledMessage(t, conn, user.Id, room.Id, time.Now().Add(time.Hour))
+
+	err := repo.Delete(context.Background(), msg.Id)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assertScheduledMessageDoesNotExist(t, conn, msg.Id)
+}
+
+func TestIT_ScheduledMessageRepository_Delete_WhenNotFound_ExpectFailure(t *testing.T) {
+	repo, conn := newTestScheduledMessageRepository(t)
+	defer conn.Close(context.Background())
+
+	err := repo.Delete(context.Background(), uuid.New())
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, db.NoMatchingRows),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestIT_ScheduledMessageRepository_DeleteDue(t *testing.T) {
+	repo, conn := newTestScheduledMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	now := time.Now()
+	due := insertTestScheduledMessage(t, conn, user.Id, room.Id, now.Add(-time.Minute))
+	pending := insertTestScheduledMessage(t, conn, user.Id, room.Id, now.Add(time.Hour))
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	actual, err := repo.DeleteDue(context.Background(), tx, now)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Contains(t, actual, due)
+	assert.NotContains(t, actual, pending)
+	assertScheduledMessageDoesNotExist(t, conn, due.Id)
+	assertScheduledMessageExists(t, conn, pending.Id)
+}
+
+func TestIT_ScheduledMessageRepository_DeleteForRoom(t *testing.T) {
+	repo, conn := newTestScheduledMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	msg := insertTestScheduledMessage(t, conn, user.Id, room.Id, time.Now().Add(time.Hour))
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	err = repo.DeleteForRoom(context.Background(), tx, room.Id)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assertScheduledMessageDoesNotExist(t, conn, msg.Id)
+}
+
+func TestIT_ScheduledMessageRepository_DeleteForUser(t *testing.T) {
+	repo, conn := newTestScheduledMessageRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	msg := insertTestScheduledMessage(t, conn, user.Id, room.Id, time.Now().Add(time.Hour))
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	err = repo.DeleteForUser(context.Background(), tx, user.Id)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assertScheduledMessageDoesNotExist(t, conn, msg.Id)
+}
+
+func newTestScheduledMessageRepository(
+	t *testing.T,
+) (ScheduledMessageRepository, db.Connection) {
+	conn := newTestConnection(t)
+	return NewScheduledMessageRepository(conn), conn
+}
+
+func assertScheduledMessageExists(t *testing.T, conn db.Connection, id uuid.UUID) {
+	value, err := db.QueryOne[int](
+		context.Background(),
+		conn,
+		"SELECT COUNT(*) FROM scheduled_message WHERE id = $1",
+		id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, 1, value)
+}
+
+func assertScheduledMessageDoesNotExist(t *testing.T, conn db.Connection, id uuid.UUID) {
+	value, err := db.QueryOne[int](
+		context.Background(),
+		conn,
+		"SELECT COUNT(*) FROM scheduled_message WHERE id = $1",
+		id,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Zero(t, value)
+}
+
+func insertTestScheduledMessage(
+	t *testing.T,
+	conn db.Connection,
+	user uuid.UUID,
+	room uuid.UUID,
+	sendAt time.Time,
+) persistence.ScheduledMessage {
+	msg := persistence.ScheduledMessage{
+		Id:       uuid.New(),
+		ChatUser: user,
+		Room:     room,
+		Message:  "Scheduled " + uuid.NewString(),
+		SendAt:   sendAt.UTC().Truncate(time.Microsecond),
+	}
+
+	times, err := db.QueryOne[createdAtUpdatedAt](
+		context.Background(),
+		conn,
+		`INSERT INTO
+			scheduled_message (id, chat_user, room, message, send_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING created_at, updated_at`,
+		msg.Id,
+		msg.ChatUser,
+		msg.Room,
+		msg.Message,
+		msg.SendAt,
+	)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	msg.CreatedAt = times.CreatedAt.UTC()
+	msg.UpdatedAt = times.UpdatedAt.UTC()
+
+	return msg
+}
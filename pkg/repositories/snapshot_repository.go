@@ -0,0 +1,228 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+)
+
+type SnapshotRepository interface {
+	// ListForUser returns the state of the rooms of the user. It should
+	// be called first in the transaction: it makes it see the same data
+	// for all the rooms.
+	ListForUser(ctx context.Context, tx db.Transaction, user uuid.UUID) ([]persistence.RoomSnapshot, error)
+}
+
+type snapshotRepositoryImpl struct{}
+
+func NewSnapshotRepository() SnapshotRepository {
+	return &snapshotRepositoryImpl{}
+}
+
+const repeatableReadSqlTemplate = `
+SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY`
+
+type roomState struct {
+	Id           uuid.UUID
+	Name         string
+	MessageTtl   *int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	LastSequence int64
+	UnreadCount  int64
+}
+
+const listRoomStatesForUserSqlTemplate = `
+SELECT
+	r.id,
+	r.name,
+	r.message_ttl,
+	r.created_at,
+	r.updated_at,
+	COALESCE(rs.last_sequence, 0) AS last_sequence,
+	(
+		SELECT
+			COUNT(*)
+		FROM
+			message AS m
+		WHERE
+			m.room = r.id
+			AND m.sequence > COALESCE(rm.sequence, 0)
+			AND m.chat_user <> ru.chat_user
+			AND (m.expires_at IS NULL OR m.expires_at > CURRENT_TIMESTAMP)
+	) AS unread_count
+FROM
+	room_user AS ru
+	LEFT JOIN room AS r ON ru.room = r.id
+	LEFT JOIN room_sequence AS rs ON rs.room = r.id
+	LEFT JOIN read_marker AS rm ON rm.room = r.id AND rm.chat_user = ru.chat_user
+WHERE
+	ru.chat_user = $1
+ORDER BY
+	r.name`
+
+type roomMember struct {
+	Room      uuid.UUID
+	Id        uuid.UUID
+	Name      string
+	ApiUser   uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int
+}
+
+const listRoomMembersForUserSqlTemplate = `
+SELECT
+	ru.room,
+	u.id,
+	u.name,
+	u.api_user,
+	u.created_at,
+	u.updated_at,
+	u.version
+FROM
+	room_user AS me
+	LEFT JOIN room_user AS ru ON ru.room = me.room
+	LEFT JOIN chat_user AS u ON ru.chat_user = u.id
+WHERE
+	me.chat_user = $1
+ORDER BY
+	u.name`
+
+const listLastMessagesForUserSqlTemplate = `
+SELECT DISTINCT ON (m.room)
+	m.id,
+	m.chat_user,
+	m.room,
+	m.message,
+	m.sequence,
+	m.created_at,
+	m.expires_at
+FROM
+	message AS m
+	LEFT JOIN room_user AS ru ON m.room = ru.room
+WHERE
+	ru.chat_user = $1
+	AND (m.expires_at IS NULL OR m.expires_at > CURRENT_TIMESTAMP)
+ORDER BY
+	m.room,
+	m.sequence DESC`
+
+const listAttachmentsForMessagesSqlTemplate = `
+SELECT
+	id,
+	chat_user,
+	room,
+	message,
+	name,
+	mime_type,
+	size,
+	width,
+	height,
+	thumbnail,
+	created_at
+FROM
+	attachment
+WHERE
+	message = ANY($1)
+ORDER BY
+	created_at`
+
+func (r *snapshotRepositoryImpl) ListForUser(
+	ctx context.Context, tx db.Transaction, user uuid.UUID,
+) ([]persistence.RoomSnapshot, error) {
+	if _, err := tx.Exec(ctx, repeatableReadSqlTemplate); err != nil {
+		return nil, err
+	}
+
+	states, err := db.QueryAllTx[roomState](ctx, tx, listRoomStatesForUserSqlTemplate, user)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := db.QueryAllTx[roomMember](ctx, tx, listRoomMembersForUserSqlTemplate, user)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := db.QueryAllTx[persistence.Message](ctx, tx, listLastMessagesForUserSqlTemplate, user)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.Id)
+	}
+
+	attachments, err := db.QueryAllTx[persistence.Attachment](
+		ctx, tx, listAttachmentsForMessagesSqlTemplate, ids,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRoomSnapshots(states, members, messages, attachments), nil
+}
+
+func toRoomSnapshots(
+	states []roomState,
+	members []roomMember,
+	messages []persistence.Message,
+	attachments []persistence.Attachment,
+) []persistence.RoomSnapshot {
+	membersByRoom := make(map[uuid.UUID][]persistence.User)
+	for _, member := range members {
+		user := persistence.User{
+			Id:        member.Id,
+			Name:      member.Name,
+			ApiUser:   member.ApiUser,
+			CreatedAt: member.CreatedAt.UTC(),
+			UpdatedAt: member.UpdatedAt.UTC(),
+			Version:   member.Version,
+		}
+		membersByRoom[member.Room] = append(membersByRoom[member.Room], user)
+	}
+
+	attachmentsByMessage := make(map[uuid.UUID][]persistence.Attachment)
+	for _, attachment := range attachments {
+		attachment.CreatedAt = attachment.CreatedAt.UTC()
+		message := *attachment.Message
+		attachmentsByMessage[message] = append(attachmentsByMessage[message], attachment)
+	}
+
+	lastMessageByRoom := make(map[uuid.UUID]persistence.Message)
+	for _, message := range messages {
+		message.CreatedAt = message.CreatedAt.UTC()
+		message.ExpiresAt = toUtcTime(message.ExpiresAt)
+		message.Attachments = attachmentsByMessage[message.Id]
+		lastMessageByRoom[message.Room] = message
+	}
+
+	out := make([]persistence.RoomSnapshot, 0, len(states))
+	for _, state := range states {
+		snapshot := persistence.RoomSnapshot{
+			Room: persistence.Room{
+				Id:         state.Id,
+				Name:       state.Name,
+				MessageTtl: state.MessageTtl,
+				CreatedAt:  state.CreatedAt.UTC(),
+				UpdatedAt:  state.UpdatedAt.UTC(),
+			},
+			Members:      membersByRoom[state.Id],
+			LastSequence: state.LastSequence,
+			UnreadCount:  state.UnreadCount,
+		}
+
+		if message, ok := lastMessageByRoom[state.Id]; ok {
+			snapshot.LastMessage = &message
+		}
+
+		out = append(out, snapshot)
+	}
+
+	return out
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/db"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIT_SnapshotRepository_ListForUser(t *testing.T) {
+	repo, conn := newTestSnapshotRepository(t)
+	defer conn.Close(context.Background())
+	room1 := insertTestRoom(t, conn)
+	room2 := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	other := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room1.Id)
+	registerUserInRoom(t, conn, other.Id, room1.Id)
+	registerUserInRoom(t, conn, other.Id, room2.Id)
+	msg1 := insertTestMessage(t, conn, other.Id, room1.Id)
+	insertTestMessage(t, conn, user.Id, room1.Id)
+	msg3 := insertTestMessage(t, conn, other.Id, room1.Id)
+	insertTestMessage(t, conn, other.Id, room2.Id)
+	insertTestReadMarker(t, conn, user.Id, msg1)
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	actual, err := repo.ListForUser(context.Background(), tx, user.Id)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Len(t, actual, 1)
+	assert.Equal(t, room1, actual[0].Room)
+	assert.ElementsMatch(t, []uuid.UUID{user.Id, other.Id}, userIds(actual[0].Members))
+	assert.Equal(t, &msg3, actual[0].LastMessage)
+	assert.Equal(t, msg3.Sequence, actual[0].LastSequence)
+	// The message of the user itself is not counted.
+	assert.Equal(t, int64(1), actual[0].UnreadCount)
+}
+
+func TestIT_SnapshotRepository_ListForUser_WhenRoomIsEmpty_ExpectNoLastMessage(t *testing.T) {
+	repo, conn := newTestSnapshotRepository(t)
+	defer conn.Close(context.Background())
+	room := insertTestRoom(t, conn)
+	user := insertTestUser(t, conn)
+	registerUserInRoom(t, conn, user.Id, room.Id)
+
+	tx, err := conn.BeginTx(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+	actual, err := repo.ListForUser(context.Background(), tx, user.Id)
+	tx.Close(context.Background())
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Len(t, actual, 1)
+	assert.Nil(t, actual[0].LastMessage)
+	assert.Equal(t, int64(0), actual[0].LastSequence)
+	assert.Equal(t, int64(0), actual[0].UnreadCount)
+}
+
+func newTestSnapshotRepository(t *testing.T) (SnapshotRepository, db.Connection) {
+	conn := newTestConnection(t)
+	return NewSnapshotRepository(), conn
+}
+
+func userIds(users []persistence.User) []uuid.UUID {
+	var out []uuid.UUID
+	for _, user := range users {
+		out = append(out, user.Id)
+	}
+	return out
+}
@@ -6,3 +6,12 @@ type createdAtUpdatedAt struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+func toUtcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	utc := t.UTC()
+	return &utc
+}
@@ -0,0 +1,8 @@
+package storage
+
+import "github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+
+const (
+	ErrInvalidBlobKey errors.ErrorCode = 700
+	ErrNoSuchBlob     errors.ErrorCode = 701
+)
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+)
+
+type filesystemStoreImpl struct {
+	root string
+}
+
+func NewFilesystemStore(root string) (BlobStore, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, err
+	}
+
+	return &filesystemStoreImpl{
+		root: root,
+	}, nil
+}
+
+func (s *filesystemStoreImpl) Put(
+	_ context.Context, key string, data io.Reader,
+) (int64, error) {
+	path, err := s.pathFor(key)
+	if err != nil {
+		return 0, err
+	}
+
+	// Write to a temporary file first so that a partial upload never
+	// shadows a complete blob with the same key.
+	tmp, err := os.CreateTemp(s.root, ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return written, err
+	}
+
+	return written, os.Rename(tmp.Name(), path)
+}
+
+func (s *filesystemStoreImpl) Get(
+	_ context.Context, key string,
+) (io.ReadCloser, error) {
+	path, err := s.pathFor(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, errors.WrapCode(err, ErrNoSuchBlob)
+	}
+
+	return file, err
+}
+
+func (s *filesystemStoreImpl) Delete(_ context.Context, key string) error {
+	path, err := s.pathFor(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *filesystemStoreImpl) pathFor(key string) (string, error) {
+	if key == "" || key != filepath.Base(key) || key[0] == '.' {
+		return "", errors.NewCode(ErrInvalidBlobKey)
+	}
+
+	return filepath.Join(s.root, key), nil
+}
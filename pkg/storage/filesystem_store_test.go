@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_FilesystemStore_WhenRootDoesNotExist_ExpectCreated(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "some", "nested", "folder")
+
+	_, err := NewFilesystemStore(root)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	info, err := os.Stat(root)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.True(t, info.IsDir())
+}
+
+func TestUnit_FilesystemStore_Put_ExpectDataCanBeRead(t *testing.T) {
+	store := newTestFilesystemStore(t)
+	data := []byte("some-data")
+
+	written, err := store.Put(context.Background(), "my-key", bytes.NewReader(data))
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, int64(len(data)), written)
+
+	actual := readBlob(t, store, "my-key")
+	assert.Equal(t, data, actual)
+}
+
+func TestUnit_FilesystemStore_Put_WhenKeyExists_ExpectOverwritten(t *testing.T) {
+	store := newTestFilesystemStore(t)
+
+	_, err := store.Put(context.Background(), "my-key", bytes.NewReader([]byte("old")))
+	assert.Nil(t, err, "Actual err: %v", err)
+	_, err = store.Put(context.Background(), "my-key", bytes.NewReader([]byte("new")))
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	actual := readBlob(t, store, "my-key")
+	assert.Equal(t, []byte("new"), actual)
+}
+
+func TestUnit_FilesystemStore_Put_WhenKeyIsInvalid_ExpectFailure(t *testing.T) {
+	store := newTestFilesystemStore(t)
+
+	keys := []string{"", "../escape", "nested/key", ".hidden"}
+	for _, key := range keys {
+		_, err := store.Put(context.Background(), key, bytes.NewReader([]byte("data")))
+		assert.True(
+			t,
+			errors.IsErrorWithCode(err, ErrInvalidBlobKey),
+			"Key: %s, actual err: %v",
+			key,
+			err,
+		)
+	}
+}
+
+func TestUnit_FilesystemStore_Get_WhenKeyDoesNotExist_ExpectFailure(t *testing.T) {
+	store := newTestFilesystemStore(t)
+
+	_, err := store.Get(context.Background(), "not-a-key")
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrNoSuchBlob),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestUnit_FilesystemStore_Delete(t *testing.T) {
+	store := newTestFilesystemStore(t)
+
+	_, err := store.Put(context.Background(), "my-key", bytes.NewReader([]byte("data")))
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	err = store.Delete(context.Background(), "my-key")
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	_, err = store.Get(context.Background(), "my-key")
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrNoSuchBlob),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestUnit_FilesystemStore_Delete_WhenKeyDoesNotExist_ExpectSuccess(t *testing.T) {
+	store := newTestFilesystemStore(t)
+
+	err := store.Delete(context.Background(), "not-a-key")
+	assert.Nil(t, err, "Actual err: %v", err)
+}
+
+func newTestFilesystemStore(t *testing.T) BlobStore {
+	store, err := NewFilesystemStore(t.TempDir())
+	assert.Nil(t, err, "Actual err: %v", err)
+	return store
+}
+
+func readBlob(t *testing.T, store BlobStore, key string) []byte {
+	reader, err := store.Get(context.Background(), key)
+	assert.Nil(t, err, "Actual err: %v", err)
+	defer reader.Close()
+
+	out, err := io.ReadAll(reader)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return out
+}
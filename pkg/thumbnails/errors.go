@@ -0,0 +1,9 @@
+package thumbnails
+
+import "github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+
+const (
+	ErrUnsupportedImage errors.ErrorCode = 800
+	ErrInvalidDimension errors.ErrorCode = 801
+	ErrImageTooLarge    errors.ErrorCode = 802
+)
@@ -0,0 +1,88 @@
+package thumbnails
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/messages"
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/Knoblauchpilze/chat-server/pkg/storage"
+)
+
+const thumbnailKeySuffix = "-thumbnail"
+
+type Generator messages.Processor[persistence.Attachment]
+
+func NewGenerator(
+	queueSize int,
+	maxDimension int,
+	maxPixels int,
+	store storage.BlobStore,
+	repos repositories.Repositories,
+) Generator {
+	callbacks := messages.Callbacks[persistence.Attachment]{
+		Message: generateThumbnailCallback(maxDimension, maxPixels, store, repos),
+	}
+
+	return messages.NewProcessor(queueSize, callbacks)
+}
+
+func Key(attachment persistence.Attachment) string {
+	return attachment.Id.String() + thumbnailKeySuffix
+}
+
+func generateThumbnailCallback(
+	maxDimension int,
+	maxPixels int,
+	store storage.BlobStore,
+	repos repositories.Repositories,
+) messages.MessageCallback[persistence.Attachment] {
+	return func(attachment persistence.Attachment) error {
+		// Failing to generate a thumbnail only means that clients will have
+		// to download the full attachment: we don't want to stop processing
+		// the next ones because of it.
+		createThumbnail(maxDimension, maxPixels, attachment, store, repos)
+		return nil
+	}
+}
+
+func createThumbnail(
+	maxDimension int,
+	maxPixels int,
+	attachment persistence.Attachment,
+	store storage.BlobStore,
+	repos repositories.Repositories,
+) error {
+	ctx := context.Background()
+
+	data, err := store.Get(ctx, attachment.Id.String())
+	if err != nil {
+		return err
+	}
+	defer data.Close()
+
+	thumbnail, err := Generate(data, maxDimension, maxPixels)
+	if err != nil {
+		return err
+	}
+
+	var encoded bytes.Buffer
+	if err := Encode(thumbnail, &encoded); err != nil {
+		return err
+	}
+
+	key := Key(attachment)
+	if _, err := store.Put(ctx, key, &encoded); err != nil {
+		return err
+	}
+
+	err = repos.Attachment.UpdateThumbnail(
+		ctx, attachment.Id, thumbnail.Width, thumbnail.Height, key,
+	)
+	if err != nil {
+		store.Delete(ctx, key)
+	}
+
+	return err
+}
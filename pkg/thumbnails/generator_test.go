@@ -0,0 +1,81 @@
+package thumbnails
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/Knoblauchpilze/chat-server/pkg/persistence"
+	"github.com/Knoblauchpilze/chat-server/pkg/repositories"
+	"github.com/Knoblauchpilze/chat-server/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_Generator_CreatesThumbnail(t *testing.T) {
+	store, repo := newTestStore(t), &mockAttachmentRepository{}
+	attachment := persistence.Attachment{Id: uuid.New(), MimeType: "image/png"}
+	putTestBlob(t, store, attachment.Id.String(), generateTestImage(t, 64, 32, png.Encode))
+
+	callback := generateThumbnailCallback(16, testMaxPixels, store, repositories.Repositories{Attachment: repo})
+	err := callback(attachment)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, attachment.Id, repo.id)
+	assert.Equal(t, 64, repo.width)
+	assert.Equal(t, 32, repo.height)
+	assert.Equal(t, Key(attachment), repo.thumbnail)
+
+	data, err := store.Get(context.Background(), Key(attachment))
+	assert.Nil(t, err, "Actual err: %v", err)
+	defer data.Close()
+	config, err := png.DecodeConfig(data)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, image.Config{ColorModel: config.ColorModel, Width: 16, Height: 8}, config)
+}
+
+func TestUnit_Generator_WhenAttachmentIsNotAnImage_ExpectNoThumbnailAndNoError(t *testing.T) {
+	store, repo := newTestStore(t), &mockAttachmentRepository{}
+	attachment := persistence.Attachment{Id: uuid.New(), MimeType: "image/png"}
+	putTestBlob(t, store, attachment.Id.String(), []byte("not-an-image"))
+
+	callback := generateThumbnailCallback(16, testMaxPixels, store, repositories.Repositories{Attachment: repo})
+	err := callback(attachment)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, uuid.Nil, repo.id)
+	_, err = store.Get(context.Background(), Key(attachment))
+	assert.NotNil(t, err)
+}
+
+type mockAttachmentRepository struct {
+	repositories.AttachmentRepository
+
+	id        uuid.UUID
+	width     int
+	height    int
+	thumbnail string
+}
+
+func (m *mockAttachmentRepository) UpdateThumbnail(
+	ctx context.Context, id uuid.UUID, width int, height int, thumbnail string,
+) error {
+	m.id = id
+	m.width = width
+	m.height = height
+	m.thumbnail = thumbnail
+	return nil
+}
+
+func newTestStore(t *testing.T) storage.BlobStore {
+	store, err := storage.NewFilesystemStore(t.TempDir())
+	assert.Nil(t, err, "Actual err: %v", err)
+	return store
+}
+
+func putTestBlob(t *testing.T, store storage.BlobStore, key string, data []byte) {
+	_, err := store.Put(context.Background(), key, bytes.NewReader(data))
+	assert.Nil(t, err, "Actual err: %v", err)
+}
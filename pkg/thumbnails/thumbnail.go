@@ -0,0 +1,81 @@
+package thumbnails
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+
+	// Registers the decoders for the formats we support. Note that the
+	// gif decoder only returns the first frame of animated images.
+	_ "image/gif"
+	_ "image/jpeg"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"golang.org/x/image/draw"
+)
+
+type Thumbnail struct {
+	// Dimensions of the original image
+	Width  int
+	Height int
+
+	Image image.Image
+}
+
+// Generate scales down the image so that it fits in a square of
+// maxDimension pixels. Images with more than maxPixels pixels are
+// rejected before being decoded as they would use too much memory.
+func Generate(data io.Reader, maxDimension int, maxPixels int) (Thumbnail, error) {
+	if maxDimension <= 0 || maxPixels <= 0 {
+		return Thumbnail{}, errors.NewCode(ErrInvalidDimension)
+	}
+
+	// The header read to get the dimensions is decoded again with the
+	// rest of the image.
+	var header bytes.Buffer
+	config, _, err := image.DecodeConfig(io.TeeReader(data, &header))
+	if err != nil {
+		return Thumbnail{}, errors.WrapCode(err, ErrUnsupportedImage)
+	}
+	if int64(config.Width)*int64(config.Height) > int64(maxPixels) {
+		return Thumbnail{}, errors.NewCode(ErrImageTooLarge)
+	}
+
+	img, _, err := image.Decode(io.MultiReader(&header, data))
+	if err != nil {
+		return Thumbnail{}, errors.WrapCode(err, ErrUnsupportedImage)
+	}
+
+	bounds := img.Bounds()
+	out := Thumbnail{
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+	}
+
+	width, height := fitInto(out.Width, out.Height, maxDimension)
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), img, bounds, draw.Src, nil)
+	out.Image = thumbnail
+
+	return out, nil
+}
+
+func Encode(thumbnail Thumbnail, out io.Writer) error {
+	return png.Encode(out, thumbnail.Image)
+}
+
+// fitInto scales down the input dimensions so that the largest one is
+// at most maxDimension while preserving the aspect ratio. Images which
+// are already small enough are not upscaled.
+func fitInto(width int, height int, maxDimension int) (int, int) {
+	if width <= maxDimension && height <= maxDimension {
+		return width, height
+	}
+
+	if width >= height {
+		return maxDimension, max(1, height*maxDimension/width)
+	}
+
+	return max(1, width*maxDimension/height), maxDimension
+}
@@ -0,0 +1,153 @@
+package thumbnails
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/Knoblauchpilze/backend-toolkit/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_Generate_Png(t *testing.T) {
+	data := generateTestImage(t, 400, 200, png.Encode)
+
+	out, err := Generate(bytes.NewReader(data), 100, testMaxPixels)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, 400, out.Width)
+	assert.Equal(t, 200, out.Height)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), out.Image.Bounds())
+}
+
+func TestUnit_Generate_Jpeg(t *testing.T) {
+	encode := func(w io.Writer, img image.Image) error {
+		return jpeg.Encode(w, img, nil)
+	}
+	data := generateTestImage(t, 100, 300, encode)
+
+	out, err := Generate(bytes.NewReader(data), 150, testMaxPixels)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, 100, out.Width)
+	assert.Equal(t, 300, out.Height)
+	assert.Equal(t, image.Rect(0, 0, 50, 150), out.Image.Bounds())
+}
+
+func TestUnit_Generate_Gif_UsesFirstFrame(t *testing.T) {
+	palette := color.Palette{color.Black, color.White}
+	first := image.NewPaletted(image.Rect(0, 0, 64, 32), palette)
+	second := image.NewPaletted(image.Rect(0, 0, 64, 32), palette)
+	animation := &gif.GIF{
+		Image: []*image.Paletted{first, second},
+		Delay: []int{10, 10},
+	}
+	var data bytes.Buffer
+	err := gif.EncodeAll(&data, animation)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	out, err := Generate(&data, 16, testMaxPixels)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, 64, out.Width)
+	assert.Equal(t, 32, out.Height)
+	assert.Equal(t, image.Rect(0, 0, 16, 8), out.Image.Bounds())
+}
+
+func TestUnit_Generate_WhenImageIsSmall_ExpectNotUpscaled(t *testing.T) {
+	data := generateTestImage(t, 20, 10, png.Encode)
+
+	out, err := Generate(bytes.NewReader(data), 100, testMaxPixels)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, image.Rect(0, 0, 20, 10), out.Image.Bounds())
+}
+
+func TestUnit_Generate_WhenDataIsNotAnImage_ExpectError(t *testing.T) {
+	_, err := Generate(bytes.NewReader([]byte("not-an-image")), 100, testMaxPixels)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrUnsupportedImage),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestUnit_Generate_WhenDimensionIsInvalid_ExpectError(t *testing.T) {
+	data := generateTestImage(t, 20, 10, png.Encode)
+
+	_, err := Generate(bytes.NewReader(data), 0, testMaxPixels)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrInvalidDimension),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestUnit_Generate_WhenImageHasTooManyPixels_ExpectError(t *testing.T) {
+	data := generateTestImage(t, 20, 10, png.Encode)
+
+	_, err := Generate(bytes.NewReader(data), 100, 199)
+
+	assert.True(
+		t,
+		errors.IsErrorWithCode(err, ErrImageTooLarge),
+		"Actual err: %v",
+		err,
+	)
+}
+
+func TestUnit_Generate_WhenImageHasMaximumPixels_ExpectGenerated(t *testing.T) {
+	data := generateTestImage(t, 20, 10, png.Encode)
+
+	out, err := Generate(bytes.NewReader(data), 100, 200)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	assert.Equal(t, image.Rect(0, 0, 20, 10), out.Image.Bounds())
+}
+
+func TestUnit_Encode_ProducesPng(t *testing.T) {
+	data := generateTestImage(t, 40, 40, png.Encode)
+	thumbnail, err := Generate(bytes.NewReader(data), 10, testMaxPixels)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	var out bytes.Buffer
+	err = Encode(thumbnail, &out)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	config, format, err := image.DecodeConfig(&out)
+	assert.Nil(t, err, "Actual err: %v", err)
+	assert.Equal(t, "png", format)
+	assert.Equal(t, 10, config.Width)
+	assert.Equal(t, 10, config.Height)
+}
+
+const testMaxPixels = 1000 * 1000
+
+func generateTestImage(
+	t *testing.T,
+	width int,
+	height int,
+	encode func(io.Writer, image.Image) error,
+) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		for y := range height {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var out bytes.Buffer
+	err := encode(&out, img)
+	assert.Nil(t, err, "Actual err: %v", err)
+
+	return out.Bytes()
+}